@@ -23,6 +23,7 @@ package v1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -32,7 +33,7 @@ func (in *Kubegres) DeepCopyInto(out *Kubegres) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Kubegres.
@@ -53,6 +54,26 @@ func (in *Kubegres) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubegresAutomaticFailover) DeepCopyInto(out *KubegresAutomaticFailover) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	if in.EndTime != nil {
+		in, out := &in.EndTime, &out.EndTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubegresAutomaticFailover.
+func (in *KubegresAutomaticFailover) DeepCopy() *KubegresAutomaticFailover {
+	if in == nil {
+		return nil
+	}
+	out := new(KubegresAutomaticFailover)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KubegresBackUp) DeepCopyInto(out *KubegresBackUp) {
 	*out = *in
@@ -73,6 +94,8 @@ func (in *KubegresBlockingOperation) DeepCopyInto(out *KubegresBlockingOperation
 	*out = *in
 	out.StatefulSetOperation = in.StatefulSetOperation
 	out.StatefulSetSpecUpdateOperation = in.StatefulSetSpecUpdateOperation
+	in.FencingOperation.DeepCopyInto(&out.FencingOperation)
+	out.SwitchoverOperation = in.SwitchoverOperation
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubegresBlockingOperation.
@@ -105,9 +128,84 @@ func (in *KubegresDatabase) DeepCopy() *KubegresDatabase {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubegresDelayedReplica) DeepCopyInto(out *KubegresDelayedReplica) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubegresDelayedReplica.
+func (in *KubegresDelayedReplica) DeepCopy() *KubegresDelayedReplica {
+	if in == nil {
+		return nil
+	}
+	out := new(KubegresDelayedReplica)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KubegresFailover) DeepCopyInto(out *KubegresFailover) {
 	*out = *in
+	if in.FencingGracePeriodSeconds != nil {
+		in, out := &in.FencingGracePeriodSeconds, &out.FencingGracePeriodSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.DetectionDelaySeconds != nil {
+		in, out := &in.DetectionDelaySeconds, &out.DetectionDelaySeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.PromotionTimeoutSeconds != nil {
+		in, out := &in.PromotionTimeoutSeconds, &out.PromotionTimeoutSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.StabilizationSeconds != nil {
+		in, out := &in.StabilizationSeconds, &out.StabilizationSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.SwitchoverCatchUpTimeoutSeconds != nil {
+		in, out := &in.SwitchoverCatchUpTimeoutSeconds, &out.SwitchoverCatchUpTimeoutSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MaximumLagBytes != nil {
+		in, out := &in.MaximumLagBytes, &out.MaximumLagBytes
+		*out = new(int64)
+		**out = **in
+	}
+	if in.PreferredZones != nil {
+		in, out := &in.PreferredZones, &out.PreferredZones
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NeverPromoteLabelSelector != nil {
+		in, out := &in.NeverPromoteLabelSelector, &out.NeverPromoteLabelSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MinimumIntervalSeconds != nil {
+		in, out := &in.MinimumIntervalSeconds, &out.MinimumIntervalSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MaximumFailovers != nil {
+		in, out := &in.MaximumFailovers, &out.MaximumFailovers
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaximumFailoversWindowSeconds != nil {
+		in, out := &in.MaximumFailoversWindowSeconds, &out.MaximumFailoversWindowSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.DetectionQuorum != nil {
+		in, out := &in.DetectionQuorum, &out.DetectionQuorum
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubegresFailover.
@@ -120,6 +218,142 @@ func (in *KubegresFailover) DeepCopy() *KubegresFailover {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubegresFailoverCandidate) DeepCopyInto(out *KubegresFailoverCandidate) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubegresFailoverCandidate.
+func (in *KubegresFailoverCandidate) DeepCopy() *KubegresFailoverCandidate {
+	if in == nil {
+		return nil
+	}
+	out := new(KubegresFailoverCandidate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubegresFailoverRecord) DeepCopyInto(out *KubegresFailoverRecord) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	if in.EndTime != nil {
+		in, out := &in.EndTime, &out.EndTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubegresFailoverRecord.
+func (in *KubegresFailoverRecord) DeepCopy() *KubegresFailoverRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(KubegresFailoverRecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubegresFailoverSelection) DeepCopyInto(out *KubegresFailoverSelection) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	if in.Candidates != nil {
+		in, out := &in.Candidates, &out.Candidates
+		*out = make([]KubegresFailoverCandidate, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubegresFailoverSelection.
+func (in *KubegresFailoverSelection) DeepCopy() *KubegresFailoverSelection {
+	if in == nil {
+		return nil
+	}
+	out := new(KubegresFailoverSelection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubegresFailoverSuspension) DeepCopyInto(out *KubegresFailoverSuspension) {
+	*out = *in
+	if in.SuspendedTime != nil {
+		in, out := &in.SuspendedTime, &out.SuspendedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.AcknowledgedTime != nil {
+		in, out := &in.AcknowledgedTime, &out.AcknowledgedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.AutomaticFailovers != nil {
+		in, out := &in.AutomaticFailovers, &out.AutomaticFailovers
+		*out = make([]KubegresAutomaticFailover, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubegresFailoverSuspension.
+func (in *KubegresFailoverSuspension) DeepCopy() *KubegresFailoverSuspension {
+	if in == nil {
+		return nil
+	}
+	out := new(KubegresFailoverSuspension)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubegresFencingOperation) DeepCopyInto(out *KubegresFencingOperation) {
+	*out = *in
+	if in.Pods != nil {
+		in, out := &in.Pods, &out.Pods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ForceDeletedPods != nil {
+		in, out := &in.ForceDeletedPods, &out.ForceDeletedPods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubegresFencingOperation.
+func (in *KubegresFencingOperation) DeepCopy() *KubegresFencingOperation {
+	if in == nil {
+		return nil
+	}
+	out := new(KubegresFencingOperation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubegresInstance) DeepCopyInto(out *KubegresInstance) {
+	*out = *in
+	if in.ReplayLagBytes != nil {
+		in, out := &in.ReplayLagBytes, &out.ReplayLagBytes
+		*out = new(int64)
+		**out = **in
+	}
+	if in.ReplayLagSeconds != nil {
+		in, out := &in.ReplayLagSeconds, &out.ReplayLagSeconds
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubegresInstance.
+func (in *KubegresInstance) DeepCopy() *KubegresInstance {
+	if in == nil {
+		return nil
+	}
+	out := new(KubegresInstance)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KubegresList) DeepCopyInto(out *KubegresList) {
 	*out = *in
@@ -152,6 +386,98 @@ func (in *KubegresList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubegresPasswords) DeepCopyInto(out *KubegresPasswords) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubegresPasswords.
+func (in *KubegresPasswords) DeepCopy() *KubegresPasswords {
+	if in == nil {
+		return nil
+	}
+	out := new(KubegresPasswords)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubegresReplicaService) DeepCopyInto(out *KubegresReplicaService) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubegresReplicaService.
+func (in *KubegresReplicaService) DeepCopy() *KubegresReplicaService {
+	if in == nil {
+		return nil
+	}
+	out := new(KubegresReplicaService)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubegresReplication) DeepCopyInto(out *KubegresReplication) {
+	*out = *in
+	if in.NumberOfSyncStandbys != nil {
+		in, out := &in.NumberOfSyncStandbys, &out.NumberOfSyncStandbys
+		*out = new(int32)
+		**out = **in
+	}
+	in.Cascade.DeepCopyInto(&out.Cascade)
+	out.Slots = in.Slots
+	if in.DelayedReplicas != nil {
+		in, out := &in.DelayedReplicas, &out.DelayedReplicas
+		*out = make([]KubegresDelayedReplica, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubegresReplication.
+func (in *KubegresReplication) DeepCopy() *KubegresReplication {
+	if in == nil {
+		return nil
+	}
+	out := new(KubegresReplication)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubegresReplicationCascade) DeepCopyInto(out *KubegresReplicationCascade) {
+	*out = *in
+	if in.NumberOfDirectReplicas != nil {
+		in, out := &in.NumberOfDirectReplicas, &out.NumberOfDirectReplicas
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubegresReplicationCascade.
+func (in *KubegresReplicationCascade) DeepCopy() *KubegresReplicationCascade {
+	if in == nil {
+		return nil
+	}
+	out := new(KubegresReplicationCascade)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubegresReplicationSlots) DeepCopyInto(out *KubegresReplicationSlots) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubegresReplicationSlots.
+func (in *KubegresReplicationSlots) DeepCopy() *KubegresReplicationSlots {
+	if in == nil {
+		return nil
+	}
+	out := new(KubegresReplicationSlots)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KubegresScheduler) DeepCopyInto(out *KubegresScheduler) {
 	*out = *in
@@ -193,7 +519,9 @@ func (in *KubegresSpec) DeepCopyInto(out *KubegresSpec) {
 		copy(*out, *in)
 	}
 	in.Database.DeepCopyInto(&out.Database)
-	out.Failover = in.Failover
+	in.Failover.DeepCopyInto(&out.Failover)
+	in.Replication.DeepCopyInto(&out.Replication)
+	out.ReplicaService = in.ReplicaService
 	out.Backup = in.Backup
 	if in.Env != nil {
 		in, out := &in.Env, &out.Env
@@ -202,6 +530,7 @@ func (in *KubegresSpec) DeepCopyInto(out *KubegresSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	out.Passwords = in.Passwords
 	in.Scheduler.DeepCopyInto(&out.Scheduler)
 	in.Resources.DeepCopyInto(&out.Resources)
 	in.Volume.DeepCopyInto(&out.Volume)
@@ -212,6 +541,7 @@ func (in *KubegresSpec) DeepCopyInto(out *KubegresSpec) {
 	}
 	in.Probe.DeepCopyInto(&out.Probe)
 	out.Standby = in.Standby
+	in.Timeouts.DeepCopyInto(&out.Timeouts)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubegresSpec.
@@ -257,8 +587,44 @@ func (in *KubegresStatefulSetSpecUpdateOperation) DeepCopy() *KubegresStatefulSe
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KubegresStatus) DeepCopyInto(out *KubegresStatus) {
 	*out = *in
-	out.BlockingOperation = in.BlockingOperation
-	out.PreviousBlockingOperation = in.PreviousBlockingOperation
+	in.BlockingOperation.DeepCopyInto(&out.BlockingOperation)
+	in.PreviousBlockingOperation.DeepCopyInto(&out.PreviousBlockingOperation)
+	if in.Instances != nil {
+		in, out := &in.Instances, &out.Instances
+		*out = make([]KubegresInstance, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InstancesUpdateTime != nil {
+		in, out := &in.InstancesUpdateTime, &out.InstancesUpdateTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastFailoverSelection != nil {
+		in, out := &in.LastFailoverSelection, &out.LastFailoverSelection
+		*out = new(KubegresFailoverSelection)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SynchronousStandbys != nil {
+		in, out := &in.SynchronousStandbys, &out.SynchronousStandbys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.FailoverHistory != nil {
+		in, out := &in.FailoverHistory, &out.FailoverHistory
+		*out = make([]KubegresFailoverRecord, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.FailoverSuspension.DeepCopyInto(&out.FailoverSuspension)
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubegresStatus.
@@ -271,6 +637,51 @@ func (in *KubegresStatus) DeepCopy() *KubegresStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubegresSwitchoverOperation) DeepCopyInto(out *KubegresSwitchoverOperation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubegresSwitchoverOperation.
+func (in *KubegresSwitchoverOperation) DeepCopy() *KubegresSwitchoverOperation {
+	if in == nil {
+		return nil
+	}
+	out := new(KubegresSwitchoverOperation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubegresTimeouts) DeepCopyInto(out *KubegresTimeouts) {
+	*out = *in
+	if in.ReplicaDeployingSeconds != nil {
+		in, out := &in.ReplicaDeployingSeconds, &out.ReplicaDeployingSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.ReplicaUndeployingSeconds != nil {
+		in, out := &in.ReplicaUndeployingSeconds, &out.ReplicaUndeployingSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.SpecUpdatingSeconds != nil {
+		in, out := &in.SpecUpdatingSeconds, &out.SpecUpdatingSeconds
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubegresTimeouts.
+func (in *KubegresTimeouts) DeepCopy() *KubegresTimeouts {
+	if in == nil {
+		return nil
+	}
+	out := new(KubegresTimeouts)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Probe) DeepCopyInto(out *Probe) {
 	*out = *in
@@ -0,0 +1,519 @@
+/*
+Copyright 2021 Reactive Tech Limited.
+"Reactive Tech Limited" is a company located in England, United Kingdom.
+https://www.reactive-tech.io
+
+Lead Developer: Alex Arica
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+	postgresV1 "reactive-tech.io/kubegres/api/v1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+const (
+	// EnvPositionsAnnotation keeps the positions in the v1 field "spec.env" of the password environment variables
+	// which were converted into typed Secret references. It allows converting back to v1 without losing their order.
+	EnvPositionsAnnotation = "kubegres.reactive-tech.io/v1-env-positions"
+
+	// FailoverEnabledAnnotation keeps on a v1 Kubegres that the v2 field "spec.failover.enabled" was explicitly set
+	// to true, as the v1 field "spec.failover.isDisabled" cannot distinguish it from an unset field.
+	FailoverEnabledAnnotation = "kubegres.reactive-tech.io/v2-failover-enabled"
+
+	envVarNameOfSuperUserPsw       = "POSTGRES_PASSWORD"
+	envVarNameOfReplicationUserPsw = "POSTGRES_REPLICATION_PASSWORD"
+)
+
+// ConvertTo converts this Kubegres to the Hub version (v1).
+func (src *Kubegres) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*postgresV1.Kubegres)
+
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	dst.Status = convertStatusToV1(*src.Status.DeepCopy())
+
+	srcSpec := src.Spec.DeepCopy()
+	dst.Spec = postgresV1.KubegresSpec{
+		Replicas:         srcSpec.Replicas,
+		Image:            srcSpec.Image,
+		Port:             srcSpec.Port,
+		ImagePullSecrets: srcSpec.ImagePullSecrets,
+		CustomConfig:     srcSpec.CustomConfig,
+		Database:         postgresV1.KubegresDatabase(srcSpec.Database),
+		Failover: postgresV1.KubegresFailover{
+			IsDisabled:                      srcSpec.Failover.Enabled != nil && !*srcSpec.Failover.Enabled,
+			PromotePod:                      srcSpec.Failover.PromotePod,
+			FencingGracePeriodSeconds:       srcSpec.Failover.FencingGracePeriodSeconds,
+			DetectionDelaySeconds:           srcSpec.Failover.DetectionDelaySeconds,
+			PromotionTimeoutSeconds:         srcSpec.Failover.PromotionTimeoutSeconds,
+			StabilizationSeconds:            srcSpec.Failover.StabilizationSeconds,
+			SwitchoverPod:                   srcSpec.Failover.SwitchoverPod,
+			SwitchoverCatchUpTimeoutSeconds: srcSpec.Failover.SwitchoverCatchUpTimeoutSeconds,
+			Pvc:                             srcSpec.Failover.Pvc,
+			MaximumLagBytes:                 srcSpec.Failover.MaximumLagBytes,
+			PreferredZones:                  srcSpec.Failover.PreferredZones,
+			NeverPromoteLabelSelector:       srcSpec.Failover.NeverPromoteLabelSelector,
+			MinimumIntervalSeconds:          srcSpec.Failover.MinimumIntervalSeconds,
+			MaximumFailovers:                srcSpec.Failover.MaximumFailovers,
+			MaximumFailoversWindowSeconds:   srcSpec.Failover.MaximumFailoversWindowSeconds,
+			DetectionMode:                   srcSpec.Failover.DetectionMode,
+			DetectionQuorum:                 srcSpec.Failover.DetectionQuorum,
+		},
+		Replication:    convertReplicationToV1(srcSpec.Replication),
+		ReplicaService: postgresV1.KubegresReplicaService(srcSpec.ReplicaService),
+		Backup: postgresV1.KubegresBackUp{
+			Schedule:    srcSpec.Backup.Schedule,
+			VolumeMount: srcSpec.Backup.VolumeMount,
+			PvcName:     srcSpec.Backup.Pvc.Name,
+		},
+		Passwords:          postgresV1.KubegresPasswords{Generate: srcSpec.Secrets.Generate},
+		Scheduler:          postgresV1.KubegresScheduler(srcSpec.Scheduler),
+		Resources:          srcSpec.Resources,
+		Volume:             convertVolumeToV1(srcSpec.Volume),
+		SecurityContext:    srcSpec.SecurityContext,
+		Probe:              postgresV1.Probe(srcSpec.Probe),
+		ServiceAccountName: srcSpec.ServiceAccountName,
+		Standby:            postgresV1.Standby(srcSpec.Standby),
+		Timeouts:           postgresV1.KubegresTimeouts(srcSpec.Timeouts),
+	}
+
+	// If the annotation cannot be parsed, the password environment variables are appended
+	envPositions := map[string]int{}
+	if positions, ok := dst.Annotations[EnvPositionsAnnotation]; ok {
+		if err := json.Unmarshal([]byte(positions), &envPositions); err != nil {
+			envPositions = map[string]int{}
+		}
+		delete(dst.Annotations, EnvPositionsAnnotation)
+		if len(dst.Annotations) == 0 {
+			dst.Annotations = nil
+		}
+	}
+
+	secretEnvVars := map[string]*v1.SecretKeySelector{
+		envVarNameOfSuperUserPsw:       srcSpec.Secrets.SuperUserPassword,
+		envVarNameOfReplicationUserPsw: srcSpec.Secrets.ReplicationUserPassword,
+	}
+	dst.Spec.Env = insertSecretEnvVars(srcSpec.Env, secretEnvVars, envPositions)
+
+	delete(dst.Annotations, FailoverEnabledAnnotation)
+	if srcSpec.Failover.Enabled != nil && *srcSpec.Failover.Enabled {
+		if dst.Annotations == nil {
+			dst.Annotations = map[string]string{}
+		}
+		dst.Annotations[FailoverEnabledAnnotation] = "true"
+	} else if len(dst.Annotations) == 0 {
+		dst.Annotations = nil
+	}
+
+	return nil
+}
+
+// ConvertFrom converts from the Hub version (v1) to this version.
+func (dst *Kubegres) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*postgresV1.Kubegres)
+
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	dst.Status = convertStatusFromV1(*src.Status.DeepCopy())
+
+	srcSpec := src.Spec.DeepCopy()
+	dst.Spec = KubegresSpec{
+		Replicas:         srcSpec.Replicas,
+		Image:            srcSpec.Image,
+		Port:             srcSpec.Port,
+		ImagePullSecrets: srcSpec.ImagePullSecrets,
+		CustomConfig:     srcSpec.CustomConfig,
+		Database:         KubegresDatabase(srcSpec.Database),
+		Failover: KubegresFailover{
+			PromotePod:                      srcSpec.Failover.PromotePod,
+			FencingGracePeriodSeconds:       srcSpec.Failover.FencingGracePeriodSeconds,
+			DetectionDelaySeconds:           srcSpec.Failover.DetectionDelaySeconds,
+			PromotionTimeoutSeconds:         srcSpec.Failover.PromotionTimeoutSeconds,
+			StabilizationSeconds:            srcSpec.Failover.StabilizationSeconds,
+			SwitchoverPod:                   srcSpec.Failover.SwitchoverPod,
+			SwitchoverCatchUpTimeoutSeconds: srcSpec.Failover.SwitchoverCatchUpTimeoutSeconds,
+			Pvc:                             srcSpec.Failover.Pvc,
+			MaximumLagBytes:                 srcSpec.Failover.MaximumLagBytes,
+			PreferredZones:                  srcSpec.Failover.PreferredZones,
+			NeverPromoteLabelSelector:       srcSpec.Failover.NeverPromoteLabelSelector,
+			MinimumIntervalSeconds:          srcSpec.Failover.MinimumIntervalSeconds,
+			MaximumFailovers:                srcSpec.Failover.MaximumFailovers,
+			MaximumFailoversWindowSeconds:   srcSpec.Failover.MaximumFailoversWindowSeconds,
+			DetectionMode:                   srcSpec.Failover.DetectionMode,
+			DetectionQuorum:                 srcSpec.Failover.DetectionQuorum,
+		},
+		Replication:    convertReplicationFromV1(srcSpec.Replication),
+		ReplicaService: KubegresReplicaService(srcSpec.ReplicaService),
+		Backup: KubegresBackUp{
+			Schedule:    srcSpec.Backup.Schedule,
+			VolumeMount: srcSpec.Backup.VolumeMount,
+			Pvc:         KubegresBackUpPvc{Name: srcSpec.Backup.PvcName},
+		},
+		Secrets:            KubegresSecrets{Generate: srcSpec.Passwords.Generate},
+		Scheduler:          KubegresScheduler(srcSpec.Scheduler),
+		Resources:          srcSpec.Resources,
+		Volume:             convertVolumeFromV1(srcSpec.Volume),
+		SecurityContext:    srcSpec.SecurityContext,
+		Probe:              Probe(srcSpec.Probe),
+		ServiceAccountName: srcSpec.ServiceAccountName,
+		Standby:            Standby(srcSpec.Standby),
+		Timeouts:           KubegresTimeouts(srcSpec.Timeouts),
+	}
+
+	if srcSpec.Failover.IsDisabled {
+		enabled := false
+		dst.Spec.Failover.Enabled = &enabled
+	} else if src.Annotations[FailoverEnabledAnnotation] == "true" {
+		enabled := true
+		dst.Spec.Failover.Enabled = &enabled
+	}
+	delete(dst.Annotations, FailoverEnabledAnnotation)
+
+	env, secretEnvVars, envPositions := extractSecretEnvVars(srcSpec.Env)
+	dst.Spec.Env = env
+	dst.Spec.Secrets.SuperUserPassword = secretEnvVars[envVarNameOfSuperUserPsw]
+	dst.Spec.Secrets.ReplicationUserPassword = secretEnvVars[envVarNameOfReplicationUserPsw]
+
+	// The positions are not kept when appending the password environment variables restores the same order
+	appendedEnv := insertSecretEnvVars(append([]v1.EnvVar(nil), env...), secretEnvVars, nil)
+	if reflect.DeepEqual(appendedEnv, srcSpec.Env) {
+		envPositions = nil
+	}
+
+	delete(dst.Annotations, EnvPositionsAnnotation)
+	if len(envPositions) > 0 {
+		positions, err := json.Marshal(envPositions)
+		if err != nil {
+			return err
+		}
+		if dst.Annotations == nil {
+			dst.Annotations = map[string]string{}
+		}
+		dst.Annotations[EnvPositionsAnnotation] = string(positions)
+	} else if len(dst.Annotations) == 0 {
+		dst.Annotations = nil
+	}
+
+	return nil
+}
+
+// extractSecretEnvVars removes from the given environment variables the first occurrence of each password variable
+// whose value comes from a Secret. It returns the remaining variables, the extracted Secret references
+// and the positions of the extracted variables.
+func extractSecretEnvVars(env []v1.EnvVar) ([]v1.EnvVar, map[string]*v1.SecretKeySelector, map[string]int) {
+
+	var remainingEnv []v1.EnvVar
+	secretEnvVars := map[string]*v1.SecretKeySelector{}
+	envPositions := map[string]int{}
+
+	for position, envVar := range env {
+
+		isPasswordEnvVar := envVar.Name == envVarNameOfSuperUserPsw || envVar.Name == envVarNameOfReplicationUserPsw
+		_, isAlreadyExtracted := secretEnvVars[envVar.Name]
+
+		if isPasswordEnvVar && !isAlreadyExtracted && isValueFromSecretOnly(envVar) {
+			secretEnvVars[envVar.Name] = envVar.ValueFrom.SecretKeyRef
+			envPositions[envVar.Name] = position
+			continue
+		}
+
+		remainingEnv = append(remainingEnv, envVar)
+	}
+
+	return remainingEnv, secretEnvVars, envPositions
+}
+
+// insertSecretEnvVars adds the given Secret references as environment variables. They are inserted at their
+// recorded position when it is known, otherwise they are appended. An environment variable of the same name is
+// dropped, as the Secret reference set in 'spec.secrets' takes precedence over it.
+func insertSecretEnvVars(env []v1.EnvVar, secretEnvVars map[string]*v1.SecretKeySelector, envPositions map[string]int) []v1.EnvVar {
+
+	var names []string
+	for name, secretKeyRef := range secretEnvVars {
+		if secretKeyRef != nil {
+			names = append(names, name)
+		}
+	}
+
+	var remainingEnv []v1.EnvVar
+	for _, envVar := range env {
+		if !slices.Contains(names, envVar.Name) {
+			remainingEnv = append(remainingEnv, envVar)
+		}
+	}
+	env = remainingEnv
+
+	sort.Slice(names, func(i, j int) bool {
+		iPosition, iHasPosition := envPositions[names[i]]
+		jPosition, jHasPosition := envPositions[names[j]]
+		if iHasPosition != jHasPosition {
+			return iHasPosition
+		}
+		if iHasPosition && iPosition != jPosition {
+			return iPosition < jPosition
+		}
+		return names[i] < names[j]
+	})
+
+	for _, name := range names {
+		envVar := v1.EnvVar{Name: name, ValueFrom: &v1.EnvVarSource{SecretKeyRef: secretEnvVars[name]}}
+
+		position, hasPosition := envPositions[name]
+		if !hasPosition || position > len(env) {
+			env = append(env, envVar)
+			continue
+		}
+
+		env = append(env, v1.EnvVar{})
+		copy(env[position+1:], env[position:])
+		env[position] = envVar
+	}
+
+	return env
+}
+
+func isValueFromSecretOnly(envVar v1.EnvVar) bool {
+	return envVar.Value == "" &&
+		envVar.ValueFrom != nil &&
+		envVar.ValueFrom.SecretKeyRef != nil &&
+		envVar.ValueFrom.FieldRef == nil &&
+		envVar.ValueFrom.ResourceFieldRef == nil &&
+		envVar.ValueFrom.ConfigMapKeyRef == nil
+}
+
+// The types below are declared by both API versions. The types with the same fields are converted directly,
+// so that the build fails if a field is added to one version only.
+
+func convertReplicationToV1(src KubegresReplication) postgresV1.KubegresReplication {
+
+	var delayedReplicas []postgresV1.KubegresDelayedReplica
+	for _, delayedReplica := range src.DelayedReplicas {
+		delayedReplicas = append(delayedReplicas, postgresV1.KubegresDelayedReplica(delayedReplica))
+	}
+
+	return postgresV1.KubegresReplication{
+		Mode:                 src.Mode,
+		NumberOfSyncStandbys: src.NumberOfSyncStandbys,
+		Cascade:              postgresV1.KubegresReplicationCascade(src.Cascade),
+		Slots:                postgresV1.KubegresReplicationSlots(src.Slots),
+		DelayedReplicas:      delayedReplicas,
+	}
+}
+
+func convertReplicationFromV1(src postgresV1.KubegresReplication) KubegresReplication {
+
+	var delayedReplicas []KubegresDelayedReplica
+	for _, delayedReplica := range src.DelayedReplicas {
+		delayedReplicas = append(delayedReplicas, KubegresDelayedReplica(delayedReplica))
+	}
+
+	return KubegresReplication{
+		Mode:                 src.Mode,
+		NumberOfSyncStandbys: src.NumberOfSyncStandbys,
+		Cascade:              KubegresReplicationCascade(src.Cascade),
+		Slots:                KubegresReplicationSlots(src.Slots),
+		DelayedReplicas:      delayedReplicas,
+	}
+}
+
+func convertVolumeToV1(src Volume) postgresV1.Volume {
+
+	var volumeClaimTemplates []postgresV1.VolumeClaimTemplate
+	for _, volumeClaimTemplate := range src.VolumeClaimTemplates {
+		volumeClaimTemplates = append(volumeClaimTemplates, postgresV1.VolumeClaimTemplate(volumeClaimTemplate))
+	}
+
+	return postgresV1.Volume{
+		VolumeMounts:         src.VolumeMounts,
+		Volumes:              src.Volumes,
+		VolumeClaimTemplates: volumeClaimTemplates,
+	}
+}
+
+func convertVolumeFromV1(src postgresV1.Volume) Volume {
+
+	var volumeClaimTemplates []VolumeClaimTemplate
+	for _, volumeClaimTemplate := range src.VolumeClaimTemplates {
+		volumeClaimTemplates = append(volumeClaimTemplates, VolumeClaimTemplate(volumeClaimTemplate))
+	}
+
+	return Volume{
+		VolumeMounts:         src.VolumeMounts,
+		Volumes:              src.Volumes,
+		VolumeClaimTemplates: volumeClaimTemplates,
+	}
+}
+
+func convertStatusToV1(src KubegresStatus) postgresV1.KubegresStatus {
+
+	var instances []postgresV1.KubegresInstance
+	for _, instance := range src.Instances {
+		instances = append(instances, postgresV1.KubegresInstance(instance))
+	}
+
+	var failoverHistory []postgresV1.KubegresFailoverRecord
+	for _, failoverRecord := range src.FailoverHistory {
+		failoverHistory = append(failoverHistory, postgresV1.KubegresFailoverRecord(failoverRecord))
+	}
+
+	var lastFailoverSelection *postgresV1.KubegresFailoverSelection
+	if src.LastFailoverSelection != nil {
+		var candidates []postgresV1.KubegresFailoverCandidate
+		for _, candidate := range src.LastFailoverSelection.Candidates {
+			candidates = append(candidates, postgresV1.KubegresFailoverCandidate(candidate))
+		}
+		lastFailoverSelection = &postgresV1.KubegresFailoverSelection{
+			Time:           src.LastFailoverSelection.Time,
+			PromotedPod:    src.LastFailoverSelection.PromotedPod,
+			PromotedWalLsn: src.LastFailoverSelection.PromotedWalLsn,
+			IsManual:       src.LastFailoverSelection.IsManual,
+			Candidates:     candidates,
+		}
+	}
+
+	return postgresV1.KubegresStatus{
+		LastCreatedInstanceIndex:   src.LastCreatedInstanceIndex,
+		BlockingOperation:          convertBlockingOperationToV1(src.BlockingOperation),
+		PreviousBlockingOperation:  convertBlockingOperationToV1(src.PreviousBlockingOperation),
+		EnforcedReplicas:           src.EnforcedReplicas,
+		ObservedGeneration:         src.ObservedGeneration,
+		CurrentPrimary:             src.CurrentPrimary,
+		Instances:                  instances,
+		InstancesUpdateTime:        src.InstancesUpdateTime,
+		Replicas:                   src.Replicas,
+		Selector:                   src.Selector,
+		PasswordsVersion:           src.PasswordsVersion,
+		LastFailoverSelection:      lastFailoverSelection,
+		FailedPrimaryInstanceIndex: src.FailedPrimaryInstanceIndex,
+		SynchronousStandbys:        src.SynchronousStandbys,
+		FailoverHistory:            failoverHistory,
+		LastPrimaryWalLsn:          src.LastPrimaryWalLsn,
+		FailoverSuspension:         convertFailoverSuspensionToV1(src.FailoverSuspension),
+		Conditions:                 src.Conditions,
+	}
+}
+
+func convertStatusFromV1(src postgresV1.KubegresStatus) KubegresStatus {
+
+	var instances []KubegresInstance
+	for _, instance := range src.Instances {
+		instances = append(instances, KubegresInstance(instance))
+	}
+
+	var failoverHistory []KubegresFailoverRecord
+	for _, failoverRecord := range src.FailoverHistory {
+		failoverHistory = append(failoverHistory, KubegresFailoverRecord(failoverRecord))
+	}
+
+	var lastFailoverSelection *KubegresFailoverSelection
+	if src.LastFailoverSelection != nil {
+		var candidates []KubegresFailoverCandidate
+		for _, candidate := range src.LastFailoverSelection.Candidates {
+			candidates = append(candidates, KubegresFailoverCandidate(candidate))
+		}
+		lastFailoverSelection = &KubegresFailoverSelection{
+			Time:           src.LastFailoverSelection.Time,
+			PromotedPod:    src.LastFailoverSelection.PromotedPod,
+			PromotedWalLsn: src.LastFailoverSelection.PromotedWalLsn,
+			IsManual:       src.LastFailoverSelection.IsManual,
+			Candidates:     candidates,
+		}
+	}
+
+	return KubegresStatus{
+		LastCreatedInstanceIndex:   src.LastCreatedInstanceIndex,
+		BlockingOperation:          convertBlockingOperationFromV1(src.BlockingOperation),
+		PreviousBlockingOperation:  convertBlockingOperationFromV1(src.PreviousBlockingOperation),
+		EnforcedReplicas:           src.EnforcedReplicas,
+		ObservedGeneration:         src.ObservedGeneration,
+		CurrentPrimary:             src.CurrentPrimary,
+		Instances:                  instances,
+		InstancesUpdateTime:        src.InstancesUpdateTime,
+		Replicas:                   src.Replicas,
+		Selector:                   src.Selector,
+		PasswordsVersion:           src.PasswordsVersion,
+		LastFailoverSelection:      lastFailoverSelection,
+		FailedPrimaryInstanceIndex: src.FailedPrimaryInstanceIndex,
+		SynchronousStandbys:        src.SynchronousStandbys,
+		FailoverHistory:            failoverHistory,
+		LastPrimaryWalLsn:          src.LastPrimaryWalLsn,
+		FailoverSuspension:         convertFailoverSuspensionFromV1(src.FailoverSuspension),
+		Conditions:                 src.Conditions,
+	}
+}
+
+func convertBlockingOperationToV1(src KubegresBlockingOperation) postgresV1.KubegresBlockingOperation {
+	return postgresV1.KubegresBlockingOperation{
+		OperationId:                    src.OperationId,
+		StepId:                         src.StepId,
+		TimeOutEpocInSeconds:           src.TimeOutEpocInSeconds,
+		HasTimedOut:                    src.HasTimedOut,
+		StatefulSetOperation:           postgresV1.KubegresStatefulSetOperation(src.StatefulSetOperation),
+		StatefulSetSpecUpdateOperation: postgresV1.KubegresStatefulSetSpecUpdateOperation(src.StatefulSetSpecUpdateOperation),
+		FencingOperation:               postgresV1.KubegresFencingOperation(src.FencingOperation),
+		SwitchoverOperation:            postgresV1.KubegresSwitchoverOperation(src.SwitchoverOperation),
+	}
+}
+
+func convertBlockingOperationFromV1(src postgresV1.KubegresBlockingOperation) KubegresBlockingOperation {
+	return KubegresBlockingOperation{
+		OperationId:                    src.OperationId,
+		StepId:                         src.StepId,
+		TimeOutEpocInSeconds:           src.TimeOutEpocInSeconds,
+		HasTimedOut:                    src.HasTimedOut,
+		StatefulSetOperation:           KubegresStatefulSetOperation(src.StatefulSetOperation),
+		StatefulSetSpecUpdateOperation: KubegresStatefulSetSpecUpdateOperation(src.StatefulSetSpecUpdateOperation),
+		FencingOperation:               KubegresFencingOperation(src.FencingOperation),
+		SwitchoverOperation:            KubegresSwitchoverOperation(src.SwitchoverOperation),
+	}
+}
+
+func convertFailoverSuspensionToV1(src KubegresFailoverSuspension) postgresV1.KubegresFailoverSuspension {
+
+	var automaticFailovers []postgresV1.KubegresAutomaticFailover
+	for _, automaticFailover := range src.AutomaticFailovers {
+		automaticFailovers = append(automaticFailovers, postgresV1.KubegresAutomaticFailover(automaticFailover))
+	}
+
+	return postgresV1.KubegresFailoverSuspension{
+		IsSuspended:        src.IsSuspended,
+		SuspendedTime:      src.SuspendedTime,
+		Reason:             src.Reason,
+		AcknowledgedTime:   src.AcknowledgedTime,
+		AutomaticFailovers: automaticFailovers,
+	}
+}
+
+func convertFailoverSuspensionFromV1(src postgresV1.KubegresFailoverSuspension) KubegresFailoverSuspension {
+
+	var automaticFailovers []KubegresAutomaticFailover
+	for _, automaticFailover := range src.AutomaticFailovers {
+		automaticFailovers = append(automaticFailovers, KubegresAutomaticFailover(automaticFailover))
+	}
+
+	return KubegresFailoverSuspension{
+		IsSuspended:        src.IsSuspended,
+		SuspendedTime:      src.SuspendedTime,
+		Reason:             src.Reason,
+		AcknowledgedTime:   src.AcknowledgedTime,
+		AutomaticFailovers: automaticFailovers,
+	}
+}
@@ -0,0 +1,322 @@
+/*
+Copyright 2021 Reactive Tech Limited.
+"Reactive Tech Limited" is a company located in England, United Kingdom.
+https://www.reactive-tech.io
+
+Lead Developer: Alex Arica
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	postgresV1 "reactive-tech.io/kubegres/api/v1"
+)
+
+func TestConvertFromV1WithPasswordsFromSecretFailoverDisabledAndBackup(t *testing.T) {
+
+	kubegresV1 := givenV1Kubegres()
+	kubegresV1.Spec.Failover.IsDisabled = true
+	kubegresV1.Spec.Backup = postgresV1.KubegresBackUp{
+		Schedule:    "*/1 * * * *",
+		VolumeMount: "/tmp/my-kubegres",
+		PvcName:     "test-pvc-for-backup",
+	}
+	kubegresV1.Spec.Env = []v1.EnvVar{
+		{Name: "TZ", Value: "Europe/London"},
+		givenSecretEnvVar("POSTGRES_PASSWORD", "superUserPassword"),
+		{Name: "LANG", Value: "en_GB.utf8"},
+		givenSecretEnvVar("POSTGRES_REPLICATION_PASSWORD", "replicationUserPassword"),
+	}
+
+	kubegresV2 := whenV1KubegresIsConvertedToV2(t, kubegresV1)
+
+	spec := kubegresV2.Spec
+	if spec.Secrets.SuperUserPassword == nil || spec.Secrets.SuperUserPassword.Key != "superUserPassword" {
+		t.Errorf("expected the superuser password to reference the key 'superUserPassword', got %v", spec.Secrets.SuperUserPassword)
+	}
+	if spec.Secrets.ReplicationUserPassword == nil || spec.Secrets.ReplicationUserPassword.Key != "replicationUserPassword" {
+		t.Errorf("expected the replication password to reference the key 'replicationUserPassword', got %v", spec.Secrets.ReplicationUserPassword)
+	}
+
+	expectedEnv := []v1.EnvVar{{Name: "TZ", Value: "Europe/London"}, {Name: "LANG", Value: "en_GB.utf8"}}
+	if !reflect.DeepEqual(spec.Env, expectedEnv) {
+		t.Errorf("expected env %v, got %v", expectedEnv, spec.Env)
+	}
+	if spec.Failover.Enabled == nil || *spec.Failover.Enabled {
+		t.Errorf("expected 'failover.enabled' to be false, got %v", spec.Failover.Enabled)
+	}
+	if spec.Backup.Pvc.Name != "test-pvc-for-backup" {
+		t.Errorf("expected 'backup.pvc.name' to be 'test-pvc-for-backup', got '%s'", spec.Backup.Pvc.Name)
+	}
+
+	thenV2KubegresConvertedBackToV1ShouldBeEqualTo(t, kubegresV2, kubegresV1)
+}
+
+func TestConvertFromV1WithPasswordSetAsPlainValue(t *testing.T) {
+
+	kubegresV1 := givenV1Kubegres()
+	kubegresV1.Spec.Env = []v1.EnvVar{
+		{Name: "POSTGRES_PASSWORD", Value: "plainPassword"},
+		givenSecretEnvVar("POSTGRES_REPLICATION_PASSWORD", "replicationUserPassword"),
+	}
+
+	kubegresV2 := whenV1KubegresIsConvertedToV2(t, kubegresV1)
+
+	if kubegresV2.Spec.Secrets.SuperUserPassword != nil {
+		t.Errorf("expected the superuser password to stay in 'env', got %v", kubegresV2.Spec.Secrets.SuperUserPassword)
+	}
+	if kubegresV2.Spec.Secrets.ReplicationUserPassword == nil {
+		t.Errorf("expected the replication password to reference a Secret")
+	}
+	if len(kubegresV2.Spec.Env) != 1 {
+		t.Errorf("expected 1 env-var, got %v", kubegresV2.Spec.Env)
+	}
+
+	thenV2KubegresConvertedBackToV1ShouldBeEqualTo(t, kubegresV2, kubegresV1)
+}
+
+func TestConvertToV1WithFailoverEnabled(t *testing.T) {
+
+	kubegresV2 := givenV2Kubegres()
+	enabled := true
+	kubegresV2.Spec.Failover.Enabled = &enabled
+
+	kubegresV1 := whenV2KubegresIsConvertedToV1(t, kubegresV2)
+
+	if kubegresV1.Spec.Failover.IsDisabled {
+		t.Errorf("expected 'failover.isDisabled' to be false")
+	}
+	if kubegresV1.Annotations[FailoverEnabledAnnotation] != "true" {
+		t.Errorf("expected the annotation '%s' to be set, got %v", FailoverEnabledAnnotation, kubegresV1.Annotations)
+	}
+
+	thenV1KubegresConvertedBackToV2ShouldBeEqualTo(t, kubegresV1, kubegresV2)
+}
+
+func TestConvertToV1WithFailoverUnset(t *testing.T) {
+
+	kubegresV2 := givenV2Kubegres()
+
+	kubegresV1 := whenV2KubegresIsConvertedToV1(t, kubegresV2)
+
+	if _, ok := kubegresV1.Annotations[FailoverEnabledAnnotation]; ok {
+		t.Errorf("expected the annotation '%s' not to be set", FailoverEnabledAnnotation)
+	}
+
+	thenV1KubegresConvertedBackToV2ShouldBeEqualTo(t, kubegresV1, kubegresV2)
+}
+
+func TestConvertToV1WithPasswordSetInSecretsAndInEnv(t *testing.T) {
+
+	kubegresV2 := givenV2Kubegres()
+	kubegresV2.Spec.Env = []v1.EnvVar{
+		{Name: "POSTGRES_PASSWORD", Value: "plainPassword"},
+		{Name: "MY_VAR", Value: "myValue"},
+	}
+
+	kubegresV1 := whenV2KubegresIsConvertedToV1(t, kubegresV2)
+
+	expectedEnv := []v1.EnvVar{
+		{Name: "MY_VAR", Value: "myValue"},
+		givenSecretEnvVar("POSTGRES_PASSWORD", "superUserPassword"),
+	}
+	if !reflect.DeepEqual(kubegresV1.Spec.Env, expectedEnv) {
+		t.Errorf("expected the env-vars %v, got %v", expectedEnv, kubegresV1.Spec.Env)
+	}
+}
+
+func TestConvertToV1WithAllSpecAndStatusFields(t *testing.T) {
+
+	kubegresV2 := givenV2Kubegres()
+	numberOfSyncStandbys := int32(1)
+	numberOfDirectReplicas := int32(2)
+	timeoutSeconds := int64(300)
+	storageClassName := "standard"
+	now := metav1.Unix(1700000000, 0)
+
+	kubegresV2.Spec.Replication = KubegresReplication{
+		Mode:                 postgresV1.ReplicationModeSynchronous,
+		NumberOfSyncStandbys: &numberOfSyncStandbys,
+		Cascade:              KubegresReplicationCascade{Enabled: true, NumberOfDirectReplicas: &numberOfDirectReplicas},
+		Slots:                KubegresReplicationSlots{Enabled: true, MaxSlotWalKeepSize: "2Gi"},
+		DelayedReplicas:      []KubegresDelayedReplica{{Delay: "4h"}},
+	}
+	kubegresV2.Spec.ReplicaService = KubegresReplicaService{MaxLag: "30s"}
+	kubegresV2.Spec.Scheduler = KubegresScheduler{Tolerations: []v1.Toleration{{Key: "dedicated", Value: "postgres"}}}
+	kubegresV2.Spec.Volume = Volume{
+		VolumeMounts: []v1.VolumeMount{{Name: "scratch", MountPath: "/scratch"}},
+		Volumes:      []v1.Volume{{Name: "scratch"}},
+		VolumeClaimTemplates: []VolumeClaimTemplate{{
+			Name: "archive",
+			Spec: v1.PersistentVolumeClaimSpec{
+				StorageClassName: &storageClassName,
+				Resources: v1.ResourceRequirements{
+					Requests: v1.ResourceList{v1.ResourceStorage: resource.MustParse("1Gi")},
+				},
+			},
+		}},
+	}
+	kubegresV2.Spec.Probe = Probe{ReadinessProbe: &v1.Probe{PeriodSeconds: 5}}
+	kubegresV2.Spec.Standby = Standby{Enabled: true, PrimaryEndpoint: "my-external-primary"}
+	kubegresV2.Spec.Timeouts = KubegresTimeouts{ReplicaDeployingSeconds: &timeoutSeconds}
+
+	kubegresV2.Status = KubegresStatus{
+		LastCreatedInstanceIndex: 3,
+		BlockingOperation: KubegresBlockingOperation{
+			OperationId:          "Primary DB count spec enforcement",
+			StepId:               "Failing over",
+			StatefulSetOperation: KubegresStatefulSetOperation{InstanceIndex: 2, Name: "my-kubegres-2"},
+			FencingOperation:     KubegresFencingOperation{Pods: []string{"my-kubegres-1-0"}, IsFenced: true},
+			SwitchoverOperation:  KubegresSwitchoverOperation{FormerPrimaryInstanceIndex: 1, NewPrimaryInstanceIndex: 2},
+		},
+		CurrentPrimary: "my-kubegres-2-0",
+		Instances: []KubegresInstance{
+			{InstanceIndex: 2, StatefulSet: "my-kubegres-2", Pod: "my-kubegres-2-0", Role: "primary", IsReady: true},
+		},
+		InstancesUpdateTime: &now,
+		LastFailoverSelection: &KubegresFailoverSelection{
+			Time:        now,
+			PromotedPod: "my-kubegres-2-0",
+			Candidates:  []KubegresFailoverCandidate{{InstanceIndex: 2, Pod: "my-kubegres-2-0", ReceivedWalLsn: "0/3000060"}},
+		},
+		SynchronousStandbys: []string{"my-kubegres-3"},
+		FailoverHistory: []KubegresFailoverRecord{{
+			FormerPrimary: "my-kubegres-1-0",
+			NewPrimary:    "my-kubegres-2-0",
+			Reason:        postgresV1.FailoverReasonPrimaryUnhealthy,
+			StartTime:     now,
+			Outcome:       postgresV1.FailoverOutcomeInProgress,
+		}},
+		LastPrimaryWalLsn: "0/3000060",
+		FailoverSuspension: KubegresFailoverSuspension{
+			IsSuspended:        true,
+			SuspendedTime:      &now,
+			AutomaticFailovers: []KubegresAutomaticFailover{{StartTime: now, EndTime: &now}},
+		},
+		Conditions: []metav1.Condition{{Type: postgresV1.ConditionTypeReady, Status: metav1.ConditionFalse}},
+	}
+
+	kubegresV1 := whenV2KubegresIsConvertedToV1(t, kubegresV2)
+
+	if kubegresV1.Status.BlockingOperation.FencingOperation.Pods[0] != "my-kubegres-1-0" {
+		t.Errorf("expected the fenced Pods to be converted, got %v", kubegresV1.Status.BlockingOperation.FencingOperation)
+	}
+	if kubegresV1.Spec.Volume.VolumeClaimTemplates[0].Name != "archive" {
+		t.Errorf("expected the volume claim templates to be converted, got %v", kubegresV1.Spec.Volume.VolumeClaimTemplates)
+	}
+
+	thenV1KubegresConvertedBackToV2ShouldBeEqualTo(t, kubegresV1, kubegresV2)
+}
+
+func givenV1Kubegres() *postgresV1.Kubegres {
+	replicas := int32(3)
+	storageClassName := "standard"
+	return &postgresV1.Kubegres{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-kubegres",
+			Namespace: "default",
+			Labels:    map[string]string{"environment": "acceptancetesting"},
+		},
+		Spec: postgresV1.KubegresSpec{
+			Replicas: &replicas,
+			Image:    "postgres:14.5",
+			Port:     5432,
+			Database: postgresV1.KubegresDatabase{
+				Size:             "200Mi",
+				StorageClassName: &storageClassName,
+				VolumeMount:      "/var/lib/postgres/data",
+			},
+			CustomConfig: "base-kubegres-config",
+		},
+	}
+}
+
+func givenV2Kubegres() *Kubegres {
+	replicas := int32(3)
+	return &Kubegres{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-kubegres",
+			Namespace: "default",
+		},
+		Spec: KubegresSpec{
+			Replicas: &replicas,
+			Image:    "postgres:14.5",
+			Database: KubegresDatabase{Size: "200Mi"},
+			Secrets: KubegresSecrets{
+				SuperUserPassword: &v1.SecretKeySelector{
+					LocalObjectReference: v1.LocalObjectReference{Name: "my-kubegres-secret"},
+					Key:                  "superUserPassword",
+				},
+			},
+		},
+	}
+}
+
+func givenSecretEnvVar(envVarName, secretKey string) v1.EnvVar {
+	return v1.EnvVar{
+		Name: envVarName,
+		ValueFrom: &v1.EnvVarSource{
+			SecretKeyRef: &v1.SecretKeySelector{
+				LocalObjectReference: v1.LocalObjectReference{Name: "my-kubegres-secret"},
+				Key:                  secretKey,
+			},
+		},
+	}
+}
+
+func whenV1KubegresIsConvertedToV2(t *testing.T, kubegresV1 *postgresV1.Kubegres) *Kubegres {
+	kubegresV2 := &Kubegres{}
+	if err := kubegresV2.ConvertFrom(kubegresV1.DeepCopy()); err != nil {
+		t.Fatalf("unable to convert from v1: %v", err)
+	}
+	return kubegresV2
+}
+
+func whenV2KubegresIsConvertedToV1(t *testing.T, kubegresV2 *Kubegres) *postgresV1.Kubegres {
+	kubegresV1 := &postgresV1.Kubegres{}
+	if err := kubegresV2.DeepCopy().ConvertTo(kubegresV1); err != nil {
+		t.Fatalf("unable to convert to v1: %v", err)
+	}
+	return kubegresV1
+}
+
+func thenV2KubegresConvertedBackToV1ShouldBeEqualTo(t *testing.T, kubegresV2 *Kubegres, expected *postgresV1.Kubegres) {
+	kubegresV1 := whenV2KubegresIsConvertedToV1(t, kubegresV2)
+	if !reflect.DeepEqual(kubegresV1.ObjectMeta, expected.ObjectMeta) {
+		t.Errorf("expected metadata %v, got %v", expected.ObjectMeta, kubegresV1.ObjectMeta)
+	}
+	if !reflect.DeepEqual(kubegresV1.Spec, expected.Spec) {
+		t.Errorf("expected spec %+v, got %+v", expected.Spec, kubegresV1.Spec)
+	}
+}
+
+func thenV1KubegresConvertedBackToV2ShouldBeEqualTo(t *testing.T, kubegresV1 *postgresV1.Kubegres, expected *Kubegres) {
+	kubegresV2 := whenV1KubegresIsConvertedToV2(t, kubegresV1)
+	if !reflect.DeepEqual(kubegresV2.ObjectMeta, expected.ObjectMeta) {
+		t.Errorf("expected metadata %v, got %v", expected.ObjectMeta, kubegresV2.ObjectMeta)
+	}
+	if !reflect.DeepEqual(kubegresV2.Spec, expected.Spec) {
+		t.Errorf("expected spec %+v, got %+v", expected.Spec, kubegresV2.Spec)
+	}
+	if !reflect.DeepEqual(kubegresV2.Status, expected.Status) {
+		t.Errorf("expected status %+v, got %+v", expected.Status, kubegresV2.Status)
+	}
+}
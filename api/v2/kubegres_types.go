@@ -0,0 +1,484 @@
+/*
+Copyright 2021 Reactive Tech Limited.
+"Reactive Tech Limited" is a company located in England, United Kingdom.
+https://www.reactive-tech.io
+
+Lead Developer: Alex Arica
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ----------------------- SPEC -------------------------------------------
+
+// KubegresSecrets references the Secrets containing the passwords of the PostgreSql users.
+type KubegresSecrets struct {
+	// When true, Kubegres generates a Secret owned by the Kubegres resource containing random passwords
+	// for the users whose password is not referenced below.
+	Generate bool `json:"generate,omitempty"`
+
+	SuperUserPassword       *v1.SecretKeySelector `json:"superUserPassword,omitempty"`
+	ReplicationUserPassword *v1.SecretKeySelector `json:"replicationUserPassword,omitempty"`
+}
+
+type KubegresBackUpPvc struct {
+	Name string `json:"name,omitempty"`
+}
+
+type KubegresBackUp struct {
+	Schedule    string            `json:"schedule,omitempty"`
+	VolumeMount string            `json:"volumeMount,omitempty"`
+	Pvc         KubegresBackUpPvc `json:"pvc,omitempty"`
+}
+
+type KubegresFailover struct {
+	// Whether a Replica is automatically promoted as Primary when the Primary fails. It is enabled by default.
+	Enabled    *bool  `json:"enabled,omitempty"`
+	PromotePod string `json:"promotePod,omitempty"`
+
+	// The number of seconds to wait for the Pod of a failing Primary to terminate before force-deleting it.
+	// A Pod is only force-deleted if its node does not exist anymore or has been NotReady for at least that period.
+	// A Replica is only promoted once the Pod of the failing Primary is terminated.
+	// +kubebuilder:validation:Minimum=0
+	FencingGracePeriodSeconds *int64 `json:"fencingGracePeriodSeconds,omitempty"`
+
+	// The number of seconds to wait once a failing Primary is detected before promoting a Replica.
+	// +kubebuilder:validation:Minimum=0
+	DetectionDelaySeconds *int64 `json:"detectionDelaySeconds,omitempty"`
+
+	// The number of seconds after which a failover is considered as failed if the promoted Replica is not ready.
+	// +kubebuilder:validation:Minimum=1
+	PromotionTimeoutSeconds *int64 `json:"promotionTimeoutSeconds,omitempty"`
+
+	// The minimum number of seconds to wait once a Replica is promoted before completing the failover, so that the
+	// connections between the new Primary and the Replicas are established. It must be lower than 'promotionTimeoutSeconds'.
+	// +kubebuilder:validation:Minimum=0
+	StabilizationSeconds *int64 `json:"stabilizationSeconds,omitempty"`
+
+	// The name of a Replica Pod to promote as Primary with a planned switchover. The writes on the Primary are stopped,
+	// the Replica is promoted once it has replayed all the WAL of the Primary and the former Primary is restarted
+	// as a Replica of the new Primary. Kubegres resets this field once the switchover has started.
+	SwitchoverPod string `json:"switchoverPod,omitempty"`
+
+	// The number of seconds to wait during a switchover for the Replica to replay all the WAL of the Primary.
+	// Once elapsed, the switchover is cancelled and the writes are allowed again on the Primary.
+	// +kubebuilder:validation:Minimum=1
+	SwitchoverCatchUpTimeoutSeconds *int64 `json:"switchoverCatchUpTimeoutSeconds,omitempty"`
+
+	// What happens to the PVC of a Primary replaced by a failover. With 'retain', the PVC is left as it is.
+	// With 'delete', the PVC is deleted. With 'reuse', the data of the former Primary is rewound with 'pg_rewind'
+	// and the PVC is attached to the next deployed Replica, so that its data does not need to be copied from scratch.
+	// +kubebuilder:validation:Enum=retain;delete;reuse
+	Pvc string `json:"pvc,omitempty"`
+
+	// The maximum number of bytes of WAL a Replica can be behind to be promoted by an automatic failover.
+	// The lag of a Replica is measured against the last WAL LSN of the Primary known by Kubegres and against the
+	// most advanced Replica. When none of the ready Replicas is within that lag, the failover does not happen
+	// and the condition 'FailoverBlocked' is set, so that a Replica can be promoted manually with 'promotePod'.
+	// By default, any ready Replica can be promoted.
+	// +kubebuilder:validation:Minimum=0
+	MaximumLagBytes *int64 `json:"maximumLagBytes,omitempty"`
+
+	// The zones where a Replica should be promoted by an automatic failover, from the most to the least preferred.
+	// The zone of a Replica is the label 'topology.kubernetes.io/zone' of the Node of its Pod. A Replica in a zone
+	// which is not listed is only promoted if there is no Replica in the listed zones. Among the Replicas of
+	// the same preference, the one which has received the most WAL is promoted.
+	PreferredZones []string `json:"preferredZones,omitempty"`
+
+	// The Replicas whose Pod runs on a Node matching this label selector are never promoted by an automatic failover.
+	// They can still be promoted manually with 'promotePod' or 'switchoverPod'.
+	NeverPromoteLabelSelector *metav1.LabelSelector `json:"neverPromoteLabelSelector,omitempty"`
+
+	// The minimum number of seconds between the end of an automatic failover and the start of the next one.
+	// If the Primary fails again before, the automatic failovers are suspended. By default, there is no minimum.
+	// +kubebuilder:validation:Minimum=0
+	MinimumIntervalSeconds *int64 `json:"minimumIntervalSeconds,omitempty"`
+
+	// The maximum number of automatic failovers within 'maximumFailoversWindowSeconds'. Once reached, the automatic
+	// failovers are suspended. It is at most 10 as only the last 10 automatic failovers are kept in
+	// 'status.failoverSuspension.automaticFailovers'.
+	// By default, there is no maximum.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=10
+	MaximumFailovers *int32 `json:"maximumFailovers,omitempty"`
+
+	// The number of seconds of the window in which 'maximumFailovers' is counted. The default value is 3600.
+	// +kubebuilder:validation:Minimum=1
+	MaximumFailoversWindowSeconds *int64 `json:"maximumFailoversWindowSeconds,omitempty"`
+
+	// How a failing Primary is detected. With 'podReadiness', a Primary is failing when its Pod is not ready.
+	// With 'replicasQuorum', its Pod must not be ready and at least 'detectionQuorum' ready Replicas must have lost
+	// their replication connection to it, as reported by 'pg_stat_wal_receiver'. It prevents a failover when only
+	// the readiness probe or the kubelet cannot reach the Primary. A Replica only notices a lost connection after
+	// the setting 'wal_receiver_timeout' of PostgreSql. When the StatefulSet or the Pod of the Primary does not exist
+	// anymore, the quorum is not required.
+	// +kubebuilder:validation:Enum=podReadiness;replicasQuorum
+	DetectionMode string `json:"detectionMode,omitempty"`
+
+	// The number of ready Replicas which must have lost their replication connection to the Primary when
+	// 'detectionMode' is 'replicasQuorum'. By default, it is the majority of the ready Replicas.
+	// +kubebuilder:validation:Minimum=1
+	DetectionQuorum *int32 `json:"detectionQuorum,omitempty"`
+}
+
+type KubegresDatabase struct {
+	Size             string  `json:"size,omitempty"`
+	VolumeMount      string  `json:"volumeMount,omitempty"`
+	StorageClassName *string `json:"storageClassName,omitempty"`
+
+	// How Kubegres uses SSL when it connects to the PostgreSql instances to read their replication states and to
+	// apply the replication settings. With 'prefer', SSL is used if the instance supports it. The certificate
+	// of an instance is not verified. The default value is 'prefer'.
+	// +kubebuilder:validation:Enum=disable;prefer;require
+	SslMode string `json:"sslMode,omitempty"`
+}
+
+// KubegresReplication sets how the Replicas replicate the WAL of the Primary.
+type KubegresReplication struct {
+	// With 'asynchronous', a transaction is committed on the Primary without waiting for the Replicas.
+	// With 'synchronous', Kubegres sets 'synchronous_standby_names' on the Primary with the deployed Replicas
+	// so that a transaction is only committed once 'numberOfSyncStandbys' Replicas have written its WAL.
+	// During a failover, only a Replica which was synchronous can be promoted.
+	// +kubebuilder:validation:Enum=asynchronous;synchronous
+	Mode string `json:"mode,omitempty"`
+
+	// The number of synchronous Replicas when 'mode' is 'synchronous'. When fewer Replicas are deployed,
+	// all of them are synchronous.
+	// +kubebuilder:validation:Minimum=1
+	NumberOfSyncStandbys *int32 `json:"numberOfSyncStandbys,omitempty"`
+
+	// Cascade sets some Replicas to copy and stream the WAL from an upstream Replica instead of from the Primary.
+	Cascade KubegresReplicationCascade `json:"cascade,omitempty"`
+
+	// Slots sets a physical replication slot per Replica, so that its upstream instance retains the WAL it has
+	// not received yet.
+	Slots KubegresReplicationSlots `json:"slots,omitempty"`
+
+	// DelayedReplicas sets Replicas which deliberately lag behind the Primary, so that the data can be recovered
+	// after a logical mistake such as a wrong 'DELETE' without a full restore. They are deployed in addition to
+	// 'spec.replicas' and they are not counted in it.
+	DelayedReplicas []KubegresDelayedReplica `json:"delayedReplicas,omitempty"`
+}
+
+// KubegresDelayedReplica sets a Replica which applies the WAL of the Primary after a delay, set as
+// 'recovery_min_apply_delay'. A delayed Replica is labeled with the replication role 'delayed-replica', so that
+// it is not selected by the Replica Service, and it is never promoted as a Primary. The delayed Replicas are matched
+// with the entries of 'spec.replication.delayedReplicas' by instance index.
+type KubegresDelayedReplica struct {
+	// The delay with which the WAL is applied, e.g. '4h' or '30m'.
+	Delay string `json:"delay"`
+}
+
+// KubegresReplicationSlots sets a physical replication slot named 'kubegres_instance_<instance index>' for each
+// Replica on its upstream instance, which is set as 'primary_slot_name' of the Replica. Kubegres creates the slots
+// of the deployed Replicas and drops the slots of the undeployed ones. It requires PostgreSql 13 or later and it is
+// not applied when 'spec.standby.enabled' is true.
+type KubegresReplicationSlots struct {
+	Enabled bool `json:"enabled,omitempty"`
+
+	// The maximum size of the WAL that the replication slots can retain on an instance, set as
+	// 'max_slot_wal_keep_size', e.g. '2Gi'. A Replica which falls further behind loses its slot, so that the WAL
+	// cannot fill the volume of its upstream instance. By default, it is half of 'spec.database.size'.
+	MaxSlotWalKeepSize string `json:"maxSlotWalKeepSize,omitempty"`
+}
+
+// KubegresReplicationCascade sets a cascading replication, which reduces the number of WAL senders on the Primary and
+// the I/O when a Replica is copied. The first 'numberOfDirectReplicas' ready Replicas by instance index stream from
+// the Primary and the other Replicas are spread across them. Kubegres updates the setting 'primary_conninfo' of the
+// Replicas accordingly and rewires a Replica to another upstream Replica when its upstream Replica is not ready.
+// It is not applied when 'spec.standby.enabled' is true.
+type KubegresReplicationCascade struct {
+	Enabled bool `json:"enabled,omitempty"`
+
+	// The number of Replicas streaming from the Primary. It must not be lower than 'numberOfSyncStandbys' when
+	// 'mode' is 'synchronous', as only a Replica streaming from the Primary can be synchronous.
+	// +kubebuilder:validation:Minimum=1
+	NumberOfDirectReplicas *int32 `json:"numberOfDirectReplicas,omitempty"`
+}
+
+// KubegresReplicaService sets which Replicas are selected by the Replica Service '<name>-replica'.
+type KubegresReplicaService struct {
+	// The maximum replay lag of a Replica selected by the Replica Service, e.g. '30s' or '5m'. When the replay lag
+	// of a Replica is over it, Kubegres labels its Pod with the replication role 'lagging-replica', so that it is
+	// removed from the endpoints of the Replica Service, and it adds it back once the Replica has caught up.
+	// A Replica streaming the WAL to other Replicas with 'spec.replication.cascade' is never removed, as its host
+	// name is resolved with the Replica Service. By default, all Replicas are selected whatever their lag.
+	MaxLag string `json:"maxLag,omitempty"`
+}
+
+// KubegresTimeouts sets the number of seconds after which an operation on a Replica or a spec update is considered
+// as failed. Until a failed operation is fixed manually, most of the features of Kubegres are disabled.
+type KubegresTimeouts struct {
+	// +kubebuilder:validation:Minimum=1
+	ReplicaDeployingSeconds *int64 `json:"replicaDeployingSeconds,omitempty"`
+
+	// +kubebuilder:validation:Minimum=1
+	ReplicaUndeployingSeconds *int64 `json:"replicaUndeployingSeconds,omitempty"`
+
+	// +kubebuilder:validation:Minimum=1
+	SpecUpdatingSeconds *int64 `json:"specUpdatingSeconds,omitempty"`
+}
+
+type KubegresScheduler struct {
+	Affinity    *v1.Affinity    `json:"affinity,omitempty"`
+	Tolerations []v1.Toleration `json:"tolerations,omitempty"`
+}
+
+type VolumeClaimTemplate struct {
+	Name string                       `json:"name,omitempty"`
+	Spec v1.PersistentVolumeClaimSpec `json:"spec,omitempty" protobuf:"bytes,2,opt,name=spec"`
+}
+
+type Volume struct {
+	VolumeMounts         []v1.VolumeMount      `json:"volumeMounts,omitempty"`
+	Volumes              []v1.Volume           `json:"volumes,omitempty"`
+	VolumeClaimTemplates []VolumeClaimTemplate `json:"volumeClaimTemplates,omitempty"`
+}
+
+type Probe struct {
+	LivenessProbe  *v1.Probe `json:"livenessProbe,omitempty"`
+	ReadinessProbe *v1.Probe `json:"readinessProbe,omitempty"`
+}
+
+type Standby struct {
+	Enabled         bool   `json:"enabled,omitempty"`
+	PrimaryEndpoint string `json:"primaryEndpoint,omitempty"`
+}
+
+type KubegresSpec struct {
+	Replicas           *int32                    `json:"replicas,omitempty"`
+	Image              string                    `json:"image,omitempty"`
+	Port               int32                     `json:"port,omitempty"`
+	ImagePullSecrets   []v1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+	CustomConfig       string                    `json:"customConfig,omitempty"`
+	Database           KubegresDatabase          `json:"database,omitempty"`
+	Failover           KubegresFailover          `json:"failover,omitempty"`
+	Replication        KubegresReplication       `json:"replication,omitempty"`
+	ReplicaService     KubegresReplicaService    `json:"replicaService,omitempty"`
+	Backup             KubegresBackUp            `json:"backup,omitempty"`
+	Secrets            KubegresSecrets           `json:"secrets,omitempty"`
+	Env                []v1.EnvVar               `json:"env,omitempty"`
+	Scheduler          KubegresScheduler         `json:"scheduler,omitempty"`
+	Resources          v1.ResourceRequirements   `json:"resources,omitempty"`
+	Volume             Volume                    `json:"volume,omitempty"`
+	SecurityContext    *v1.PodSecurityContext    `json:"securityContext,omitempty"`
+	Probe              Probe                     `json:"probe,omitempty"`
+	ServiceAccountName string                    `json:"serviceAccountName,omitempty"`
+	Standby            Standby                   `json:"standby,omitempty"`
+	Timeouts           KubegresTimeouts          `json:"timeouts,omitempty"`
+}
+
+// ----------------------- STATUS -----------------------------------------
+
+type KubegresStatefulSetOperation struct {
+	InstanceIndex int32  `json:"instanceIndex,omitempty"`
+	Name          string `json:"name,omitempty"`
+}
+
+type KubegresStatefulSetSpecUpdateOperation struct {
+	SpecDifferences string `json:"specDifferences,omitempty"`
+}
+
+// KubegresFencingOperation records the fencing of the Pods of a failing Primary before a Replica is promoted.
+// A fenced Pod is removed from the endpoints of the Primary Service and deleted. It is force-deleted only if its node
+// was lost for at least the fencing grace period.
+type KubegresFencingOperation struct {
+	StartEpocInSeconds int64    `json:"startEpocInSeconds,omitempty"`
+	Pods               []string `json:"pods,omitempty"`
+	ForceDeletedPods   []string `json:"forceDeletedPods,omitempty"`
+	IsFenced           bool     `json:"isFenced,omitempty"`
+}
+
+// KubegresSwitchoverOperation records the instances involved in a planned switchover and the WAL position
+// of the former Primary once its writes were stopped.
+type KubegresSwitchoverOperation struct {
+	FormerPrimaryInstanceIndex int32  `json:"formerPrimaryInstanceIndex,omitempty"`
+	NewPrimaryInstanceIndex    int32  `json:"newPrimaryInstanceIndex,omitempty"`
+	FormerPrimaryWalLsn        string `json:"formerPrimaryWalLsn,omitempty"`
+}
+
+type KubegresBlockingOperation struct {
+	OperationId          string `json:"operationId,omitempty"`
+	StepId               string `json:"stepId,omitempty"`
+	TimeOutEpocInSeconds int64  `json:"timeOutEpocInSeconds,omitempty"`
+	HasTimedOut          bool   `json:"hasTimedOut,omitempty"`
+
+	// Custom operation fields
+	StatefulSetOperation           KubegresStatefulSetOperation           `json:"statefulSetOperation,omitempty"`
+	StatefulSetSpecUpdateOperation KubegresStatefulSetSpecUpdateOperation `json:"statefulSetSpecUpdateOperation,omitempty"`
+	FencingOperation               KubegresFencingOperation               `json:"fencingOperation,omitempty"`
+	SwitchoverOperation            KubegresSwitchoverOperation            `json:"switchoverOperation,omitempty"`
+}
+
+type KubegresInstance struct {
+	InstanceIndex int32  `json:"instanceIndex"`
+	StatefulSet   string `json:"statefulSet,omitempty"`
+	Pod           string `json:"pod,omitempty"`
+	Node          string `json:"node,omitempty"`
+	Role          string `json:"role,omitempty"`
+	IsReady       bool   `json:"isReady,omitempty"`
+	IsStuck       bool   `json:"isStuck,omitempty"`
+
+	// The following fields are only set when the operator can connect to the PostgreSql instance.
+	// For a Primary, 'walLsn' is the current WAL LSN and for a Replica it is the last replayed WAL LSN.
+	WalLsn           string `json:"walLsn,omitempty"`
+	ReplayLagBytes   *int64 `json:"replayLagBytes,omitempty"`
+	ReplayLagSeconds *int64 `json:"replayLagSeconds,omitempty"`
+}
+
+// KubegresFailoverCandidate is a ready Replica which was considered for a promotion as a Primary during a failover.
+// The WAL positions are only set when the operator could connect to the PostgreSql instance of the Replica.
+type KubegresFailoverCandidate struct {
+	InstanceIndex  int32  `json:"instanceIndex"`
+	Pod            string `json:"pod,omitempty"`
+	ReceivedWalLsn string `json:"receivedWalLsn,omitempty"`
+	ReplayedWalLsn string `json:"replayedWalLsn,omitempty"`
+}
+
+// KubegresFailoverSelection records the Replica which was promoted as a Primary during a failover.
+// When the failover is automatic, the promoted Replica is the candidate which has received the most WAL.
+type KubegresFailoverSelection struct {
+	Time           metav1.Time                 `json:"time"`
+	PromotedPod    string                      `json:"promotedPod"`
+	PromotedWalLsn string                      `json:"promotedWalLsn,omitempty"`
+	IsManual       bool                        `json:"isManual,omitempty"`
+	Candidates     []KubegresFailoverCandidate `json:"candidates,omitempty"`
+}
+
+// KubegresFailoverRecord records a failover or a switchover promoting a Replica as Primary.
+type KubegresFailoverRecord struct {
+	// The Pods of the Primary before and after the promotion
+	FormerPrimary string `json:"formerPrimary,omitempty"`
+	NewPrimary    string `json:"newPrimary,omitempty"`
+
+	// Either 'PrimaryUnhealthy', 'ManualPromotion' or 'Switchover'
+	Reason string `json:"reason"`
+
+	StartTime metav1.Time  `json:"startTime"`
+	EndTime   *metav1.Time `json:"endTime,omitempty"`
+
+	// Either 'InProgress', 'Succeeded', 'Failed' or 'Cancelled'
+	Outcome     string `json:"outcome"`
+	HasTimedOut bool   `json:"hasTimedOut,omitempty"`
+}
+
+// KubegresFailoverSuspension records that the automatic failovers are suspended because they happened too often.
+// They remain suspended until the annotation 'kubegres.reactive-tech.io/acknowledge-failover-suspension' is set
+// on the Kubegres resource.
+type KubegresFailoverSuspension struct {
+	IsSuspended   bool         `json:"isSuspended,omitempty"`
+	SuspendedTime *metav1.Time `json:"suspendedTime,omitempty"`
+
+	// Either 'MinimumIntervalNotElapsed' or 'MaximumFailoversReached'
+	Reason string `json:"reason,omitempty"`
+
+	// The last time the automatic failovers were acknowledged. Only the failovers which started after it are counted.
+	AcknowledgedTime *metav1.Time `json:"acknowledgedTime,omitempty"`
+
+	// The last 10 automatic failovers which started after the last acknowledgement. They are recorded apart from
+	// 'failoverHistory', so that the switchovers and the manual promotions do not push them out of the count.
+	AutomaticFailovers []KubegresAutomaticFailover `json:"automaticFailovers,omitempty"`
+}
+
+type KubegresAutomaticFailover struct {
+	StartTime metav1.Time  `json:"startTime"`
+	EndTime   *metav1.Time `json:"endTime,omitempty"`
+}
+
+type KubegresStatus struct {
+	LastCreatedInstanceIndex  int32                     `json:"lastCreatedInstanceIndex,omitempty"`
+	BlockingOperation         KubegresBlockingOperation `json:"blockingOperation,omitempty"`
+	PreviousBlockingOperation KubegresBlockingOperation `json:"previousBlockingOperation,omitempty"`
+	EnforcedReplicas          int32                     `json:"enforcedReplicas,omitempty"`
+	ObservedGeneration        int64                     `json:"observedGeneration,omitempty"`
+	CurrentPrimary            string                    `json:"currentPrimary,omitempty"`
+	Instances                 []KubegresInstance        `json:"instances,omitempty"`
+	InstancesUpdateTime       *metav1.Time              `json:"instancesUpdateTime,omitempty"`
+
+	// The number of deployed PostgreSql instances. It is read by the scale subresource.
+	Replicas int32 `json:"replicas,omitempty"`
+
+	// The label selector of the Pods of the PostgreSql instances. It is read by the scale subresource.
+	Selector string `json:"selector,omitempty"`
+
+	// The version of the passwords applied to PostgreSql by the last password rotation. It is set as an annotation
+	// in the template of the Pods, so that they are restarted once the passwords are rotated.
+	PasswordsVersion string `json:"passwordsVersion,omitempty"`
+
+	// The Replica promoted as a Primary by the last failover, with the WAL positions of the Replicas which were
+	// candidates for the promotion.
+	LastFailoverSelection *KubegresFailoverSelection `json:"lastFailoverSelection,omitempty"`
+
+	// The instance index of the Primary replaced by the last failover, until its PVC is handled according to
+	// the policy set in 'spec.failover.pvc'.
+	FailedPrimaryInstanceIndex int32 `json:"failedPrimaryInstanceIndex,omitempty"`
+
+	// The StatefulSets of the Replicas which were synchronous the last time the Primary could be queried,
+	// when 'spec.replication.mode' is 'synchronous'. Only those Replicas can be promoted during a failover.
+	SynchronousStandbys []string `json:"synchronousStandbys,omitempty"`
+
+	// The last failovers and switchovers, from the oldest to the most recent.
+	FailoverHistory []KubegresFailoverRecord `json:"failoverHistory,omitempty"`
+
+	// The last WAL LSN of the Primary known by Kubegres. It is refreshed with 'instances', at least every 30 seconds
+	// while the Primary can be queried, and is used during a failover to measure the lag of the Replicas once
+	// the Primary cannot be queried anymore.
+	LastPrimaryWalLsn string `json:"lastPrimaryWalLsn,omitempty"`
+
+	// Whether the automatic failovers are suspended because they happened too often.
+	FailoverSuspension KubegresFailoverSuspension `json:"failoverSuspension,omitempty"`
+
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+// ----------------------- RESOURCE ---------------------------------------
+
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +kubebuilder:subresource:status
+// +kubebuilder:subresource:scale:specpath=.spec.replicas,statuspath=.status.replicas,selectorpath=.status.selector
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+// +kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].reason"
+// +kubebuilder:printcolumn:name="Replicas",type="integer",JSONPath=".spec.replicas"
+// +kubebuilder:printcolumn:name="Primary",type="string",JSONPath=".status.currentPrimary"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// Kubegres is the Schema for the kubegres API
+type Kubegres struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   KubegresSpec   `json:"spec,omitempty"`
+	Status KubegresStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// KubegresList contains a list of Kubegres
+type KubegresList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Kubegres `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Kubegres{}, &KubegresList{})
+}
@@ -0,0 +1,806 @@
+//go:build !ignore_autogenerated
+// +build !ignore_autogenerated
+
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v2
+
+import (
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+)
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Kubegres) DeepCopyInto(out *Kubegres) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Kubegres.
+func (in *Kubegres) DeepCopy() *Kubegres {
+	if in == nil {
+		return nil
+	}
+	out := new(Kubegres)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Kubegres) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubegresAutomaticFailover) DeepCopyInto(out *KubegresAutomaticFailover) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	if in.EndTime != nil {
+		in, out := &in.EndTime, &out.EndTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubegresAutomaticFailover.
+func (in *KubegresAutomaticFailover) DeepCopy() *KubegresAutomaticFailover {
+	if in == nil {
+		return nil
+	}
+	out := new(KubegresAutomaticFailover)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubegresBackUp) DeepCopyInto(out *KubegresBackUp) {
+	*out = *in
+	out.Pvc = in.Pvc
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubegresBackUp.
+func (in *KubegresBackUp) DeepCopy() *KubegresBackUp {
+	if in == nil {
+		return nil
+	}
+	out := new(KubegresBackUp)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubegresBackUpPvc) DeepCopyInto(out *KubegresBackUpPvc) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubegresBackUpPvc.
+func (in *KubegresBackUpPvc) DeepCopy() *KubegresBackUpPvc {
+	if in == nil {
+		return nil
+	}
+	out := new(KubegresBackUpPvc)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubegresBlockingOperation) DeepCopyInto(out *KubegresBlockingOperation) {
+	*out = *in
+	out.StatefulSetOperation = in.StatefulSetOperation
+	out.StatefulSetSpecUpdateOperation = in.StatefulSetSpecUpdateOperation
+	in.FencingOperation.DeepCopyInto(&out.FencingOperation)
+	out.SwitchoverOperation = in.SwitchoverOperation
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubegresBlockingOperation.
+func (in *KubegresBlockingOperation) DeepCopy() *KubegresBlockingOperation {
+	if in == nil {
+		return nil
+	}
+	out := new(KubegresBlockingOperation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubegresDatabase) DeepCopyInto(out *KubegresDatabase) {
+	*out = *in
+	if in.StorageClassName != nil {
+		in, out := &in.StorageClassName, &out.StorageClassName
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubegresDatabase.
+func (in *KubegresDatabase) DeepCopy() *KubegresDatabase {
+	if in == nil {
+		return nil
+	}
+	out := new(KubegresDatabase)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubegresDelayedReplica) DeepCopyInto(out *KubegresDelayedReplica) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubegresDelayedReplica.
+func (in *KubegresDelayedReplica) DeepCopy() *KubegresDelayedReplica {
+	if in == nil {
+		return nil
+	}
+	out := new(KubegresDelayedReplica)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubegresFailover) DeepCopyInto(out *KubegresFailover) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.FencingGracePeriodSeconds != nil {
+		in, out := &in.FencingGracePeriodSeconds, &out.FencingGracePeriodSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.DetectionDelaySeconds != nil {
+		in, out := &in.DetectionDelaySeconds, &out.DetectionDelaySeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.PromotionTimeoutSeconds != nil {
+		in, out := &in.PromotionTimeoutSeconds, &out.PromotionTimeoutSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.StabilizationSeconds != nil {
+		in, out := &in.StabilizationSeconds, &out.StabilizationSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.SwitchoverCatchUpTimeoutSeconds != nil {
+		in, out := &in.SwitchoverCatchUpTimeoutSeconds, &out.SwitchoverCatchUpTimeoutSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MaximumLagBytes != nil {
+		in, out := &in.MaximumLagBytes, &out.MaximumLagBytes
+		*out = new(int64)
+		**out = **in
+	}
+	if in.PreferredZones != nil {
+		in, out := &in.PreferredZones, &out.PreferredZones
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NeverPromoteLabelSelector != nil {
+		in, out := &in.NeverPromoteLabelSelector, &out.NeverPromoteLabelSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MinimumIntervalSeconds != nil {
+		in, out := &in.MinimumIntervalSeconds, &out.MinimumIntervalSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MaximumFailovers != nil {
+		in, out := &in.MaximumFailovers, &out.MaximumFailovers
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaximumFailoversWindowSeconds != nil {
+		in, out := &in.MaximumFailoversWindowSeconds, &out.MaximumFailoversWindowSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.DetectionQuorum != nil {
+		in, out := &in.DetectionQuorum, &out.DetectionQuorum
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubegresFailover.
+func (in *KubegresFailover) DeepCopy() *KubegresFailover {
+	if in == nil {
+		return nil
+	}
+	out := new(KubegresFailover)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubegresFailoverCandidate) DeepCopyInto(out *KubegresFailoverCandidate) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubegresFailoverCandidate.
+func (in *KubegresFailoverCandidate) DeepCopy() *KubegresFailoverCandidate {
+	if in == nil {
+		return nil
+	}
+	out := new(KubegresFailoverCandidate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubegresFailoverRecord) DeepCopyInto(out *KubegresFailoverRecord) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	if in.EndTime != nil {
+		in, out := &in.EndTime, &out.EndTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubegresFailoverRecord.
+func (in *KubegresFailoverRecord) DeepCopy() *KubegresFailoverRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(KubegresFailoverRecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubegresFailoverSelection) DeepCopyInto(out *KubegresFailoverSelection) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	if in.Candidates != nil {
+		in, out := &in.Candidates, &out.Candidates
+		*out = make([]KubegresFailoverCandidate, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubegresFailoverSelection.
+func (in *KubegresFailoverSelection) DeepCopy() *KubegresFailoverSelection {
+	if in == nil {
+		return nil
+	}
+	out := new(KubegresFailoverSelection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubegresFailoverSuspension) DeepCopyInto(out *KubegresFailoverSuspension) {
+	*out = *in
+	if in.SuspendedTime != nil {
+		in, out := &in.SuspendedTime, &out.SuspendedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.AcknowledgedTime != nil {
+		in, out := &in.AcknowledgedTime, &out.AcknowledgedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.AutomaticFailovers != nil {
+		in, out := &in.AutomaticFailovers, &out.AutomaticFailovers
+		*out = make([]KubegresAutomaticFailover, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubegresFailoverSuspension.
+func (in *KubegresFailoverSuspension) DeepCopy() *KubegresFailoverSuspension {
+	if in == nil {
+		return nil
+	}
+	out := new(KubegresFailoverSuspension)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubegresFencingOperation) DeepCopyInto(out *KubegresFencingOperation) {
+	*out = *in
+	if in.Pods != nil {
+		in, out := &in.Pods, &out.Pods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ForceDeletedPods != nil {
+		in, out := &in.ForceDeletedPods, &out.ForceDeletedPods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubegresFencingOperation.
+func (in *KubegresFencingOperation) DeepCopy() *KubegresFencingOperation {
+	if in == nil {
+		return nil
+	}
+	out := new(KubegresFencingOperation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubegresInstance) DeepCopyInto(out *KubegresInstance) {
+	*out = *in
+	if in.ReplayLagBytes != nil {
+		in, out := &in.ReplayLagBytes, &out.ReplayLagBytes
+		*out = new(int64)
+		**out = **in
+	}
+	if in.ReplayLagSeconds != nil {
+		in, out := &in.ReplayLagSeconds, &out.ReplayLagSeconds
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubegresInstance.
+func (in *KubegresInstance) DeepCopy() *KubegresInstance {
+	if in == nil {
+		return nil
+	}
+	out := new(KubegresInstance)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubegresList) DeepCopyInto(out *KubegresList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Kubegres, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubegresList.
+func (in *KubegresList) DeepCopy() *KubegresList {
+	if in == nil {
+		return nil
+	}
+	out := new(KubegresList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KubegresList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubegresReplicaService) DeepCopyInto(out *KubegresReplicaService) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubegresReplicaService.
+func (in *KubegresReplicaService) DeepCopy() *KubegresReplicaService {
+	if in == nil {
+		return nil
+	}
+	out := new(KubegresReplicaService)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubegresReplication) DeepCopyInto(out *KubegresReplication) {
+	*out = *in
+	if in.NumberOfSyncStandbys != nil {
+		in, out := &in.NumberOfSyncStandbys, &out.NumberOfSyncStandbys
+		*out = new(int32)
+		**out = **in
+	}
+	in.Cascade.DeepCopyInto(&out.Cascade)
+	out.Slots = in.Slots
+	if in.DelayedReplicas != nil {
+		in, out := &in.DelayedReplicas, &out.DelayedReplicas
+		*out = make([]KubegresDelayedReplica, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubegresReplication.
+func (in *KubegresReplication) DeepCopy() *KubegresReplication {
+	if in == nil {
+		return nil
+	}
+	out := new(KubegresReplication)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubegresReplicationCascade) DeepCopyInto(out *KubegresReplicationCascade) {
+	*out = *in
+	if in.NumberOfDirectReplicas != nil {
+		in, out := &in.NumberOfDirectReplicas, &out.NumberOfDirectReplicas
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubegresReplicationCascade.
+func (in *KubegresReplicationCascade) DeepCopy() *KubegresReplicationCascade {
+	if in == nil {
+		return nil
+	}
+	out := new(KubegresReplicationCascade)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubegresReplicationSlots) DeepCopyInto(out *KubegresReplicationSlots) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubegresReplicationSlots.
+func (in *KubegresReplicationSlots) DeepCopy() *KubegresReplicationSlots {
+	if in == nil {
+		return nil
+	}
+	out := new(KubegresReplicationSlots)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubegresScheduler) DeepCopyInto(out *KubegresScheduler) {
+	*out = *in
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(v1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubegresScheduler.
+func (in *KubegresScheduler) DeepCopy() *KubegresScheduler {
+	if in == nil {
+		return nil
+	}
+	out := new(KubegresScheduler)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubegresSecrets) DeepCopyInto(out *KubegresSecrets) {
+	*out = *in
+	if in.SuperUserPassword != nil {
+		in, out := &in.SuperUserPassword, &out.SuperUserPassword
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReplicationUserPassword != nil {
+		in, out := &in.ReplicationUserPassword, &out.ReplicationUserPassword
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubegresSecrets.
+func (in *KubegresSecrets) DeepCopy() *KubegresSecrets {
+	if in == nil {
+		return nil
+	}
+	out := new(KubegresSecrets)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubegresSpec) DeepCopyInto(out *KubegresSpec) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]v1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	in.Database.DeepCopyInto(&out.Database)
+	in.Failover.DeepCopyInto(&out.Failover)
+	in.Replication.DeepCopyInto(&out.Replication)
+	out.ReplicaService = in.ReplicaService
+	out.Backup = in.Backup
+	in.Secrets.DeepCopyInto(&out.Secrets)
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]v1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Scheduler.DeepCopyInto(&out.Scheduler)
+	in.Resources.DeepCopyInto(&out.Resources)
+	in.Volume.DeepCopyInto(&out.Volume)
+	if in.SecurityContext != nil {
+		in, out := &in.SecurityContext, &out.SecurityContext
+		*out = new(v1.PodSecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Probe.DeepCopyInto(&out.Probe)
+	out.Standby = in.Standby
+	in.Timeouts.DeepCopyInto(&out.Timeouts)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubegresSpec.
+func (in *KubegresSpec) DeepCopy() *KubegresSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KubegresSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubegresStatefulSetOperation) DeepCopyInto(out *KubegresStatefulSetOperation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubegresStatefulSetOperation.
+func (in *KubegresStatefulSetOperation) DeepCopy() *KubegresStatefulSetOperation {
+	if in == nil {
+		return nil
+	}
+	out := new(KubegresStatefulSetOperation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubegresStatefulSetSpecUpdateOperation) DeepCopyInto(out *KubegresStatefulSetSpecUpdateOperation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubegresStatefulSetSpecUpdateOperation.
+func (in *KubegresStatefulSetSpecUpdateOperation) DeepCopy() *KubegresStatefulSetSpecUpdateOperation {
+	if in == nil {
+		return nil
+	}
+	out := new(KubegresStatefulSetSpecUpdateOperation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubegresStatus) DeepCopyInto(out *KubegresStatus) {
+	*out = *in
+	in.BlockingOperation.DeepCopyInto(&out.BlockingOperation)
+	in.PreviousBlockingOperation.DeepCopyInto(&out.PreviousBlockingOperation)
+	if in.Instances != nil {
+		in, out := &in.Instances, &out.Instances
+		*out = make([]KubegresInstance, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InstancesUpdateTime != nil {
+		in, out := &in.InstancesUpdateTime, &out.InstancesUpdateTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastFailoverSelection != nil {
+		in, out := &in.LastFailoverSelection, &out.LastFailoverSelection
+		*out = new(KubegresFailoverSelection)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SynchronousStandbys != nil {
+		in, out := &in.SynchronousStandbys, &out.SynchronousStandbys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.FailoverHistory != nil {
+		in, out := &in.FailoverHistory, &out.FailoverHistory
+		*out = make([]KubegresFailoverRecord, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.FailoverSuspension.DeepCopyInto(&out.FailoverSuspension)
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubegresStatus.
+func (in *KubegresStatus) DeepCopy() *KubegresStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KubegresStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubegresSwitchoverOperation) DeepCopyInto(out *KubegresSwitchoverOperation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubegresSwitchoverOperation.
+func (in *KubegresSwitchoverOperation) DeepCopy() *KubegresSwitchoverOperation {
+	if in == nil {
+		return nil
+	}
+	out := new(KubegresSwitchoverOperation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubegresTimeouts) DeepCopyInto(out *KubegresTimeouts) {
+	*out = *in
+	if in.ReplicaDeployingSeconds != nil {
+		in, out := &in.ReplicaDeployingSeconds, &out.ReplicaDeployingSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.ReplicaUndeployingSeconds != nil {
+		in, out := &in.ReplicaUndeployingSeconds, &out.ReplicaUndeployingSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.SpecUpdatingSeconds != nil {
+		in, out := &in.SpecUpdatingSeconds, &out.SpecUpdatingSeconds
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubegresTimeouts.
+func (in *KubegresTimeouts) DeepCopy() *KubegresTimeouts {
+	if in == nil {
+		return nil
+	}
+	out := new(KubegresTimeouts)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Probe) DeepCopyInto(out *Probe) {
+	*out = *in
+	if in.LivenessProbe != nil {
+		in, out := &in.LivenessProbe, &out.LivenessProbe
+		*out = new(v1.Probe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReadinessProbe != nil {
+		in, out := &in.ReadinessProbe, &out.ReadinessProbe
+		*out = new(v1.Probe)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Probe.
+func (in *Probe) DeepCopy() *Probe {
+	if in == nil {
+		return nil
+	}
+	out := new(Probe)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Standby) DeepCopyInto(out *Standby) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Standby.
+func (in *Standby) DeepCopy() *Standby {
+	if in == nil {
+		return nil
+	}
+	out := new(Standby)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Volume) DeepCopyInto(out *Volume) {
+	*out = *in
+	if in.VolumeMounts != nil {
+		in, out := &in.VolumeMounts, &out.VolumeMounts
+		*out = make([]v1.VolumeMount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Volumes != nil {
+		in, out := &in.Volumes, &out.Volumes
+		*out = make([]v1.Volume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.VolumeClaimTemplates != nil {
+		in, out := &in.VolumeClaimTemplates, &out.VolumeClaimTemplates
+		*out = make([]VolumeClaimTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Volume.
+func (in *Volume) DeepCopy() *Volume {
+	if in == nil {
+		return nil
+	}
+	out := new(Volume)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeClaimTemplate) DeepCopyInto(out *VolumeClaimTemplate) {
+	*out = *in
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeClaimTemplate.
+func (in *VolumeClaimTemplate) DeepCopy() *VolumeClaimTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeClaimTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -22,9 +22,12 @@ package ctx
 
 import (
 	"context"
+	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
+	"k8s.io/apimachinery/pkg/api/resource"
 	"reactive-tech.io/kubegres/api/v1"
 	"reactive-tech.io/kubegres/controllers/ctx/log"
 	"reactive-tech.io/kubegres/controllers/ctx/status"
@@ -40,21 +43,44 @@ type KubegresContext struct {
 }
 
 const (
-	PrimaryRoleName                        = "primary"
-	KindKubegres                           = "Kubegres"
-	DeploymentOwnerKey                     = ".metadata.controller"
-	DatabaseVolumeName                     = "postgres-db"
-	BaseConfigMapVolumeName                = "base-config"
-	CustomConfigMapVolumeName              = "custom-config"
-	BaseConfigMapName                      = "base-kubegres-config"
-	CronJobNamePrefix                      = "backup-"
-	DefaultContainerPortNumber             = 5432
-	DefaultPodServiceAccountName           = "default"
-	DefaultDatabaseVolumeMount             = "/var/lib/postgresql/data"
-	DefaultDatabaseFolder                  = "pgdata"
-	EnvVarNamePgData                       = "PGDATA"
-	EnvVarNameOfPostgresSuperUserPsw       = "POSTGRES_PASSWORD"
-	EnvVarNameOfPostgresReplicationUserPsw = "POSTGRES_REPLICATION_PASSWORD"
+	PrimaryRoleName                         = "primary"
+	ReplicaRoleName                         = "replica"
+	DelayedReplicaRoleName                  = "delayed-replica"
+	LaggingReplicaRoleName                  = "lagging-replica"
+	FencedRoleName                          = "fenced"
+	KindKubegres                            = "Kubegres"
+	DeploymentOwnerKey                      = ".metadata.controller"
+	DatabaseVolumeName                      = "postgres-db"
+	BaseConfigMapVolumeName                 = "base-config"
+	CustomConfigMapVolumeName               = "custom-config"
+	BaseConfigMapName                       = "base-kubegres-config"
+	CronJobNamePrefix                       = "backup-"
+	DefaultContainerPortNumber              = 5432
+	DefaultPodServiceAccountName            = "default"
+	DefaultDatabaseVolumeMount              = "/var/lib/postgresql/data"
+	DefaultDatabaseFolder                   = "pgdata"
+	DefaultFencingGracePeriodSeconds        = 30
+	DefaultFailoverDetectionDelaySeconds    = 10
+	DefaultFailoverPromotionTimeoutSeconds  = 300
+	DefaultFailoverStabilizationSeconds     = 40
+	DefaultReplicaDeployingTimeoutSeconds   = 300
+	DefaultReplicaUndeployingTimeoutSeconds = 60
+	DefaultSpecUpdatingTimeoutSeconds       = 300
+	DefaultSwitchoverCatchUpTimeoutSeconds  = 60
+	DefaultNumberOfSyncStandbys             = 1
+	DefaultNumberOfDirectReplicas           = 1
+	DefaultMaximumFailoversWindowSeconds    = 3600
+	EnvVarNamePgData                        = "PGDATA"
+	EnvVarNameOfPostgresSuperUserPsw        = "POSTGRES_PASSWORD"
+	EnvVarNameOfPostgresReplicationUserPsw  = "POSTGRES_REPLICATION_PASSWORD"
+	PasswordsSecretNameSuffix               = "-passwords"
+	AppliedPasswordsSecretNameSuffix        = "-applied-passwords"
+	PasswordsVersionAnnotationKey           = "kubegres.reactive-tech.io/passwords-version"
+	FailoverSuspensionAckAnnotationKey      = "kubegres.reactive-tech.io/acknowledge-failover-suspension"
+	PausedAnnotationKey                     = "kubegres.reactive-tech.io/paused"
+	ReplicationSlotNamePrefix               = "kubegres_instance_"
+	PasswordsSecretKeySuperUser             = "superUserPassword"
+	PasswordsSecretKeyReplicationUser       = "replicationUserPassword"
 )
 
 func (r *KubegresContext) GetServiceResourceName(isPrimary bool) string {
@@ -68,6 +94,147 @@ func (r *KubegresContext) GetStatefulSetResourceName(instanceIndex int32) string
 	return r.Kubegres.Name + "-" + strconv.Itoa(int(instanceIndex))
 }
 
+// GetDatabasePvcName returns the name of the PVC created by the StatefulSet of the given instance index
+// for the data of PostgreSql.
+func (r *KubegresContext) GetDatabasePvcName(instanceIndex int32) string {
+	return DatabaseVolumeName + "-" + r.GetStatefulSetResourceName(instanceIndex) + "-0"
+}
+
+func (r *KubegresContext) GetPasswordsSecretName() string {
+	return r.Kubegres.Name + PasswordsSecretNameSuffix
+}
+
+// GetAppliedPasswordsSecretName returns the name of the Secret where Kubegres stores the passwords currently set
+// in PostgreSql. It allows Kubegres to connect to PostgreSql while the passwords are being rotated.
+func (r *KubegresContext) GetAppliedPasswordsSecretName() string {
+	return r.Kubegres.Name + AppliedPasswordsSecretNameSuffix
+}
+
+func (r *KubegresContext) GetFencingGracePeriodSeconds() int64 {
+	return r.getSecondsOrDefault(r.Kubegres.Spec.Failover.FencingGracePeriodSeconds, DefaultFencingGracePeriodSeconds)
+}
+
+func (r *KubegresContext) GetFailoverDetectionDelaySeconds() int64 {
+	return r.getSecondsOrDefault(r.Kubegres.Spec.Failover.DetectionDelaySeconds, DefaultFailoverDetectionDelaySeconds)
+}
+
+func (r *KubegresContext) GetFailoverPromotionTimeoutSeconds() int64 {
+	return r.getSecondsOrDefault(r.Kubegres.Spec.Failover.PromotionTimeoutSeconds, DefaultFailoverPromotionTimeoutSeconds)
+}
+
+func (r *KubegresContext) GetFailoverStabilizationSeconds() int64 {
+	return r.getSecondsOrDefault(r.Kubegres.Spec.Failover.StabilizationSeconds, DefaultFailoverStabilizationSeconds)
+}
+
+func (r *KubegresContext) GetReplicaDeployingTimeoutSeconds() int64 {
+	return r.getSecondsOrDefault(r.Kubegres.Spec.Timeouts.ReplicaDeployingSeconds, DefaultReplicaDeployingTimeoutSeconds)
+}
+
+func (r *KubegresContext) GetReplicaUndeployingTimeoutSeconds() int64 {
+	return r.getSecondsOrDefault(r.Kubegres.Spec.Timeouts.ReplicaUndeployingSeconds, DefaultReplicaUndeployingTimeoutSeconds)
+}
+
+func (r *KubegresContext) GetSpecUpdatingTimeoutSeconds() int64 {
+	return r.getSecondsOrDefault(r.Kubegres.Spec.Timeouts.SpecUpdatingSeconds, DefaultSpecUpdatingTimeoutSeconds)
+}
+
+func (r *KubegresContext) GetSwitchoverCatchUpTimeoutSeconds() int64 {
+	return r.getSecondsOrDefault(r.Kubegres.Spec.Failover.SwitchoverCatchUpTimeoutSeconds, DefaultSwitchoverCatchUpTimeoutSeconds)
+}
+
+func (r *KubegresContext) GetMaximumFailoversWindowSeconds() int64 {
+	return r.getSecondsOrDefault(r.Kubegres.Spec.Failover.MaximumFailoversWindowSeconds, DefaultMaximumFailoversWindowSeconds)
+}
+
+// IsPaused returns whether the annotation 'PausedAnnotationKey' is set to "true" on the Kubegres resource.
+// While paused, Kubegres does not enforce the spec and only refreshes the status.
+func (r *KubegresContext) IsPaused() bool {
+	return r.Kubegres.Annotations[PausedAnnotationKey] == "true"
+}
+
+func (r *KubegresContext) GetDatabaseSslMode() string {
+	if r.Kubegres.Spec.Database.SslMode == "" {
+		return v1.DatabaseSslModePrefer
+	}
+	return r.Kubegres.Spec.Database.SslMode
+}
+
+func (r *KubegresContext) IsFailoverDetectedByReplicasQuorum() bool {
+	return r.Kubegres.Spec.Failover.DetectionMode == v1.FailoverDetectionModeReplicasQuorum
+}
+
+func (r *KubegresContext) IsSynchronousReplicationEnabled() bool {
+	return r.Kubegres.Spec.Replication.Mode == v1.ReplicationModeSynchronous
+}
+
+func (r *KubegresContext) GetNumberOfSyncStandbys() int32 {
+	if r.Kubegres.Spec.Replication.NumberOfSyncStandbys == nil {
+		return DefaultNumberOfSyncStandbys
+	}
+	return *r.Kubegres.Spec.Replication.NumberOfSyncStandbys
+}
+
+func (r *KubegresContext) IsCascadingReplicationEnabled() bool {
+	return r.Kubegres.Spec.Replication.Cascade.Enabled && !r.Kubegres.Spec.Standby.Enabled
+}
+
+func (r *KubegresContext) GetNumberOfDirectReplicas() int32 {
+	if r.Kubegres.Spec.Replication.Cascade.NumberOfDirectReplicas == nil {
+		return DefaultNumberOfDirectReplicas
+	}
+	return *r.Kubegres.Spec.Replication.Cascade.NumberOfDirectReplicas
+}
+
+// GetReplicaPodHostName returns the host name of the Pod of a Replica, which is resolved by the Replica Service.
+func (r *KubegresContext) GetReplicaPodHostName(podName string) string {
+	return podName + "." + r.GetServiceResourceName(false)
+}
+
+func (r *KubegresContext) IsReplicationSlotsEnabled() bool {
+	return r.Kubegres.Spec.Replication.Slots.Enabled && !r.Kubegres.Spec.Standby.Enabled
+}
+
+func (r *KubegresContext) GetReplicationSlotName(instanceIndex int32) string {
+	return ReplicationSlotNamePrefix + strconv.Itoa(int(instanceIndex))
+}
+
+// GetMaxSlotWalKeepSizeMegabytes returns the value of 'max_slot_wal_keep_size' in megabytes, which is half of the
+// database size if it is not set. It returns an error if the size it is computed from is not a valid quantity.
+func (r *KubegresContext) GetMaxSlotWalKeepSizeMegabytes() (int64, error) {
+	const megabyte = 1024 * 1024
+
+	maxSlotWalKeepSize := r.Kubegres.Spec.Replication.Slots.MaxSlotWalKeepSize
+	if maxSlotWalKeepSize == "" {
+		databaseSize, err := resource.ParseQuantity(r.Kubegres.Spec.Database.Size)
+		if err != nil {
+			return 0, fmt.Errorf("the value of the field 'spec.database.size' is not a valid quantity: %w", err)
+		}
+		return databaseSize.Value() / 2 / megabyte, nil
+	}
+
+	size, err := resource.ParseQuantity(maxSlotWalKeepSize)
+	if err != nil {
+		return 0, fmt.Errorf("the value of the field 'spec.replication.slots.maxSlotWalKeepSize' is not a valid quantity: %w", err)
+	}
+	return size.Value() / megabyte, nil
+}
+
+// GetReplicaServiceMaxLagSeconds returns the value of 'spec.replicaService.maxLag' in seconds, or 0 if it is not set.
+func (r *KubegresContext) GetReplicaServiceMaxLagSeconds() int64 {
+	maxLag, err := time.ParseDuration(r.Kubegres.Spec.ReplicaService.MaxLag)
+	if err != nil {
+		return 0
+	}
+	return int64(maxLag.Seconds())
+}
+
+func (r *KubegresContext) getSecondsOrDefault(seconds *int64, defaultSeconds int64) int64 {
+	if seconds == nil {
+		return defaultSeconds
+	}
+	return *seconds
+}
+
 func (r *KubegresContext) IsReservedVolumeName(volumeName string) bool {
 	return volumeName == DatabaseVolumeName ||
 		volumeName == BaseConfigMapVolumeName ||
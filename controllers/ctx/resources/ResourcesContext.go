@@ -33,6 +33,8 @@ import (
 	log3 "reactive-tech.io/kubegres/controllers/operation/log"
 	"reactive-tech.io/kubegres/controllers/spec/checker"
 	"reactive-tech.io/kubegres/controllers/spec/defaultspec"
+	"reactive-tech.io/kubegres/controllers/spec/enforcer/passwords_spec"
+	"reactive-tech.io/kubegres/controllers/spec/enforcer/replication_spec"
 	"reactive-tech.io/kubegres/controllers/spec/enforcer/resources_count_spec"
 	"reactive-tech.io/kubegres/controllers/spec/enforcer/resources_count_spec/statefulset"
 	"reactive-tech.io/kubegres/controllers/spec/enforcer/resources_count_spec/statefulset/failover"
@@ -40,33 +42,43 @@ import (
 	"reactive-tech.io/kubegres/controllers/spec/template"
 	"reactive-tech.io/kubegres/controllers/states"
 	log2 "reactive-tech.io/kubegres/controllers/states/log"
+	"reactive-tech.io/kubegres/controllers/status_update"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 type ResourcesContext struct {
-	LogWrapper                   log.LogWrapper
-	KubegresStatusWrapper        *status.KubegresStatusWrapper
-	KubegresContext              ctx2.KubegresContext
-	ResourcesStates              states.ResourcesStates
-	ResourcesStatesLogger        log2.ResourcesStatesLogger
-	SpecChecker                  checker.SpecChecker
-	DefaultStorageClass          defaultspec.DefaultStorageClass
-	CustomConfigSpecHelper       template.CustomConfigSpecHelper
-	ResourcesCreatorFromTemplate template.ResourcesCreatorFromTemplate
-	ResourcesCountSpecEnforcer   resources_count_spec.ResourcesCountSpecEnforcer
-	AllStatefulSetsSpecEnforcer  statefulset_spec.AllStatefulSetsSpecEnforcer
-	StatefulSetsSpecsEnforcer    statefulset_spec.StatefulSetsSpecsEnforcer
+	LogWrapper                     log.LogWrapper
+	KubegresStatusWrapper          *status.KubegresStatusWrapper
+	KubegresContext                ctx2.KubegresContext
+	ResourcesStates                states.ResourcesStates
+	ResourcesStatesLogger          log2.ResourcesStatesLogger
+	SpecChecker                    checker.SpecChecker
+	CustomConfigSpecHelper         template.CustomConfigSpecHelper
+	ResourcesCreatorFromTemplate   template.ResourcesCreatorFromTemplate
+	ResourcesCountSpecEnforcer     resources_count_spec.ResourcesCountSpecEnforcer
+	PasswordsRotationEnforcer      passwords_spec.PasswordsRotationSpecEnforcer
+	SynchronousReplicationEnforcer replication_spec.SynchronousReplicationSpecEnforcer
+	ReplicationSlotsEnforcer       replication_spec.ReplicationSlotsSpecEnforcer
+	CascadeReplicationEnforcer     replication_spec.CascadeReplicationSpecEnforcer
+	DelayedReplicasEnforcer        replication_spec.DelayedReplicasSpecEnforcer
+	ReplicaServiceLagEnforcer      replication_spec.ReplicaServiceLagSpecEnforcer
+	AllStatefulSetsSpecEnforcer    statefulset_spec.AllStatefulSetsSpecEnforcer
+	StatefulSetsSpecsEnforcer      statefulset_spec.StatefulSetsSpecsEnforcer
+	ConditionsStatusUpdater        status_update.ConditionsStatusUpdater
+	InstancesStatusUpdater         status_update.InstancesStatusUpdater
 
 	BlockingOperation          *operation.BlockingOperation
 	BlockingOperationLogger    log3.BlockingOperationLogger
 	PrimaryToReplicaFailOver   failover.PrimaryToReplicaFailOver
+	PrimarySwitchover          failover.PrimarySwitchover
 	PrimaryDbCountSpecEnforcer statefulset.PrimaryDbCountSpecEnforcer
 	ReplicaDbCountSpecEnforcer statefulset.ReplicaDbCountSpecEnforcer
 
-	BaseConfigMapCountSpecEnforcer resources_count_spec.BaseConfigMapCountSpecEnforcer
-	StatefulSetCountSpecEnforcer   resources_count_spec.StatefulSetCountSpecEnforcer
-	ServicesCountSpecEnforcer      resources_count_spec.ServicesCountSpecEnforcer
-	BackUpCronJobCountSpecEnforcer resources_count_spec.BackUpCronJobCountSpecEnforcer
+	PasswordsSecretCountSpecEnforcer resources_count_spec.PasswordsSecretCountSpecEnforcer
+	BaseConfigMapCountSpecEnforcer   resources_count_spec.BaseConfigMapCountSpecEnforcer
+	StatefulSetCountSpecEnforcer     resources_count_spec.StatefulSetCountSpecEnforcer
+	ServicesCountSpecEnforcer        resources_count_spec.ServicesCountSpecEnforcer
+	BackUpCronJobCountSpecEnforcer   resources_count_spec.BackUpCronJobCountSpecEnforcer
 }
 
 func CreateResourcesContext(kubegres *postgresV1.Kubegres,
@@ -75,8 +87,6 @@ func CreateResourcesContext(kubegres *postgresV1.Kubegres,
 	client client.Client,
 	recorder record.EventRecorder) (rc *ResourcesContext, err error) {
 
-	setReplicaFieldToZeroIfNil(kubegres)
-
 	rc = &ResourcesContext{}
 
 	rc.LogWrapper = log.LogWrapper{Kubegres: kubegres, Logger: logger, Recorder: recorder}
@@ -98,10 +108,11 @@ func CreateResourcesContext(kubegres *postgresV1.Kubegres,
 		Client:   client,
 	}
 
-	rc.DefaultStorageClass = defaultspec.CreateDefaultStorageClass(rc.KubegresContext)
-	if err = defaultspec.SetDefaultForUndefinedSpecValues(rc.KubegresContext, rc.DefaultStorageClass); err != nil {
+	if err = defaultspec.PersistDefaultForUndefinedSpecValues(rc.KubegresContext); err != nil {
 		return nil, err
 	}
+	setReplicaFieldToZeroIfNil(kubegres)
+	defaultspec.AddGeneratedPasswordsEnvVars(rc.KubegresContext)
 
 	rc.BlockingOperation = operation.CreateBlockingOperation(rc.KubegresContext)
 
@@ -121,9 +132,18 @@ func CreateResourcesContext(kubegres *postgresV1.Kubegres,
 	rc.ResourcesCreatorFromTemplate = template.CreateResourcesCreatorFromTemplate(rc.KubegresContext, rc.CustomConfigSpecHelper, resourceTemplateLoader)
 
 	addResourcesCountSpecEnforcers(rc)
+	rc.PasswordsRotationEnforcer = passwords_spec.CreatePasswordsRotationSpecEnforcer(rc.KubegresContext, rc.ResourcesStates, rc.ResourcesCreatorFromTemplate, rc.BlockingOperation)
+	rc.SynchronousReplicationEnforcer = replication_spec.CreateSynchronousReplicationSpecEnforcer(rc.KubegresContext, rc.ResourcesStates, rc.BlockingOperation)
+	rc.ReplicationSlotsEnforcer = replication_spec.CreateReplicationSlotsSpecEnforcer(rc.KubegresContext, rc.ResourcesStates, rc.BlockingOperation)
+	rc.CascadeReplicationEnforcer = replication_spec.CreateCascadeReplicationSpecEnforcer(rc.KubegresContext, rc.ResourcesStates, rc.BlockingOperation)
+	rc.DelayedReplicasEnforcer = replication_spec.CreateDelayedReplicasSpecEnforcer(rc.KubegresContext, rc.ResourcesStates, rc.BlockingOperation)
+	rc.ReplicaServiceLagEnforcer = replication_spec.CreateReplicaServiceLagSpecEnforcer(rc.KubegresContext, rc.ResourcesStates, rc.BlockingOperation)
 	addStatefulSetSpecEnforcers(rc)
 	addBlockingOperationConfigs(rc)
 
+	rc.ConditionsStatusUpdater = status_update.CreateConditionsStatusUpdater(rc.KubegresContext, rc.ResourcesStates, rc.BlockingOperation)
+	rc.InstancesStatusUpdater = status_update.CreateInstancesStatusUpdater(rc.KubegresContext, rc.ResourcesStates)
+
 	return rc, nil
 }
 
@@ -139,15 +159,18 @@ func setReplicaFieldToZeroIfNil(kubegres *postgresV1.Kubegres) {
 func addResourcesCountSpecEnforcers(rc *ResourcesContext) {
 
 	rc.PrimaryToReplicaFailOver = failover.CreatePrimaryToReplicaFailOver(rc.KubegresContext, rc.ResourcesStates, rc.BlockingOperation)
-	rc.PrimaryDbCountSpecEnforcer = statefulset.CreatePrimaryDbCountSpecEnforcer(rc.KubegresContext, rc.ResourcesStates, rc.ResourcesCreatorFromTemplate, rc.BlockingOperation, rc.PrimaryToReplicaFailOver)
+	rc.PrimarySwitchover = failover.CreatePrimarySwitchover(rc.KubegresContext, rc.ResourcesStates, rc.ResourcesCreatorFromTemplate, rc.BlockingOperation)
+	rc.PrimaryDbCountSpecEnforcer = statefulset.CreatePrimaryDbCountSpecEnforcer(rc.KubegresContext, rc.ResourcesStates, rc.ResourcesCreatorFromTemplate, rc.BlockingOperation, rc.PrimaryToReplicaFailOver, rc.PrimarySwitchover)
 	rc.ReplicaDbCountSpecEnforcer = statefulset.CreateReplicaDbCountSpecEnforcer(rc.KubegresContext, rc.ResourcesStates, rc.ResourcesCreatorFromTemplate, rc.BlockingOperation)
 	rc.StatefulSetCountSpecEnforcer = resources_count_spec.CreateStatefulSetCountSpecEnforcer(rc.PrimaryDbCountSpecEnforcer, rc.ReplicaDbCountSpecEnforcer)
 
+	rc.PasswordsSecretCountSpecEnforcer = resources_count_spec.CreatePasswordsSecretCountSpecEnforcer(rc.KubegresContext, rc.ResourcesStates, rc.ResourcesCreatorFromTemplate)
 	rc.BaseConfigMapCountSpecEnforcer = resources_count_spec.CreateBaseConfigMapCountSpecEnforcer(rc.KubegresContext, rc.ResourcesStates, rc.ResourcesCreatorFromTemplate, rc.BlockingOperation)
 	rc.ServicesCountSpecEnforcer = resources_count_spec.CreateServicesCountSpecEnforcer(rc.KubegresContext, rc.ResourcesStates, rc.ResourcesCreatorFromTemplate)
 	rc.BackUpCronJobCountSpecEnforcer = resources_count_spec.CreateBackUpCronJobCountSpecEnforcer(rc.KubegresContext, rc.ResourcesStates, rc.ResourcesCreatorFromTemplate)
 
 	rc.ResourcesCountSpecEnforcer = resources_count_spec.ResourcesCountSpecEnforcer{}
+	rc.ResourcesCountSpecEnforcer.AddSpecEnforcer(&rc.PasswordsSecretCountSpecEnforcer)
 	rc.ResourcesCountSpecEnforcer.AddSpecEnforcer(&rc.BaseConfigMapCountSpecEnforcer)
 	rc.ResourcesCountSpecEnforcer.AddSpecEnforcer(&rc.StatefulSetCountSpecEnforcer)
 	rc.ResourcesCountSpecEnforcer.AddSpecEnforcer(&rc.ServicesCountSpecEnforcer)
@@ -169,6 +192,7 @@ func addStatefulSetSpecEnforcers(rc *ResourcesContext) {
 	serviceAccountNameSpecEnforcer := statefulset_spec.CreateServiceAccountNameSpecEnforcer(rc.KubegresContext)
 	metadataSpecEnforcer := statefulset_spec.CreateMetadataSpecEnforcer(rc.KubegresContext)
 	standbyPrimaryEndpointSpecEnforcer := statefulset_spec.CreateStandbyPrimaryEndpointSpecEnforcer(rc.KubegresContext)
+	passwordsVersionSpecEnforcer := statefulset_spec.CreatePasswordsVersionSpecEnforcer(rc.KubegresContext)
 
 	rc.StatefulSetsSpecsEnforcer = statefulset_spec.CreateStatefulSetsSpecsEnforcer(rc.KubegresContext)
 	rc.StatefulSetsSpecsEnforcer.AddSpecEnforcer(&imageSpecEnforcer)
@@ -185,6 +209,7 @@ func addStatefulSetSpecEnforcers(rc *ResourcesContext) {
 	rc.StatefulSetsSpecsEnforcer.AddSpecEnforcer(&serviceAccountNameSpecEnforcer)
 	rc.StatefulSetsSpecsEnforcer.AddSpecEnforcer(&metadataSpecEnforcer)
 	rc.StatefulSetsSpecsEnforcer.AddSpecEnforcer(&standbyPrimaryEndpointSpecEnforcer)
+	rc.StatefulSetsSpecsEnforcer.AddSpecEnforcer(&passwordsVersionSpecEnforcer)
 
 	rc.AllStatefulSetsSpecEnforcer = statefulset_spec.CreateAllStatefulSetsSpecEnforcer(rc.KubegresContext, rc.ResourcesStates, rc.BlockingOperation, rc.StatefulSetsSpecsEnforcer)
 }
@@ -197,6 +222,11 @@ func addBlockingOperationConfigs(rc *ResourcesContext) {
 	rc.BlockingOperation.AddConfig(rc.PrimaryToReplicaFailOver.CreateOperationConfigWaitingBeforeForFailingOver())
 	rc.BlockingOperation.AddConfig(rc.PrimaryToReplicaFailOver.CreateOperationConfigForFailingOver())
 
+	rc.BlockingOperation.AddConfig(rc.PrimarySwitchover.CreateOperationConfigForWaitingForReplicaToCatchUp())
+	rc.BlockingOperation.AddConfig(rc.PrimarySwitchover.CreateOperationConfigForStoppingFormerPrimary())
+	rc.BlockingOperation.AddConfig(rc.PrimarySwitchover.CreateOperationConfigForPromotingReplica())
+	rc.BlockingOperation.AddConfig(rc.PrimarySwitchover.CreateOperationConfigForRejoiningFormerPrimary())
+
 	rc.BlockingOperation.AddConfig(rc.ReplicaDbCountSpecEnforcer.CreateOperationConfigForReplicaDbDeploying())
 	rc.BlockingOperation.AddConfig(rc.ReplicaDbCountSpecEnforcer.CreateOperationConfigForReplicaDbUndeploying())
 
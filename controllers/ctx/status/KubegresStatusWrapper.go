@@ -2,6 +2,9 @@ package status
 
 import (
 	"context"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	v1 "reactive-tech.io/kubegres/api/v1"
 	"reactive-tech.io/kubegres/controllers/ctx/log"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -51,6 +54,146 @@ func (r *KubegresStatusWrapper) SetPreviousBlockingOperation(value v1.KubegresBl
 	r.Kubegres.Status.PreviousBlockingOperation = value
 }
 
+func (r *KubegresStatusWrapper) GetObservedGeneration() int64 {
+	return r.Kubegres.Status.ObservedGeneration
+}
+
+func (r *KubegresStatusWrapper) SetObservedGeneration(value int64) {
+	r.addStatusFieldToUpdate("ObservedGeneration", value)
+	r.Kubegres.Status.ObservedGeneration = value
+}
+
+func (r *KubegresStatusWrapper) GetCurrentPrimary() string {
+	return r.Kubegres.Status.CurrentPrimary
+}
+
+func (r *KubegresStatusWrapper) SetCurrentPrimary(value string) {
+	r.addStatusFieldToUpdate("CurrentPrimary", value)
+	r.Kubegres.Status.CurrentPrimary = value
+}
+
+func (r *KubegresStatusWrapper) GetInstances() []v1.KubegresInstance {
+	return r.Kubegres.Status.Instances
+}
+
+func (r *KubegresStatusWrapper) SetInstances(value []v1.KubegresInstance) {
+	r.addStatusFieldToUpdate("Instances", value)
+	r.Kubegres.Status.Instances = value
+}
+
+func (r *KubegresStatusWrapper) GetInstancesUpdateTime() *metav1.Time {
+	return r.Kubegres.Status.InstancesUpdateTime
+}
+
+func (r *KubegresStatusWrapper) SetInstancesUpdateTime(value *metav1.Time) {
+	r.addStatusFieldToUpdate("InstancesUpdateTime", value)
+	r.Kubegres.Status.InstancesUpdateTime = value
+}
+
+func (r *KubegresStatusWrapper) GetReplicas() int32 {
+	return r.Kubegres.Status.Replicas
+}
+
+func (r *KubegresStatusWrapper) SetReplicas(value int32) {
+	r.addStatusFieldToUpdate("Replicas", value)
+	r.Kubegres.Status.Replicas = value
+}
+
+func (r *KubegresStatusWrapper) GetSelector() string {
+	return r.Kubegres.Status.Selector
+}
+
+func (r *KubegresStatusWrapper) SetSelector(value string) {
+	r.addStatusFieldToUpdate("Selector", value)
+	r.Kubegres.Status.Selector = value
+}
+
+func (r *KubegresStatusWrapper) GetPasswordsVersion() string {
+	return r.Kubegres.Status.PasswordsVersion
+}
+
+func (r *KubegresStatusWrapper) SetPasswordsVersion(value string) {
+	r.addStatusFieldToUpdate("PasswordsVersion", value)
+	r.Kubegres.Status.PasswordsVersion = value
+}
+
+func (r *KubegresStatusWrapper) GetLastFailoverSelection() *v1.KubegresFailoverSelection {
+	return r.Kubegres.Status.LastFailoverSelection
+}
+
+func (r *KubegresStatusWrapper) SetLastFailoverSelection(value *v1.KubegresFailoverSelection) {
+	r.addStatusFieldToUpdate("LastFailoverSelection", value)
+	r.Kubegres.Status.LastFailoverSelection = value
+}
+
+func (r *KubegresStatusWrapper) GetFailedPrimaryInstanceIndex() int32 {
+	return r.Kubegres.Status.FailedPrimaryInstanceIndex
+}
+
+func (r *KubegresStatusWrapper) SetFailedPrimaryInstanceIndex(value int32) {
+	r.addStatusFieldToUpdate("FailedPrimaryInstanceIndex", value)
+	r.Kubegres.Status.FailedPrimaryInstanceIndex = value
+}
+
+func (r *KubegresStatusWrapper) GetSynchronousStandbys() []string {
+	return r.Kubegres.Status.SynchronousStandbys
+}
+
+func (r *KubegresStatusWrapper) SetSynchronousStandbys(value []string) {
+	r.addStatusFieldToUpdate("SynchronousStandbys", value)
+	r.Kubegres.Status.SynchronousStandbys = value
+}
+
+func (r *KubegresStatusWrapper) GetFailoverHistory() []v1.KubegresFailoverRecord {
+	return r.Kubegres.Status.FailoverHistory
+}
+
+func (r *KubegresStatusWrapper) SetFailoverHistory(value []v1.KubegresFailoverRecord) {
+	r.addStatusFieldToUpdate("FailoverHistory", value)
+	r.Kubegres.Status.FailoverHistory = value
+}
+
+func (r *KubegresStatusWrapper) GetLastPrimaryWalLsn() string {
+	return r.Kubegres.Status.LastPrimaryWalLsn
+}
+
+func (r *KubegresStatusWrapper) SetLastPrimaryWalLsn(value string) {
+	r.addStatusFieldToUpdate("LastPrimaryWalLsn", value)
+	r.Kubegres.Status.LastPrimaryWalLsn = value
+}
+
+func (r *KubegresStatusWrapper) GetFailoverSuspension() v1.KubegresFailoverSuspension {
+	return r.Kubegres.Status.FailoverSuspension
+}
+
+func (r *KubegresStatusWrapper) SetFailoverSuspension(value v1.KubegresFailoverSuspension) {
+	r.addStatusFieldToUpdate("FailoverSuspension", value)
+	r.Kubegres.Status.FailoverSuspension = value
+}
+
+func (r *KubegresStatusWrapper) GetCondition(conditionType string) *metav1.Condition {
+	return meta.FindStatusCondition(r.Kubegres.Status.Conditions, conditionType)
+}
+
+// SetCondition adds or updates the given condition. The status is only marked for update when the condition
+// has changed, so that the field "LastTransitionTime" is preserved between reconciliations.
+func (r *KubegresStatusWrapper) SetCondition(value metav1.Condition) {
+
+	value.ObservedGeneration = r.Kubegres.Generation
+
+	current := r.GetCondition(value.Type)
+	if current != nil &&
+		current.Status == value.Status &&
+		current.Reason == value.Reason &&
+		current.Message == value.Message &&
+		current.ObservedGeneration == value.ObservedGeneration {
+		return
+	}
+
+	r.addStatusFieldToUpdate("Conditions."+value.Type, value.Status)
+	meta.SetStatusCondition(&r.Kubegres.Status.Conditions, value)
+}
+
 func (r *KubegresStatusWrapper) UpdateStatusIfChanged() error {
 	if r.statusFieldsToUpdate == nil {
 		return nil
@@ -27,9 +27,12 @@ import (
 	"github.com/go-logr/logr"
 	apps "k8s.io/api/apps/v1"
 	core "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/client-go/tools/record"
 	ctx2 "reactive-tech.io/kubegres/controllers/ctx"
 	"reactive-tech.io/kubegres/controllers/ctx/resources"
+	"reactive-tech.io/kubegres/controllers/postgres"
+	"reactive-tech.io/kubegres/controllers/status_update"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -52,12 +55,14 @@ type KubegresReconciler struct {
 
 // +kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch
 // +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="apps",resources=statefulsets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="batch",resources=cronjobs,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="storage.k8s.io",resources=storageclasses,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -71,6 +76,9 @@ func (r *KubegresReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 	kubegres, err := r.getDeployedKubegresResource(ctx, req)
 	if err != nil {
 		r.Logger.Info("Kubegres resource does not exist")
+		if apierrors.IsNotFound(err) {
+			postgres.ClosePooledConnectionsOfKubegres(req.Namespace, req.Name)
+		}
 		return ctrl.Result{}, nil
 	}
 
@@ -79,6 +87,13 @@ func (r *KubegresReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 		return ctrl.Result{}, err
 	}
 
+	if resourcesContext.KubegresContext.IsPaused() {
+		resourcesContext.BlockingOperation.ReadActiveOperation()
+		r.Logger.Info("The reconciliation is paused with the annotation '" + ctx2.PausedAnnotationKey + "'. " +
+			"Only the status is refreshed.")
+		return r.returnn(ctrl.Result{}, nil, resourcesContext)
+	}
+
 	nbreSecondsLeftBeforeTimeOut := resourcesContext.BlockingOperation.LoadActiveOperation()
 	resourcesContext.BlockingOperationLogger.Log()
 	resourcesContext.ResourcesStatesLogger.Log()
@@ -95,8 +110,10 @@ func (r *KubegresReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 	specCheckResult, err := resourcesContext.SpecChecker.CheckSpec()
 	if err != nil {
 		return r.returnn(ctrl.Result{}, err, resourcesContext)
+	}
 
-	} else if specCheckResult.HasSpecFatalError {
+	resourcesContext.ConditionsStatusUpdater.UpdateSpecCondition(specCheckResult)
+	if specCheckResult.HasSpecFatalError {
 		return r.returnn(ctrl.Result{}, nil, resourcesContext)
 	}
 
@@ -107,11 +124,19 @@ func (r *KubegresReconciler) returnn(result ctrl.Result,
 	err error,
 	resourcesContext *resources.ResourcesContext) (ctrl.Result, error) {
 
+	resourcesContext.ConditionsStatusUpdater.UpdateConditions()
+	resourcesContext.InstancesStatusUpdater.UpdateInstances()
+
 	errStatusUpt := resourcesContext.KubegresContext.Status.UpdateStatusIfChanged()
 	if errStatusUpt != nil && err == nil {
 		return result, errStatusUpt
 	}
 
+	// We requeue periodically so that the replication states of the instances (WAL LSN and lag) are refreshed in status
+	if err == nil && !result.Requeue && result.RequeueAfter == 0 {
+		result.RequeueAfter = status_update.InstancesRefreshPeriod
+	}
+
 	return result, err
 }
 
@@ -138,6 +163,36 @@ func (r *KubegresReconciler) enforceSpec(resourcesContext *resources.ResourcesCo
 		return err
 	}
 
+	err = r.enforcePasswordsRotation(resourcesContext)
+	if err != nil {
+		return err
+	}
+
+	err = r.enforceSynchronousReplication(resourcesContext)
+	if err != nil {
+		return err
+	}
+
+	err = r.enforceReplicationSlots(resourcesContext)
+	if err != nil {
+		return err
+	}
+
+	err = r.enforceCascadeReplication(resourcesContext)
+	if err != nil {
+		return err
+	}
+
+	err = r.enforceDelayedReplicas(resourcesContext)
+	if err != nil {
+		return err
+	}
+
+	err = r.enforceReplicaServiceLag(resourcesContext)
+	if err != nil {
+		return err
+	}
+
 	return r.enforceAllStatefulSetsSpec(resourcesContext)
 }
 
@@ -145,6 +200,30 @@ func (r *KubegresReconciler) enforceResourcesCountSpec(resourcesContext *resourc
 	return resourcesContext.ResourcesCountSpecEnforcer.EnforceSpec()
 }
 
+func (r *KubegresReconciler) enforcePasswordsRotation(resourcesContext *resources.ResourcesContext) error {
+	return resourcesContext.PasswordsRotationEnforcer.EnforceSpec()
+}
+
+func (r *KubegresReconciler) enforceSynchronousReplication(resourcesContext *resources.ResourcesContext) error {
+	return resourcesContext.SynchronousReplicationEnforcer.EnforceSpec()
+}
+
+func (r *KubegresReconciler) enforceReplicationSlots(resourcesContext *resources.ResourcesContext) error {
+	return resourcesContext.ReplicationSlotsEnforcer.EnforceSpec()
+}
+
+func (r *KubegresReconciler) enforceCascadeReplication(resourcesContext *resources.ResourcesContext) error {
+	return resourcesContext.CascadeReplicationEnforcer.EnforceSpec()
+}
+
+func (r *KubegresReconciler) enforceDelayedReplicas(resourcesContext *resources.ResourcesContext) error {
+	return resourcesContext.DelayedReplicasEnforcer.EnforceSpec()
+}
+
+func (r *KubegresReconciler) enforceReplicaServiceLag(resourcesContext *resources.ResourcesContext) error {
+	return resourcesContext.ReplicaServiceLagEnforcer.EnforceSpec()
+}
+
 func (r *KubegresReconciler) enforceAllStatefulSetsSpec(resourcesContext *resources.ResourcesContext) error {
 	return resourcesContext.AllStatefulSetsSpecEnforcer.EnforceSpec()
 }
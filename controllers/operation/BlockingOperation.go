@@ -61,10 +61,21 @@ func (r *BlockingOperation) LoadActiveOperation() int64 {
 	return nbreSecondsLeftBeforeTimeOut
 }
 
+// ReadActiveOperation loads the active operation from the status without checking whether it has completed or
+// timed-out, so that it is left unchanged while the reconciliation is paused.
+func (r *BlockingOperation) ReadActiveOperation() {
+	r.activeOperation = r.kubegresContext.Status.GetBlockingOperation()
+	r.previouslyActiveOperation = r.kubegresContext.Status.GetPreviousBlockingOperation()
+}
+
 func (r *BlockingOperation) IsActiveOperationIdDifferentOf(operationId string) bool {
 	return r.isThereActiveOperation() && r.activeOperation.OperationId != operationId
 }
 
+func (r *BlockingOperation) IsThereActiveOperation() bool {
+	return r.isThereActiveOperation()
+}
+
 func (r *BlockingOperation) IsActiveOperationInTransition(operationId string) bool {
 	return r.activeOperation.OperationId == operationId &&
 		r.activeOperation.StepId == TransitionOperationStepId
@@ -103,6 +114,31 @@ func (r *BlockingOperation) ActivateOperationOnStatefulSetSpecUpdate(operationId
 	return r.activateOperation(blockingOperation)
 }
 
+func (r *BlockingOperation) ActivateOperationOnStatefulSetFailOver(operationId string, stepId string,
+	statefulSetInstanceIndex int32, fencingOperation v1.KubegresFencingOperation) error {
+
+	blockingOperation := r.createOperationObj(operationId, stepId)
+	blockingOperation.StatefulSetOperation = r.createStatefulSetOperationObj(statefulSetInstanceIndex)
+	blockingOperation.FencingOperation = fencingOperation
+
+	return r.activateOperation(blockingOperation)
+}
+
+func (r *BlockingOperation) ActivateOperationOnStatefulSetSwitchover(operationId string, stepId string,
+	statefulSetInstanceIndex int32, switchoverOperation v1.KubegresSwitchoverOperation) error {
+
+	blockingOperation := r.createOperationObj(operationId, stepId)
+	blockingOperation.StatefulSetOperation = r.createStatefulSetOperationObj(statefulSetInstanceIndex)
+	blockingOperation.SwitchoverOperation = switchoverOperation
+
+	return r.activateOperation(blockingOperation)
+}
+
+func (r *BlockingOperation) SetActiveOperationFencing(fencingOperation v1.KubegresFencingOperation) {
+	r.activeOperation.FencingOperation = fencingOperation
+	r.kubegresContext.Status.SetBlockingOperation(r.activeOperation)
+}
+
 func (r *BlockingOperation) RemoveActiveOperation() {
 	r.removeActiveOperation(false)
 }
@@ -34,6 +34,12 @@ const (
 	OperationStepIdPrimaryDbWaitingBeforeFailingOver = "Waiting few seconds before failing over by promoting a Replica DB as a Primary DB"
 	OperationStepIdPrimaryDbFailingOver              = "Failing over by promoting a Replica DB as a Primary DB"
 
+	OperationIdPrimaryDbSwitchover                      = "Primary DB switchover"
+	OperationStepIdSwitchoverWaitingForReplicaToCatchUp = "Writes are stopped on the Primary DB. Waiting for the Replica DB to replay all the WAL"
+	OperationStepIdSwitchoverStoppingFormerPrimaryDb    = "Stopping the former Primary DB"
+	OperationStepIdSwitchoverPromotingReplicaDb         = "Promoting the Replica DB as the new Primary DB"
+	OperationStepIdSwitchoverRejoiningFormerPrimaryDb   = "Restarting the former Primary DB as a Replica DB"
+
 	OperationIdReplicaDbCountSpecEnforcement = "Replica DB count spec enforcement"
 	OperationStepIdReplicaDbDeploying        = "Replica DB is deploying"
 	OperationStepIdReplicaDbUndeploying      = "Replica DB is undeploying"
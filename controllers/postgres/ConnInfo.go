@@ -0,0 +1,159 @@
+/*
+Copyright 2021 Reactive Tech Limited.
+"Reactive Tech Limited" is a company located in England, United Kingdom.
+https://www.reactive-tech.io
+
+Lead Developer: Alex Arica
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"errors"
+	"strings"
+)
+
+type connInfoParam struct {
+	key   string
+	value string
+}
+
+// SetConnInfoPassword returns the given libpq connection string, in the "key=value" format, with its password
+// replaced by the given one. It is used to update the setting 'primary_conninfo' of a Replica.
+func SetConnInfoPassword(connInfo, password string) (string, error) {
+	return SetConnInfoParam(connInfo, "password", password)
+}
+
+// GetConnInfoParam returns the value of the given key in a libpq connection string in the "key=value" format.
+// It returns an empty value if the key is not set.
+func GetConnInfoParam(connInfo, key string) (string, error) {
+
+	params, err := parseConnInfo(connInfo)
+	if err != nil {
+		return "", err
+	}
+
+	for _, param := range params {
+		if param.key == key {
+			return param.value, nil
+		}
+	}
+
+	return "", nil
+}
+
+// SetConnInfoParam returns the given libpq connection string, in the "key=value" format, with the value of
+// the given key replaced by the given one. The key is appended if it is not set.
+func SetConnInfoParam(connInfo, key, value string) (string, error) {
+
+	params, err := parseConnInfo(connInfo)
+	if err != nil {
+		return "", err
+	}
+
+	isParamSet := false
+	for i := range params {
+		if params[i].key == key {
+			params[i].value = value
+			isParamSet = true
+		}
+	}
+
+	if !isParamSet {
+		params = append(params, connInfoParam{key: key, value: value})
+	}
+
+	var formattedParams []string
+	for _, param := range params {
+		formattedParams = append(formattedParams, param.key+"="+quoteConnInfoValue(param.value))
+	}
+
+	return strings.Join(formattedParams, " "), nil
+}
+
+func parseConnInfo(connInfo string) ([]connInfoParam, error) {
+
+	if strings.HasPrefix(connInfo, "postgres://") || strings.HasPrefix(connInfo, "postgresql://") {
+		return nil, errors.New("a connection string in the URI format is not supported")
+	}
+
+	var params []connInfoParam
+	chars := []rune(connInfo)
+	i := 0
+
+	skipSpaces := func() {
+		for i < len(chars) && isConnInfoSpace(chars[i]) {
+			i++
+		}
+	}
+
+	for {
+		skipSpaces()
+		if i >= len(chars) {
+			return params, nil
+		}
+
+		keyStart := i
+		for i < len(chars) && chars[i] != '=' && !isConnInfoSpace(chars[i]) {
+			i++
+		}
+		key := string(chars[keyStart:i])
+
+		skipSpaces()
+		if i >= len(chars) || chars[i] != '=' || key == "" {
+			return nil, errors.New("missing '=' after the key '" + key + "' in the connection string")
+		}
+		i++
+		skipSpaces()
+
+		var value strings.Builder
+		if i < len(chars) && chars[i] == '\'' {
+			i++
+			for {
+				if i >= len(chars) {
+					return nil, errors.New("unterminated quoted value in the connection string")
+				}
+				if chars[i] == '\\' && i+1 < len(chars) {
+					i++
+				} else if chars[i] == '\'' {
+					i++
+					break
+				}
+				value.WriteRune(chars[i])
+				i++
+			}
+		} else {
+			for i < len(chars) && !isConnInfoSpace(chars[i]) {
+				if chars[i] == '\\' && i+1 < len(chars) {
+					i++
+				}
+				value.WriteRune(chars[i])
+				i++
+			}
+		}
+
+		params = append(params, connInfoParam{key: key, value: value.String()})
+	}
+}
+
+func quoteConnInfoValue(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `'`, `\'`)
+	return "'" + value + "'"
+}
+
+func isConnInfoSpace(char rune) bool {
+	return char == ' ' || char == '\t' || char == '\n' || char == '\r'
+}
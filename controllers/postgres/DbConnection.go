@@ -0,0 +1,52 @@
+/*
+Copyright 2021 Reactive Tech Limited.
+"Reactive Tech Limited" is a company located in England, United Kingdom.
+https://www.reactive-tech.io
+
+Lead Developer: Alex Arica
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+)
+
+// DbConnection is an open SQL connection to a PostgreSql instance. Its underlying connections are pooled by DbConnector.
+type DbConnection struct {
+	PodName string
+	db      *sql.DB
+	ctx     context.Context
+}
+
+func (r *DbConnection) QueryRow(query string, args ...interface{}) *sql.Row {
+	return r.db.QueryRowContext(r.ctx, query, args...)
+}
+
+func (r *DbConnection) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return r.db.QueryContext(r.ctx, query, args...)
+}
+
+func (r *DbConnection) Exec(query string, args ...interface{}) error {
+	_, err := r.db.ExecContext(r.ctx, query, args...)
+	return err
+}
+
+// Close intentionally does not close the connection, which stays pooled by DbConnector so that it is reused by the
+// next reconciliation. Its idle connections are closed after 'connectionMaxIdleTimeInSecond', and it is closed once
+// its Pod or its Kubegres resource is deleted. It is kept so that the callers release a connection once done with it.
+func (r *DbConnection) Close() {
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2021 Reactive Tech Limited.
+"Reactive Tech Limited" is a company located in England, United Kingdom.
+https://www.reactive-tech.io
+
+Lead Developer: Alex Arica
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"database/sql"
+	"slices"
+	"sync"
+)
+
+// dbConnectionsPool keeps the SQL connections opened to each PostgreSql instance, so that they are reused across
+// reconciliations instead of being opened every time the replication states are refreshed. A connection is
+// replaced once the IP address of its Pod, the user's password or the SSL mode changes.
+//
+// The connections are grouped by Kubegres resource, so that the connections to the Pods which do not exist anymore
+// are closed when the states of that resource are loaded. Each pooled entry has its own lock, so that opening
+// a connection to an instance which is slow to answer does not block the connections to the other instances.
+type dbConnectionsPool struct {
+	mutex sync.Mutex
+	dbs   map[string]map[string]*pooledDb
+}
+
+type pooledDb struct {
+	mutex         sync.Mutex
+	podName       string
+	connectionUrl string
+	db            *sql.DB
+}
+
+var connectionsPool = &dbConnectionsPool{dbs: make(map[string]map[string]*pooledDb)}
+
+// get returns the pooled connections for the given key if they were opened with the same connection URL and if they
+// are still alive. Otherwise, it closes them and it opens new ones with the given function.
+func (r *dbConnectionsPool) get(kubegresKey, podName, key, connectionUrl string,
+	ping func(db *sql.DB) error,
+	open func() (*sql.DB, error)) (*sql.DB, error) {
+
+	pooled := r.getOrAddEntry(kubegresKey, podName, key)
+
+	pooled.mutex.Lock()
+	defer pooled.mutex.Unlock()
+
+	if pooled.db != nil {
+		if pooled.connectionUrl == connectionUrl && ping(pooled.db) == nil {
+			return pooled.db, nil
+		}
+		_ = pooled.db.Close()
+		pooled.db = nil
+	}
+
+	db, err := open()
+	if err != nil {
+		return nil, err
+	}
+
+	pooled.connectionUrl = connectionUrl
+	pooled.db = db
+	return db, nil
+}
+
+func (r *dbConnectionsPool) getOrAddEntry(kubegresKey, podName, key string) *pooledDb {
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	kubegresDbs, exists := r.dbs[kubegresKey]
+	if !exists {
+		kubegresDbs = make(map[string]*pooledDb)
+		r.dbs[kubegresKey] = kubegresDbs
+	}
+
+	pooled, exists := kubegresDbs[key]
+	if !exists {
+		pooled = &pooledDb{podName: podName}
+		kubegresDbs[key] = pooled
+	}
+	return pooled
+}
+
+// evict closes the pooled connections of the given Kubegres resource to the Pods which are not in the given names.
+// An entry is removed from the pool before it is closed, so that the next calls to 'get' open a new entry.
+func (r *dbConnectionsPool) evict(kubegresKey string, podNamesToKeep []string) {
+
+	var evictedDbs []*pooledDb
+
+	r.mutex.Lock()
+	for key, pooled := range r.dbs[kubegresKey] {
+		if !slices.Contains(podNamesToKeep, pooled.podName) {
+			evictedDbs = append(evictedDbs, pooled)
+			delete(r.dbs[kubegresKey], key)
+		}
+	}
+	if len(r.dbs[kubegresKey]) == 0 {
+		delete(r.dbs, kubegresKey)
+	}
+	r.mutex.Unlock()
+
+	for _, pooled := range evictedDbs {
+		pooled.mutex.Lock()
+		if pooled.db != nil {
+			_ = pooled.db.Close()
+			pooled.db = nil
+		}
+		pooled.mutex.Unlock()
+	}
+}
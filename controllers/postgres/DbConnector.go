@@ -0,0 +1,243 @@
+/*
+Copyright 2021 Reactive Tech Limited.
+"Reactive Tech Limited" is a company located in England, United Kingdom.
+https://www.reactive-tech.io
+
+Lead Developer: Alex Arica
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/lib/pq"
+	core "k8s.io/api/core/v1"
+	postgresV1 "reactive-tech.io/kubegres/api/v1"
+	"reactive-tech.io/kubegres/controllers/ctx"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DbConnector opens SQL connections to the PostgreSql instances managed by a Kubegres resource.
+// It connects to the IP of a Pod, with the passwords defined in the env-vars of the Kubegres resource.
+// Once Kubegres stored the passwords applied to PostgreSql in a Secret, the passwords of that Secret are used instead,
+// so that Kubegres can still connect while the passwords are being rotated.
+type DbConnector struct {
+	kubegresContext ctx.KubegresContext
+}
+
+const (
+	DefaultSuperUserName      = "postgres"
+	ReplicationUserName       = "replication"
+	databaseName              = "postgres"
+	connectionTimeoutInSecond = 2
+	// The idle connections to an instance are closed after that time, so that an instance does not keep
+	// the connections of Kubegres open between two reconciliations
+	connectionMaxIdleTimeInSecond = 90
+	maxIdleConnectionsPerInstance = 1
+	envVarNameOfSuperUserName     = "POSTGRES_USER"
+)
+
+func CreateDbConnector(kubegresContext ctx.KubegresContext) DbConnector {
+	return DbConnector{kubegresContext: kubegresContext}
+}
+
+func (r *DbConnector) ConnectAsSuperUser(pod core.Pod) (*DbConnection, error) {
+
+	password, err := r.getAppliedPassword(ctx.EnvVarNameOfPostgresSuperUserPsw, ctx.PasswordsSecretKeySuperUser)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.Connect(pod, r.GetSuperUserName(), password)
+}
+
+func (r *DbConnector) ConnectAsReplicationUser(pod core.Pod) (*DbConnection, error) {
+
+	password, err := r.getAppliedPassword(ctx.EnvVarNameOfPostgresReplicationUserPsw, ctx.PasswordsSecretKeyReplicationUser)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.Connect(pod, ReplicationUserName, password)
+}
+
+// Connect returns a connection to the PostgreSql instance of the given Pod. The connections are pooled per Pod and user,
+// so that they are reused across reconciliations.
+func (r *DbConnector) Connect(pod core.Pod, userName, password string) (*DbConnection, error) {
+
+	if pod.Status.PodIP == "" {
+		return nil, errors.New("the Pod '" + pod.Name + "' does not have an IP address")
+	}
+
+	sslMode := r.kubegresContext.GetDatabaseSslMode()
+	poolKey := pod.Name + "/" + userName
+	poolConnectionUrl := r.createConnectionUrl(pod.Status.PodIP, userName, password, sslMode)
+
+	db, err := connectionsPool.get(r.getKubegresKey(), pod.Name, poolKey, poolConnectionUrl, r.ping, func() (*sql.DB, error) {
+		return r.open(pod.Status.PodIP, userName, password, sslMode)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &DbConnection{db: db, ctx: r.kubegresContext.Ctx, PodName: pod.Name}, nil
+}
+
+// ClosePooledConnectionsOfDeletedPods closes the pooled connections of the Kubegres resource to the Pods which are
+// not in the given names, as they were deleted.
+func (r *DbConnector) ClosePooledConnectionsOfDeletedPods(podNames []string) {
+	connectionsPool.evict(r.getKubegresKey(), podNames)
+}
+
+// ClosePooledConnectionsOfKubegres closes all the pooled connections of a Kubegres resource which was deleted.
+func ClosePooledConnectionsOfKubegres(namespace, kubegresName string) {
+	connectionsPool.evict(namespace+"/"+kubegresName, nil)
+}
+
+func (r *DbConnector) getKubegresKey() string {
+	return r.kubegresContext.Kubegres.Namespace + "/" + r.kubegresContext.Kubegres.Name
+}
+
+// open opens the connections to the given host. As the PostgreSql driver does not support the SSL mode 'prefer',
+// it connects with the mode 'require' and, if the instance does not support SSL, with the mode 'disable'.
+func (r *DbConnector) open(host, userName, password, sslMode string) (*sql.DB, error) {
+
+	if sslMode != postgresV1.DatabaseSslModePrefer {
+		return r.openAndPing(r.createConnectionUrl(host, userName, password, sslMode))
+	}
+
+	db, err := r.openAndPing(r.createConnectionUrl(host, userName, password, postgresV1.DatabaseSslModeRequire))
+	if errors.Is(err, pq.ErrSSLNotSupported) {
+		return r.openAndPing(r.createConnectionUrl(host, userName, password, postgresV1.DatabaseSslModeDisable))
+	}
+
+	return db, err
+}
+
+func (r *DbConnector) openAndPing(connectionUrl string) (*sql.DB, error) {
+
+	db, err := sql.Open("postgres", connectionUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	db.SetMaxIdleConns(maxIdleConnectionsPerInstance)
+	db.SetConnMaxIdleTime(connectionMaxIdleTimeInSecond * time.Second)
+
+	if err = r.ping(db); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// ping checks that the connections are alive. It times out, so that a pooled connection to an unreachable node
+// does not block the reconciliation until TCP gives up.
+func (r *DbConnector) ping(db *sql.DB) error {
+	pingCtx, cancel := context.WithTimeout(r.kubegresContext.Ctx, connectionTimeoutInSecond*time.Second)
+	defer cancel()
+	return db.PingContext(pingCtx)
+}
+
+func (r *DbConnector) GetSuperUserName() string {
+	for _, envVar := range r.kubegresContext.Kubegres.Spec.Env {
+		if envVar.Name == envVarNameOfSuperUserName && envVar.Value != "" {
+			return envVar.Value
+		}
+	}
+	return DefaultSuperUserName
+}
+
+// GetEnvVarValue returns the value of the given env-var of the Kubegres resource. If the env-var references
+// a key in a Secret, the value is read from that Secret.
+func (r *DbConnector) GetEnvVarValue(envVarName string) (string, error) {
+
+	for _, envVar := range r.kubegresContext.Kubegres.Spec.Env {
+
+		if envVar.Name != envVarName {
+			continue
+		}
+
+		if envVar.ValueFrom == nil || envVar.ValueFrom.SecretKeyRef == nil {
+			return envVar.Value, nil
+		}
+
+		return r.getSecretValue(envVar.ValueFrom.SecretKeyRef)
+	}
+
+	return "", errors.New("the env-var '" + envVarName + "' is not defined in the Kubegres resource")
+}
+
+// getAppliedPassword returns the password stored in the Secret of the applied passwords.
+// If that Secret or its key does not exist, the password is read from the given env-var.
+func (r *DbConnector) getAppliedPassword(envVarName, appliedPasswordsSecretKey string) (string, error) {
+
+	appliedPasswordsSecret := &core.SecretKeySelector{
+		LocalObjectReference: core.LocalObjectReference{Name: r.kubegresContext.GetAppliedPasswordsSecretName()},
+		Key:                  appliedPasswordsSecretKey,
+	}
+
+	if password, err := r.getSecretValue(appliedPasswordsSecret); err == nil {
+		return password, nil
+	}
+
+	return r.GetEnvVarValue(envVarName)
+}
+
+func (r *DbConnector) getSecretValue(secretKeySelector *core.SecretKeySelector) (string, error) {
+
+	secret := &core.Secret{}
+	secretKey := client.ObjectKey{Namespace: r.kubegresContext.Kubegres.Namespace, Name: secretKeySelector.Name}
+	if err := r.kubegresContext.Client.Get(r.kubegresContext.Ctx, secretKey, secret); err != nil {
+		return "", err
+	}
+
+	value, ok := secret.Data[secretKeySelector.Key]
+	if !ok {
+		return "", errors.New("the key '" + secretKeySelector.Key + "' does not exist in the Secret '" + secretKeySelector.Name + "'")
+	}
+
+	return string(value), nil
+}
+
+func (r *DbConnector) createConnectionUrl(host, userName, password, sslMode string) string {
+
+	port := strconv.Itoa(int(r.kubegresContext.Kubegres.Spec.Port))
+	if r.kubegresContext.Kubegres.Spec.Port == 0 {
+		port = strconv.Itoa(ctx.DefaultContainerPortNumber)
+	}
+
+	query := url.Values{}
+	query.Set("sslmode", sslMode)
+	query.Set("connect_timeout", strconv.Itoa(connectionTimeoutInSecond))
+
+	connectionUrl := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(userName, password),
+		Host:     net.JoinHostPort(host, port),
+		Path:     databaseName,
+		RawQuery: query.Encode(),
+	}
+
+	return connectionUrl.String()
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2021 Reactive Tech Limited.
+"Reactive Tech Limited" is a company located in England, United Kingdom.
+https://www.reactive-tech.io
+
+Lead Developer: Alex Arica
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// Lsn is a PostgreSql WAL "Log Sequence Number". Its text representation is "XXX/YYY" where XXX and YYY are
+// respectively the high and low 32 bits in hexadecimal.
+type Lsn uint64
+
+func ParseLsn(value string) (Lsn, error) {
+
+	parts := strings.Split(value, "/")
+	if len(parts) != 2 {
+		return 0, errors.New("the value '" + value + "' is not a valid LSN")
+	}
+
+	high, err := strconv.ParseUint(parts[0], 16, 32)
+	if err != nil {
+		return 0, errors.New("the value '" + value + "' is not a valid LSN")
+	}
+
+	low, err := strconv.ParseUint(parts[1], 16, 32)
+	if err != nil {
+		return 0, errors.New("the value '" + value + "' is not a valid LSN")
+	}
+
+	return Lsn(high<<32 | low), nil
+}
+
+func (r Lsn) String() string {
+	high := strconv.FormatUint(uint64(r)>>32, 16)
+	low := strconv.FormatUint(uint64(r)&0xFFFFFFFF, 16)
+	return strings.ToUpper(high) + "/" + strings.ToUpper(low)
+}
+
+// BytesBehind returns the number of bytes between this LSN and the given one which is ahead.
+// It returns 0 if this LSN is not behind.
+func (r Lsn) BytesBehind(aheadLsn Lsn) int64 {
+	if aheadLsn <= r {
+		return 0
+	}
+	return int64(aheadLsn - r)
+}
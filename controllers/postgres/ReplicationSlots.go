@@ -0,0 +1,39 @@
+/*
+Copyright 2021 Reactive Tech Limited.
+"Reactive Tech Limited" is a company located in England, United Kingdom.
+https://www.reactive-tech.io
+
+Lead Developer: Alex Arica
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+// CreatePhysicalReplicationSlot creates a physical replication slot which reserves the WAL immediately,
+// unless it already exists.
+func CreatePhysicalReplicationSlot(connection *DbConnection, slotName string) error {
+	return connection.Exec(`SELECT pg_create_physical_replication_slot($1, true)
+		WHERE NOT EXISTS (SELECT 1 FROM pg_replication_slots WHERE slot_name = $1)`, slotName)
+}
+
+// DropInactiveReplicationSlot drops a replication slot unless a Replica is streaming with it. It returns whether
+// the slot was dropped.
+func DropInactiveReplicationSlot(connection *DbConnection, slotName string) (bool, error) {
+
+	var nbreDroppedSlots int
+	err := connection.QueryRow(`SELECT count(*) FROM (SELECT pg_drop_replication_slot(slot_name)
+		FROM pg_replication_slots WHERE slot_name = $1 AND NOT active) AS dropped_slots`, slotName).Scan(&nbreDroppedSlots)
+
+	return nbreDroppedSlots > 0, err
+}
@@ -23,9 +23,14 @@ package checker
 import (
 	"errors"
 	"reflect"
+	"strconv"
+	"time"
 
 	apps "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	postgresV1 "reactive-tech.io/kubegres/api/v1"
 	"reactive-tech.io/kubegres/controllers/ctx"
 	"reactive-tech.io/kubegres/controllers/states"
@@ -50,178 +55,346 @@ func (r *SpecChecker) CheckSpec() (SpecCheckResult, error) {
 
 	specCheckResult := SpecCheckResult{}
 
-	spec := &r.kubegresContext.Kubegres.Spec
-	primaryStatefulSet := r.getPrimaryStatefulSet()
-	primaryStatefulSetSpec := primaryStatefulSet.StatefulSet.Spec
-	const emptyStr = ""
-
-	if primaryStatefulSet.Pod.IsReady {
+	for _, specErr := range r.ValidateSpec() {
+		specCheckResult.HasSpecFatalError = true
+		specCheckResult.FatalErrorMessage = r.logSpecErrMsg(specErr.Detail)
+	}
 
-		primaryVolumeMount := primaryStatefulSetSpec.Template.Spec.Containers[0].VolumeMounts[0].MountPath
-		if spec.Database.VolumeMount != primaryVolumeMount {
+	return specCheckResult, nil
+}
 
-			specCheckResult.HasSpecFatalError = true
-			specCheckResult.FatalErrorMessage = r.createErrMsgSpecCannotBeChanged("spec.database.volumeMount",
-				primaryVolumeMount, spec.Database.VolumeMount,
-				"Otherwise, the cluster of PostgreSql servers risk of being inconsistent.")
+// ValidateSpec returns the list of errors found in the Kubegres spec during a reconciliation.
+// The fields which cannot be changed are compared with the ones of the deployed Primary StatefulSet.
+func (r *SpecChecker) ValidateSpec() field.ErrorList {
 
-			r.kubegresContext.Kubegres.Spec.Database.VolumeMount = primaryVolumeMount
-			r.updateKubegresSpec("spec.database.volumeMount", primaryVolumeMount)
+	if r.getPrimaryStatefulSet().Pod.IsReady {
+		if specErrs := r.validateImmutableSpec(field.NewPath("spec")); len(specErrs) > 0 {
+			return specErrs
 		}
+	}
 
-		primaryStorageClassName := primaryStatefulSetSpec.VolumeClaimTemplates[0].Spec.StorageClassName
-		if *spec.Database.StorageClassName != *primaryStorageClassName {
+	specErrs := r.ValidateDeployedResources(nil)
+	return append(specErrs, r.ValidateSpecValues()...)
+}
 
-			specCheckResult.HasSpecFatalError = true
-			specCheckResult.FatalErrorMessage = r.createErrMsgSpecCannotBeChanged("spec.database.storageClassName",
-				*primaryStorageClassName,
-				*spec.Database.StorageClassName,
-				"Otherwise, the cluster of PostgreSql servers risk of being inconsistent.")
+// ValidateSpecUpdate returns the list of errors found when comparing the fields of the Kubegres spec which cannot
+// be changed with their values in the given previous spec. It is called by the validating admission webhook.
+func (r *SpecChecker) ValidateSpecUpdate(oldSpec *postgresV1.KubegresSpec) field.ErrorList {
+
+	var specErrs field.ErrorList
+
+	spec := &r.kubegresContext.Kubegres.Spec
+	specPath := field.NewPath("spec")
 
-			r.kubegresContext.Kubegres.Spec.Database.StorageClassName = primaryStorageClassName
-			r.updateKubegresSpec("spec.database.storageClassName", *primaryStorageClassName)
+	if oldSpec.Database.VolumeMount != "" && spec.Database.VolumeMount != oldSpec.Database.VolumeMount {
+		specErrs = append(specErrs, field.Forbidden(specPath.Child("database", "volumeMount"),
+			r.createErrMsgSpecCannotBeChanged("spec.database.volumeMount",
+				oldSpec.Database.VolumeMount, spec.Database.VolumeMount,
+				"Otherwise, the cluster of PostgreSql servers risk of being inconsistent.")))
+	}
+
+	if oldSpec.Database.StorageClassName != nil && *oldSpec.Database.StorageClassName != "" &&
+		(spec.Database.StorageClassName == nil || *spec.Database.StorageClassName != *oldSpec.Database.StorageClassName) {
+		newStorageClassName := ""
+		if spec.Database.StorageClassName != nil {
+			newStorageClassName = *spec.Database.StorageClassName
 		}
+		specErrs = append(specErrs, field.Forbidden(specPath.Child("database", "storageClassName"),
+			r.createErrMsgSpecCannotBeChanged("spec.database.storageClassName",
+				*oldSpec.Database.StorageClassName,
+				newStorageClassName,
+				"Otherwise, the cluster of PostgreSql servers risk of being inconsistent.")))
+	}
 
-		primaryStorageSizeQuantity := primaryStatefulSetSpec.VolumeClaimTemplates[0].Spec.Resources.Requests[v1.ResourceStorage]
-		primaryStorageSize := primaryStorageSizeQuantity.String()
-		if spec.Database.Size != primaryStorageSize && !r.doesStorageClassAllowVolumeExpansion() {
+	if oldSpec.Database.Size != "" && spec.Database.Size != oldSpec.Database.Size && !r.doesStorageClassAllowVolumeExpansion() {
+		specErrs = append(specErrs, field.Forbidden(specPath.Child("database", "size"),
+			r.createErrMsgSpecCannotBeChanged("spec.database.size",
+				oldSpec.Database.Size,
+				spec.Database.Size,
+				"The StorageClass does not allow volume expansion. The option AllowVolumeExpansion is set to false.")))
 
-			specCheckResult.HasSpecFatalError = true
-			specCheckResult.FatalErrorMessage = r.createErrMsgSpecCannotBeChanged("spec.database.size",
-				primaryStorageSize,
+		// TODO: condition to remove when Kubernetes allows updating storage size in StatefulSet (see https://github.com/kubernetes/enhancements/pull/2842)
+	} else if oldSpec.Database.Size != "" && spec.Database.Size != oldSpec.Database.Size {
+		specErrs = append(specErrs, field.Forbidden(specPath.Child("database", "size"),
+			r.createErrMsgSpecCannotBeChanged("spec.database.size",
+				oldSpec.Database.Size,
 				spec.Database.Size,
-				"The StorageClass does not allow volume expansion. The option AllowVolumeExpansion is set to false.")
+				"The database size cannot be modified after the creation of the Postgres cluster.")))
+	}
 
-			spec.Database.Size = primaryStorageSize
-			r.updateKubegresSpec("spec.database.size", primaryStorageSize)
+	if r.haveCustomVolumeClaimTemplatesChanged(oldSpec.Volume.VolumeClaimTemplates) {
+		specErrs = append(specErrs, field.Forbidden(specPath.Child("volume", "volumeClaimTemplates"),
+			"In the Resources Spec, the array 'spec.Volume.VolumeClaimTemplates' "+
+				"has changed. Kubernetes does not allow to update that field in StatefulSet specification. Please rollback your changes in the YAML."))
+	}
 
-			// TODO: condition to remove when Kubernetes allows updating storage size in StatefulSet (see https://github.com/kubernetes/enhancements/pull/2842)
-		} else if spec.Database.Size != primaryStorageSize {
-			specCheckResult.HasSpecFatalError = true
-			specCheckResult.FatalErrorMessage = r.createErrMsgSpecCannotBeChanged("spec.database.size",
-				primaryStorageSize,
-				spec.Database.Size,
-				"The database size cannot be modified after the creation of the Postgres cluster. "+
-					"We are rolling back that value to its previous value.")
+	return specErrs
+}
 
-			spec.Database.Size = primaryStorageSize
-			r.updateKubegresSpec("spec.database.size", primaryStorageSize)
-		}
+// ValidateDeployedResources checks that the resources referenced by the Kubegres spec are deployed in the cluster.
+// When a previous spec is given, only the references which changed are checked.
+func (r *SpecChecker) ValidateDeployedResources(oldSpec *postgresV1.KubegresSpec) field.ErrorList {
 
-		if r.hasCustomVolumeClaimTemplatesChanged(primaryStatefulSetSpec) {
-			specCheckResult.HasSpecFatalError = true
-			specCheckResult.FatalErrorMessage = r.logSpecErrMsg("In the Resources Spec, the array 'spec.Volume.VolumeClaimTemplates' " +
-				"has changed. Kubernetes does not allow to update that field in StatefulSet specification. Please rollback your changes in the YAML.")
-		}
+	var specErrs field.ErrorList
 
-		if specCheckResult.HasSpecFatalError {
-			return specCheckResult, nil
-		}
+	spec := &r.kubegresContext.Kubegres.Spec
+	specPath := field.NewPath("spec")
+
+	if spec.Database.StorageClassName != nil && !r.dbStorageClassDeployed() &&
+		(oldSpec == nil || !reflect.DeepEqual(oldSpec.Database.StorageClassName, spec.Database.StorageClassName)) {
+		specErrs = append(specErrs, field.Invalid(specPath.Child("database", "storageClassName"), *spec.Database.StorageClassName,
+			"In the Resources Spec the value of "+
+				"'spec.database.storageClassName' has a StorageClass name which is not deployed. Please deploy this StorageClass, "+
+				"otherwise this operator cannot work correctly."))
 	}
 
-	if !r.dbStorageClassDeployed() {
-		specCheckResult.HasSpecFatalError = true
-		specCheckResult.FatalErrorMessage = r.logSpecErrMsg("In the Resources Spec the value of " +
-			"'spec.database.storageClassName' has a StorageClass name which is not deployed. Please deploy this StorageClass, " +
-			"otherwise this operator cannot work correctly.")
+	if r.isCustomConfigNotDeployed(spec) && (oldSpec == nil || oldSpec.CustomConfig != spec.CustomConfig) {
+		specErrs = append(specErrs, field.Invalid(specPath.Child("customConfig"), spec.CustomConfig,
+			"In the Resources Spec the value of "+
+				"'spec.customConfig' has a configMap name which is not deployed. Please deploy this configMap otherwise this "+
+				"operator cannot work correctly."))
 	}
 
-	if spec.Database.Size == emptyStr {
-		specCheckResult.HasSpecFatalError = true
-		specCheckResult.FatalErrorMessage = r.createErrMsgSpecUndefined("spec.database.size")
+	if r.isBackUpConfigured(spec) && spec.Backup.PvcName != "" && !r.isBackUpPvcDeployed() &&
+		(oldSpec == nil || oldSpec.Backup.PvcName != spec.Backup.PvcName) {
+		specErrs = append(specErrs, field.Invalid(specPath.Child("backup", "pvcName"), spec.Backup.PvcName,
+			"In the Resources Spec the value of "+
+				"'spec.Backup.PvcName' has a PersistentVolumeClaim name which is not deployed. Please deploy this "+
+				"PersistentVolumeClaim, otherwise this operator cannot work correctly."))
 	}
 
-	if r.isCustomConfigNotDeployed(spec) {
-		specCheckResult.HasSpecFatalError = true
-		specCheckResult.FatalErrorMessage = r.logSpecErrMsg("In the Resources Spec the value of " +
-			"'spec.customConfig' has a configMap name which is not deployed. Please deploy this configMap otherwise this " +
-			"operator cannot work correctly.")
+	return specErrs
+}
+
+// ValidateSpecValues returns the list of errors found in the values of the Kubegres spec.
+// It only depends on the spec, so that it gives the same result at admission time and during a reconciliation.
+func (r *SpecChecker) ValidateSpecValues() field.ErrorList {
+
+	var specErrs field.ErrorList
+
+	spec := &r.kubegresContext.Kubegres.Spec
+	specPath := field.NewPath("spec")
+	const emptyStr = ""
+
+	if spec.Database.Size == emptyStr {
+		specErrs = append(specErrs, field.Required(specPath.Child("database", "size"), r.createErrMsgSpecUndefined("spec.database.size")))
 	}
 
 	if !r.doesEnvVarExist(ctx.EnvVarNameOfPostgresSuperUserPsw) {
-		specCheckResult.HasSpecFatalError = true
-		specCheckResult.FatalErrorMessage = r.createErrMsgSpecUndefined("spec.env.POSTGRES_PASSWORD")
+		specErrs = append(specErrs, field.Required(specPath.Child("env").Key(ctx.EnvVarNameOfPostgresSuperUserPsw),
+			r.createErrMsgSpecUndefined("spec.env.POSTGRES_PASSWORD")))
 	}
 
 	if !r.doesEnvVarExist(ctx.EnvVarNameOfPostgresReplicationUserPsw) {
-		specCheckResult.HasSpecFatalError = true
-		specCheckResult.FatalErrorMessage = r.createErrMsgSpecUndefined("spec.env.POSTGRES_REPLICATION_PASSWORD")
+		specErrs = append(specErrs, field.Required(specPath.Child("env").Key(ctx.EnvVarNameOfPostgresReplicationUserPsw),
+			r.createErrMsgSpecUndefined("spec.env.POSTGRES_REPLICATION_PASSWORD")))
 	}
 
-	if *spec.Replicas <= 0 {
-		specCheckResult.HasSpecFatalError = true
-		specCheckResult.FatalErrorMessage = r.createErrMsgSpecUndefined("spec.replicas")
+	if spec.Replicas == nil || *spec.Replicas <= 0 {
+		specErrs = append(specErrs, field.Required(specPath.Child("replicas"), r.createErrMsgSpecUndefined("spec.replicas")))
 	}
 
 	if spec.Image == "" {
-		specCheckResult.HasSpecFatalError = true
-		specCheckResult.FatalErrorMessage = r.createErrMsgSpecUndefined("spec.image")
+		specErrs = append(specErrs, field.Required(specPath.Child("image"), r.createErrMsgSpecUndefined("spec.image")))
 	}
 
 	if spec.Standby.Enabled && spec.Standby.PrimaryEndpoint == emptyStr {
-		specCheckResult.HasSpecFatalError = true
-		specCheckResult.FatalErrorMessage = r.createErrMsgSpecUndefined("spec.standby.primaryEndpoint")
+		specErrs = append(specErrs, field.Required(specPath.Child("standby", "primaryEndpoint"),
+			r.createErrMsgSpecUndefined("spec.standby.primaryEndpoint")))
+	}
+
+	if r.kubegresContext.GetFailoverStabilizationSeconds() >= r.kubegresContext.GetFailoverPromotionTimeoutSeconds() {
+		specErrs = append(specErrs, field.Invalid(specPath.Child("failover", "stabilizationSeconds"),
+			r.kubegresContext.GetFailoverStabilizationSeconds(),
+			"In the Resources Spec the value of 'spec.failover.stabilizationSeconds' must be lower than the value of "+
+				"'spec.failover.promotionTimeoutSeconds'. Otherwise, a failover would always time-out."))
+	}
+
+	if spec.Replication.Cascade.Enabled && spec.Replication.Mode == postgresV1.ReplicationModeSynchronous &&
+		r.kubegresContext.GetNumberOfSyncStandbys() > r.kubegresContext.GetNumberOfDirectReplicas() {
+		specErrs = append(specErrs, field.Invalid(specPath.Child("replication", "cascade", "numberOfDirectReplicas"),
+			r.kubegresContext.GetNumberOfDirectReplicas(),
+			"In the Resources Spec the value of 'spec.replication.cascade.numberOfDirectReplicas' must not be lower "+
+				"than 'spec.replication.numberOfSyncStandbys', as only a Replica streaming from the Primary can be synchronous."))
+	}
+
+	if spec.Replication.Slots.MaxSlotWalKeepSize != emptyStr {
+		maxSlotWalKeepSize, err := resource.ParseQuantity(spec.Replication.Slots.MaxSlotWalKeepSize)
+		if err != nil || maxSlotWalKeepSize.Value() < 1024*1024 {
+			specErrs = append(specErrs, field.Invalid(specPath.Child("replication", "slots", "maxSlotWalKeepSize"),
+				spec.Replication.Slots.MaxSlotWalKeepSize,
+				"In the Resources Spec the value of 'spec.replication.slots.maxSlotWalKeepSize' must be a size of at least 1Mi, e.g. '2Gi'."))
+		}
+	}
+
+	for i, delayedReplica := range spec.Replication.DelayedReplicas {
+		if delay, err := time.ParseDuration(delayedReplica.Delay); err != nil || delay < time.Millisecond {
+			specErrs = append(specErrs, field.Invalid(specPath.Child("replication", "delayedReplicas").Index(i).Child("delay"),
+				delayedReplica.Delay,
+				"In the Resources Spec the value of 'spec.replication.delayedReplicas["+strconv.Itoa(i)+"].delay' must be a "+
+					"positive duration, e.g. '4h' or '30m'."))
+		}
+	}
+
+	if spec.ReplicaService.MaxLag != emptyStr {
+		if maxLag, err := time.ParseDuration(spec.ReplicaService.MaxLag); err != nil || maxLag < time.Second {
+			specErrs = append(specErrs, field.Invalid(specPath.Child("replicaService", "maxLag"),
+				spec.ReplicaService.MaxLag,
+				"In the Resources Spec the value of 'spec.replicaService.maxLag' must be a duration of at least "+
+					"1 second, e.g. '30s' or '5m'."))
+		}
+	}
+
+	if spec.Failover.NeverPromoteLabelSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(spec.Failover.NeverPromoteLabelSelector); err != nil {
+			specErrs = append(specErrs, field.Invalid(specPath.Child("failover", "neverPromoteLabelSelector"),
+				spec.Failover.NeverPromoteLabelSelector,
+				"In the Resources Spec the value of 'spec.failover.neverPromoteLabelSelector' is not a valid label selector: "+
+					err.Error()))
+		}
 	}
 
 	if r.isBackUpConfigured(spec) {
 
+		backupPath := specPath.Child("backup")
+
 		if spec.Backup.VolumeMount == emptyStr {
-			specCheckResult.HasSpecFatalError = true
-			specCheckResult.FatalErrorMessage = r.createErrMsgSpecUndefined("spec.Backup.VolumeMount")
+			specErrs = append(specErrs, field.Required(backupPath.Child("volumeMount"), r.createErrMsgSpecUndefined("spec.Backup.VolumeMount")))
 		}
 
 		if spec.Backup.PvcName == emptyStr {
-			specCheckResult.HasSpecFatalError = true
-			specCheckResult.FatalErrorMessage = r.createErrMsgSpecUndefined("spec.Backup.PvcName")
-		}
-
-		if spec.Backup.PvcName != emptyStr && !r.isBackUpPvcDeployed() {
-			specCheckResult.HasSpecFatalError = true
-			specCheckResult.FatalErrorMessage = r.logSpecErrMsg("In the Resources Spec the value of " +
-				"'spec.Backup.PvcName' has a PersistentVolumeClaim name which is not deployed. Please deploy this " +
-				"PersistentVolumeClaim, otherwise this operator cannot work correctly.")
+			specErrs = append(specErrs, field.Required(backupPath.Child("pvcName"), r.createErrMsgSpecUndefined("spec.Backup.PvcName")))
 		}
 	}
 
+	volumePath := specPath.Child("volume")
+
 	reservedVolumeName := r.doCustomVolumeClaimTemplatesHaveReservedName()
 	if reservedVolumeName != "" {
-		specCheckResult.HasSpecFatalError = true
-		specCheckResult.FatalErrorMessage = r.logSpecErrMsg("In the Resources Spec the value of " +
-			"'spec.Volume.VolumeClaimTemplates' has an entry with a volume name which is a reserved name: " + reservedVolumeName + " . " +
-			"That name cannot be used and it is reserved for Kubegres internal usages. Please change that name in the YAML.")
+		specErrs = append(specErrs, field.Forbidden(volumePath.Child("volumeClaimTemplates"),
+			"In the Resources Spec the value of "+
+				"'spec.Volume.VolumeClaimTemplates' has an entry with a volume name which is a reserved name: "+reservedVolumeName+" . "+
+				"That name cannot be used and it is reserved for Kubegres internal usages. Please change that name in the YAML."))
 	}
 
 	reservedVolumeName = r.doCustomVolumesHaveReservedName()
 	if reservedVolumeName != "" {
-		specCheckResult.HasSpecFatalError = true
-		specCheckResult.FatalErrorMessage = r.logSpecErrMsg("In the Resources Spec the value of " +
-			"'spec.Volume.Volumes' has an entry with a volume name which is a reserved name: " + reservedVolumeName + " . " +
-			"That name cannot be used and it is reserved for Kubegres internal usages. Please change that name in the YAML.")
+		specErrs = append(specErrs, field.Forbidden(volumePath.Child("volumes"),
+			"In the Resources Spec the value of "+
+				"'spec.Volume.Volumes' has an entry with a volume name which is a reserved name: "+reservedVolumeName+" . "+
+				"That name cannot be used and it is reserved for Kubegres internal usages. Please change that name in the YAML."))
 	}
 
 	reservedVolumeName = r.doCustomVolumeMountsHaveReservedName()
 	if reservedVolumeName != "" {
-		specCheckResult.HasSpecFatalError = true
-		specCheckResult.FatalErrorMessage = r.logSpecErrMsg("In the Resources Spec the value of " +
-			"'spec.Volume.VolumeMounts' has an entry with a volume name which is a reserved name: " + reservedVolumeName + " . " +
-			"That name cannot be used and it is reserved for Kubegres internal usages. Please change that name in the YAML.")
+		specErrs = append(specErrs, field.Forbidden(volumePath.Child("volumeMounts"),
+			"In the Resources Spec the value of "+
+				"'spec.Volume.VolumeMounts' has an entry with a volume name which is a reserved name: "+reservedVolumeName+" . "+
+				"That name cannot be used and it is reserved for Kubegres internal usages. Please change that name in the YAML."))
 	}
 
 	if r.doCustomVolumeMountsHaveReservedPath() {
-		specCheckResult.HasSpecFatalError = true
-		specCheckResult.FatalErrorMessage = r.logSpecErrMsg("In the Resources Spec the value of 'spec.Volume.VolumeMounts' " +
-			"has an entry with a 'mountPath' value which is reserved for the Postgres database: " + r.kubegresContext.Kubegres.Spec.Database.VolumeMount + " . " +
-			"That value cannot be used and it is reserved for Kubegres internal usages. Please change that value in the YAML.")
+		specErrs = append(specErrs, field.Forbidden(volumePath.Child("volumeMounts"),
+			"In the Resources Spec the value of 'spec.Volume.VolumeMounts' "+
+				"has an entry with a 'mountPath' value which is reserved for the Postgres database: "+spec.Database.VolumeMount+" . "+
+				"That value cannot be used and it is reserved for Kubegres internal usages. Please change that value in the YAML."))
 	}
 
-	return specCheckResult, nil
+	return specErrs
 }
 
-func (r *SpecChecker) updateKubegresSpec(specName string, specValue string) {
-	err := r.kubegresContext.Client.Update(r.kubegresContext.Ctx, r.kubegresContext.Kubegres)
-	if err != nil {
-		r.kubegresContext.Log.Error(err, "Unable to rollback the value of '"+specName+"' to '"+specValue+"'")
+// ValidatePromotePod checks that 'spec.failover.promotePod' is the name of a deployed Replica Pod.
+// It is only called at admission time since the Kubegres controller resets that field once the Replica is promoted.
+func (r *SpecChecker) ValidatePromotePod() field.ErrorList {
+
+	promotePod := r.kubegresContext.Kubegres.Spec.Failover.PromotePod
+	if promotePod == "" {
+		return nil
+	}
+
+	for _, replica := range r.resourcesStates.StatefulSets.Replicas.All.GetAllSortedByInstanceIndex() {
+		if replica.Pod.IsDeployed && replica.Pod.Pod.Name == promotePod {
+			return nil
+		}
+	}
+
+	return field.ErrorList{field.Invalid(field.NewPath("spec", "failover", "promotePod"), promotePod,
+		"The value of the field 'failover.promotePod' is set to '"+promotePod+"'. "+
+			"That value is either the name of a Primary Pod OR a Pod which does not exist. "+
+			"Please set the name of a Replica Pod that you would like to promote as a Primary Pod.")}
+}
+
+// ValidateSwitchoverPod checks that 'spec.failover.switchoverPod' is the name of a deployed Replica Pod.
+// It is only called at admission time since the Kubegres controller resets that field once the switchover has started.
+func (r *SpecChecker) ValidateSwitchoverPod() field.ErrorList {
+
+	switchoverPod := r.kubegresContext.Kubegres.Spec.Failover.SwitchoverPod
+	if switchoverPod == "" {
+		return nil
+	}
+
+	for _, replica := range r.resourcesStates.StatefulSets.Replicas.All.GetAllSortedByInstanceIndex() {
+		if replica.Pod.IsDeployed && replica.Pod.Pod.Name == switchoverPod {
+			return nil
+		}
+	}
+
+	return field.ErrorList{field.Invalid(field.NewPath("spec", "failover", "switchoverPod"), switchoverPod,
+		"The value of the field 'failover.switchoverPod' is set to '"+switchoverPod+"'. "+
+			"That value is either the name of a Primary Pod OR a Pod which does not exist. "+
+			"Please set the name of a Replica Pod that you would like to promote as a Primary Pod.")}
+}
+
+func (r *SpecChecker) validateImmutableSpec(specPath *field.Path) field.ErrorList {
+
+	var specErrs field.ErrorList
+
+	spec := &r.kubegresContext.Kubegres.Spec
+	primaryStatefulSetSpec := r.getPrimaryStatefulSet().StatefulSet.Spec
+
+	primaryVolumeMount := primaryStatefulSetSpec.Template.Spec.Containers[0].VolumeMounts[0].MountPath
+	if spec.Database.VolumeMount != "" && spec.Database.VolumeMount != primaryVolumeMount {
+		specErrs = append(specErrs, field.Forbidden(specPath.Child("database", "volumeMount"),
+			r.createErrMsgSpecCannotBeChanged("spec.database.volumeMount",
+				primaryVolumeMount, spec.Database.VolumeMount,
+				"Otherwise, the cluster of PostgreSql servers risk of being inconsistent.")))
 	}
+
+	primaryStorageClassName := primaryStatefulSetSpec.VolumeClaimTemplates[0].Spec.StorageClassName
+	if spec.Database.StorageClassName != nil && primaryStorageClassName != nil &&
+		*spec.Database.StorageClassName != *primaryStorageClassName {
+		specErrs = append(specErrs, field.Forbidden(specPath.Child("database", "storageClassName"),
+			r.createErrMsgSpecCannotBeChanged("spec.database.storageClassName",
+				*primaryStorageClassName,
+				*spec.Database.StorageClassName,
+				"Otherwise, the cluster of PostgreSql servers risk of being inconsistent.")))
+	}
+
+	primaryStorageSizeQuantity := primaryStatefulSetSpec.VolumeClaimTemplates[0].Spec.Resources.Requests[v1.ResourceStorage]
+	primaryStorageSize := primaryStorageSizeQuantity.String()
+	if spec.Database.Size != primaryStorageSize && !r.doesStorageClassAllowVolumeExpansion() {
+		specErrs = append(specErrs, field.Forbidden(specPath.Child("database", "size"),
+			r.createErrMsgSpecCannotBeChanged("spec.database.size",
+				primaryStorageSize,
+				spec.Database.Size,
+				"The StorageClass does not allow volume expansion. The option AllowVolumeExpansion is set to false.")))
+
+		// TODO: condition to remove when Kubernetes allows updating storage size in StatefulSet (see https://github.com/kubernetes/enhancements/pull/2842)
+	} else if spec.Database.Size != primaryStorageSize {
+		specErrs = append(specErrs, field.Forbidden(specPath.Child("database", "size"),
+			r.createErrMsgSpecCannotBeChanged("spec.database.size",
+				primaryStorageSize,
+				spec.Database.Size,
+				"The database size cannot be modified after the creation of the Postgres cluster.")))
+	}
+
+	if r.hasCustomVolumeClaimTemplatesChanged(primaryStatefulSetSpec) {
+		specErrs = append(specErrs, field.Forbidden(specPath.Child("volume", "volumeClaimTemplates"),
+			"In the Resources Spec, the array 'spec.Volume.VolumeClaimTemplates' "+
+				"has changed. Kubernetes does not allow to update that field in StatefulSet specification. Please rollback your changes in the YAML."))
+	}
+
+	return specErrs
 }
 
 func (r *SpecChecker) isBackUpConfigured(spec *postgresV1.KubegresSpec) bool {
@@ -243,16 +416,14 @@ func (r *SpecChecker) isCustomConfigNotDeployed(spec *postgresV1.KubegresSpec) b
 }
 
 func (r *SpecChecker) createErrMsgSpecUndefined(specName string) string {
-	errorMsg := "In the Resources Spec the value of '" + specName + "' is undefined. Please set a value otherwise this operator cannot work correctly."
-	return r.logSpecErrMsg(errorMsg)
+	return "In the Resources Spec the value of '" + specName + "' is undefined. Please set a value otherwise this operator cannot work correctly."
 }
 
 func (r *SpecChecker) createErrMsgSpecCannotBeChanged(specName, currentValue, newValue, reason string) string {
-	errorMsg := "In the Resources Spec the value of '" + specName + "' cannot be changed from '" + currentValue + "' to '" + newValue + "' after Pods were created. " +
+	return "In the Resources Spec the value of '" + specName + "' cannot be changed from '" + currentValue + "' to '" + newValue + "' after Pods were created. " +
 		reason + " " +
-		"We roll-backed Kubegres spec to the currently working value '" + currentValue + "'. " +
+		"Please rollback that value to '" + currentValue + "'. " +
 		"If you know what you are doing, you can manually update that spec in every StatefulSet of your PostgreSql cluster and then Kubegres will automatically update itself."
-	return r.logSpecErrMsg(errorMsg)
 }
 
 func (r *SpecChecker) logSpecErrMsg(errorMsg string) string {
@@ -337,6 +508,23 @@ func (r *SpecChecker) hasCustomVolumeClaimTemplatesChanged(primaryStatefulSetSpe
 	return false
 }
 
+func (r *SpecChecker) haveCustomVolumeClaimTemplatesChanged(oldCustomVolumeClaimTemplates []postgresV1.VolumeClaimTemplate) bool {
+
+	if len(oldCustomVolumeClaimTemplates) != len(r.kubegresContext.Kubegres.Spec.Volume.VolumeClaimTemplates) {
+		return true
+	}
+
+	for _, oldCustomVolumeClaimTemplate := range oldCustomVolumeClaimTemplates {
+		if !r.doesCurrentVolumeClaimTemplateExistInExpectedSpec(v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: oldCustomVolumeClaimTemplate.Name},
+			Spec:       oldCustomVolumeClaimTemplate.Spec,
+		}) {
+			return true
+		}
+	}
+	return false
+}
+
 func (r *SpecChecker) doesCurrentVolumeClaimTemplateExistInExpectedSpec(currentCustomVolumeClaimTemplate v1.PersistentVolumeClaim) bool {
 
 	for _, expectedCustomVolumeClaimTemplate := range r.kubegresContext.Kubegres.Spec.Volume.VolumeClaimTemplates {
@@ -0,0 +1,61 @@
+/*
+Copyright 2021 Reactive Tech Limited.
+"Reactive Tech Limited" is a company located in England, United Kingdom.
+https://www.reactive-tech.io
+
+Lead Developer: Alex Arica
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package defaultspec
+
+import (
+	core "k8s.io/api/core/v1"
+	postgresV1 "reactive-tech.io/kubegres/api/v1"
+	"reactive-tech.io/kubegres/controllers/ctx"
+)
+
+// AddGeneratedPasswordsEnvVars adds in memory to 'spec.env' the env-vars referencing the passwords Secret generated
+// by Kubegres, when 'spec.passwords.generate' is true. An env-var which is already set in 'spec.env' is kept as it is.
+// The Kubegres resource is not updated in Kubernetes, so that the user can still decide to set a password later.
+func AddGeneratedPasswordsEnvVars(kubegresContext ctx.KubegresContext) {
+
+	kubegresSpec := &kubegresContext.Kubegres.Spec
+	if !kubegresSpec.Passwords.Generate {
+		return
+	}
+
+	secretName := kubegresContext.GetPasswordsSecretName()
+	addEnvVarIfUndefined(kubegresSpec, ctx.EnvVarNameOfPostgresSuperUserPsw, secretName, ctx.PasswordsSecretKeySuperUser)
+	addEnvVarIfUndefined(kubegresSpec, ctx.EnvVarNameOfPostgresReplicationUserPsw, secretName, ctx.PasswordsSecretKeyReplicationUser)
+}
+
+func addEnvVarIfUndefined(kubegresSpec *postgresV1.KubegresSpec, envVarName, secretName, secretKey string) {
+
+	for _, envVar := range kubegresSpec.Env {
+		if envVar.Name == envVarName {
+			return
+		}
+	}
+
+	kubegresSpec.Env = append(kubegresSpec.Env, core.EnvVar{
+		Name: envVarName,
+		ValueFrom: &core.EnvVarSource{
+			SecretKeyRef: &core.SecretKeySelector{
+				LocalObjectReference: core.LocalObjectReference{Name: secretName},
+				Key:                  secretKey,
+			},
+		},
+	})
+}
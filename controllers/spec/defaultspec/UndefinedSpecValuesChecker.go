@@ -21,8 +21,10 @@ limitations under the License.
 package defaultspec
 
 import (
+	"errors"
 	core "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	postgresV1 "reactive-tech.io/kubegres/api/v1"
 	"reactive-tech.io/kubegres/controllers/ctx"
 	"strconv"
 )
@@ -30,8 +32,12 @@ import (
 type UndefinedSpecValuesChecker struct {
 	kubegresContext     ctx.KubegresContext
 	defaultStorageClass DefaultStorageClass
+	isDryRun            bool
+	wasSpecChanged      bool
 }
 
+// SetDefaultForUndefinedSpecValues is called by the mutating webhook. It sets a default value for each undefined
+// field of the Kubegres spec, which is persisted when the Kubegres resource is admitted.
 func SetDefaultForUndefinedSpecValues(kubegresContext ctx.KubegresContext, defaultStorageClass DefaultStorageClass) error {
 	defaultSpec := UndefinedSpecValuesChecker{
 		kubegresContext:     kubegresContext,
@@ -41,32 +47,86 @@ func SetDefaultForUndefinedSpecValues(kubegresContext ctx.KubegresContext, defau
 	return defaultSpec.apply()
 }
 
+// PersistDefaultForUndefinedSpecValues is called during a reconciliation, which only reads the values stored in
+// the Kubegres resource. If that resource was admitted before the mutating webhook was deployed, it is updated
+// without any change, so that the mutating webhook sets and persists a default value for each undefined field.
+func PersistDefaultForUndefinedSpecValues(kubegresContext ctx.KubegresContext) error {
+
+	if !hasUndefinedSpecValues(kubegresContext.Kubegres) {
+		return nil
+	}
+
+	kubegresContext.Log.Info("Updating Kubegres so that the mutating webhook sets a default value for each undefined field of its spec.")
+	if err := kubegresContext.Client.Update(kubegresContext.Ctx, kubegresContext.Kubegres); err != nil {
+		return err
+	}
+
+	if hasUndefinedSpecValues(kubegresContext.Kubegres) {
+		return errors.New("some fields of the Kubegres spec are undefined and no default value was set for them. " +
+			"Please make sure the mutating webhook of Kubegres is deployed")
+	}
+
+	return nil
+}
+
+func hasUndefinedSpecValues(kubegres *postgresV1.Kubegres) bool {
+	defaultSpec := UndefinedSpecValuesChecker{
+		kubegresContext: ctx.KubegresContext{Kubegres: kubegres.DeepCopy()},
+		isDryRun:        true,
+	}
+
+	_ = defaultSpec.apply()
+	return defaultSpec.wasSpecChanged
+}
+
 func (r *UndefinedSpecValuesChecker) apply() error {
 
-	wasSpecChanged := false
 	kubegresSpec := &r.kubegresContext.Kubegres.Spec
 	const emptyStr = ""
 
 	if kubegresSpec.Port <= 0 {
-		wasSpecChanged = true
 		kubegresSpec.Port = ctx.DefaultContainerPortNumber
 		r.createLog("spec.port", strconv.Itoa(int(kubegresSpec.Port)))
 	}
 
 	if kubegresSpec.Database.VolumeMount == emptyStr {
-		wasSpecChanged = true
 		kubegresSpec.Database.VolumeMount = ctx.DefaultDatabaseVolumeMount
 		r.createLog("spec.database.volumeMount", kubegresSpec.Database.VolumeMount)
 	}
 
 	if kubegresSpec.CustomConfig == emptyStr {
-		wasSpecChanged = true
 		kubegresSpec.CustomConfig = ctx.BaseConfigMapName
 		r.createLog("spec.customConfig", kubegresSpec.CustomConfig)
 	}
 
-	if r.isStorageClassNameUndefinedInSpec() {
-		wasSpecChanged = true
+	if kubegresSpec.Failover.Pvc == emptyStr {
+		kubegresSpec.Failover.Pvc = postgresV1.FailoverPvcPolicyRetain
+		r.createLog("spec.failover.pvc", kubegresSpec.Failover.Pvc)
+	}
+
+	if kubegresSpec.Failover.DetectionMode == emptyStr {
+		kubegresSpec.Failover.DetectionMode = postgresV1.FailoverDetectionModePodReadiness
+		r.createLog("spec.failover.detectionMode", kubegresSpec.Failover.DetectionMode)
+	}
+
+	if kubegresSpec.Replication.Mode == emptyStr {
+		kubegresSpec.Replication.Mode = postgresV1.ReplicationModeAsynchronous
+		r.createLog("spec.replication.mode", kubegresSpec.Replication.Mode)
+	}
+
+	r.setDefaultSeconds(&kubegresSpec.Failover.FencingGracePeriodSeconds, ctx.DefaultFencingGracePeriodSeconds, "spec.failover.fencingGracePeriodSeconds")
+	r.setDefaultSeconds(&kubegresSpec.Failover.DetectionDelaySeconds, ctx.DefaultFailoverDetectionDelaySeconds, "spec.failover.detectionDelaySeconds")
+	r.setDefaultSeconds(&kubegresSpec.Failover.PromotionTimeoutSeconds, ctx.DefaultFailoverPromotionTimeoutSeconds, "spec.failover.promotionTimeoutSeconds")
+	r.setDefaultSeconds(&kubegresSpec.Failover.StabilizationSeconds, ctx.DefaultFailoverStabilizationSeconds, "spec.failover.stabilizationSeconds")
+	r.setDefaultSeconds(&kubegresSpec.Failover.SwitchoverCatchUpTimeoutSeconds, ctx.DefaultSwitchoverCatchUpTimeoutSeconds, "spec.failover.switchoverCatchUpTimeoutSeconds")
+	r.setDefaultSeconds(&kubegresSpec.Timeouts.ReplicaDeployingSeconds, ctx.DefaultReplicaDeployingTimeoutSeconds, "spec.timeouts.replicaDeployingSeconds")
+	r.setDefaultSeconds(&kubegresSpec.Timeouts.ReplicaUndeployingSeconds, ctx.DefaultReplicaUndeployingTimeoutSeconds, "spec.timeouts.replicaUndeployingSeconds")
+	r.setDefaultSeconds(&kubegresSpec.Timeouts.SpecUpdatingSeconds, ctx.DefaultSpecUpdatingTimeoutSeconds, "spec.timeouts.specUpdatingSeconds")
+
+	if r.isStorageClassNameUndefinedInSpec() && r.isDryRun {
+		r.createLog("spec.Database.StorageClassName", "")
+
+	} else if r.isStorageClassNameUndefinedInSpec() {
 		defaultStorageClassName, err := r.defaultStorageClass.GetDefaultStorageClassName()
 		if err != nil {
 			return err
@@ -78,18 +138,25 @@ func (r *UndefinedSpecValuesChecker) apply() error {
 
 	if kubegresSpec.Scheduler.Affinity == nil {
 		kubegresSpec.Scheduler.Affinity = r.createDefaultAffinity()
-		wasSpecChanged = true
 		r.createLog("spec.Affinity", kubegresSpec.Scheduler.Affinity.String())
 	}
 
-	if wasSpecChanged {
-		return r.updateSpec()
-	}
-
 	return nil
 }
 
+func (r *UndefinedSpecValuesChecker) setDefaultSeconds(seconds **int64, defaultSeconds int64, specName string) {
+	if *seconds != nil {
+		return
+	}
+	*seconds = &defaultSeconds
+	r.createLog(specName, strconv.FormatInt(defaultSeconds, 10))
+}
+
 func (r *UndefinedSpecValuesChecker) createLog(specName string, specValue string) {
+	r.wasSpecChanged = true
+	if r.isDryRun {
+		return
+	}
 	r.kubegresContext.Log.InfoEvent("DefaultSpecValue", "A default value was set for a field in Kubegres YAML spec.", specName, "New value: "+specValue+"")
 }
 
@@ -98,11 +165,6 @@ func (r *UndefinedSpecValuesChecker) isStorageClassNameUndefinedInSpec() bool {
 	return storageClassName == nil || *storageClassName == ""
 }
 
-func (r *UndefinedSpecValuesChecker) updateSpec() error {
-	r.kubegresContext.Log.Info("Updating Kubegres Spec", "name", r.kubegresContext.Kubegres.Name)
-	return r.kubegresContext.Client.Update(r.kubegresContext.Ctx, r.kubegresContext.Kubegres)
-}
-
 func (r *UndefinedSpecValuesChecker) createDefaultAffinity() *core.Affinity {
 
 	resourceName := r.kubegresContext.Kubegres.Name
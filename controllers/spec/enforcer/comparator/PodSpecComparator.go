@@ -23,16 +23,19 @@ package comparator
 import (
 	core "k8s.io/api/core/v1"
 	postgresV1 "reactive-tech.io/kubegres/api/v1"
+	"reactive-tech.io/kubegres/controllers/ctx"
 )
 
 type PodSpecComparator struct {
-	Pod          core.Pod
-	PostgresSpec postgresV1.KubegresSpec
+	Pod              core.Pod
+	PostgresSpec     postgresV1.KubegresSpec
+	PasswordsVersion string
 }
 
 func (r *PodSpecComparator) IsSpecUpToDate() bool {
 	return r.isImageUpToDate() &&
-		r.isPortUpToDate()
+		r.isPortUpToDate() &&
+		r.isPasswordsVersionUpToDate()
 }
 
 func (r *PodSpecComparator) isImageUpToDate() bool {
@@ -56,3 +59,13 @@ func (r *PodSpecComparator) isPortUpToDate() bool {
 	expected := r.PostgresSpec.Port
 	return current == expected
 }
+
+func (r *PodSpecComparator) isPasswordsVersionUpToDate() bool {
+
+	if r.PasswordsVersion == "" {
+		return true
+	}
+
+	current := r.Pod.Annotations[ctx.PasswordsVersionAnnotationKey]
+	return current == r.PasswordsVersion
+}
@@ -0,0 +1,302 @@
+/*
+Copyright 2021 Reactive Tech Limited.
+"Reactive Tech Limited" is a company located in England, United Kingdom.
+https://www.reactive-tech.io
+
+Lead Developer: Alex Arica
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package passwords_spec
+
+import (
+	"github.com/lib/pq"
+	core "k8s.io/api/core/v1"
+	"reactive-tech.io/kubegres/controllers/ctx"
+	"reactive-tech.io/kubegres/controllers/operation"
+	"reactive-tech.io/kubegres/controllers/postgres"
+	"reactive-tech.io/kubegres/controllers/spec/template"
+	"reactive-tech.io/kubegres/controllers/states"
+)
+
+// PasswordsRotationSpecEnforcer rotates online the passwords of the superuser and of the replication user when they
+// change in the Secrets referenced by the env-vars of the Kubegres resource.
+//
+// Kubegres stores the passwords set in PostgreSql in the Secret of the applied passwords. When they are different from
+// the ones in the env-vars, it updates 'primary_conninfo' on each Replica, runs 'ALTER ROLE' on the Primary and stores
+// the new passwords. It then sets a new passwords version in the status so that the Pods are restarted one by one
+// by AllStatefulSetsSpecEnforcer. No database is re-cloned.
+type PasswordsRotationSpecEnforcer struct {
+	kubegresContext   ctx.KubegresContext
+	resourcesStates   states.ResourcesStates
+	resourcesCreator  template.ResourcesCreatorFromTemplate
+	blockingOperation *operation.BlockingOperation
+	dbConnector       postgres.DbConnector
+}
+
+type passwords struct {
+	superUser       string
+	replicationUser string
+}
+
+func CreatePasswordsRotationSpecEnforcer(kubegresContext ctx.KubegresContext,
+	resourcesStates states.ResourcesStates,
+	resourcesCreator template.ResourcesCreatorFromTemplate,
+	blockingOperation *operation.BlockingOperation) PasswordsRotationSpecEnforcer {
+
+	return PasswordsRotationSpecEnforcer{
+		kubegresContext:   kubegresContext,
+		resourcesStates:   resourcesStates,
+		resourcesCreator:  resourcesCreator,
+		blockingOperation: blockingOperation,
+		dbConnector:       postgres.CreateDbConnector(kubegresContext),
+	}
+}
+
+func (r *PasswordsRotationSpecEnforcer) EnforceSpec() error {
+
+	if !r.isStandbyEnabled() && !r.isPrimaryDbReady() {
+		return nil
+	}
+
+	if r.blockingOperation.IsThereActiveOperation() {
+		return nil
+	}
+
+	expectedPasswords, err := r.getExpectedPasswords()
+	if err != nil {
+		r.kubegresContext.Log.ErrorEvent("PasswordsRotationErr", err,
+			"Unable to read the passwords referenced by the env-vars of the Kubegres resource.")
+		return err
+	}
+
+	if !r.isAppliedPasswordsSecretDeployed() {
+		return r.deployAppliedPasswordsSecret(expectedPasswords)
+	}
+
+	appliedPasswords := r.getAppliedPasswords()
+	if appliedPasswords == expectedPasswords {
+		return nil
+	}
+
+	if !r.areAllReplicasReady() {
+		r.kubegresContext.Log.Info("The passwords have changed. Waiting for all Replicas to be ready before rotating the passwords.")
+		return nil
+	}
+
+	r.kubegresContext.Log.InfoEvent("PasswordsRotation", "The passwords have changed in the Secrets referenced by the "+
+		"env-vars of the Kubegres resource. Rotating the passwords in PostgreSql.")
+
+	if appliedPasswords.replicationUser != expectedPasswords.replicationUser {
+		for _, replica := range r.resourcesStates.StatefulSets.GetReplicasAndDelayedReplicas() {
+			if err := r.updatePrimaryConnInfo(replica.Pod.Pod, appliedPasswords, expectedPasswords); err != nil {
+				return err
+			}
+		}
+	}
+
+	if !r.isStandbyEnabled() {
+		if err := r.alterRoles(r.resourcesStates.StatefulSets.Primary.Pod.Pod, appliedPasswords, expectedPasswords); err != nil {
+			return err
+		}
+	}
+
+	passwordsVersion, err := r.updateAppliedPasswordsSecret(expectedPasswords)
+	if err != nil {
+		return err
+	}
+
+	r.kubegresContext.Status.SetPasswordsVersion(passwordsVersion)
+	r.kubegresContext.Log.InfoEvent("PasswordsRotation", "Rotated the passwords in PostgreSql. "+
+		"The Pods are going to be restarted one by one in order to use the new passwords.")
+
+	return nil
+}
+
+func (r *PasswordsRotationSpecEnforcer) isPrimaryDbReady() bool {
+	return r.resourcesStates.StatefulSets.Primary.IsReady
+}
+
+func (r *PasswordsRotationSpecEnforcer) isStandbyEnabled() bool {
+	return r.kubegresContext.Kubegres.Spec.Standby.Enabled
+}
+
+func (r *PasswordsRotationSpecEnforcer) areAllReplicasReady() bool {
+	replicas := r.resourcesStates.StatefulSets.Replicas
+	delayedReplicas := r.resourcesStates.StatefulSets.DelayedReplicas
+	return replicas.NbreDeployed == replicas.NbreReady && delayedReplicas.NbreDeployed == delayedReplicas.NbreReady
+}
+
+func (r *PasswordsRotationSpecEnforcer) isAppliedPasswordsSecretDeployed() bool {
+	return r.resourcesStates.Passwords.Applied.IsDeployed
+}
+
+func (r *PasswordsRotationSpecEnforcer) getExpectedPasswords() (passwords, error) {
+
+	superUserPassword, err := r.dbConnector.GetEnvVarValue(ctx.EnvVarNameOfPostgresSuperUserPsw)
+	if err != nil {
+		return passwords{}, err
+	}
+
+	replicationUserPassword, err := r.dbConnector.GetEnvVarValue(ctx.EnvVarNameOfPostgresReplicationUserPsw)
+	if err != nil {
+		return passwords{}, err
+	}
+
+	return passwords{superUser: superUserPassword, replicationUser: replicationUserPassword}, nil
+}
+
+func (r *PasswordsRotationSpecEnforcer) getAppliedPasswords() passwords {
+	appliedPasswordsSecret := r.resourcesStates.Passwords.Applied.Secret
+	return passwords{
+		superUser:       string(appliedPasswordsSecret.Data[ctx.PasswordsSecretKeySuperUser]),
+		replicationUser: string(appliedPasswordsSecret.Data[ctx.PasswordsSecretKeyReplicationUser]),
+	}
+}
+
+// deployAppliedPasswordsSecret is called the first time the Primary is ready, in which case the passwords set
+// in PostgreSql are the ones referenced by the env-vars.
+func (r *PasswordsRotationSpecEnforcer) deployAppliedPasswordsSecret(appliedPasswords passwords) error {
+
+	appliedPasswordsSecret, err := r.resourcesCreator.CreateAppliedPasswordsSecret(appliedPasswords.superUser, appliedPasswords.replicationUser)
+	if err != nil {
+		r.kubegresContext.Log.ErrorEvent("AppliedPasswordsSecretTemplateErr", err,
+			"Unable to create an Applied Passwords Secret object from template.",
+			"Secret name", r.resourcesStates.Passwords.Applied.Name)
+		return err
+	}
+
+	if err := r.kubegresContext.Client.Create(r.kubegresContext.Ctx, &appliedPasswordsSecret); err != nil {
+		r.kubegresContext.Log.ErrorEvent("AppliedPasswordsSecretDeploymentErr", err,
+			"Unable to deploy Applied Passwords Secret.",
+			"Secret name", appliedPasswordsSecret.Name)
+		return err
+	}
+
+	r.kubegresContext.Log.Info("Deployed Applied Passwords Secret.", "Secret name", appliedPasswordsSecret.Name)
+	return nil
+}
+
+func (r *PasswordsRotationSpecEnforcer) updateAppliedPasswordsSecret(newPasswords passwords) (passwordsVersion string, err error) {
+
+	appliedPasswordsSecret := r.resourcesStates.Passwords.Applied.Secret.DeepCopy()
+	appliedPasswordsSecret.Data = map[string][]byte{
+		ctx.PasswordsSecretKeySuperUser:       []byte(newPasswords.superUser),
+		ctx.PasswordsSecretKeyReplicationUser: []byte(newPasswords.replicationUser),
+	}
+
+	if err := r.kubegresContext.Client.Update(r.kubegresContext.Ctx, appliedPasswordsSecret); err != nil {
+		r.kubegresContext.Log.ErrorEvent("PasswordsRotationErr", err,
+			"Unable to store the rotated passwords in the Applied Passwords Secret.",
+			"Secret name", appliedPasswordsSecret.Name)
+		return "", err
+	}
+
+	return appliedPasswordsSecret.ResourceVersion, nil
+}
+
+// updatePrimaryConnInfo sets the new password of the replication user in the setting 'primary_conninfo' of a Replica.
+// The setting is reloaded without restarting PostgreSql.
+func (r *PasswordsRotationSpecEnforcer) updatePrimaryConnInfo(replicaPod core.Pod, appliedPasswords, expectedPasswords passwords) error {
+
+	connection, err := r.connectAsSuperUser(replicaPod, appliedPasswords, expectedPasswords)
+	if err != nil {
+		return err
+	}
+	defer connection.Close()
+
+	var primaryConnInfo string
+	if err := connection.QueryRow("SELECT current_setting('primary_conninfo')").Scan(&primaryConnInfo); err != nil {
+		r.logRotationErr(err, "Unable to read the setting 'primary_conninfo' of a Replica.", replicaPod)
+		return err
+	}
+
+	if primaryConnInfo == "" {
+		r.kubegresContext.Log.Info("The setting 'primary_conninfo' is not set for a Replica. Skipping it.", "Pod name", replicaPod.Name)
+		return nil
+	}
+
+	newPrimaryConnInfo, err := postgres.SetConnInfoPassword(primaryConnInfo, expectedPasswords.replicationUser)
+	if err != nil {
+		r.logRotationErr(err, "Unable to set the new password in the setting 'primary_conninfo' of a Replica.", replicaPod)
+		return err
+	}
+
+	if err := connection.Exec("ALTER SYSTEM SET primary_conninfo = " + pq.QuoteLiteral(newPrimaryConnInfo)); err != nil {
+		r.logRotationErr(err, "Unable to update the setting 'primary_conninfo' of a Replica.", replicaPod)
+		return err
+	}
+
+	if err := connection.Exec("SELECT pg_reload_conf()"); err != nil {
+		r.logRotationErr(err, "Unable to reload the configuration of a Replica.", replicaPod)
+		return err
+	}
+
+	r.kubegresContext.Log.Info("Updated the password of the replication user in the setting 'primary_conninfo' of a Replica.",
+		"Pod name", replicaPod.Name)
+	return nil
+}
+
+func (r *PasswordsRotationSpecEnforcer) alterRoles(primaryPod core.Pod, appliedPasswords, expectedPasswords passwords) error {
+
+	connection, err := r.connectAsSuperUser(primaryPod, appliedPasswords, expectedPasswords)
+	if err != nil {
+		return err
+	}
+	defer connection.Close()
+
+	if appliedPasswords.replicationUser != expectedPasswords.replicationUser {
+		if err := r.alterRolePassword(connection, postgres.ReplicationUserName, expectedPasswords.replicationUser); err != nil {
+			r.logRotationErr(err, "Unable to change the password of the replication user on the Primary.", primaryPod)
+			return err
+		}
+	}
+
+	if appliedPasswords.superUser != expectedPasswords.superUser {
+		if err := r.alterRolePassword(connection, r.dbConnector.GetSuperUserName(), expectedPasswords.superUser); err != nil {
+			r.logRotationErr(err, "Unable to change the password of the superuser on the Primary.", primaryPod)
+			return err
+		}
+	}
+
+	r.kubegresContext.Log.Info("Changed the passwords on the Primary.", "Pod name", primaryPod.Name)
+	return nil
+}
+
+func (r *PasswordsRotationSpecEnforcer) alterRolePassword(connection *postgres.DbConnection, roleName, password string) error {
+	return connection.Exec("ALTER ROLE " + pq.QuoteIdentifier(roleName) + " WITH PASSWORD " + pq.QuoteLiteral(password))
+}
+
+// connectAsSuperUser connects with the applied password of the superuser. If it fails, it connects with
+// the new password in case a previous rotation was interrupted after changing it in PostgreSql.
+func (r *PasswordsRotationSpecEnforcer) connectAsSuperUser(pod core.Pod, appliedPasswords, expectedPasswords passwords) (*postgres.DbConnection, error) {
+
+	superUserName := r.dbConnector.GetSuperUserName()
+
+	connection, err := r.dbConnector.Connect(pod, superUserName, appliedPasswords.superUser)
+	if err != nil && appliedPasswords.superUser != expectedPasswords.superUser {
+		connection, err = r.dbConnector.Connect(pod, superUserName, expectedPasswords.superUser)
+	}
+
+	if err != nil {
+		r.logRotationErr(err, "Unable to connect to PostgreSql in order to rotate the passwords.", pod)
+		return nil, err
+	}
+
+	return connection, nil
+}
+
+func (r *PasswordsRotationSpecEnforcer) logRotationErr(err error, errorMsg string, pod core.Pod) {
+	r.kubegresContext.Log.ErrorEvent("PasswordsRotationErr", err, errorMsg, "Pod name", pod.Name)
+}
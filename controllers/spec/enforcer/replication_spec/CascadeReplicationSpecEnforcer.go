@@ -0,0 +1,142 @@
+/*
+Copyright 2021 Reactive Tech Limited.
+"Reactive Tech Limited" is a company located in England, United Kingdom.
+https://www.reactive-tech.io
+
+Lead Developer: Alex Arica
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replication_spec
+
+import (
+	"github.com/lib/pq"
+	"reactive-tech.io/kubegres/controllers/ctx"
+	"reactive-tech.io/kubegres/controllers/operation"
+	"reactive-tech.io/kubegres/controllers/postgres"
+	"reactive-tech.io/kubegres/controllers/states"
+	"reactive-tech.io/kubegres/controllers/states/statefulset"
+)
+
+// CascadeReplicationSpecEnforcer keeps the host in the setting 'primary_conninfo' of each Replica in line with the
+// cascading replication topology when 'spec.replication.cascade.enabled' is true.
+//
+// A Replica streaming from the Primary connects to the Primary Service and a downstream Replica connects to the Pod
+// of its upstream Replica. When an upstream Replica is not ready, its downstream Replicas are rewired to another
+// upstream Replica or to the Primary. When the cascading replication is disabled, all Replicas are rewired to the
+// Primary. The settings are reloaded without restarting PostgreSql.
+type CascadeReplicationSpecEnforcer struct {
+	kubegresContext   ctx.KubegresContext
+	resourcesStates   states.ResourcesStates
+	blockingOperation *operation.BlockingOperation
+	dbConnector       postgres.DbConnector
+}
+
+func CreateCascadeReplicationSpecEnforcer(kubegresContext ctx.KubegresContext,
+	resourcesStates states.ResourcesStates,
+	blockingOperation *operation.BlockingOperation) CascadeReplicationSpecEnforcer {
+
+	return CascadeReplicationSpecEnforcer{
+		kubegresContext:   kubegresContext,
+		resourcesStates:   resourcesStates,
+		blockingOperation: blockingOperation,
+		dbConnector:       postgres.CreateDbConnector(kubegresContext),
+	}
+}
+
+func (r *CascadeReplicationSpecEnforcer) EnforceSpec() error {
+
+	if r.isStandbyEnabled() || !r.isPrimaryDbReady() {
+		return nil
+	}
+
+	if r.blockingOperation.IsThereActiveOperation() {
+		return nil
+	}
+
+	for _, replica := range r.resourcesStates.StatefulSets.Replicas.All.GetAllSortedByInstanceIndex() {
+		if !replica.Pod.IsReady {
+			continue
+		}
+
+		replicationState := r.resourcesStates.Replication.GetByInstanceIndex(replica.InstanceIndex)
+		if !replicationState.IsReachable || !replicationState.IsInRecovery || replicationState.UpstreamHost == "" {
+			continue
+		}
+
+		expectedUpstreamHost := r.resourcesStates.Cascade.GetUpstreamHostName(replica.InstanceIndex)
+		if replicationState.UpstreamHost == expectedUpstreamHost {
+			continue
+		}
+
+		if err := r.updateReplicaUpstreamHost(replica, replicationState.UpstreamHost, expectedUpstreamHost); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *CascadeReplicationSpecEnforcer) isPrimaryDbReady() bool {
+	return r.resourcesStates.StatefulSets.Primary.IsReady
+}
+
+func (r *CascadeReplicationSpecEnforcer) isStandbyEnabled() bool {
+	return r.kubegresContext.Kubegres.Spec.Standby.Enabled
+}
+
+// updateReplicaUpstreamHost sets the given host in the setting 'primary_conninfo' of a Replica, so that its WAL
+// receiver reconnects to the new upstream instance once the configuration is reloaded.
+func (r *CascadeReplicationSpecEnforcer) updateReplicaUpstreamHost(replica statefulset.StatefulSetWrapper,
+	currentUpstreamHost, expectedUpstreamHost string) error {
+
+	replicaPod := replica.Pod.Pod
+	connection, err := r.dbConnector.ConnectAsSuperUser(replicaPod)
+	if err != nil {
+		r.logCascadeReplicationErr(err, "Unable to connect to a Replica in order to set its upstream instance.", replicaPod.Name)
+		return err
+	}
+	defer connection.Close()
+
+	var primaryConnInfo string
+	if err := connection.QueryRow("SELECT current_setting('primary_conninfo')").Scan(&primaryConnInfo); err != nil {
+		r.logCascadeReplicationErr(err, "Unable to read the setting 'primary_conninfo' of a Replica.", replicaPod.Name)
+		return err
+	}
+
+	newPrimaryConnInfo, err := postgres.SetConnInfoParam(primaryConnInfo, "host", expectedUpstreamHost)
+	if err != nil {
+		r.logCascadeReplicationErr(err, "Unable to set 'host' in the setting 'primary_conninfo' of a Replica.", replicaPod.Name)
+		return err
+	}
+
+	if err := connection.Exec("ALTER SYSTEM SET primary_conninfo = " + pq.QuoteLiteral(newPrimaryConnInfo)); err != nil {
+		r.logCascadeReplicationErr(err, "Unable to update the setting 'primary_conninfo' of a Replica.", replicaPod.Name)
+		return err
+	}
+
+	if err := connection.Exec("SELECT pg_reload_conf()"); err != nil {
+		r.logCascadeReplicationErr(err, "Unable to reload the configuration of a Replica.", replicaPod.Name)
+		return err
+	}
+
+	r.kubegresContext.Log.InfoEvent("ReplicaUpstreamUpdated",
+		"Updated the upstream instance from which a Replica streams the WAL.",
+		"Pod name", replicaPod.Name, "Former upstream", currentUpstreamHost, "New upstream", expectedUpstreamHost)
+	return nil
+}
+
+func (r *CascadeReplicationSpecEnforcer) logCascadeReplicationErr(err error, errorMsg string, podName string) {
+	r.kubegresContext.Log.ErrorEvent("CascadeReplicationErr", err, errorMsg, "Pod name", podName)
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2021 Reactive Tech Limited.
+"Reactive Tech Limited" is a company located in England, United Kingdom.
+https://www.reactive-tech.io
+
+Lead Developer: Alex Arica
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replication_spec
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/lib/pq"
+	"reactive-tech.io/kubegres/controllers/ctx"
+	"reactive-tech.io/kubegres/controllers/operation"
+	"reactive-tech.io/kubegres/controllers/postgres"
+	"reactive-tech.io/kubegres/controllers/states"
+	"reactive-tech.io/kubegres/controllers/states/statefulset"
+)
+
+// DelayedReplicasSpecEnforcer keeps the setting 'recovery_min_apply_delay' of each delayed Replica in line with
+// the field 'spec.replication.delayedReplicas'. The delayed Replicas sorted by instance index are matched with the
+// entries of that field in order. The setting is reloaded without restarting PostgreSql.
+type DelayedReplicasSpecEnforcer struct {
+	kubegresContext   ctx.KubegresContext
+	resourcesStates   states.ResourcesStates
+	blockingOperation *operation.BlockingOperation
+	dbConnector       postgres.DbConnector
+}
+
+func CreateDelayedReplicasSpecEnforcer(kubegresContext ctx.KubegresContext,
+	resourcesStates states.ResourcesStates,
+	blockingOperation *operation.BlockingOperation) DelayedReplicasSpecEnforcer {
+
+	return DelayedReplicasSpecEnforcer{
+		kubegresContext:   kubegresContext,
+		resourcesStates:   resourcesStates,
+		blockingOperation: blockingOperation,
+		dbConnector:       postgres.CreateDbConnector(kubegresContext),
+	}
+}
+
+func (r *DelayedReplicasSpecEnforcer) EnforceSpec() error {
+
+	if r.blockingOperation.IsThereActiveOperation() {
+		return nil
+	}
+
+	delayedReplicasSpec := r.kubegresContext.Kubegres.Spec.Replication.DelayedReplicas
+
+	for i, delayedReplica := range r.resourcesStates.StatefulSets.DelayedReplicas.All.GetAllSortedByInstanceIndex() {
+		if i >= len(delayedReplicasSpec) {
+			break
+		}
+
+		if !delayedReplica.Pod.IsReady {
+			continue
+		}
+
+		delay, err := time.ParseDuration(delayedReplicasSpec[i].Delay)
+		if err != nil {
+			return err
+		}
+
+		if err := r.updateRecoveryMinApplyDelay(delayedReplica, delay); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// updateRecoveryMinApplyDelay sets 'recovery_min_apply_delay' in milliseconds, which is the unit of its value in 'pg_settings'.
+func (r *DelayedReplicasSpecEnforcer) updateRecoveryMinApplyDelay(delayedReplica statefulset.StatefulSetWrapper, delay time.Duration) error {
+
+	delayedReplicaPod := delayedReplica.Pod.Pod
+	connection, err := r.dbConnector.ConnectAsSuperUser(delayedReplicaPod)
+	if err != nil {
+		r.logDelayedReplicasErr(err, "Unable to connect to a delayed Replica in order to set its 'recovery_min_apply_delay'.", delayedReplicaPod.Name)
+		return err
+	}
+	defer connection.Close()
+
+	expectedDelay := strconv.FormatInt(delay.Milliseconds(), 10)
+
+	var currentDelay string
+	if err := connection.QueryRow("SELECT setting FROM pg_settings WHERE name = 'recovery_min_apply_delay'").Scan(&currentDelay); err != nil {
+		r.logDelayedReplicasErr(err, "Unable to read the setting 'recovery_min_apply_delay' of a delayed Replica.", delayedReplicaPod.Name)
+		return err
+	}
+
+	if currentDelay == expectedDelay {
+		return nil
+	}
+
+	if err := connection.Exec("ALTER SYSTEM SET recovery_min_apply_delay = " + pq.QuoteLiteral(expectedDelay+"ms")); err != nil {
+		r.logDelayedReplicasErr(err, "Unable to update the setting 'recovery_min_apply_delay' of a delayed Replica.", delayedReplicaPod.Name)
+		return err
+	}
+
+	if err := connection.Exec("SELECT pg_reload_conf()"); err != nil {
+		r.logDelayedReplicasErr(err, "Unable to reload the configuration of a delayed Replica.", delayedReplicaPod.Name)
+		return err
+	}
+
+	r.kubegresContext.Log.InfoEvent("DelayedReplicaDelayUpdated",
+		"Updated the setting 'recovery_min_apply_delay' of a delayed Replica.",
+		"Pod name", delayedReplicaPod.Name, "recovery_min_apply_delay", delay.String())
+	return nil
+}
+
+func (r *DelayedReplicasSpecEnforcer) logDelayedReplicasErr(err error, errorMsg string, podName string) {
+	r.kubegresContext.Log.ErrorEvent("DelayedReplicaErr", err, errorMsg, "Pod name", podName)
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2021 Reactive Tech Limited.
+"Reactive Tech Limited" is a company located in England, United Kingdom.
+https://www.reactive-tech.io
+
+Lead Developer: Alex Arica
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replication_spec
+
+import (
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"reactive-tech.io/kubegres/controllers/ctx"
+	"reactive-tech.io/kubegres/controllers/operation"
+	"reactive-tech.io/kubegres/controllers/states"
+	"reactive-tech.io/kubegres/controllers/states/statefulset"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ReplicaServiceLagSpecEnforcer removes the Replicas whose replay lag is over 'spec.replicaService.maxLag' from
+// the endpoints of the Replica Service, so that the applications do not read stale data from them.
+//
+// The Pod of a lagging Replica is labeled with the replication role 'lagging-replica', which is not selected by the
+// Replica Service, and it is labeled back with 'replica' once its replay lag is within the maximum. While the lag of
+// a Replica is unknown, for example because the Primary is not reachable, its label is left as it is. When the field
+// is removed, all the lagging Replicas are added back to the Replica Service.
+type ReplicaServiceLagSpecEnforcer struct {
+	kubegresContext   ctx.KubegresContext
+	resourcesStates   states.ResourcesStates
+	blockingOperation *operation.BlockingOperation
+}
+
+func CreateReplicaServiceLagSpecEnforcer(kubegresContext ctx.KubegresContext,
+	resourcesStates states.ResourcesStates,
+	blockingOperation *operation.BlockingOperation) ReplicaServiceLagSpecEnforcer {
+
+	return ReplicaServiceLagSpecEnforcer{
+		kubegresContext:   kubegresContext,
+		resourcesStates:   resourcesStates,
+		blockingOperation: blockingOperation,
+	}
+}
+
+func (r *ReplicaServiceLagSpecEnforcer) EnforceSpec() error {
+
+	if r.blockingOperation.IsThereActiveOperation() {
+		return nil
+	}
+
+	maxLagSeconds := r.kubegresContext.GetReplicaServiceMaxLagSeconds()
+
+	for _, replica := range r.resourcesStates.StatefulSets.Replicas.All.GetAllSortedByInstanceIndex() {
+		if !replica.Pod.IsDeployed {
+			continue
+		}
+
+		isExcluded := replica.Pod.Pod.Labels["replicationRole"] == ctx.LaggingReplicaRoleName
+		shouldBeExcluded := r.shouldBeExcluded(replica, maxLagSeconds, isExcluded)
+		if isExcluded == shouldBeExcluded {
+			continue
+		}
+
+		if err := r.updateReplicationRoleLabel(replica, shouldBeExcluded, maxLagSeconds); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *ReplicaServiceLagSpecEnforcer) shouldBeExcluded(replica statefulset.StatefulSetWrapper,
+	maxLagSeconds int64, isExcluded bool) bool {
+
+	if maxLagSeconds == 0 || r.resourcesStates.Cascade.IsUpstreamReplica(replica.InstanceIndex) {
+		return false
+	}
+
+	replicationState := r.resourcesStates.Replication.GetByInstanceIndex(replica.InstanceIndex)
+	if !replica.Pod.IsReady || replicationState.ReplayLagBytes < 0 || replicationState.ReplayLagSeconds < 0 {
+		return isExcluded
+	}
+
+	return replicationState.ReplayLagSeconds > maxLagSeconds
+}
+
+// updateReplicationRoleLabel sets the label 'replicationRole' of the Pod of a Replica. The StatefulSet does not
+// select its Pod with that label, so that the Pod is neither restarted nor orphaned.
+func (r *ReplicaServiceLagSpecEnforcer) updateReplicationRoleLabel(replica statefulset.StatefulSetWrapper,
+	isLagging bool, maxLagSeconds int64) error {
+
+	replicationRole := ctx.ReplicaRoleName
+	if isLagging {
+		replicationRole = ctx.LaggingReplicaRoleName
+	}
+
+	replicaPod := replica.Pod.Pod
+	updatedPod := replicaPod.DeepCopy()
+	updatedPod.Labels["replicationRole"] = replicationRole
+
+	err := r.kubegresContext.Client.Patch(r.kubegresContext.Ctx, updatedPod, client.MergeFrom(&replicaPod))
+	if err != nil && !apierrors.IsNotFound(err) {
+		r.kubegresContext.Log.ErrorEvent("ReplicaServiceLagErr", err,
+			"Unable to update the label 'replicationRole' of the Pod of a Replica.",
+			"Pod name", replicaPod.Name, "replicationRole", replicationRole)
+		return err
+	}
+
+	replayLagSeconds := r.resourcesStates.Replication.GetByInstanceIndex(replica.InstanceIndex).ReplayLagSeconds
+
+	if isLagging {
+		r.kubegresContext.Log.WarningEvent("ReplicaRemovedFromService",
+			"Removed a Replica from the endpoints of the Replica Service as its replay lag is over 'spec.replicaService.maxLag'.",
+			"Pod name", replicaPod.Name, "Replay lag seconds", replayLagSeconds,
+			"Max lag seconds", maxLagSeconds)
+	} else {
+		r.kubegresContext.Log.InfoEvent("ReplicaAddedToService",
+			"Added back a Replica to the endpoints of the Replica Service.",
+			"Pod name", replicaPod.Name, "Replay lag seconds", replayLagSeconds)
+	}
+	return nil
+}
@@ -0,0 +1,336 @@
+/*
+Copyright 2021 Reactive Tech Limited.
+"Reactive Tech Limited" is a company located in England, United Kingdom.
+https://www.reactive-tech.io
+
+Lead Developer: Alex Arica
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replication_spec
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/lib/pq"
+	"reactive-tech.io/kubegres/controllers/ctx"
+	"reactive-tech.io/kubegres/controllers/operation"
+	"reactive-tech.io/kubegres/controllers/postgres"
+	"reactive-tech.io/kubegres/controllers/states"
+	"reactive-tech.io/kubegres/controllers/states/statefulset"
+)
+
+// ReplicationSlotsSpecEnforcer manages a physical replication slot per Replica when 'spec.replication.slots.enabled'
+// is true, so that an upstream instance retains the WAL which its Replicas have not received yet.
+//
+// The slot of a Replica is created on its upstream instance, which is the Primary or an upstream Replica with a
+// cascading replication, and it is set as 'primary_slot_name' of the Replica. The slots of the undeployed Replicas
+// are dropped once no Replica streams with them. The WAL retained by the slots of an instance is capped by the setting
+// 'max_slot_wal_keep_size'. When the slots are disabled, the settings of the Replicas are reset and the slots are
+// dropped. The settings are reloaded without restarting PostgreSql.
+type ReplicationSlotsSpecEnforcer struct {
+	kubegresContext   ctx.KubegresContext
+	resourcesStates   states.ResourcesStates
+	blockingOperation *operation.BlockingOperation
+	dbConnector       postgres.DbConnector
+}
+
+func CreateReplicationSlotsSpecEnforcer(kubegresContext ctx.KubegresContext,
+	resourcesStates states.ResourcesStates,
+	blockingOperation *operation.BlockingOperation) ReplicationSlotsSpecEnforcer {
+
+	return ReplicationSlotsSpecEnforcer{
+		kubegresContext:   kubegresContext,
+		resourcesStates:   resourcesStates,
+		blockingOperation: blockingOperation,
+		dbConnector:       postgres.CreateDbConnector(kubegresContext),
+	}
+}
+
+func (r *ReplicationSlotsSpecEnforcer) EnforceSpec() error {
+
+	if r.isStandbyEnabled() || !r.isPrimaryDbReady() {
+		return nil
+	}
+
+	if r.blockingOperation.IsThereActiveOperation() {
+		return nil
+	}
+
+	// The slots are created before they are set in the Replicas and they are dropped once they are not set anymore
+	if r.kubegresContext.IsReplicationSlotsEnabled() {
+		if err := r.enforceUpstreamInstancesSlots(); err != nil {
+			return err
+		}
+		return r.enforceReplicasSlotName()
+	}
+
+	if err := r.enforceReplicasSlotName(); err != nil {
+		return err
+	}
+	return r.enforceUpstreamInstancesSlots()
+}
+
+func (r *ReplicationSlotsSpecEnforcer) isPrimaryDbReady() bool {
+	return r.resourcesStates.StatefulSets.Primary.IsReady
+}
+
+func (r *ReplicationSlotsSpecEnforcer) isStandbyEnabled() bool {
+	return r.kubegresContext.Kubegres.Spec.Standby.Enabled
+}
+
+func (r *ReplicationSlotsSpecEnforcer) enforceUpstreamInstancesSlots() error {
+
+	expectedSlotNamesByUpstream := r.getExpectedSlotNamesByUpstreamInstanceIndex()
+
+	upstreamInstances := []statefulset.StatefulSetWrapper{r.resourcesStates.StatefulSets.Primary}
+	upstreamInstances = append(upstreamInstances, r.getReadyReplicas()...)
+
+	for _, upstreamInstance := range upstreamInstances {
+		expectedSlotNames := expectedSlotNamesByUpstream[upstreamInstance.InstanceIndex]
+		if err := r.enforceUpstreamInstanceSlots(upstreamInstance, expectedSlotNames); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getExpectedSlotNamesByUpstreamInstanceIndex returns the names of the slots of the deployed Replicas grouped by
+// the instance index of their upstream instance. It is empty when the slots are disabled.
+func (r *ReplicationSlotsSpecEnforcer) getExpectedSlotNamesByUpstreamInstanceIndex() map[int32][]string {
+
+	expectedSlotNamesByUpstream := make(map[int32][]string)
+	if !r.kubegresContext.IsReplicationSlotsEnabled() {
+		return expectedSlotNamesByUpstream
+	}
+
+	for _, replica := range r.resourcesStates.StatefulSets.GetReplicasAndDelayedReplicas() {
+
+		upstreamInstanceIndex := r.resourcesStates.StatefulSets.Primary.InstanceIndex
+		if upstreamReplica, exists := r.resourcesStates.Cascade.GetUpstreamReplica(replica.InstanceIndex); exists {
+			upstreamInstanceIndex = upstreamReplica.InstanceIndex
+		}
+
+		expectedSlotNamesByUpstream[upstreamInstanceIndex] = append(expectedSlotNamesByUpstream[upstreamInstanceIndex],
+			r.kubegresContext.GetReplicationSlotName(replica.InstanceIndex))
+	}
+
+	return expectedSlotNamesByUpstream
+}
+
+func (r *ReplicationSlotsSpecEnforcer) enforceUpstreamInstanceSlots(upstreamInstance statefulset.StatefulSetWrapper, expectedSlotNames []string) error {
+
+	replicationState := r.resourcesStates.Replication.GetByInstanceIndex(upstreamInstance.InstanceIndex)
+	if !replicationState.IsReachable {
+		return nil
+	}
+
+	if len(expectedSlotNames) == 0 && len(replicationState.ReplicationSlotNames) == 0 {
+		return nil
+	}
+
+	podName := upstreamInstance.Pod.Pod.Name
+	connection, err := r.dbConnector.ConnectAsSuperUser(upstreamInstance.Pod.Pod)
+	if err != nil {
+		r.logReplicationSlotsErr(err, "Unable to connect to an instance in order to manage its replication slots.", podName)
+		return err
+	}
+	defer connection.Close()
+
+	if r.kubegresContext.IsReplicationSlotsEnabled() {
+		if err := r.enforceMaxSlotWalKeepSize(connection); err != nil {
+			return err
+		}
+	} else if err := r.resetMaxSlotWalKeepSize(connection); err != nil {
+		return err
+	}
+
+	for _, slotName := range expectedSlotNames {
+		if slices.Contains(replicationState.ReplicationSlotNames, slotName) {
+			continue
+		}
+
+		if err := postgres.CreatePhysicalReplicationSlot(connection, slotName); err != nil {
+			r.logReplicationSlotsErr(err, "Unable to create the replication slot '"+slotName+"'.", podName)
+			return err
+		}
+
+		r.kubegresContext.Log.InfoEvent("ReplicationSlotCreated", "Created a replication slot for a Replica.",
+			"Pod name", podName, "Slot name", slotName)
+	}
+
+	for _, slotName := range replicationState.ReplicationSlotNames {
+		if slices.Contains(expectedSlotNames, slotName) {
+			continue
+		}
+
+		isDropped, err := postgres.DropInactiveReplicationSlot(connection, slotName)
+		if err != nil {
+			r.logReplicationSlotsErr(err, "Unable to drop the replication slot '"+slotName+"'.", podName)
+			return err
+		}
+
+		if !isDropped {
+			r.kubegresContext.Log.Info("A replication slot which is not required anymore is still in use. "+
+				"It will be dropped once it is not used.", "Pod name", podName, "Slot name", slotName)
+			continue
+		}
+
+		r.kubegresContext.Log.InfoEvent("ReplicationSlotDropped", "Dropped a replication slot which is not required anymore.",
+			"Pod name", podName, "Slot name", slotName)
+	}
+
+	return nil
+}
+
+// enforceMaxSlotWalKeepSize sets 'max_slot_wal_keep_size' in megabytes, which is the unit of its value in 'pg_settings'.
+func (r *ReplicationSlotsSpecEnforcer) enforceMaxSlotWalKeepSize(connection *postgres.DbConnection) error {
+
+	expectedSizeMegabytes, err := r.kubegresContext.GetMaxSlotWalKeepSizeMegabytes()
+	if err != nil {
+		r.logReplicationSlotsErr(err, "Unable to compute the setting 'max_slot_wal_keep_size'.", connection.PodName)
+		return err
+	}
+	expectedSize := strconv.FormatInt(expectedSizeMegabytes, 10)
+
+	var currentSize string
+	if err := connection.QueryRow("SELECT setting FROM pg_settings WHERE name = 'max_slot_wal_keep_size'").Scan(&currentSize); err != nil {
+		r.logReplicationSlotsErr(err, "Unable to read the setting 'max_slot_wal_keep_size'. "+
+			"The field 'spec.replication.slots' requires PostgreSql 13 or later.", connection.PodName)
+		return err
+	}
+
+	if currentSize == expectedSize {
+		return nil
+	}
+
+	if err := connection.Exec("ALTER SYSTEM SET max_slot_wal_keep_size = " + pq.QuoteLiteral(expectedSize+"MB")); err != nil {
+		r.logReplicationSlotsErr(err, "Unable to update the setting 'max_slot_wal_keep_size'.", connection.PodName)
+		return err
+	}
+
+	if err := connection.Exec("SELECT pg_reload_conf()"); err != nil {
+		r.logReplicationSlotsErr(err, "Unable to reload the configuration.", connection.PodName)
+		return err
+	}
+
+	r.kubegresContext.Log.InfoEvent("MaxSlotWalKeepSizeUpdated",
+		"Updated the setting 'max_slot_wal_keep_size' capping the WAL retained by the replication slots.",
+		"Pod name", connection.PodName, "max_slot_wal_keep_size", expectedSize+"MB")
+	return nil
+}
+
+// resetMaxSlotWalKeepSize resets the setting 'max_slot_wal_keep_size' if it was set by Kubegres, which is the case
+// when it is set in 'postgresql.auto.conf'. A value set in the custom config is kept.
+func (r *ReplicationSlotsSpecEnforcer) resetMaxSlotWalKeepSize(connection *postgres.DbConnection) error {
+
+	var sourceFile string
+	const query = `SELECT COALESCE(sourcefile, '') FROM pg_settings WHERE name = 'max_slot_wal_keep_size'`
+	if err := connection.QueryRow(query).Scan(&sourceFile); err != nil {
+		r.logReplicationSlotsErr(err, "Unable to read the setting 'max_slot_wal_keep_size'.", connection.PodName)
+		return err
+	}
+
+	if !strings.HasSuffix(sourceFile, "postgresql.auto.conf") {
+		return nil
+	}
+
+	if err := connection.Exec("ALTER SYSTEM RESET max_slot_wal_keep_size"); err != nil {
+		r.logReplicationSlotsErr(err, "Unable to reset the setting 'max_slot_wal_keep_size'.", connection.PodName)
+		return err
+	}
+
+	if err := connection.Exec("SELECT pg_reload_conf()"); err != nil {
+		r.logReplicationSlotsErr(err, "Unable to reload the configuration.", connection.PodName)
+		return err
+	}
+
+	return nil
+}
+
+func (r *ReplicationSlotsSpecEnforcer) enforceReplicasSlotName() error {
+
+	for _, replica := range r.getReadyReplicas() {
+
+		replicationState := r.resourcesStates.Replication.GetByInstanceIndex(replica.InstanceIndex)
+		if !replicationState.IsReachable || !replicationState.IsInRecovery {
+			continue
+		}
+
+		expectedSlotName := ""
+		if r.kubegresContext.IsReplicationSlotsEnabled() {
+			expectedSlotName = r.kubegresContext.GetReplicationSlotName(replica.InstanceIndex)
+		} else if !strings.HasPrefix(replicationState.SlotName, ctx.ReplicationSlotNamePrefix) {
+			// A slot which is not managed by Kubegres is kept
+			continue
+		}
+
+		if replicationState.SlotName == expectedSlotName {
+			continue
+		}
+
+		if err := r.updateReplicaSlotName(replica, expectedSlotName); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *ReplicationSlotsSpecEnforcer) updateReplicaSlotName(replica statefulset.StatefulSetWrapper, slotName string) error {
+
+	replicaPod := replica.Pod.Pod
+	connection, err := r.dbConnector.ConnectAsSuperUser(replicaPod)
+	if err != nil {
+		r.logReplicationSlotsErr(err, "Unable to connect to a Replica in order to set its 'primary_slot_name'.", replicaPod.Name)
+		return err
+	}
+	defer connection.Close()
+
+	query := "ALTER SYSTEM SET primary_slot_name = " + pq.QuoteLiteral(slotName)
+	if slotName == "" {
+		query = "ALTER SYSTEM RESET primary_slot_name"
+	}
+
+	if err := connection.Exec(query); err != nil {
+		r.logReplicationSlotsErr(err, "Unable to update the setting 'primary_slot_name' of a Replica.", replicaPod.Name)
+		return err
+	}
+
+	if err := connection.Exec("SELECT pg_reload_conf()"); err != nil {
+		r.logReplicationSlotsErr(err, "Unable to reload the configuration of a Replica.", replicaPod.Name)
+		return err
+	}
+
+	r.kubegresContext.Log.InfoEvent("ReplicaSlotNameUpdated", "Updated the setting 'primary_slot_name' of a Replica.",
+		"Pod name", replicaPod.Name, "primary_slot_name", slotName)
+	return nil
+}
+
+func (r *ReplicationSlotsSpecEnforcer) getReadyReplicas() []statefulset.StatefulSetWrapper {
+	var readyReplicas []statefulset.StatefulSetWrapper
+	for _, replica := range r.resourcesStates.StatefulSets.GetReplicasAndDelayedReplicas() {
+		if replica.Pod.IsReady {
+			readyReplicas = append(readyReplicas, replica)
+		}
+	}
+	return readyReplicas
+}
+
+func (r *ReplicationSlotsSpecEnforcer) logReplicationSlotsErr(err error, errorMsg string, podName string) {
+	r.kubegresContext.Log.ErrorEvent("ReplicationSlotsErr", err, errorMsg, "Pod name", podName)
+}
@@ -0,0 +1,299 @@
+/*
+Copyright 2021 Reactive Tech Limited.
+"Reactive Tech Limited" is a company located in England, United Kingdom.
+https://www.reactive-tech.io
+
+Lead Developer: Alex Arica
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replication_spec
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/lib/pq"
+	"reactive-tech.io/kubegres/controllers/ctx"
+	"reactive-tech.io/kubegres/controllers/operation"
+	"reactive-tech.io/kubegres/controllers/postgres"
+	"reactive-tech.io/kubegres/controllers/states"
+	"reactive-tech.io/kubegres/controllers/states/statefulset"
+)
+
+// SynchronousReplicationSpecEnforcer keeps the setting 'synchronous_standby_names' of the Primary in line with
+// the deployed Replicas when 'spec.replication.mode' is 'synchronous'.
+//
+// Each Replica connects to the Primary with the name of its StatefulSet as 'application_name', which is set in
+// its setting 'primary_conninfo'. The Replicas are listed in 'synchronous_standby_names' by instance index, so that
+// the first 'numberOfSyncStandbys' connected Replicas are synchronous. The settings are reloaded without restarting
+// PostgreSql. The Replicas which are synchronous are stored in the status, so that a failover only promotes one of them.
+type SynchronousReplicationSpecEnforcer struct {
+	kubegresContext   ctx.KubegresContext
+	resourcesStates   states.ResourcesStates
+	blockingOperation *operation.BlockingOperation
+	dbConnector       postgres.DbConnector
+}
+
+func CreateSynchronousReplicationSpecEnforcer(kubegresContext ctx.KubegresContext,
+	resourcesStates states.ResourcesStates,
+	blockingOperation *operation.BlockingOperation) SynchronousReplicationSpecEnforcer {
+
+	return SynchronousReplicationSpecEnforcer{
+		kubegresContext:   kubegresContext,
+		resourcesStates:   resourcesStates,
+		blockingOperation: blockingOperation,
+		dbConnector:       postgres.CreateDbConnector(kubegresContext),
+	}
+}
+
+func (r *SynchronousReplicationSpecEnforcer) EnforceSpec() error {
+
+	if r.isStandbyEnabled() || !r.isPrimaryDbReady() {
+		return nil
+	}
+
+	if r.blockingOperation.IsThereActiveOperation() {
+		return nil
+	}
+
+	if r.kubegresContext.IsSynchronousReplicationEnabled() {
+		for _, replica := range r.resourcesStates.StatefulSets.Replicas.All.GetAllSortedByInstanceIndex() {
+			if !replica.Pod.IsReady {
+				continue
+			}
+			if err := r.updateReplicaApplicationName(replica); err != nil {
+				return err
+			}
+		}
+	}
+
+	primaryPod := r.resourcesStates.StatefulSets.Primary.Pod.Pod
+	connection, err := r.dbConnector.ConnectAsSuperUser(primaryPod)
+	if err != nil {
+		r.logSynchronousReplicationErr(err, "Unable to connect to the Primary in order to enforce the replication mode.", primaryPod.Name)
+		return err
+	}
+	defer connection.Close()
+
+	if !r.kubegresContext.IsSynchronousReplicationEnabled() {
+		return r.disableSynchronousReplication(connection)
+	}
+
+	if err := r.updateSynchronousStandbyNames(connection); err != nil {
+		return err
+	}
+
+	return r.updateSynchronousStandbysStatus(connection)
+}
+
+func (r *SynchronousReplicationSpecEnforcer) isPrimaryDbReady() bool {
+	return r.resourcesStates.StatefulSets.Primary.IsReady
+}
+
+func (r *SynchronousReplicationSpecEnforcer) isStandbyEnabled() bool {
+	return r.kubegresContext.Kubegres.Spec.Standby.Enabled
+}
+
+// updateReplicaApplicationName sets the name of the StatefulSet of a Replica as 'application_name' in its setting
+// 'primary_conninfo', so that it can be referenced in the setting 'synchronous_standby_names' of the Primary.
+func (r *SynchronousReplicationSpecEnforcer) updateReplicaApplicationName(replica statefulset.StatefulSetWrapper) error {
+
+	replicaPod := replica.Pod.Pod
+	connection, err := r.dbConnector.ConnectAsSuperUser(replicaPod)
+	if err != nil {
+		r.logSynchronousReplicationErr(err, "Unable to connect to a Replica in order to set its 'application_name'.", replicaPod.Name)
+		return err
+	}
+	defer connection.Close()
+
+	var primaryConnInfo string
+	if err := connection.QueryRow("SELECT current_setting('primary_conninfo')").Scan(&primaryConnInfo); err != nil {
+		r.logSynchronousReplicationErr(err, "Unable to read the setting 'primary_conninfo' of a Replica.", replicaPod.Name)
+		return err
+	}
+
+	if primaryConnInfo == "" {
+		r.kubegresContext.Log.Info("The setting 'primary_conninfo' is not set for a Replica. Skipping it.", "Pod name", replicaPod.Name)
+		return nil
+	}
+
+	applicationName, err := postgres.GetConnInfoParam(primaryConnInfo, "application_name")
+	if err != nil {
+		r.logSynchronousReplicationErr(err, "Unable to read 'application_name' in the setting 'primary_conninfo' of a Replica.", replicaPod.Name)
+		return err
+	}
+
+	if applicationName == replica.StatefulSet.Name {
+		return nil
+	}
+
+	newPrimaryConnInfo, err := postgres.SetConnInfoParam(primaryConnInfo, "application_name", replica.StatefulSet.Name)
+	if err != nil {
+		r.logSynchronousReplicationErr(err, "Unable to set 'application_name' in the setting 'primary_conninfo' of a Replica.", replicaPod.Name)
+		return err
+	}
+
+	if err := connection.Exec("ALTER SYSTEM SET primary_conninfo = " + pq.QuoteLiteral(newPrimaryConnInfo)); err != nil {
+		r.logSynchronousReplicationErr(err, "Unable to update the setting 'primary_conninfo' of a Replica.", replicaPod.Name)
+		return err
+	}
+
+	if err := connection.Exec("SELECT pg_reload_conf()"); err != nil {
+		r.logSynchronousReplicationErr(err, "Unable to reload the configuration of a Replica.", replicaPod.Name)
+		return err
+	}
+
+	r.kubegresContext.Log.Info("Set 'application_name' in the setting 'primary_conninfo' of a Replica.",
+		"Pod name", replicaPod.Name, "application_name", replica.StatefulSet.Name)
+	return nil
+}
+
+func (r *SynchronousReplicationSpecEnforcer) updateSynchronousStandbyNames(connection *postgres.DbConnection) error {
+
+	expectedStandbyNames, nbreSyncStandbys := r.getExpectedSynchronousStandbyNames()
+
+	var currentStandbyNames string
+	if err := connection.QueryRow("SELECT current_setting('synchronous_standby_names')").Scan(&currentStandbyNames); err != nil {
+		r.logSynchronousReplicationErr(err, "Unable to read the setting 'synchronous_standby_names' of the Primary.", connection.PodName)
+		return err
+	}
+
+	if currentStandbyNames == expectedStandbyNames {
+		return nil
+	}
+
+	if err := connection.Exec("ALTER SYSTEM SET synchronous_standby_names = " + pq.QuoteLiteral(expectedStandbyNames)); err != nil {
+		r.logSynchronousReplicationErr(err, "Unable to update the setting 'synchronous_standby_names' of the Primary.", connection.PodName)
+		return err
+	}
+
+	if err := connection.Exec("SELECT pg_reload_conf()"); err != nil {
+		r.logSynchronousReplicationErr(err, "Unable to reload the configuration of the Primary.", connection.PodName)
+		return err
+	}
+
+	r.kubegresContext.Log.InfoEvent("SynchronousStandbyNamesUpdated",
+		"Updated the setting 'synchronous_standby_names' of the Primary with the deployed Replicas.",
+		"Pod name", connection.PodName, "synchronous_standby_names", expectedStandbyNames)
+
+	if nbreSyncStandbys < r.kubegresContext.GetNumberOfSyncStandbys() {
+		r.kubegresContext.Log.WarningEvent("NotEnoughSynchronousStandbys",
+			"The field 'spec.replication.numberOfSyncStandbys' is set to '"+
+				strconv.Itoa(int(r.kubegresContext.GetNumberOfSyncStandbys()))+"'. However, only '"+
+				strconv.Itoa(int(nbreSyncStandbys))+"' Replicas are deployed. All of them are synchronous.")
+	}
+
+	return nil
+}
+
+// getExpectedSynchronousStandbyNames returns the value of 'synchronous_standby_names' listing the StatefulSets of the
+// deployed Replicas by instance index, and the number of synchronous Replicas it requires. The value is empty if
+// no Replica is deployed, so that the writes on the Primary are not blocked.
+func (r *SynchronousReplicationSpecEnforcer) getExpectedSynchronousStandbyNames() (string, int32) {
+
+	var standbyNames []string
+	for _, replica := range r.resourcesStates.StatefulSets.Replicas.All.GetAllSortedByInstanceIndex() {
+		standbyNames = append(standbyNames, `"`+replica.StatefulSet.Name+`"`)
+	}
+
+	if len(standbyNames) == 0 {
+		return "", 0
+	}
+
+	nbreSyncStandbys := r.kubegresContext.GetNumberOfSyncStandbys()
+	if int(nbreSyncStandbys) > len(standbyNames) {
+		nbreSyncStandbys = int32(len(standbyNames))
+	}
+
+	return "FIRST " + strconv.Itoa(int(nbreSyncStandbys)) + " (" + strings.Join(standbyNames, ", ") + ")", nbreSyncStandbys
+}
+
+func (r *SynchronousReplicationSpecEnforcer) updateSynchronousStandbysStatus(connection *postgres.DbConnection) error {
+
+	rows, err := connection.Query("SELECT application_name FROM pg_stat_replication WHERE sync_state = 'sync' ORDER BY application_name")
+	if err != nil {
+		r.logSynchronousReplicationErr(err, "Unable to query the synchronous Replicas of the Primary.", connection.PodName)
+		return err
+	}
+	defer rows.Close()
+
+	var synchronousStandbys []string
+	for rows.Next() {
+		var applicationName string
+		if err := rows.Scan(&applicationName); err != nil {
+			r.logSynchronousReplicationErr(err, "Unable to query the synchronous Replicas of the Primary.", connection.PodName)
+			return err
+		}
+		synchronousStandbys = append(synchronousStandbys, applicationName)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logSynchronousReplicationErr(err, "Unable to query the synchronous Replicas of the Primary.", connection.PodName)
+		return err
+	}
+
+	r.setSynchronousStandbysStatus(synchronousStandbys)
+	return nil
+}
+
+// disableSynchronousReplication resets the setting 'synchronous_standby_names' of the Primary if it was set by
+// Kubegres, which is the case when it is set in 'postgresql.auto.conf'. A value set in the custom config is kept.
+func (r *SynchronousReplicationSpecEnforcer) disableSynchronousReplication(connection *postgres.DbConnection) error {
+
+	r.setSynchronousStandbysStatus(nil)
+
+	var standbyNames, sourceFile string
+	const query = `SELECT setting, COALESCE(sourcefile, '') FROM pg_settings WHERE name = 'synchronous_standby_names'`
+	if err := connection.QueryRow(query).Scan(&standbyNames, &sourceFile); err != nil {
+		r.logSynchronousReplicationErr(err, "Unable to read the setting 'synchronous_standby_names' of the Primary.", connection.PodName)
+		return err
+	}
+
+	if standbyNames == "" || !strings.HasSuffix(sourceFile, "postgresql.auto.conf") {
+		return nil
+	}
+
+	if err := connection.Exec("ALTER SYSTEM RESET synchronous_standby_names"); err != nil {
+		r.logSynchronousReplicationErr(err, "Unable to reset the setting 'synchronous_standby_names' of the Primary.", connection.PodName)
+		return err
+	}
+
+	if err := connection.Exec("SELECT pg_reload_conf()"); err != nil {
+		r.logSynchronousReplicationErr(err, "Unable to reload the configuration of the Primary.", connection.PodName)
+		return err
+	}
+
+	r.kubegresContext.Log.InfoEvent("SynchronousReplicationDisabled",
+		"The field 'spec.replication.mode' is set to 'asynchronous'. "+
+			"Reset the setting 'synchronous_standby_names' of the Primary.", "Pod name", connection.PodName)
+	return nil
+}
+
+func (r *SynchronousReplicationSpecEnforcer) setSynchronousStandbysStatus(synchronousStandbys []string) {
+
+	currentSynchronousStandbys := r.kubegresContext.Status.GetSynchronousStandbys()
+	if len(currentSynchronousStandbys) == 0 && len(synchronousStandbys) == 0 {
+		return
+	}
+
+	if !reflect.DeepEqual(currentSynchronousStandbys, synchronousStandbys) {
+		r.kubegresContext.Status.SetSynchronousStandbys(synchronousStandbys)
+	}
+}
+
+func (r *SynchronousReplicationSpecEnforcer) logSynchronousReplicationErr(err error, errorMsg string, podName string) {
+	r.kubegresContext.Log.ErrorEvent("SynchronousReplicationErr", err, errorMsg, "Pod name", podName)
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2021 Reactive Tech Limited.
+"Reactive Tech Limited" is a company located in England, United Kingdom.
+https://www.reactive-tech.io
+
+Lead Developer: Alex Arica
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources_count_spec
+
+import (
+	"reactive-tech.io/kubegres/controllers/ctx"
+	"reactive-tech.io/kubegres/controllers/spec/template"
+	"reactive-tech.io/kubegres/controllers/states"
+)
+
+// PasswordsSecretCountSpecEnforcer deploys the Secret containing the generated passwords when 'spec.passwords.generate'
+// is true. Once deployed, the Secret is never replaced, so that the passwords of an existing database stay valid.
+// It is deleted by Kubernetes with the Kubegres resource which owns it.
+type PasswordsSecretCountSpecEnforcer struct {
+	kubegresContext  ctx.KubegresContext
+	resourcesStates  states.ResourcesStates
+	resourcesCreator template.ResourcesCreatorFromTemplate
+}
+
+func CreatePasswordsSecretCountSpecEnforcer(kubegresContext ctx.KubegresContext,
+	resourcesStates states.ResourcesStates,
+	resourcesCreator template.ResourcesCreatorFromTemplate) PasswordsSecretCountSpecEnforcer {
+
+	return PasswordsSecretCountSpecEnforcer{
+		kubegresContext:  kubegresContext,
+		resourcesStates:  resourcesStates,
+		resourcesCreator: resourcesCreator,
+	}
+}
+
+func (r *PasswordsSecretCountSpecEnforcer) EnforceSpec() error {
+
+	if !r.isGenerationEnabled() || r.isPasswordsSecretDeployed() {
+		return nil
+	}
+
+	passwordsSecret, err := r.resourcesCreator.CreatePasswordsSecret()
+	if err != nil {
+		r.kubegresContext.Log.ErrorEvent("PasswordsSecretTemplateErr", err,
+			"Unable to create a Passwords Secret object from template.",
+			"Secret name", r.resourcesStates.Passwords.Generated.Name)
+		return err
+	}
+
+	if err := r.kubegresContext.Client.Create(r.kubegresContext.Ctx, &passwordsSecret); err != nil {
+		r.kubegresContext.Log.ErrorEvent("PasswordsSecretDeploymentErr", err,
+			"Unable to deploy Passwords Secret.",
+			"Secret name", passwordsSecret.Name)
+		return err
+	}
+
+	r.kubegresContext.Log.InfoEvent("PasswordsSecretDeployment", "Deployed Passwords Secret with generated passwords.",
+		"Secret name", passwordsSecret.Name)
+	return nil
+}
+
+func (r *PasswordsSecretCountSpecEnforcer) isGenerationEnabled() bool {
+	return r.kubegresContext.Kubegres.Spec.Passwords.Generate
+}
+
+func (r *PasswordsSecretCountSpecEnforcer) isPasswordsSecretDeployed() bool {
+	return r.resourcesStates.Passwords.Generated.IsDeployed
+}
@@ -39,6 +39,8 @@ type PrimaryDbCountSpecEnforcer struct {
 	resourcesStates          states.ResourcesStates
 	resourcesCreator         template.ResourcesCreatorFromTemplate
 	primaryToReplicaFailOver failover.PrimaryToReplicaFailOver
+	primarySwitchover        failover.PrimarySwitchover
+	replicaPromotion         failover.ReplicaPromotion
 	blockingOperation        *operation.BlockingOperation
 }
 
@@ -47,7 +49,8 @@ func CreatePrimaryDbCountSpecEnforcer(
 	resourcesStates states.ResourcesStates,
 	resourcesCreator template.ResourcesCreatorFromTemplate,
 	blockingOperation *operation.BlockingOperation,
-	primaryToReplicaFailOver failover.PrimaryToReplicaFailOver) PrimaryDbCountSpecEnforcer {
+	primaryToReplicaFailOver failover.PrimaryToReplicaFailOver,
+	primarySwitchover failover.PrimarySwitchover) PrimaryDbCountSpecEnforcer {
 
 	return PrimaryDbCountSpecEnforcer{
 		kubegresContext:          kubegresContext,
@@ -55,6 +58,8 @@ func CreatePrimaryDbCountSpecEnforcer(
 		resourcesCreator:         resourcesCreator,
 		blockingOperation:        blockingOperation,
 		primaryToReplicaFailOver: primaryToReplicaFailOver,
+		primarySwitchover:        primarySwitchover,
+		replicaPromotion:         failover.CreateReplicaPromotion(kubegresContext, resourcesStates),
 	}
 }
 
@@ -79,6 +84,14 @@ func (r *PrimaryDbCountSpecEnforcer) Enforce() error {
 	// added in Kubegres' status from version 1.8
 	r.initialiseStatusEnforcedReplicas()
 
+	if err := r.labelRecreatedPrimaryPod(); err != nil {
+		return err
+	}
+
+	if r.primarySwitchover.ShouldWeSwitchover() {
+		return r.primarySwitchover.Switchover()
+	}
+
 	if r.blockingOperation.IsActiveOperationIdDifferentOf(operation.OperationIdPrimaryDbCountSpecEnforcement) {
 		return nil
 	}
@@ -114,6 +127,31 @@ func (r *PrimaryDbCountSpecEnforcer) initialiseStatusEnforcedReplicas() {
 	}
 }
 
+// labelRecreatedPrimaryPod labels the Pod of the Primary as Primary when it was recreated from the template of
+// a Replica promoted online, which is still labelled as Replica. A fenced Pod is left as it is.
+func (r *PrimaryDbCountSpecEnforcer) labelRecreatedPrimaryPod() error {
+
+	primary := r.resourcesStates.StatefulSets.Primary
+	if !primary.IsDeployed || !primary.Pod.IsDeployed {
+		return nil
+	}
+
+	replicationRole := primary.Pod.Pod.Labels["replicationRole"]
+	if replicationRole != ctx.ReplicaRoleName && replicationRole != ctx.LaggingReplicaRoleName {
+		return nil
+	}
+
+	if err := r.replicaPromotion.LabelPodAsPrimary(primary.Pod); err != nil {
+		r.kubegresContext.Log.ErrorEvent("PrimaryPodLabelErr", err,
+			"Unable to label the Pod of the Primary as Primary.", "Pod name", primary.Pod.Pod.Name)
+		return err
+	}
+
+	r.kubegresContext.Log.InfoEvent("PrimaryPodLabelled",
+		"Labelled the recreated Pod of the Primary as Primary.", "Pod name", primary.Pod.Pod.Name)
+	return nil
+}
+
 func (r *PrimaryDbCountSpecEnforcer) hasLastPrimaryCountSpecEnforcementAttemptTimedOut() bool {
 	return r.blockingOperation.HasActiveOperationIdTimedOut(operation.OperationIdPrimaryDbCountSpecEnforcement)
 }
@@ -233,8 +271,7 @@ func (r *PrimaryDbCountSpecEnforcer) activateBlockingOperation(statefulSetInstan
 func (r *PrimaryDbCountSpecEnforcer) getLastDeployedPrimaryPvc() *v1.PersistentVolumeClaim {
 
 	lastCreatedInstanceIndex := r.kubegresContext.Status.GetLastCreatedInstanceIndex()
-	kubegresName := r.kubegresContext.Kubegres.Name
-	resourceName := "postgres-db-" + kubegresName + "-" + strconv.Itoa(int(lastCreatedInstanceIndex)) + "-0"
+	resourceName := r.kubegresContext.GetDatabasePvcName(lastCreatedInstanceIndex)
 
 	namespace := r.kubegresContext.Kubegres.Namespace
 	resourceKey := client.ObjectKey{Namespace: namespace, Name: resourceName}
@@ -25,12 +25,17 @@ import (
 	"strconv"
 
 	v1 "k8s.io/api/apps/v1"
+	core "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	postgresV1 "reactive-tech.io/kubegres/api/v1"
 	"reactive-tech.io/kubegres/controllers/ctx"
 	"reactive-tech.io/kubegres/controllers/operation"
+	"reactive-tech.io/kubegres/controllers/postgres"
 	"reactive-tech.io/kubegres/controllers/spec/template"
 	"reactive-tech.io/kubegres/controllers/states"
 	"reactive-tech.io/kubegres/controllers/states/statefulset"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 type ReplicaDbCountSpecEnforcer struct {
@@ -38,6 +43,7 @@ type ReplicaDbCountSpecEnforcer struct {
 	resourcesStates   states.ResourcesStates
 	resourcesCreator  template.ResourcesCreatorFromTemplate
 	blockingOperation *operation.BlockingOperation
+	dbConnector       postgres.DbConnector
 }
 
 func CreateReplicaDbCountSpecEnforcer(
@@ -51,6 +57,7 @@ func CreateReplicaDbCountSpecEnforcer(
 		resourcesStates:   resourcesStates,
 		resourcesCreator:  resourcesCreator,
 		blockingOperation: blockingOperation,
+		dbConnector:       postgres.CreateDbConnector(kubegresContext),
 	}
 }
 
@@ -59,7 +66,7 @@ func (r *ReplicaDbCountSpecEnforcer) CreateOperationConfigForReplicaDbDeploying(
 	return operation.BlockingOperationConfig{
 		OperationId:       operation.OperationIdReplicaDbCountSpecEnforcement,
 		StepId:            operation.OperationStepIdReplicaDbDeploying,
-		TimeOutInSeconds:  300,
+		TimeOutInSeconds:  r.kubegresContext.GetReplicaDeployingTimeoutSeconds(),
 		CompletionChecker: r.isReplicaDbReady,
 	}
 }
@@ -69,7 +76,7 @@ func (r *ReplicaDbCountSpecEnforcer) CreateOperationConfigForReplicaDbUndeployin
 	return operation.BlockingOperationConfig{
 		OperationId:       operation.OperationIdReplicaDbCountSpecEnforcement,
 		StepId:            operation.OperationStepIdReplicaDbUndeploying,
-		TimeOutInSeconds:  60,
+		TimeOutInSeconds:  r.kubegresContext.GetReplicaUndeployingTimeoutSeconds(),
 		CompletionChecker: r.isReplicaDbUndeployed,
 	}
 }
@@ -105,6 +112,8 @@ func (r *ReplicaDbCountSpecEnforcer) Enforce() error {
 		return nil
 	}
 
+	r.enforceFailedPrimaryPvcPolicy()
+
 	// Check if the number of deployed replicas == spec, if not then deploy one
 	nbreNewReplicaToDeploy := r.getExpectedNbreReplicasToDeploy() - r.getNbreDeployedReplicas()
 
@@ -132,6 +141,25 @@ func (r *ReplicaDbCountSpecEnforcer) Enforce() error {
 		}
 	}
 
+	return r.enforceDelayedReplicasCount()
+}
+
+// enforceDelayedReplicasCount deploys or undeploys a delayed Replica once the Replicas are as expected, so that a
+// delayed Replica never delays the deployment of a Replica which can be promoted. Unlike a Replica, a delayed Replica
+// which is not ready is not replaced, as a new delayed Replica would not have the past data anymore.
+func (r *ReplicaDbCountSpecEnforcer) enforceDelayedReplicasCount() error {
+
+	nbreNewDelayedReplicaToDeploy := int32(len(r.kubegresContext.Kubegres.Spec.Replication.DelayedReplicas)) -
+		r.resourcesStates.StatefulSets.DelayedReplicas.NbreDeployed
+
+	if nbreNewDelayedReplicaToDeploy > 0 {
+		return r.deployDelayedReplicaStatefulSet()
+
+	} else if nbreNewDelayedReplicaToDeploy < 0 {
+		delayedReplicasToUndeploy := r.resourcesStates.StatefulSets.DelayedReplicas.All.GetAllReverseSortedByInstanceIndex()
+		return r.undeployDelayedReplicaStatefulSet(delayedReplicasToUndeploy[0])
+	}
+
 	return nil
 }
 
@@ -147,6 +175,14 @@ func (r *ReplicaDbCountSpecEnforcer) getDeployedReplicas() []statefulset.Statefu
 	return r.resourcesStates.StatefulSets.Replicas.All.GetAllSortedByInstanceIndex()
 }
 
+// getReplicaByInstanceIndex returns a Replica or a delayed Replica, as both are deployed and undeployed by this enforcer.
+func (r *ReplicaDbCountSpecEnforcer) getReplicaByInstanceIndex(instanceIndex int32) (statefulset.StatefulSetWrapper, error) {
+	if delayedReplica, err := r.resourcesStates.StatefulSets.DelayedReplicas.All.GetByInstanceIndex(instanceIndex); err == nil {
+		return delayedReplica, nil
+	}
+	return r.resourcesStates.StatefulSets.Replicas.All.GetByInstanceIndex(instanceIndex)
+}
+
 func (r *ReplicaDbCountSpecEnforcer) getNbreDeployedReplicas() int32 {
 	return r.resourcesStates.StatefulSets.Replicas.NbreDeployed
 }
@@ -171,7 +207,7 @@ func (r *ReplicaDbCountSpecEnforcer) hasLastAttemptTimedOut() bool {
 func (r *ReplicaDbCountSpecEnforcer) isPreviouslyFailedAttemptOnReplicaDbFixed() bool {
 	activeOperation := r.blockingOperation.GetActiveOperation()
 	replicaInstanceIndex := activeOperation.StatefulSetOperation.InstanceIndex
-	replica, err := r.resourcesStates.StatefulSets.Replicas.All.GetByInstanceIndex(replicaInstanceIndex)
+	replica, err := r.getReplicaByInstanceIndex(replicaInstanceIndex)
 
 	return err != nil || replica.IsReady
 }
@@ -220,8 +256,18 @@ func (r *ReplicaDbCountSpecEnforcer) doesSpecRequireTheDeploymentOfAdditionalRep
 
 func (r *ReplicaDbCountSpecEnforcer) resetInSpecManualFailover() error {
 	r.kubegresContext.Log.Info("Resetting the field 'failover.promotePod' in spec.")
+
+	// We only patch that field so that the default values set in memory in the spec are not persisted
+	kubegresToPatch := r.kubegresContext.Kubegres.DeepCopy()
+	patch := client.MergeFrom(kubegresToPatch.DeepCopy())
+	kubegresToPatch.Spec.Failover.PromotePod = ""
+	if err := r.kubegresContext.Client.Patch(r.kubegresContext.Ctx, kubegresToPatch, patch); err != nil {
+		return err
+	}
+
 	r.kubegresContext.Kubegres.Spec.Failover.PromotePod = ""
-	return r.kubegresContext.Client.Update(r.kubegresContext.Ctx, r.kubegresContext.Kubegres)
+	r.kubegresContext.Kubegres.ResourceVersion = kubegresToPatch.ResourceVersion
+	return nil
 }
 
 func (r *ReplicaDbCountSpecEnforcer) isPrimaryDbReady() bool {
@@ -231,7 +277,7 @@ func (r *ReplicaDbCountSpecEnforcer) isPrimaryDbReady() bool {
 func (r *ReplicaDbCountSpecEnforcer) isReplicaDbReady(operation postgresV1.KubegresBlockingOperation) bool {
 
 	statefulSetInstanceIndex := operation.StatefulSetOperation.InstanceIndex
-	statefulSetWrapper, err := r.resourcesStates.StatefulSets.Replicas.All.GetByInstanceIndex(statefulSetInstanceIndex)
+	statefulSetWrapper, err := r.getReplicaByInstanceIndex(statefulSetInstanceIndex)
 	if err != nil {
 		r.kubegresContext.Log.InfoEvent("A replica StatefulSet's instanceIndex does not exist. As a result "+
 			"we will return false inside a blocking operation completion checker 'isReplicaDbReady()'",
@@ -244,13 +290,13 @@ func (r *ReplicaDbCountSpecEnforcer) isReplicaDbReady(operation postgresV1.Kubeg
 
 func (r *ReplicaDbCountSpecEnforcer) isReplicaDbUndeployed(operation postgresV1.KubegresBlockingOperation) bool {
 	statefulSetInstanceIndex := operation.StatefulSetOperation.InstanceIndex
-	_, err := r.resourcesStates.StatefulSets.Replicas.All.GetByInstanceIndex(statefulSetInstanceIndex)
+	_, err := r.getReplicaByInstanceIndex(statefulSetInstanceIndex)
 	return err != nil
 }
 
 func (r *ReplicaDbCountSpecEnforcer) deployReplicaStatefulSet() error {
 
-	instanceIndex := r.kubegresContext.Status.GetLastCreatedInstanceIndex() + 1
+	instanceIndex, isFailedPrimaryPvcReused := r.getInstanceIndexOfReplicaToDeploy()
 
 	err := r.activateBlockingOperationForDeployment(instanceIndex)
 	if err != nil {
@@ -265,6 +311,12 @@ func (r *ReplicaDbCountSpecEnforcer) deployReplicaStatefulSet() error {
 		return err
 	}
 
+	if isFailedPrimaryPvcReused {
+		r.resourcesCreator.SetReplicaToRewindFormerPrimary(&replicaStatefulSet)
+	} else {
+		r.resourcesCreator.SetReplicaUpstreamHostName(&replicaStatefulSet, r.resourcesStates.Cascade.GetUpstreamHostNameOfNewReplica())
+	}
+
 	r.kubegresContext.Log.Info("Deploying Replica statefulSet '" + replicaStatefulSet.Name + "'")
 	err = r.kubegresContext.Client.Create(r.kubegresContext.Ctx, &replicaStatefulSet)
 	if err != nil {
@@ -275,11 +327,140 @@ func (r *ReplicaDbCountSpecEnforcer) deployReplicaStatefulSet() error {
 
 	r.kubegresContext.Status.SetEnforcedReplicas(r.kubegresContext.Kubegres.Status.EnforcedReplicas + 1)
 
+	if isFailedPrimaryPvcReused {
+		r.kubegresContext.Status.SetFailedPrimaryInstanceIndex(0)
+		r.kubegresContext.Log.InfoEvent("FailedPrimaryPvcReused", "Deployed Replica StatefulSet reusing the PVC of the Primary "+
+			"replaced by the last failover. Its data will be rewound with 'pg_rewind'.",
+			"Replica name", replicaStatefulSet.Name, "PVC name", r.kubegresContext.GetDatabasePvcName(instanceIndex))
+		return nil
+	}
+
 	r.kubegresContext.Status.SetLastCreatedInstanceIndex(instanceIndex)
 	r.kubegresContext.Log.InfoEvent("ReplicaStatefulSetDeployment", "Deployed Replica StatefulSet.", "Replica name", replicaStatefulSet.Name)
 	return nil
 }
 
+func (r *ReplicaDbCountSpecEnforcer) deployDelayedReplicaStatefulSet() error {
+
+	instanceIndex := r.kubegresContext.Status.GetLastCreatedInstanceIndex() + 1
+
+	err := r.activateBlockingOperationForDeployment(instanceIndex)
+	if err != nil {
+		r.kubegresContext.Log.ErrorEvent("ReplicaStatefulSetOperationActivationErr", err, "Error while activating blocking operation for the deployment of a delayed Replica StatefulSet.", "InstanceIndex", instanceIndex)
+		return err
+	}
+
+	delayedReplicaStatefulSet, err := r.resourcesCreator.CreateDelayedReplicaStatefulSet(instanceIndex)
+	if err != nil {
+		r.kubegresContext.Log.ErrorEvent("ReplicaStatefulSetTemplateErr", err, "Error while creating a delayed Replica StatefulSet object from template.", "InstanceIndex", instanceIndex)
+		r.blockingOperation.RemoveActiveOperation()
+		return err
+	}
+
+	r.kubegresContext.Log.Info("Deploying delayed Replica statefulSet '" + delayedReplicaStatefulSet.Name + "'")
+	err = r.kubegresContext.Client.Create(r.kubegresContext.Ctx, &delayedReplicaStatefulSet)
+	if err != nil {
+		r.kubegresContext.Log.ErrorEvent("ReplicaStatefulSetDeploymentErr", err, "Unable to deploy delayed Replica StatefulSet.", "Replica name", delayedReplicaStatefulSet.Name)
+		r.blockingOperation.RemoveActiveOperation()
+		return err
+	}
+
+	r.kubegresContext.Status.SetLastCreatedInstanceIndex(instanceIndex)
+	r.kubegresContext.Log.InfoEvent("DelayedReplicaStatefulSetDeployment", "Deployed delayed Replica StatefulSet.", "Replica name", delayedReplicaStatefulSet.Name)
+	return nil
+}
+
+func (r *ReplicaDbCountSpecEnforcer) undeployDelayedReplicaStatefulSet(delayedReplicaToUndeploy statefulset.StatefulSetWrapper) error {
+
+	r.kubegresContext.Log.Info("We are going to undeploy a delayed Replica statefulSet.", "InstanceIndex", delayedReplicaToUndeploy.InstanceIndex)
+
+	err := r.activateBlockingOperationForUndeployment(delayedReplicaToUndeploy.InstanceIndex)
+	if err != nil {
+		r.kubegresContext.Log.ErrorEvent("ReplicaStatefulSetOperationActivationErr", err, "Error while activating blocking operation for the undeployment of a delayed Replica StatefulSet.", "InstanceIndex", delayedReplicaToUndeploy.InstanceIndex)
+		return err
+	}
+
+	err = r.deleteStatefulSet(delayedReplicaToUndeploy.StatefulSet)
+	if err != nil {
+		r.blockingOperation.RemoveActiveOperation()
+		return err
+	}
+
+	r.dropReplicationSlot(delayedReplicaToUndeploy)
+
+	return nil
+}
+
+// getInstanceIndexOfReplicaToDeploy returns the instance index of the Primary replaced by the last failover if its PVC
+// has to be reused as set in 'failover.pvc'. Otherwise, it returns a new instance index.
+func (r *ReplicaDbCountSpecEnforcer) getInstanceIndexOfReplicaToDeploy() (instanceIndex int32, isFailedPrimaryPvcReused bool) {
+
+	failedPrimaryInstanceIndex := r.kubegresContext.Status.GetFailedPrimaryInstanceIndex()
+	if failedPrimaryInstanceIndex > 0 && r.getFailoverPvcPolicy() == postgresV1.FailoverPvcPolicyReuse {
+
+		if r.doesFailedPrimaryPvcExist(failedPrimaryInstanceIndex) {
+			return failedPrimaryInstanceIndex, true
+		}
+
+		r.kubegresContext.Log.Info("The PVC of the Primary replaced by the last failover does not exist anymore. "+
+			"A new PVC will be created.", "PVC name", r.kubegresContext.GetDatabasePvcName(failedPrimaryInstanceIndex))
+		r.kubegresContext.Status.SetFailedPrimaryInstanceIndex(0)
+	}
+
+	return r.kubegresContext.Status.GetLastCreatedInstanceIndex() + 1, false
+}
+
+// enforceFailedPrimaryPvcPolicy handles the PVC of the Primary replaced by the last failover, as set in 'failover.pvc'.
+// With the policy 'reuse', the PVC is handled when the next Replica is deployed.
+func (r *ReplicaDbCountSpecEnforcer) enforceFailedPrimaryPvcPolicy() {
+
+	failedPrimaryInstanceIndex := r.kubegresContext.Status.GetFailedPrimaryInstanceIndex()
+	if failedPrimaryInstanceIndex == 0 {
+		return
+	}
+
+	if _, err := r.resourcesStates.StatefulSets.All.GetByInstanceIndex(failedPrimaryInstanceIndex); err == nil {
+		r.kubegresContext.Status.SetFailedPrimaryInstanceIndex(0)
+		return
+	}
+
+	pvcName := r.kubegresContext.GetDatabasePvcName(failedPrimaryInstanceIndex)
+
+	switch r.getFailoverPvcPolicy() {
+
+	case postgresV1.FailoverPvcPolicyReuse:
+		return
+
+	case postgresV1.FailoverPvcPolicyDelete:
+		pvc := &core.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: pvcName, Namespace: r.kubegresContext.Kubegres.Namespace}}
+		err := r.kubegresContext.Client.Delete(r.kubegresContext.Ctx, pvc)
+		if err != nil && !apierrors.IsNotFound(err) {
+			r.kubegresContext.Log.ErrorEvent("FailedPrimaryPvcDeletionErr", err,
+				"Unable to delete the PVC of the Primary replaced by the last failover.", "PVC name", pvcName)
+			return
+		}
+		r.kubegresContext.Log.InfoEvent("FailedPrimaryPvcDeleted",
+			"Deleted the PVC of the Primary replaced by the last failover.", "PVC name", pvcName)
+
+	default:
+		r.kubegresContext.Log.InfoEvent("FailedPrimaryPvcRetained",
+			"Retained the PVC of the Primary replaced by the last failover. It must be deleted manually.", "PVC name", pvcName)
+	}
+
+	r.kubegresContext.Status.SetFailedPrimaryInstanceIndex(0)
+}
+
+func (r *ReplicaDbCountSpecEnforcer) doesFailedPrimaryPvcExist(failedPrimaryInstanceIndex int32) bool {
+	pvcKey := client.ObjectKey{Namespace: r.kubegresContext.Kubegres.Namespace, Name: r.kubegresContext.GetDatabasePvcName(failedPrimaryInstanceIndex)}
+	pvc := &core.PersistentVolumeClaim{}
+	err := r.kubegresContext.Client.Get(r.kubegresContext.Ctx, pvcKey, pvc)
+	return err == nil && pvc.DeletionTimestamp == nil
+}
+
+func (r *ReplicaDbCountSpecEnforcer) getFailoverPvcPolicy() string {
+	return r.kubegresContext.Kubegres.Spec.Failover.Pvc
+}
+
 func (r *ReplicaDbCountSpecEnforcer) activateBlockingOperationForDeployment(statefulSetInstanceIndex int32) error {
 	return r.blockingOperation.ActivateOperationOnStatefulSet(operation.OperationIdReplicaDbCountSpecEnforcement,
 		operation.OperationStepIdReplicaDbDeploying,
@@ -314,9 +495,50 @@ func (r *ReplicaDbCountSpecEnforcer) undeployReplicaStatefulSets(replicaToUndepl
 
 	r.kubegresContext.Status.SetEnforcedReplicas(r.kubegresContext.Kubegres.Status.EnforcedReplicas - 1)
 
+	r.dropReplicationSlot(replicaToUndeploy)
+
 	return nil
 }
 
+// dropReplicationSlot drops the replication slot of an undeployed Replica on its upstream instance. If the Replica
+// is still streaming with it, the slot is dropped by ReplicationSlotsSpecEnforcer once it is not used anymore.
+func (r *ReplicaDbCountSpecEnforcer) dropReplicationSlot(undeployedReplica statefulset.StatefulSetWrapper) {
+
+	if !r.kubegresContext.IsReplicationSlotsEnabled() {
+		return
+	}
+
+	upstreamInstance := r.resourcesStates.StatefulSets.Primary
+	if upstreamReplica, exists := r.resourcesStates.Cascade.GetUpstreamReplica(undeployedReplica.InstanceIndex); exists {
+		upstreamInstance = upstreamReplica
+	}
+
+	if !upstreamInstance.Pod.IsReady {
+		return
+	}
+
+	slotName := r.kubegresContext.GetReplicationSlotName(undeployedReplica.InstanceIndex)
+	connection, err := r.dbConnector.ConnectAsSuperUser(upstreamInstance.Pod.Pod)
+	if err != nil {
+		r.kubegresContext.Log.ErrorEvent("ReplicationSlotsErr", err, "Unable to connect to an instance in order to drop "+
+			"the replication slot of an undeployed Replica.", "Pod name", upstreamInstance.Pod.Pod.Name, "Slot name", slotName)
+		return
+	}
+	defer connection.Close()
+
+	isDropped, err := postgres.DropInactiveReplicationSlot(connection, slotName)
+	if err != nil {
+		r.kubegresContext.Log.ErrorEvent("ReplicationSlotsErr", err, "Unable to drop the replication slot of an undeployed Replica.",
+			"Pod name", upstreamInstance.Pod.Pod.Name, "Slot name", slotName)
+		return
+	}
+
+	if isDropped {
+		r.kubegresContext.Log.InfoEvent("ReplicationSlotDropped", "Dropped the replication slot of an undeployed Replica.",
+			"Pod name", upstreamInstance.Pod.Pod.Name, "Slot name", slotName)
+	}
+}
+
 func (r *ReplicaDbCountSpecEnforcer) getReplicaToUndeploy() statefulset.StatefulSetWrapper {
 
 	replicasToUndeploy := r.getReplicasReverseSortedByInstanceIndex()
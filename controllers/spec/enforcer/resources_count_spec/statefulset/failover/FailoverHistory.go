@@ -0,0 +1,181 @@
+/*
+Copyright 2021 Reactive Tech Limited.
+"Reactive Tech Limited" is a company located in England, United Kingdom.
+https://www.reactive-tech.io
+
+Lead Developer: Alex Arica
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package failover
+
+import (
+	"reflect"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	v1 "reactive-tech.io/kubegres/api/v1"
+	"reactive-tech.io/kubegres/controllers/ctx"
+)
+
+// FailoverHistory records the failovers and the switchovers in the status field 'failoverHistory', so that they
+// can be reviewed once the Kubernetes events have expired. Only the last 'FailoverHistoryMaxLength' records are kept.
+// Only the most recent record is updated and only while its outcome is 'InProgress'.
+// The automatic failovers are also recorded in the status field 'failoverSuspension.automaticFailovers', where they
+// are counted by FailoverRateLimit. Only the last 'AutomaticFailoversMaxLength' of them are kept.
+type FailoverHistory struct {
+	kubegresContext ctx.KubegresContext
+}
+
+const (
+	FailoverHistoryMaxLength = 10
+	// It must not be lower than the maximum of the field 'failover.maximumFailovers'
+	AutomaticFailoversMaxLength = 10
+)
+
+func CreateFailoverHistory(kubegresContext ctx.KubegresContext) FailoverHistory {
+	return FailoverHistory{kubegresContext: kubegresContext}
+}
+
+// RecordStart adds a record in progress. If the previous record is still in progress, its outcome is set to 'Failed'
+// as it was interrupted.
+func (r *FailoverHistory) RecordStart(reason, formerPrimary, newPrimary string) {
+
+	r.RecordEnd(v1.FailoverOutcomeFailed)
+
+	startTime := metav1.Now()
+	history := append(r.getHistoryCopy(), v1.KubegresFailoverRecord{
+		FormerPrimary: formerPrimary,
+		NewPrimary:    newPrimary,
+		Reason:        reason,
+		StartTime:     startTime,
+		Outcome:       v1.FailoverOutcomeInProgress,
+	})
+
+	if len(history) > FailoverHistoryMaxLength {
+		history = history[len(history)-FailoverHistoryMaxLength:]
+	}
+
+	r.kubegresContext.Status.SetFailoverHistory(history)
+
+	if reason == v1.FailoverReasonPrimaryUnhealthy {
+		r.recordAutomaticFailoverStart(startTime)
+	}
+}
+
+// RecordNewPrimary sets the Pod of the promoted Replica, in case another Replica than the one initially selected
+// was promoted.
+func (r *FailoverHistory) RecordNewPrimary(newPrimary string) {
+	r.updateRecordInProgress(func(record *v1.KubegresFailoverRecord) {
+		record.NewPrimary = newPrimary
+	})
+}
+
+func (r *FailoverHistory) RecordEnd(outcome string) {
+	r.updateRecordInProgress(func(record *v1.KubegresFailoverRecord) {
+		now := metav1.Now()
+		record.Outcome = outcome
+		record.EndTime = &now
+	})
+}
+
+// RecordTimedOut flags the record in progress as timed-out. If the given outcome is not empty, the record is ended
+// with it. Otherwise, the record stays in progress as it can still complete once fixed manually.
+func (r *FailoverHistory) RecordTimedOut(outcome string) {
+	r.updateRecordInProgress(func(record *v1.KubegresFailoverRecord) {
+		record.HasTimedOut = true
+		if outcome != "" {
+			now := metav1.Now()
+			record.Outcome = outcome
+			record.EndTime = &now
+		}
+	})
+}
+
+// IsInProgress returns whether the most recent failover or switchover has not ended yet.
+func (r *FailoverHistory) IsInProgress() bool {
+	history := r.kubegresContext.Status.GetFailoverHistory()
+	return len(history) > 0 && history[len(history)-1].Outcome == v1.FailoverOutcomeInProgress
+}
+
+func (r *FailoverHistory) updateRecordInProgress(update func(record *v1.KubegresFailoverRecord)) {
+
+	history := r.getHistoryCopy()
+	if len(history) == 0 {
+		return
+	}
+
+	lastRecord := &history[len(history)-1]
+	if lastRecord.Outcome != v1.FailoverOutcomeInProgress {
+		return
+	}
+
+	updatedRecord := *lastRecord.DeepCopy()
+	update(&updatedRecord)
+	if reflect.DeepEqual(*lastRecord, updatedRecord) {
+		return
+	}
+
+	*lastRecord = updatedRecord
+	r.kubegresContext.Status.SetFailoverHistory(history)
+
+	if updatedRecord.Reason == v1.FailoverReasonPrimaryUnhealthy && updatedRecord.EndTime != nil {
+		r.recordAutomaticFailoverEnd(updatedRecord.StartTime, *updatedRecord.EndTime)
+	}
+}
+
+func (r *FailoverHistory) recordAutomaticFailoverStart(startTime metav1.Time) {
+
+	suspension := r.kubegresContext.Status.GetFailoverSuspension()
+	automaticFailovers := append(r.getAutomaticFailoversCopy(suspension), v1.KubegresAutomaticFailover{StartTime: startTime})
+
+	if len(automaticFailovers) > AutomaticFailoversMaxLength {
+		automaticFailovers = automaticFailovers[len(automaticFailovers)-AutomaticFailoversMaxLength:]
+	}
+
+	suspension.AutomaticFailovers = automaticFailovers
+	r.kubegresContext.Status.SetFailoverSuspension(suspension)
+}
+
+// recordAutomaticFailoverEnd sets the end time of the automatic failover which started at the given time. It is not
+// found if the automatic failovers were acknowledged since it started.
+func (r *FailoverHistory) recordAutomaticFailoverEnd(startTime, endTime metav1.Time) {
+
+	suspension := r.kubegresContext.Status.GetFailoverSuspension()
+	automaticFailovers := r.getAutomaticFailoversCopy(suspension)
+
+	for i := range automaticFailovers {
+		if automaticFailovers[i].StartTime.Equal(&startTime) && automaticFailovers[i].EndTime == nil {
+			automaticFailovers[i].EndTime = &endTime
+			suspension.AutomaticFailovers = automaticFailovers
+			r.kubegresContext.Status.SetFailoverSuspension(suspension)
+			return
+		}
+	}
+}
+
+func (r *FailoverHistory) getAutomaticFailoversCopy(suspension v1.KubegresFailoverSuspension) []v1.KubegresAutomaticFailover {
+	var automaticFailovers []v1.KubegresAutomaticFailover
+	for _, automaticFailover := range suspension.AutomaticFailovers {
+		automaticFailovers = append(automaticFailovers, *automaticFailover.DeepCopy())
+	}
+	return automaticFailovers
+}
+
+func (r *FailoverHistory) getHistoryCopy() []v1.KubegresFailoverRecord {
+	var history []v1.KubegresFailoverRecord
+	for _, record := range r.kubegresContext.Status.GetFailoverHistory() {
+		history = append(history, *record.DeepCopy())
+	}
+	return history
+}
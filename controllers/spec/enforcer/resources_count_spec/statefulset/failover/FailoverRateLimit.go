@@ -0,0 +1,158 @@
+/*
+Copyright 2021 Reactive Tech Limited.
+"Reactive Tech Limited" is a company located in England, United Kingdom.
+https://www.reactive-tech.io
+
+Lead Developer: Alex Arica
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package failover
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	v1 "reactive-tech.io/kubegres/api/v1"
+	"reactive-tech.io/kubegres/controllers/ctx"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// FailoverRateLimit suspends the automatic failovers when they happen too often, as set in the fields
+// 'failover.minimumIntervalSeconds' and 'failover.maximumFailovers'. It prevents a Primary which keeps failing,
+// for instance because of a faulty Node, from causing a succession of failovers. The automatic failovers stay
+// suspended until a human acknowledges it by setting the annotation 'FailoverSuspensionAckAnnotationKey'.
+// The failovers are counted from the status field 'failoverSuspension.automaticFailovers', which is reset on each
+// acknowledgement.
+type FailoverRateLimit struct {
+	kubegresContext ctx.KubegresContext
+}
+
+func CreateFailoverRateLimit(kubegresContext ctx.KubegresContext) FailoverRateLimit {
+	return FailoverRateLimit{kubegresContext: kubegresContext}
+}
+
+func (r *FailoverRateLimit) IsSuspended() bool {
+	return r.kubegresContext.Status.GetFailoverSuspension().IsSuspended
+}
+
+// GetSuspensionReason returns why a new automatic failover would exceed the rate limit, or an empty string if it can
+// happen. Only the automatic failovers which started after the last acknowledgement are counted.
+func (r *FailoverRateLimit) GetSuspensionReason() string {
+
+	failovers := r.kubegresContext.Status.GetFailoverSuspension().AutomaticFailovers
+	if len(failovers) == 0 {
+		return ""
+	}
+
+	if minimumIntervalSeconds := r.kubegresContext.Kubegres.Spec.Failover.MinimumIntervalSeconds; minimumIntervalSeconds != nil {
+		lastFailover := failovers[len(failovers)-1]
+		lastFailoverTime := lastFailover.StartTime.Time
+		if lastFailover.EndTime != nil {
+			lastFailoverTime = lastFailover.EndTime.Time
+		}
+
+		if time.Since(lastFailoverTime) < time.Duration(*minimumIntervalSeconds)*time.Second {
+			return v1.FailoverSuspensionReasonMinimumIntervalNotElapsed
+		}
+	}
+
+	if maximumFailovers := r.kubegresContext.Kubegres.Spec.Failover.MaximumFailovers; maximumFailovers != nil {
+		window := time.Duration(r.kubegresContext.GetMaximumFailoversWindowSeconds()) * time.Second
+
+		var nbreFailoversInWindow int32 = 0
+		for _, failover := range failovers {
+			if time.Since(failover.StartTime.Time) < window {
+				nbreFailoversInWindow++
+			}
+		}
+
+		if nbreFailoversInWindow >= *maximumFailovers {
+			return v1.FailoverSuspensionReasonMaximumFailoversReached
+		}
+	}
+
+	return ""
+}
+
+func (r *FailoverRateLimit) Suspend(reason string) {
+
+	now := metav1.Now()
+	suspension := r.kubegresContext.Status.GetFailoverSuspension()
+	suspension.IsSuspended = true
+	suspension.SuspendedTime = &now
+	suspension.Reason = reason
+	r.kubegresContext.Status.SetFailoverSuspension(suspension)
+
+	r.kubegresContext.Log.ErrorEvent("FailoverSuspended", errors.New(reason),
+		"The automatic failovers are suspended because they happened too often. "+r.getReasonMessage(reason)+" "+
+			"Once the cause is fixed, please set the annotation '"+ctx.FailoverSuspensionAckAnnotationKey+"' "+
+			"on the Kubegres resource to resume the automatic failovers.")
+}
+
+// ApplyAcknowledgement resumes the automatic failovers if the acknowledgement annotation is set on the Kubegres
+// resource. The annotation is then removed, so that it only acknowledges the failovers which happened until now.
+func (r *FailoverRateLimit) ApplyAcknowledgement() {
+
+	if _, isAcknowledged := r.kubegresContext.Kubegres.Annotations[ctx.FailoverSuspensionAckAnnotationKey]; !isAcknowledged {
+		return
+	}
+
+	if err := r.removeAcknowledgementAnnotation(); err != nil {
+		r.kubegresContext.Log.ErrorEvent("FailoverSuspensionAcknowledgementErr", err,
+			"Unable to remove the annotation '"+ctx.FailoverSuspensionAckAnnotationKey+"' from the Kubegres resource.")
+		return
+	}
+
+	wasSuspended := r.IsSuspended()
+	now := metav1.Now()
+	r.kubegresContext.Status.SetFailoverSuspension(v1.KubegresFailoverSuspension{AcknowledgedTime: &now})
+
+	if wasSuspended {
+		r.kubegresContext.Log.InfoEvent("FailoverSuspensionAcknowledged",
+			"The suspension of the automatic failovers was acknowledged. The automatic failovers are resumed.")
+	} else {
+		r.kubegresContext.Log.InfoEvent("FailoverSuspensionAcknowledged",
+			"The automatic failovers were acknowledged. Only the next automatic failovers are counted.")
+	}
+}
+
+func (r *FailoverRateLimit) getReasonMessage(reason string) string {
+	if reason == v1.FailoverSuspensionReasonMinimumIntervalNotElapsed {
+		return "The Primary failed less than " +
+			strconv.FormatInt(*r.kubegresContext.Kubegres.Spec.Failover.MinimumIntervalSeconds, 10) +
+			" seconds after the last failover, as set in the field 'failover.minimumIntervalSeconds'."
+	}
+
+	return "The maximum of " + strconv.Itoa(int(*r.kubegresContext.Kubegres.Spec.Failover.MaximumFailovers)) +
+		" failovers within " + strconv.FormatInt(r.kubegresContext.GetMaximumFailoversWindowSeconds(), 10) +
+		" seconds was reached, as set in the field 'failover.maximumFailovers'."
+}
+
+func (r *FailoverRateLimit) removeAcknowledgementAnnotation() error {
+
+	// We only patch the annotations so that the default values set in memory in the spec are not persisted
+	kubegresToPatch := r.kubegresContext.Kubegres.DeepCopy()
+	patch := client.MergeFrom(kubegresToPatch.DeepCopy())
+	delete(kubegresToPatch.Annotations, ctx.FailoverSuspensionAckAnnotationKey)
+	if err := r.kubegresContext.Client.Patch(r.kubegresContext.Ctx, kubegresToPatch, patch); err != nil {
+		return err
+	}
+
+	delete(r.kubegresContext.Kubegres.Annotations, ctx.FailoverSuspensionAckAnnotationKey)
+	r.kubegresContext.Kubegres.ResourceVersion = kubegresToPatch.ResourceVersion
+	return nil
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2021 Reactive Tech Limited.
+"Reactive Tech Limited" is a company located in England, United Kingdom.
+https://www.reactive-tech.io
+
+Lead Developer: Alex Arica
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package failover
+
+import (
+	"reactive-tech.io/kubegres/controllers/ctx"
+	"reactive-tech.io/kubegres/controllers/states"
+	"reactive-tech.io/kubegres/controllers/states/replication"
+)
+
+// PrimaryFailureQuorum confirms that a Primary whose Pod is not ready has failed when 'failover.detectionMode' is
+// 'replicasQuorum'. The failure is confirmed once at least 'failover.detectionQuorum' ready Replicas have lost their
+// replication connection to the Primary, so that a failover does not happen because of a readiness probe time-out
+// or a network partition between the kubelet and the Pod of the Primary. With a cascading replication, only the
+// Replicas streaming from the Primary are counted, as the other ones are not connected to it.
+type PrimaryFailureQuorum struct {
+	kubegresContext ctx.KubegresContext
+	resourcesStates states.ResourcesStates
+}
+
+func CreatePrimaryFailureQuorum(kubegresContext ctx.KubegresContext, resourcesStates states.ResourcesStates) PrimaryFailureQuorum {
+	return PrimaryFailureQuorum{kubegresContext: kubegresContext, resourcesStates: resourcesStates}
+}
+
+// IsPrimaryFailureConfirmed returns true if the quorum is not required or if it is reached. The quorum is not
+// required when the StatefulSet or the Pod of the Primary does not exist, as the Primary cannot serve requests.
+func (r *PrimaryFailureQuorum) IsPrimaryFailureConfirmed() bool {
+
+	if !r.kubegresContext.IsFailoverDetectedByReplicasQuorum() {
+		return true
+	}
+
+	primary := r.resourcesStates.StatefulSets.Primary
+	if !primary.IsDeployed || !primary.Pod.IsDeployed {
+		return true
+	}
+
+	var nbreReadyReplicas, nbreDisconnectedReplicas int32 = 0, 0
+	for _, replica := range r.resourcesStates.StatefulSets.Replicas.All.GetAllSortedByInstanceIndex() {
+		if !replica.IsReady {
+			continue
+		}
+
+		replicationState := r.resourcesStates.Replication.GetByInstanceIndex(replica.InstanceIndex)
+		if r.isStreamingFromReplica(replicationState) {
+			continue
+		}
+
+		nbreReadyReplicas++
+		if replicationState.IsReachable && !replicationState.IsWalReceiverStreaming {
+			nbreDisconnectedReplicas++
+		}
+	}
+
+	quorum := r.getQuorum(nbreReadyReplicas)
+	if nbreDisconnectedReplicas >= quorum {
+		return true
+	}
+
+	r.kubegresContext.Log.InfoEvent("PrimaryFailureNotConfirmedByReplicas",
+		"The Pod of the Primary is not ready. However, a failover cannot happen yet because not enough Replicas "+
+			"have lost their replication connection to the Primary, as required by 'failover.detectionMode'.",
+		"Primary", primary.Pod.Pod.Name,
+		"Disconnected Replicas", nbreDisconnectedReplicas,
+		"Ready Replicas", nbreReadyReplicas,
+		"Quorum", quorum)
+	return false
+}
+
+func (r *PrimaryFailureQuorum) isStreamingFromReplica(replicationState replication.InstanceReplicationState) bool {
+	return r.kubegresContext.IsCascadingReplicationEnabled() &&
+		replicationState.UpstreamHost != "" &&
+		replicationState.UpstreamHost != r.kubegresContext.GetServiceResourceName(true)
+}
+
+func (r *PrimaryFailureQuorum) getQuorum(nbreReadyReplicas int32) int32 {
+	if detectionQuorum := r.kubegresContext.Kubegres.Spec.Failover.DetectionQuorum; detectionQuorum != nil {
+		return *detectionQuorum
+	}
+	return nbreReadyReplicas/2 + 1
+}
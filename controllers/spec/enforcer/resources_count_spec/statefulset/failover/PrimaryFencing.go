@@ -0,0 +1,226 @@
+/*
+Copyright 2021 Reactive Tech Limited.
+"Reactive Tech Limited" is a company located in England, United Kingdom.
+https://www.reactive-tech.io
+
+Lead Developer: Alex Arica
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package failover
+
+import (
+	"time"
+
+	core "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "reactive-tech.io/kubegres/api/v1"
+	"reactive-tech.io/kubegres/controllers/ctx"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// PrimaryFencing makes sure that the Pod of a failing Primary cannot accept writes anymore before a Replica is promoted.
+// The Pod is removed from the endpoints of the Primary Service by setting its label 'replicationRole' to 'fenced'
+// and then it is deleted. A deleted Pod only disappears once the kubelet of its node confirmed that its containers
+// were stopped. If the kubelet cannot confirm it, the Pod is only force-deleted once its node is gone or has been
+// NotReady for at least the grace period, since a Pod which is force-deleted while its node is running could still
+// accept writes.
+type PrimaryFencing struct {
+	kubegresContext ctx.KubegresContext
+}
+
+func CreatePrimaryFencing(kubegresContext ctx.KubegresContext) PrimaryFencing {
+	return PrimaryFencing{kubegresContext: kubegresContext}
+}
+
+// Fence continues the fencing recorded in the given operation and returns its new state.
+// The fencing is confirmed once none of the Pods labelled as Primary or as fenced exist anymore, which means either
+// their kubelet stopped their containers or their node was lost for at least the grace period.
+func (r *PrimaryFencing) Fence(fencingOperation v1.KubegresFencingOperation) (v1.KubegresFencingOperation, error) {
+
+	if fencingOperation.StartEpocInSeconds == 0 {
+		fencingOperation.StartEpocInSeconds = time.Now().Unix()
+	}
+
+	podsToFence, err := r.getPodsToFence()
+	if err != nil {
+		r.kubegresContext.Log.ErrorEvent("FencingErr", err, "Unable to load the Pods of the failing Primary to fence.")
+		return fencingOperation, err
+	}
+
+	for _, pod := range podsToFence {
+
+		fencingOperation.Pods = r.addIfMissing(fencingOperation.Pods, pod.Name)
+
+		if err = r.removeFromPrimaryServiceEndpoints(pod); err != nil {
+			return fencingOperation, err
+		}
+
+		if pod.DeletionTimestamp == nil {
+			if err = r.deletePod(pod); err != nil {
+				return fencingOperation, err
+			}
+
+		} else if r.hasGracePeriodElapsed(fencingOperation) {
+
+			isNodeLost, err := r.isNodeLost(pod)
+			if err != nil {
+				return fencingOperation, err
+			}
+
+			if !isNodeLost {
+				r.kubegresContext.Log.Info("Fencing: The Pod of the failing Primary is still terminating while its node is Ready. "+
+					"Waiting for its kubelet to stop it, as force-deleting it could leave its containers accepting writes.",
+					"Pod name", pod.Name, "Node name", pod.Spec.NodeName)
+				continue
+			}
+
+			if err = r.forceDeletePod(pod); err != nil {
+				return fencingOperation, err
+			}
+			fencingOperation.ForceDeletedPods = r.addIfMissing(fencingOperation.ForceDeletedPods, pod.Name)
+		}
+	}
+
+	fencingOperation.IsFenced = len(podsToFence) == 0
+	return fencingOperation, nil
+}
+
+func (r *PrimaryFencing) getPodsToFence() ([]core.Pod, error) {
+
+	list := &core.PodList{}
+	opts := []client.ListOption{
+		client.InNamespace(r.kubegresContext.Kubegres.Namespace),
+		client.MatchingLabels{"app": r.kubegresContext.Kubegres.Name},
+	}
+
+	if err := r.kubegresContext.Client.List(r.kubegresContext.Ctx, list, opts...); err != nil {
+		return nil, err
+	}
+
+	var podsToFence []core.Pod
+	for _, pod := range list.Items {
+		replicationRole := pod.Labels["replicationRole"]
+		if replicationRole == ctx.PrimaryRoleName || replicationRole == ctx.FencedRoleName {
+			podsToFence = append(podsToFence, pod)
+		}
+	}
+
+	return podsToFence, nil
+}
+
+func (r *PrimaryFencing) removeFromPrimaryServiceEndpoints(pod core.Pod) error {
+
+	if pod.Labels["replicationRole"] == ctx.FencedRoleName {
+		return nil
+	}
+
+	fencedPod := pod.DeepCopy()
+	fencedPod.Labels["replicationRole"] = ctx.FencedRoleName
+
+	err := r.kubegresContext.Client.Patch(r.kubegresContext.Ctx, fencedPod, client.MergeFrom(&pod))
+	if err != nil && !apierrors.IsNotFound(err) {
+		r.kubegresContext.Log.ErrorEvent("FencingErr", err,
+			"Unable to remove the Pod of the failing Primary from the endpoints of the Primary Service.",
+			"Pod name", pod.Name)
+		return err
+	}
+
+	r.kubegresContext.Log.InfoEvent("Fencing",
+		"Removed the Pod of the failing Primary from the endpoints of the Primary Service.",
+		"Pod name", pod.Name)
+	return nil
+}
+
+func (r *PrimaryFencing) deletePod(pod core.Pod) error {
+
+	err := r.kubegresContext.Client.Delete(r.kubegresContext.Ctx, &pod)
+	if err != nil && !apierrors.IsNotFound(err) {
+		r.kubegresContext.Log.ErrorEvent("FencingErr", err,
+			"Unable to delete the Pod of the failing Primary.",
+			"Pod name", pod.Name)
+		return err
+	}
+
+	r.kubegresContext.Log.InfoEvent("Fencing", "Deleted the Pod of the failing Primary.", "Pod name", pod.Name)
+	return nil
+}
+
+// isNodeLost returns true if the node of the given Pod does not exist anymore or if it has been NotReady for at least
+// the grace period. In that case the Pod cannot run anymore or is unreachable since long enough to be considered stopped.
+func (r *PrimaryFencing) isNodeLost(pod core.Pod) (bool, error) {
+
+	if pod.Spec.NodeName == "" {
+		return true, nil
+	}
+
+	node := &core.Node{}
+	nodeKey := client.ObjectKey{Name: pod.Spec.NodeName}
+
+	if err := r.kubegresContext.Client.Get(r.kubegresContext.Ctx, nodeKey, node); err != nil {
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+		r.kubegresContext.Log.ErrorEvent("FencingErr", err,
+			"Unable to load the node of the Pod of the failing Primary.",
+			"Pod name", pod.Name, "Node name", pod.Spec.NodeName)
+		return false, err
+	}
+
+	for _, condition := range node.Status.Conditions {
+		if condition.Type != core.NodeReady {
+			continue
+		}
+		if condition.Status == core.ConditionTrue {
+			return false, nil
+		}
+		nbreSecondsNotReady := time.Now().Unix() - condition.LastTransitionTime.Unix()
+		return nbreSecondsNotReady >= r.getGracePeriodSeconds(), nil
+	}
+
+	return false, nil
+}
+
+func (r *PrimaryFencing) forceDeletePod(pod core.Pod) error {
+
+	err := r.kubegresContext.Client.Delete(r.kubegresContext.Ctx, &pod, client.GracePeriodSeconds(0))
+	if err != nil && !apierrors.IsNotFound(err) {
+		r.kubegresContext.Log.ErrorEvent("FencingErr", err,
+			"Unable to force-delete the Pod of the failing Primary.",
+			"Pod name", pod.Name)
+		return err
+	}
+
+	r.kubegresContext.Log.WarningEvent("FencingForceDelete",
+		"Force-deleted the Pod of the failing Primary as it was still terminating and its node was lost for at least the fencing grace period.",
+		"Pod name", pod.Name, "Node name", pod.Spec.NodeName, "Grace period in seconds", r.getGracePeriodSeconds())
+	return nil
+}
+
+func (r *PrimaryFencing) hasGracePeriodElapsed(fencingOperation v1.KubegresFencingOperation) bool {
+	return time.Now().Unix()-fencingOperation.StartEpocInSeconds >= r.getGracePeriodSeconds()
+}
+
+func (r *PrimaryFencing) getGracePeriodSeconds() int64 {
+	return r.kubegresContext.GetFencingGracePeriodSeconds()
+}
+
+func (r *PrimaryFencing) addIfMissing(podNames []string, podName string) []string {
+	for _, existingPodName := range podNames {
+		if existingPodName == podName {
+			return podNames
+		}
+	}
+	return append(podNames, podName)
+}
@@ -0,0 +1,641 @@
+/*
+Copyright 2021 Reactive Tech Limited.
+"Reactive Tech Limited" is a company located in England, United Kingdom.
+https://www.reactive-tech.io
+
+Lead Developer: Alex Arica
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package failover
+
+import (
+	"errors"
+	"strconv"
+
+	core "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "reactive-tech.io/kubegres/api/v1"
+	"reactive-tech.io/kubegres/controllers/ctx"
+	"reactive-tech.io/kubegres/controllers/operation"
+	"reactive-tech.io/kubegres/controllers/postgres"
+	"reactive-tech.io/kubegres/controllers/spec/template"
+	"reactive-tech.io/kubegres/controllers/states"
+	"reactive-tech.io/kubegres/controllers/states/statefulset"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// PrimarySwitchover promotes the Replica set in 'spec.failover.switchoverPod' as Primary without losing any data.
+// It runs as a blocking operation with the following steps:
+// 1) the Primary is removed from the endpoints of the Primary Service, its clients are disconnected and we wait for
+// the Replica to replay all the WAL of the Primary,
+// 2) the former Primary StatefulSet is deleted so that PostgreSql is shut down cleanly,
+// 3) the Replica is promoted online as Primary,
+// 4) a Replica StatefulSet is created with the same instance index as the former Primary. It reuses the PVC of the
+// former Primary, which is rewound with 'pg_rewind' and restarted as a Replica of the new Primary. If the rewind
+// fails, the data of the former Primary is kept and its Pod does not start, so that it can be fixed manually.
+// Once 'timeouts.replicaDeployingSeconds' elapsed, it is replaced by a new Replica and its PVC is kept.
+type PrimarySwitchover struct {
+	kubegresContext   ctx.KubegresContext
+	resourcesStates   states.ResourcesStates
+	resourcesCreator  template.ResourcesCreatorFromTemplate
+	blockingOperation *operation.BlockingOperation
+	dbConnector       postgres.DbConnector
+	replicaPromotion  ReplicaPromotion
+	failoverHistory   FailoverHistory
+}
+
+func CreatePrimarySwitchover(kubegresContext ctx.KubegresContext,
+	resourcesStates states.ResourcesStates,
+	resourcesCreator template.ResourcesCreatorFromTemplate,
+	blockingOperation *operation.BlockingOperation) PrimarySwitchover {
+
+	return PrimarySwitchover{
+		kubegresContext:   kubegresContext,
+		resourcesStates:   resourcesStates,
+		resourcesCreator:  resourcesCreator,
+		blockingOperation: blockingOperation,
+		dbConnector:       postgres.CreateDbConnector(kubegresContext),
+		replicaPromotion:  CreateReplicaPromotion(kubegresContext, resourcesStates),
+		failoverHistory:   CreateFailoverHistory(kubegresContext),
+	}
+}
+
+func (r *PrimarySwitchover) CreateOperationConfigForWaitingForReplicaToCatchUp() operation.BlockingOperationConfig {
+	return operation.BlockingOperationConfig{
+		OperationId:                         operation.OperationIdPrimaryDbSwitchover,
+		StepId:                              operation.OperationStepIdSwitchoverWaitingForReplicaToCatchUp,
+		TimeOutInSeconds:                    r.kubegresContext.GetSwitchoverCatchUpTimeoutSeconds(),
+		CompletionChecker:                   r.hasReplicaCaughtUp,
+		AfterCompletionMoveToTransitionStep: true,
+	}
+}
+
+func (r *PrimarySwitchover) CreateOperationConfigForStoppingFormerPrimary() operation.BlockingOperationConfig {
+	return operation.BlockingOperationConfig{
+		OperationId:                         operation.OperationIdPrimaryDbSwitchover,
+		StepId:                              operation.OperationStepIdSwitchoverStoppingFormerPrimaryDb,
+		TimeOutInSeconds:                    r.kubegresContext.GetReplicaUndeployingTimeoutSeconds(),
+		CompletionChecker:                   r.isFormerPrimaryStopped,
+		AfterCompletionMoveToTransitionStep: true,
+	}
+}
+
+func (r *PrimarySwitchover) CreateOperationConfigForPromotingReplica() operation.BlockingOperationConfig {
+	return operation.BlockingOperationConfig{
+		OperationId:                         operation.OperationIdPrimaryDbSwitchover,
+		StepId:                              operation.OperationStepIdSwitchoverPromotingReplicaDb,
+		TimeOutInSeconds:                    r.kubegresContext.GetFailoverPromotionTimeoutSeconds(),
+		CompletionChecker:                   r.isNewPrimaryReady,
+		AfterCompletionMoveToTransitionStep: true,
+	}
+}
+
+func (r *PrimarySwitchover) CreateOperationConfigForRejoiningFormerPrimary() operation.BlockingOperationConfig {
+	return operation.BlockingOperationConfig{
+		OperationId:       operation.OperationIdPrimaryDbSwitchover,
+		StepId:            operation.OperationStepIdSwitchoverRejoiningFormerPrimaryDb,
+		TimeOutInSeconds:  r.kubegresContext.GetReplicaDeployingTimeoutSeconds(),
+		CompletionChecker: r.hasFormerPrimaryRejoined,
+	}
+}
+
+// ShouldWeSwitchover returns true if a switchover is in progress or if a valid switchover is requested in the spec.
+// A switchover only starts when the Primary is ready and when no other blocking operation is active.
+func (r *PrimarySwitchover) ShouldWeSwitchover() bool {
+
+	if r.isSwitchoverInProgress() {
+		return true
+
+	} else if r.getPodToSwitchoverTo() == "" || r.blockingOperation.IsThereActiveOperation() || !r.isPrimaryDbReady() {
+		return false
+
+	} else if r.getPodToSwitchoverTo() == r.resourcesStates.StatefulSets.Primary.Pod.Pod.Name {
+		r.kubegresContext.Log.Info("The Pod set in 'failover.switchoverPod' is already the Primary.",
+			"Pod name", r.getPodToSwitchoverTo())
+		_ = r.resetInSpecSwitchoverPod()
+		return false
+	}
+
+	if _, err := r.getReplicaToSwitchoverTo(); err != nil {
+		r.logSwitchoverCannotHappenAsConfigErr()
+		return false
+	}
+
+	return true
+}
+
+func (r *PrimarySwitchover) Switchover() error {
+
+	if !r.isSwitchoverInProgress() {
+		return r.startSwitchover()
+	}
+
+	activeOperation := r.blockingOperation.GetActiveOperation()
+	switchoverOperation := activeOperation.SwitchoverOperation
+
+	if activeOperation.HasTimedOut {
+		return r.handleTimedOutSwitchover(activeOperation)
+	}
+
+	if !r.blockingOperation.IsActiveOperationInTransition(operation.OperationIdPrimaryDbSwitchover) {
+		return nil
+	}
+
+	switch r.blockingOperation.GetPreviouslyActiveOperation().StepId {
+	case operation.OperationStepIdSwitchoverWaitingForReplicaToCatchUp:
+		return r.stopFormerPrimary(switchoverOperation)
+	case operation.OperationStepIdSwitchoverStoppingFormerPrimaryDb:
+		return r.promoteReplica(switchoverOperation)
+	case operation.OperationStepIdSwitchoverPromotingReplicaDb:
+		return r.rejoinFormerPrimary(switchoverOperation)
+	}
+
+	return nil
+}
+
+func (r *PrimarySwitchover) startSwitchover() error {
+
+	primary := r.resourcesStates.StatefulSets.Primary
+	newPrimary, err := r.getReplicaToSwitchoverTo()
+	if err != nil {
+		return err
+	}
+
+	r.kubegresContext.Log.InfoEvent("Switchover", "Switchover: Stopping the writes on the Primary.",
+		"Primary", primary.Pod.Pod.Name, "Replica to promote", newPrimary.Pod.Pod.Name)
+
+	r.warnIfFormerPrimaryCannotBeRewound(primary)
+
+	walLsn, err := r.stopWritesOnPrimary(primary)
+	if err != nil {
+		r.kubegresContext.Log.ErrorEvent("SwitchoverErr", err,
+			"Switchover: Unable to stop the writes on the Primary.", "Primary", primary.Pod.Pod.Name)
+		r.allowWritesOnPrimary()
+		return err
+	}
+
+	switchoverOperation := v1.KubegresSwitchoverOperation{
+		FormerPrimaryInstanceIndex: primary.InstanceIndex,
+		NewPrimaryInstanceIndex:    newPrimary.InstanceIndex,
+		FormerPrimaryWalLsn:        walLsn,
+	}
+
+	err = r.activateOperation(operation.OperationStepIdSwitchoverWaitingForReplicaToCatchUp,
+		newPrimary.InstanceIndex, switchoverOperation)
+	if err != nil {
+		r.allowWritesOnPrimary()
+		return err
+	}
+
+	r.failoverHistory.RecordStart(v1.FailoverReasonSwitchover, primary.Pod.Pod.Name, newPrimary.Pod.Pod.Name)
+
+	if err = r.resetInSpecSwitchoverPod(); err != nil {
+		r.kubegresContext.Log.ErrorEvent("SwitchoverErr", err, "Switchover: Unable to reset the field 'failover.switchoverPod' in spec.")
+	}
+
+	r.kubegresContext.Log.InfoEvent("Switchover",
+		"Switchover: Stopped the writes on the Primary. Waiting for the Replica to replay all the WAL before promoting it.",
+		"WAL LSN", walLsn, "Replica to promote", newPrimary.Pod.Pod.Name)
+	return nil
+}
+
+// stopWritesOnPrimary removes the Primary from the endpoints of the Primary Service, so that its clients cannot
+// reconnect to it, disconnects its clients and returns its WAL position. The Primary is also set in read-only mode
+// for the clients connecting to its Pod directly. The setting 'default_transaction_read_only' is removed from
+// the data folder of the former Primary before it restarts as a Replica.
+func (r *PrimarySwitchover) stopWritesOnPrimary(primary statefulset.StatefulSetWrapper) (string, error) {
+
+	if err := r.setPrimaryPodRole(primary.Pod, ctx.FencedRoleName); err != nil {
+		return "", err
+	}
+
+	connection, err := r.dbConnector.ConnectAsSuperUser(primary.Pod.Pod)
+	if err != nil {
+		return "", err
+	}
+	defer connection.Close()
+
+	statements := []string{
+		"CHECKPOINT",
+		"ALTER SYSTEM SET default_transaction_read_only = on",
+		"SELECT pg_reload_conf()",
+		"SELECT pg_terminate_backend(pid) FROM pg_stat_activity WHERE backend_type = 'client backend' AND pid <> pg_backend_pid()",
+	}
+
+	for _, statement := range statements {
+		if err = connection.Exec(statement); err != nil {
+			return "", err
+		}
+	}
+
+	var walLsn string
+	if err = connection.QueryRow("SELECT pg_current_wal_lsn()::text").Scan(&walLsn); err != nil {
+		return "", err
+	}
+
+	return walLsn, nil
+}
+
+// allowWritesOnPrimary adds the Primary back to the endpoints of the Primary Service and resets its read-only mode
+// when a switchover is cancelled.
+func (r *PrimarySwitchover) allowWritesOnPrimary() {
+
+	primary := r.resourcesStates.StatefulSets.Primary
+	if err := r.setPrimaryPodRole(primary.Pod, ctx.PrimaryRoleName); err != nil {
+		r.logAllowWritesErr(err, primary)
+		return
+	}
+
+	if !primary.Pod.IsReady {
+		return
+	}
+
+	connection, err := r.dbConnector.ConnectAsSuperUser(primary.Pod.Pod)
+	if err != nil {
+		r.logAllowWritesErr(err, primary)
+		return
+	}
+	defer connection.Close()
+
+	if err = connection.Exec("ALTER SYSTEM RESET default_transaction_read_only"); err != nil {
+		r.logAllowWritesErr(err, primary)
+		return
+	}
+
+	if err = connection.Exec("SELECT pg_reload_conf()"); err != nil {
+		r.logAllowWritesErr(err, primary)
+	}
+}
+
+// setPrimaryPodRole sets the label 'replicationRole' of the Pod of the Primary. The Primary Service only selects
+// the Pod while it is labelled as Primary.
+func (r *PrimarySwitchover) setPrimaryPodRole(primaryPod statefulset.PodWrapper, replicationRole string) error {
+
+	pod, err := r.getPod(primaryPod)
+	if err != nil || pod.Labels["replicationRole"] == replicationRole {
+		return err
+	}
+
+	updatedPod := pod.DeepCopy()
+	updatedPod.Labels["replicationRole"] = replicationRole
+
+	err = r.kubegresContext.Client.Patch(r.kubegresContext.Ctx, updatedPod, client.MergeFrom(&pod))
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	r.kubegresContext.Log.Info("Switchover: Updated the label 'replicationRole' of the Pod of the Primary.",
+		"Pod name", pod.Name, "replicationRole", replicationRole)
+	return nil
+}
+
+// getPod returns the Pod of the Primary as it is in the cluster, as its labels may have been updated during the
+// current reconciliation.
+func (r *PrimarySwitchover) getPod(podWrapper statefulset.PodWrapper) (core.Pod, error) {
+
+	pod := core.Pod{}
+	if !podWrapper.IsDeployed {
+		return pod, nil
+	}
+
+	podKey := client.ObjectKey{Namespace: podWrapper.Pod.Namespace, Name: podWrapper.Pod.Name}
+	err := r.kubegresContext.Client.Get(r.kubegresContext.Ctx, podKey, &pod)
+	if apierrors.IsNotFound(err) {
+		return pod, nil
+	}
+	return pod, err
+}
+
+// warnIfFormerPrimaryCannotBeRewound checks that 'pg_rewind' can restart the former Primary as a Replica, which
+// requires either the setting 'wal_log_hints' or the data checksums to be enabled. A custom 'postgres.conf' may
+// not enable 'wal_log_hints'. Otherwise, the former Primary does not rejoin and it is replaced by a new Replica.
+func (r *PrimarySwitchover) warnIfFormerPrimaryCannotBeRewound(primary statefulset.StatefulSetWrapper) {
+
+	connection, err := r.dbConnector.ConnectAsSuperUser(primary.Pod.Pod)
+	if err != nil {
+		return
+	}
+	defer connection.Close()
+
+	var canBeRewound bool
+	err = connection.QueryRow("SELECT current_setting('wal_log_hints') = 'on' OR current_setting('data_checksums') = 'on'").
+		Scan(&canBeRewound)
+	if err != nil || canBeRewound {
+		return
+	}
+
+	r.kubegresContext.Log.WarningEvent("SwitchoverWalLogHintsDisabled",
+		"Switchover: The setting 'wal_log_hints' and the data checksums are disabled on the Primary, "+
+			"so 'pg_rewind' cannot restart the former Primary as a Replica. Its data will be kept in its PVC and "+
+			"it will be replaced by a new Replica copied from the new Primary once the deployment of the former "+
+			"Primary as a Replica times out. "+
+			"Please set 'wal_log_hints = on' in the custom 'postgres.conf'.",
+		"Primary", primary.Pod.Pod.Name)
+}
+
+func (r *PrimarySwitchover) stopFormerPrimary(switchoverOperation v1.KubegresSwitchoverOperation) error {
+
+	err := r.activateOperation(operation.OperationStepIdSwitchoverStoppingFormerPrimaryDb,
+		switchoverOperation.FormerPrimaryInstanceIndex, switchoverOperation)
+	if err != nil {
+		return err
+	}
+
+	formerPrimary, err := r.resourcesStates.StatefulSets.All.GetByInstanceIndex(switchoverOperation.FormerPrimaryInstanceIndex)
+	if err != nil {
+		return nil
+	}
+
+	if err = r.kubegresContext.Client.Delete(r.kubegresContext.Ctx, &formerPrimary.StatefulSet); err != nil {
+		r.kubegresContext.Log.ErrorEvent("SwitchoverErr", err,
+			"Switchover: Unable to delete the former Primary StatefulSet.", "Primary name", formerPrimary.StatefulSet.Name)
+		return err
+	}
+
+	r.kubegresContext.Log.InfoEvent("SwitchoverPrimaryStopped",
+		"Switchover: The Replica replayed all the WAL of the Primary. Deleted the former Primary StatefulSet.",
+		"Primary name", formerPrimary.StatefulSet.Name)
+	return nil
+}
+
+func (r *PrimarySwitchover) promoteReplica(switchoverOperation v1.KubegresSwitchoverOperation) error {
+
+	newPrimary, err := r.resourcesStates.StatefulSets.Replicas.All.GetByInstanceIndex(switchoverOperation.NewPrimaryInstanceIndex)
+	if err != nil {
+		r.kubegresContext.Log.ErrorEvent("SwitchoverErr", err,
+			"Switchover: The Replica to promote is not deployed anymore. It must be fixed manually.",
+			"InstanceIndex", switchoverOperation.NewPrimaryInstanceIndex)
+		return err
+	}
+
+	err = r.activateOperation(operation.OperationStepIdSwitchoverPromotingReplicaDb,
+		switchoverOperation.NewPrimaryInstanceIndex, switchoverOperation)
+	if err != nil {
+		return err
+	}
+
+	r.kubegresContext.Log.InfoEvent("SwitchoverPromotion", "Switchover: Promoting Replica to Primary.",
+		"Replica to promote", newPrimary.StatefulSet.Name)
+
+	err = r.replicaPromotion.PromoteDb(newPrimary)
+	if err != nil {
+		r.kubegresContext.Log.ErrorEvent("SwitchoverErr", err,
+			"Switchover: Unable to promote the PostgreSql of a Replica to Primary.", "Replica to promote", newPrimary.StatefulSet.Name)
+		return err
+	}
+
+	if err = r.replicaPromotion.LabelAsPrimary(newPrimary); err != nil {
+		r.kubegresContext.Log.ErrorEvent("SwitchoverErr", err,
+			"Switchover: Unable to label the promoted Replica as Primary.", "Replica to promote", newPrimary.StatefulSet.Name)
+		return err
+	}
+
+	return nil
+}
+
+// promoteReplicaOnRestart completes the promotion of a Replica whose PostgreSql did not complete its promotion in time,
+// by restarting its Pod with the promotion script as init container.
+func (r *PrimarySwitchover) promoteReplicaOnRestart(newPrimary statefulset.StatefulSetWrapper) error {
+
+	r.kubegresContext.Log.WarningEvent("SwitchoverPromotionOnRestart",
+		"Switchover: The PostgreSql of the Replica did not complete its promotion in time. Restarting its Pod to complete the promotion.",
+		"Replica to promote", newPrimary.StatefulSet.Name)
+
+	if err := r.replicaPromotion.PromoteOnRestart(newPrimary); err != nil {
+		r.kubegresContext.Log.ErrorEvent("SwitchoverErr", err,
+			"Switchover: Unable to set the Replica to be promoted when its Pod restarts.", "Replica to promote", newPrimary.StatefulSet.Name)
+		return err
+	}
+	return nil
+}
+
+func (r *PrimarySwitchover) rejoinFormerPrimary(switchoverOperation v1.KubegresSwitchoverOperation) error {
+
+	instanceIndex := switchoverOperation.FormerPrimaryInstanceIndex
+	replicaStatefulSet, err := r.resourcesCreator.CreateReplicaStatefulSet(instanceIndex)
+	if err != nil {
+		r.kubegresContext.Log.ErrorEvent("ReplicaStatefulSetTemplateErr", err,
+			"Error while creating a Replica StatefulSet object from template.", "InstanceIndex", instanceIndex)
+		return err
+	}
+	r.resourcesCreator.SetReplicaToRewindFormerPrimary(&replicaStatefulSet)
+
+	if err = r.activateOperation(operation.OperationStepIdSwitchoverRejoiningFormerPrimaryDb, instanceIndex, switchoverOperation); err != nil {
+		return err
+	}
+
+	if err = r.kubegresContext.Client.Create(r.kubegresContext.Ctx, &replicaStatefulSet); err != nil {
+		r.kubegresContext.Log.ErrorEvent("SwitchoverErr", err,
+			"Switchover: Unable to deploy the former Primary as a Replica StatefulSet.", "Replica name", replicaStatefulSet.Name)
+		return err
+	}
+
+	r.kubegresContext.Log.InfoEvent("SwitchoverRejoin",
+		"Switchover: The new Primary is ready. Deployed the former Primary as a Replica StatefulSet.",
+		"Replica name", replicaStatefulSet.Name)
+	return nil
+}
+
+// handleTimedOutSwitchover cancels a switchover if the Replica did not catch-up with the Primary in time.
+// Once the former Primary is stopped, the switchover cannot be cancelled anymore and it continues once the step
+// which timed-out is fixed manually.
+func (r *PrimarySwitchover) handleTimedOutSwitchover(activeOperation v1.KubegresBlockingOperation) error {
+
+	switchoverOperation := activeOperation.SwitchoverOperation
+
+	switch activeOperation.StepId {
+
+	case operation.OperationStepIdSwitchoverWaitingForReplicaToCatchUp:
+		r.allowWritesOnPrimary()
+		r.blockingOperation.RemoveActiveOperation()
+		r.failoverHistory.RecordTimedOut(v1.FailoverOutcomeCancelled)
+		r.kubegresContext.Log.WarningEvent("SwitchoverCancelled",
+			"Switchover: The Replica did not replay all the WAL of the Primary within "+
+				strconv.FormatInt(r.kubegresContext.GetSwitchoverCatchUpTimeoutSeconds(), 10)+" seconds. "+
+				"The switchover is cancelled and the writes are allowed again on the Primary.")
+		return nil
+
+	case operation.OperationStepIdSwitchoverStoppingFormerPrimaryDb:
+		if r.isFormerPrimaryStopped(activeOperation) {
+			return r.promoteReplica(switchoverOperation)
+		}
+
+	case operation.OperationStepIdSwitchoverPromotingReplicaDb:
+		if r.isNewPrimaryReady(activeOperation) {
+			return r.rejoinFormerPrimary(switchoverOperation)
+		}
+
+	case operation.OperationStepIdSwitchoverRejoiningFormerPrimaryDb:
+		if r.isNewPrimaryReady(activeOperation) {
+			r.blockingOperation.RemoveActiveOperation()
+			r.failoverHistory.RecordEnd(v1.FailoverOutcomeSucceeded)
+			r.kubegresContext.Log.InfoEvent("KubegresReEnabled", "The new Primary DB is ready. "+
+				"The former Primary DB which did not rejoin as a Replica DB will be replaced. "+
+				"We can safely re-enable all features of Kubegres.")
+			return nil
+		}
+	}
+
+	r.failoverHistory.RecordTimedOut("")
+	r.logSwitchoverTimedOut(activeOperation)
+	return nil
+}
+
+func (r *PrimarySwitchover) hasReplicaCaughtUp(activeOperation v1.KubegresBlockingOperation) bool {
+
+	switchoverOperation := activeOperation.SwitchoverOperation
+	formerPrimaryState := r.resourcesStates.Replication.GetByInstanceIndex(switchoverOperation.FormerPrimaryInstanceIndex)
+	newPrimaryState := r.resourcesStates.Replication.GetByInstanceIndex(switchoverOperation.NewPrimaryInstanceIndex)
+
+	formerPrimaryWalLsn, err := postgres.ParseLsn(switchoverOperation.FormerPrimaryWalLsn)
+	if err != nil || !formerPrimaryState.IsReachable || !newPrimaryState.IsReachable {
+		return false
+	}
+
+	// The Primary can still write a few WAL records once the clients are disconnected, e.g. by autovacuum
+	if formerPrimaryState.WalLsn > formerPrimaryWalLsn {
+		formerPrimaryWalLsn = formerPrimaryState.WalLsn
+	}
+
+	return newPrimaryState.WalLsn >= formerPrimaryWalLsn
+}
+
+func (r *PrimarySwitchover) isFormerPrimaryStopped(activeOperation v1.KubegresBlockingOperation) bool {
+
+	instanceIndex := activeOperation.SwitchoverOperation.FormerPrimaryInstanceIndex
+	if _, err := r.resourcesStates.StatefulSets.All.GetByInstanceIndex(instanceIndex); err == nil {
+		return false
+	}
+
+	podKey := client.ObjectKey{
+		Namespace: r.kubegresContext.Kubegres.Namespace,
+		Name:      r.kubegresContext.GetStatefulSetResourceName(instanceIndex) + "-0",
+	}
+	err := r.kubegresContext.Client.Get(r.kubegresContext.Ctx, podKey, &core.Pod{})
+	return apierrors.IsNotFound(err)
+}
+
+func (r *PrimarySwitchover) isNewPrimaryReady(activeOperation v1.KubegresBlockingOperation) bool {
+	primary := r.resourcesStates.StatefulSets.Primary
+	return primary.IsReady && primary.InstanceIndex == activeOperation.SwitchoverOperation.NewPrimaryInstanceIndex &&
+		r.isNewPrimaryPromoted(activeOperation)
+}
+
+// isNewPrimaryPromoted returns whether the PostgreSql of the promoted Replica left the recovery. If it did not within
+// 'promotionWaitSeconds', its Pod is restarted to complete the promotion.
+func (r *PrimarySwitchover) isNewPrimaryPromoted(activeOperation v1.KubegresBlockingOperation) bool {
+
+	instanceIndex := activeOperation.SwitchoverOperation.NewPrimaryInstanceIndex
+	if r.replicaPromotion.IsPromoted(instanceIndex) {
+		return true
+	}
+
+	newPrimary, err := r.resourcesStates.StatefulSets.All.GetByInstanceIndex(instanceIndex)
+	if err == nil && activeOperation.StepId == operation.OperationStepIdSwitchoverPromotingReplicaDb &&
+		r.replicaPromotion.IsPromotionOnRestartRequired(newPrimary, r.blockingOperation.GetNbreSecondsSinceOperationHasStarted()) {
+		_ = r.promoteReplicaOnRestart(newPrimary)
+	}
+
+	return false
+}
+
+func (r *PrimarySwitchover) hasFormerPrimaryRejoined(activeOperation v1.KubegresBlockingOperation) bool {
+
+	instanceIndex := activeOperation.SwitchoverOperation.FormerPrimaryInstanceIndex
+	replica, err := r.resourcesStates.StatefulSets.Replicas.All.GetByInstanceIndex(instanceIndex)
+	if err != nil || !replica.IsReady {
+		return false
+	}
+
+	r.failoverHistory.RecordEnd(v1.FailoverOutcomeSucceeded)
+	r.kubegresContext.Log.InfoEvent("SwitchoverCompleted",
+		"Switchover: The former Primary is ready as a Replica of the new Primary.", "Replica name", replica.StatefulSet.Name)
+	return true
+}
+
+func (r *PrimarySwitchover) isSwitchoverInProgress() bool {
+	return r.blockingOperation.GetActiveOperation().OperationId == operation.OperationIdPrimaryDbSwitchover
+}
+
+func (r *PrimarySwitchover) isPrimaryDbReady() bool {
+	return r.resourcesStates.StatefulSets.Primary.IsReady
+}
+
+func (r *PrimarySwitchover) getPodToSwitchoverTo() string {
+	return r.kubegresContext.Kubegres.Spec.Failover.SwitchoverPod
+}
+
+func (r *PrimarySwitchover) getReplicaToSwitchoverTo() (statefulset.StatefulSetWrapper, error) {
+
+	podToSwitchoverTo := r.getPodToSwitchoverTo()
+	for _, statefulSetWrapper := range r.resourcesStates.StatefulSets.Replicas.All.GetAllSortedByInstanceIndex() {
+		if statefulSetWrapper.IsReady && statefulSetWrapper.Pod.Pod.Name == podToSwitchoverTo {
+			return statefulSetWrapper, nil
+		}
+	}
+
+	return statefulset.StatefulSetWrapper{}, errors.New("the Pod '" + podToSwitchoverTo + "' is not a ready Replica")
+}
+
+func (r *PrimarySwitchover) activateOperation(stepId string, instanceIndex int32, switchoverOperation v1.KubegresSwitchoverOperation) error {
+
+	err := r.blockingOperation.ActivateOperationOnStatefulSetSwitchover(operation.OperationIdPrimaryDbSwitchover,
+		stepId, instanceIndex, switchoverOperation)
+	if err != nil {
+		r.kubegresContext.Log.ErrorEvent("SwitchoverOperationActivationErr", err,
+			"Error while activating a blocking operation for the switchover of a Primary DB.",
+			"StepId", stepId, "InstanceIndex", instanceIndex)
+	}
+	return err
+}
+
+func (r *PrimarySwitchover) resetInSpecSwitchoverPod() error {
+	r.kubegresContext.Log.Info("Resetting the field 'failover.switchoverPod' in spec.")
+
+	// We only patch that field so that the default values set in memory in the spec are not persisted
+	kubegresToPatch := r.kubegresContext.Kubegres.DeepCopy()
+	patch := client.MergeFrom(kubegresToPatch.DeepCopy())
+	kubegresToPatch.Spec.Failover.SwitchoverPod = ""
+	if err := r.kubegresContext.Client.Patch(r.kubegresContext.Ctx, kubegresToPatch, patch); err != nil {
+		return err
+	}
+
+	r.kubegresContext.Kubegres.Spec.Failover.SwitchoverPod = ""
+	r.kubegresContext.Kubegres.ResourceVersion = kubegresToPatch.ResourceVersion
+	return nil
+}
+
+func (r *PrimarySwitchover) logAllowWritesErr(err error, primary statefulset.StatefulSetWrapper) {
+	r.kubegresContext.Log.ErrorEvent("SwitchoverErr", err,
+		"Switchover: Unable to allow the writes again on the Primary. "+
+			"Please set the label 'replicationRole' of its Pod to 'primary' and "+
+			"run 'ALTER SYSTEM RESET default_transaction_read_only' and 'SELECT pg_reload_conf()' on the Primary.",
+		"Primary", primary.Pod.Pod.Name)
+}
+
+func (r *PrimarySwitchover) logSwitchoverTimedOut(activeOperation v1.KubegresBlockingOperation) {
+	err := errors.New("switchover timed-out")
+	r.kubegresContext.Log.ErrorEvent("SwitchoverTimedOutErr", err,
+		"Last switchover attempt has timed-out at the step '"+activeOperation.StepId+"'. It must be fixed manually. "+
+			"Until it is fixed, most of the features of Kubegres are disabled for safety reason. ",
+		"StatefulSet to fix", activeOperation.StatefulSetOperation.Name)
+}
+
+func (r *PrimarySwitchover) logSwitchoverCannotHappenAsConfigErr() {
+	r.kubegresContext.Log.WarningEvent("SwitchoverCannotHappenAsConfigErr",
+		"The value of the field 'failover.switchoverPod' is set to '"+r.getPodToSwitchoverTo()+"'. "+
+			"That value is either the name of a Replica Pod which is not ready OR a Pod which does not exist. "+
+			"Please set the name of a Replica Pod that you would like to promote as a Primary Pod.")
+}
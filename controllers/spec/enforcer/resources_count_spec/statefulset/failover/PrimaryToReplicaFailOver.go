@@ -23,19 +23,31 @@ package failover
 import (
 	"errors"
 	"strconv"
+	"strings"
 
-	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	v1 "reactive-tech.io/kubegres/api/v1"
 	"reactive-tech.io/kubegres/controllers/ctx"
 	"reactive-tech.io/kubegres/controllers/operation"
+	"reactive-tech.io/kubegres/controllers/postgres"
 	"reactive-tech.io/kubegres/controllers/states"
+	"reactive-tech.io/kubegres/controllers/states/replication"
 	"reactive-tech.io/kubegres/controllers/states/statefulset"
 )
 
+// The reason of the condition 'FailoverBlocked' when all the ready Replicas lag more than 'failover.maximumLagBytes'
+const failoverBlockedReasonReplicasLagTooMuch = "ReplicasLagTooMuch"
+
 type PrimaryToReplicaFailOver struct {
-	kubegresContext   ctx.KubegresContext
-	resourcesStates   states.ResourcesStates
-	blockingOperation *operation.BlockingOperation
+	kubegresContext      ctx.KubegresContext
+	resourcesStates      states.ResourcesStates
+	blockingOperation    *operation.BlockingOperation
+	primaryFencing       PrimaryFencing
+	replicaPromotion     ReplicaPromotion
+	failoverHistory      FailoverHistory
+	promotionPreference  PromotionPreference
+	failoverRateLimit    FailoverRateLimit
+	primaryFailureQuorum PrimaryFailureQuorum
 }
 
 func CreatePrimaryToReplicaFailOver(kubegresContext ctx.KubegresContext,
@@ -43,9 +55,15 @@ func CreatePrimaryToReplicaFailOver(kubegresContext ctx.KubegresContext,
 	blockingOperation *operation.BlockingOperation) PrimaryToReplicaFailOver {
 
 	return PrimaryToReplicaFailOver{
-		kubegresContext:   kubegresContext,
-		resourcesStates:   resourcesStates,
-		blockingOperation: blockingOperation,
+		kubegresContext:      kubegresContext,
+		resourcesStates:      resourcesStates,
+		blockingOperation:    blockingOperation,
+		primaryFencing:       CreatePrimaryFencing(kubegresContext),
+		replicaPromotion:     CreateReplicaPromotion(kubegresContext, resourcesStates),
+		failoverHistory:      CreateFailoverHistory(kubegresContext),
+		promotionPreference:  CreatePromotionPreference(kubegresContext),
+		failoverRateLimit:    CreateFailoverRateLimit(kubegresContext),
+		primaryFailureQuorum: CreatePrimaryFailureQuorum(kubegresContext, resourcesStates),
 	}
 }
 
@@ -53,7 +71,7 @@ func (r *PrimaryToReplicaFailOver) CreateOperationConfigWaitingBeforeForFailingO
 	return operation.BlockingOperationConfig{
 		OperationId:                         operation.OperationIdPrimaryDbCountSpecEnforcement,
 		StepId:                              operation.OperationStepIdPrimaryDbWaitingBeforeFailingOver,
-		TimeOutInSeconds:                    10,
+		TimeOutInSeconds:                    r.kubegresContext.GetFailoverDetectionDelaySeconds(),
 		AfterCompletionMoveToTransitionStep: true,
 	}
 }
@@ -62,13 +80,15 @@ func (r *PrimaryToReplicaFailOver) CreateOperationConfigForFailingOver() operati
 	return operation.BlockingOperationConfig{
 		OperationId:       operation.OperationIdPrimaryDbCountSpecEnforcement,
 		StepId:            operation.OperationStepIdPrimaryDbFailingOver,
-		TimeOutInSeconds:  300,
+		TimeOutInSeconds:  r.kubegresContext.GetFailoverPromotionTimeoutSeconds(),
 		CompletionChecker: r.isFailOverCompleted,
 	}
 }
 
 func (r *PrimaryToReplicaFailOver) ShouldWeFailOver() bool {
 
+	r.failoverRateLimit.ApplyAcknowledgement()
+
 	if !r.hasPrimaryEverBeenDeployed() {
 		return false
 
@@ -84,6 +104,16 @@ func (r *PrimaryToReplicaFailOver) ShouldWeFailOver() bool {
 		if r.isAutomaticFailoverDisabled() {
 			r.logFailoverCannotHappenAsAutomaticFailoverIsDisabled()
 			return false
+
+		} else if !r.primaryFailureQuorum.IsPrimaryFailureConfirmed() {
+			return false
+
+		} else if r.doAllReplicasLagTooMuch() {
+			return false
+
+		} else if r.isAutomaticFailoverSuspended() {
+			r.logFailoverCannotHappenAsAutomaticFailoverIsSuspended()
+			return false
 		}
 		return true
 	}
@@ -98,6 +128,7 @@ func (r *PrimaryToReplicaFailOver) FailOver() error {
 	}
 
 	if r.hasLastFailOverAttemptTimedOut() {
+		r.failoverHistory.RecordTimedOut(v1.FailoverOutcomeFailed)
 		r.logFailoverTimedOut()
 		return nil
 	}
@@ -109,14 +140,23 @@ func (r *PrimaryToReplicaFailOver) FailOver() error {
 
 	if !r.isWaitingBeforeStartingFailOver() {
 		return r.waitBeforePromotingReplicaToPrimary(newPrimary)
-	} else {
-		return r.promoteReplicaToPrimary(newPrimary)
 	}
+
+	fencingOperation, err := r.fencePrimary()
+	if err != nil || !fencingOperation.IsFenced {
+		return err
+	}
+
+	return r.promoteReplicaToPrimary(newPrimary, fencingOperation)
 }
 
 func (r *PrimaryToReplicaFailOver) isFailOverCompleted(operation v1.KubegresBlockingOperation) bool {
 
-	if r.blockingOperation.GetNbreSecondsSinceOperationHasStarted() < 40 {
+	if !r.isNewPrimaryPromoted(operation) {
+		return false
+	}
+
+	if r.blockingOperation.GetNbreSecondsSinceOperationHasStarted() < r.kubegresContext.GetFailoverStabilizationSeconds() {
 
 		if r.isPrimaryDbReady() {
 			r.kubegresContext.Log.Info("The new Primary Pod is ready. " +
@@ -126,7 +166,29 @@ func (r *PrimaryToReplicaFailOver) isFailOverCompleted(operation v1.KubegresBloc
 		return false
 	}
 
-	return r.isPrimaryDbReady()
+	if !r.isPrimaryDbReady() {
+		return false
+	}
+
+	r.failoverHistory.RecordEnd(v1.FailoverOutcomeSucceeded)
+	return true
+}
+
+// isNewPrimaryPromoted returns whether the PostgreSql of the promoted Replica left the recovery. If it did not within
+// 'promotionWaitSeconds', its Pod is restarted to complete the promotion.
+func (r *PrimaryToReplicaFailOver) isNewPrimaryPromoted(operation v1.KubegresBlockingOperation) bool {
+
+	instanceIndex := operation.StatefulSetOperation.InstanceIndex
+	if r.replicaPromotion.IsPromoted(instanceIndex) {
+		return true
+	}
+
+	newPrimary, err := r.resourcesStates.StatefulSets.All.GetByInstanceIndex(instanceIndex)
+	if err == nil && r.replicaPromotion.IsPromotionOnRestartRequired(newPrimary, r.blockingOperation.GetNbreSecondsSinceOperationHasStarted()) {
+		_ = r.promoteReplicaToPrimaryOnRestart(newPrimary)
+	}
+
+	return false
 }
 
 func (r *PrimaryToReplicaFailOver) isNewPrimaryRequired() bool {
@@ -145,6 +207,54 @@ func (r *PrimaryToReplicaFailOver) isAutomaticFailoverDisabled() bool {
 	return r.kubegresContext.Kubegres.Spec.Failover.IsDisabled
 }
 
+// doAllReplicasLagTooMuch returns whether 'failover.maximumLagBytes' prevents every ready Replica from being promoted,
+// in which case the failover is blocked until a Replica catches up or the Primary recovers. A failover which has
+// already started is not checked, so that it can complete; the selection of the Replica checks the lag again.
+func (r *PrimaryToReplicaFailOver) doAllReplicasLagTooMuch() bool {
+
+	maximumLagBytes := r.kubegresContext.Kubegres.Spec.Failover.MaximumLagBytes
+	if maximumLagBytes == nil || r.failoverHistory.IsInProgress() {
+		return false
+	}
+
+	readyReplicas := r.getReadyReplicas()
+	candidates := readyReplicas
+	if r.kubegresContext.IsSynchronousReplicationEnabled() {
+		candidates = r.getSynchronousReplicas(candidates)
+	}
+
+	if len(r.getReplicasWithinMaximumLag(candidates, r.getReferenceLsn(readyReplicas), *maximumLagBytes)) > 0 {
+		return false
+	}
+
+	if r.isFailoverBlockedBy(failoverBlockedReasonReplicasLagTooMuch) {
+		r.kubegresContext.Log.Info("FailOver: A failover is still blocked as all the ready Replicas lag too much.",
+			"Maximum lag bytes", *maximumLagBytes)
+	} else {
+		r.logFailoverCannotHappenAsReplicasLagTooMuch(*maximumLagBytes)
+	}
+	return true
+}
+
+// isAutomaticFailoverSuspended returns whether the automatic failovers are suspended, or suspends them if a new one
+// would exceed the rate limit. A failover which has already started is not rate limited, so that it can complete.
+func (r *PrimaryToReplicaFailOver) isAutomaticFailoverSuspended() bool {
+
+	if r.failoverRateLimit.IsSuspended() {
+		return true
+
+	} else if r.failoverHistory.IsInProgress() {
+		return false
+	}
+
+	if suspensionReason := r.failoverRateLimit.GetSuspensionReason(); suspensionReason != "" {
+		r.failoverRateLimit.Suspend(suspensionReason)
+		return true
+	}
+
+	return false
+}
+
 func (r *PrimaryToReplicaFailOver) isPrimaryDbDeployed() bool {
 	return r.resourcesStates.StatefulSets.Primary.IsDeployed
 }
@@ -215,14 +325,195 @@ func (r *PrimaryToReplicaFailOver) selectReplicaToPromote() (statefulset.Statefu
 		return r.manuallySelectReplicaToPromote()
 	}
 
+	return r.selectMostAdvancedReplica()
+}
+
+// selectMostAdvancedReplica returns the ready Replica which has received the most WAL, so that promoting it
+// loses as few transactions as possible. A Replica whose WAL positions could not be queried is only selected
+// if none of the other Replicas could be queried. Ties are broken by the lowest instance index.
+// When the replication is synchronous, only a Replica which was synchronous can be selected, so that no committed
+// transaction is lost. When 'failover.maximumLagBytes' is set, only a Replica within that lag can be selected.
+// When 'failover.preferredZones' or 'failover.neverPromoteLabelSelector' are set, the Replica is selected among
+// the most preferred Replicas which can be promoted.
+func (r *PrimaryToReplicaFailOver) selectMostAdvancedReplica() (statefulset.StatefulSetWrapper, error) {
+
+	readyReplicas := r.getReadyReplicas()
+	if len(readyReplicas) == 0 {
+		errorMsg := r.logFailoverCannotHappenAsNoHealthyReplica()
+		return statefulset.StatefulSetWrapper{}, errors.New(errorMsg)
+	}
+
+	candidates := readyReplicas
+	if r.kubegresContext.IsSynchronousReplicationEnabled() {
+		candidates = r.getSynchronousReplicas(candidates)
+		if len(candidates) == 0 {
+			errorMsg := r.logFailoverCannotHappenAsNoSynchronousReplica()
+			return statefulset.StatefulSetWrapper{}, errors.New(errorMsg)
+		}
+	}
+
+	if maximumLagBytes := r.kubegresContext.Kubegres.Spec.Failover.MaximumLagBytes; maximumLagBytes != nil {
+		candidates = r.getReplicasWithinMaximumLag(candidates, r.getReferenceLsn(readyReplicas), *maximumLagBytes)
+		if len(candidates) == 0 {
+			errorMsg := r.logFailoverCannotHappenAsReplicasLagTooMuch(*maximumLagBytes)
+			return statefulset.StatefulSetWrapper{}, errors.New(errorMsg)
+		}
+	}
+
+	if r.promotionPreference.IsConfigured() {
+		candidates = r.promotionPreference.GetMostPreferredReplicas(candidates)
+		if len(candidates) == 0 {
+			errorMsg := r.logFailoverCannotHappenAsNoPromotableReplica()
+			return statefulset.StatefulSetWrapper{}, errors.New(errorMsg)
+		}
+	}
+
+	selectedReplica := candidates[0]
+	selectedReplicationState := r.resourcesStates.Replication.GetByInstanceIndex(selectedReplica.InstanceIndex)
+
+	for _, statefulSetWrapper := range candidates[1:] {
+		replicationState := r.resourcesStates.Replication.GetByInstanceIndex(statefulSetWrapper.InstanceIndex)
+		if r.isMoreAdvanced(replicationState, selectedReplicationState) {
+			selectedReplica = statefulSetWrapper
+			selectedReplicationState = replicationState
+		}
+	}
+
+	return selectedReplica, nil
+}
+
+func (r *PrimaryToReplicaFailOver) getReadyReplicas() []statefulset.StatefulSetWrapper {
+	var readyReplicas []statefulset.StatefulSetWrapper
 	for _, statefulSetWrapper := range r.resourcesStates.StatefulSets.Replicas.All.GetAllSortedByInstanceIndex() {
 		if statefulSetWrapper.IsReady {
-			return statefulSetWrapper, nil
+			readyReplicas = append(readyReplicas, statefulSetWrapper)
+		}
+	}
+	return readyReplicas
+}
+
+func (r *PrimaryToReplicaFailOver) getSynchronousReplicas(replicas []statefulset.StatefulSetWrapper) []statefulset.StatefulSetWrapper {
+	var synchronousReplicas []statefulset.StatefulSetWrapper
+	for _, statefulSetWrapper := range replicas {
+		if r.wasSynchronous(statefulSetWrapper) {
+			synchronousReplicas = append(synchronousReplicas, statefulSetWrapper)
 		}
 	}
+	return synchronousReplicas
+}
 
-	errorMsg := r.logFailoverCannotHappenAsNoHealthyReplica()
-	return statefulset.StatefulSetWrapper{}, errors.New(errorMsg)
+// getReplicasWithinMaximumLag returns the Replicas which are not behind the reference LSN by more than the given
+// number of bytes. A Replica whose WAL positions could not be queried is excluded as its lag is unknown.
+func (r *PrimaryToReplicaFailOver) getReplicasWithinMaximumLag(replicas []statefulset.StatefulSetWrapper,
+	referenceLsn postgres.Lsn,
+	maximumLagBytes int64) []statefulset.StatefulSetWrapper {
+
+	var replicasWithinMaximumLag []statefulset.StatefulSetWrapper
+	for _, statefulSetWrapper := range replicas {
+
+		replicationState := r.resourcesStates.Replication.GetByInstanceIndex(statefulSetWrapper.InstanceIndex)
+		if !replicationState.IsReachable {
+			continue
+		}
+
+		lagBytes := r.getMostAdvancedLsn(replicationState).BytesBehind(referenceLsn)
+		if lagBytes > maximumLagBytes {
+			r.kubegresContext.Log.Info("FailOver: A Replica cannot be promoted as it lags more than 'failover.maximumLagBytes'.",
+				"Replica", statefulSetWrapper.StatefulSet.Name, "Lag bytes", lagBytes, "Maximum lag bytes", maximumLagBytes)
+			continue
+		}
+
+		replicasWithinMaximumLag = append(replicasWithinMaximumLag, statefulSetWrapper)
+	}
+	return replicasWithinMaximumLag
+}
+
+// getReferenceLsn returns the most advanced WAL LSN known, either the last one of the Primary recorded in the status
+// or the one of the most advanced Replica. The lag of the Replicas is measured against it. The LSN of the Primary
+// is only recorded every 'InstancesRefreshPeriod', so it misses the WAL written since the last refresh.
+func (r *PrimaryToReplicaFailOver) getReferenceLsn(readyReplicas []statefulset.StatefulSetWrapper) postgres.Lsn {
+
+	referenceLsn, err := postgres.ParseLsn(r.kubegresContext.Status.GetLastPrimaryWalLsn())
+	if err != nil {
+		referenceLsn = 0
+	}
+
+	for _, statefulSetWrapper := range readyReplicas {
+		replicationState := r.resourcesStates.Replication.GetByInstanceIndex(statefulSetWrapper.InstanceIndex)
+		if replicationState.IsReachable && r.getMostAdvancedLsn(replicationState) > referenceLsn {
+			referenceLsn = r.getMostAdvancedLsn(replicationState)
+		}
+	}
+
+	return referenceLsn
+}
+
+// wasSynchronous returns whether a Replica was synchronous the last time the Primary could be queried.
+func (r *PrimaryToReplicaFailOver) wasSynchronous(statefulSetWrapper statefulset.StatefulSetWrapper) bool {
+	for _, synchronousStandby := range r.kubegresContext.Status.GetSynchronousStandbys() {
+		if synchronousStandby == statefulSetWrapper.StatefulSet.Name {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *PrimaryToReplicaFailOver) isMoreAdvanced(replicationState, otherReplicationState replication.InstanceReplicationState) bool {
+
+	if replicationState.IsReachable != otherReplicationState.IsReachable {
+		return replicationState.IsReachable
+	}
+
+	lsn := r.getMostAdvancedLsn(replicationState)
+	otherLsn := r.getMostAdvancedLsn(otherReplicationState)
+	if lsn != otherLsn {
+		return lsn > otherLsn
+	}
+
+	return replicationState.WalLsn > otherReplicationState.WalLsn
+}
+
+// getMostAdvancedLsn returns the position of the last WAL a Replica has, whether it was replayed or only received.
+// Once promoted, a Replica replays all the WAL it received before accepting writes.
+func (r *PrimaryToReplicaFailOver) getMostAdvancedLsn(replicationState replication.InstanceReplicationState) postgres.Lsn {
+	if replicationState.ReceivedWalLsn > replicationState.WalLsn {
+		return replicationState.ReceivedWalLsn
+	}
+	return replicationState.WalLsn
+}
+
+func (r *PrimaryToReplicaFailOver) createFailoverSelection(newPrimary statefulset.StatefulSetWrapper) *v1.KubegresFailoverSelection {
+
+	failoverSelection := &v1.KubegresFailoverSelection{
+		Time:        metav1.Now(),
+		PromotedPod: newPrimary.Pod.Pod.Name,
+		IsManual:    r.isManualFailoverRequested(),
+	}
+
+	for _, statefulSetWrapper := range r.resourcesStates.StatefulSets.Replicas.All.GetAllSortedByInstanceIndex() {
+		if !statefulSetWrapper.IsReady {
+			continue
+		}
+
+		candidate := v1.KubegresFailoverCandidate{
+			InstanceIndex: statefulSetWrapper.InstanceIndex,
+			Pod:           statefulSetWrapper.Pod.Pod.Name,
+		}
+
+		replicationState := r.resourcesStates.Replication.GetByInstanceIndex(statefulSetWrapper.InstanceIndex)
+		if replicationState.IsReachable {
+			candidate.ReceivedWalLsn = replicationState.ReceivedWalLsn.String()
+			candidate.ReplayedWalLsn = replicationState.WalLsn.String()
+
+			if statefulSetWrapper.InstanceIndex == newPrimary.InstanceIndex {
+				failoverSelection.PromotedWalLsn = r.getMostAdvancedLsn(replicationState).String()
+			}
+		}
+
+		failoverSelection.Candidates = append(failoverSelection.Candidates, candidate)
+	}
+
+	return failoverSelection
 }
 
 func (r *PrimaryToReplicaFailOver) manuallySelectReplicaToPromote() (statefulset.StatefulSetWrapper, error) {
@@ -240,21 +531,13 @@ func (r *PrimaryToReplicaFailOver) manuallySelectReplicaToPromote() (statefulset
 	return statefulset.StatefulSetWrapper{}, errors.New(errorMsg)
 }
 
-func (r *PrimaryToReplicaFailOver) promoteReplicaToPrimary(newPrimary statefulset.StatefulSetWrapper) error {
-
-	newPrimary.StatefulSet.Labels["replicationRole"] = ctx.PrimaryRoleName
-	newPrimary.StatefulSet.Spec.Template.Labels["replicationRole"] = ctx.PrimaryRoleName
-	volumeMount := core.VolumeMount{
-		Name:      r.resourcesStates.Config.ConfigLocations.PromoteReplica,
-		MountPath: "/tmp/promote_replica_to_primary.sh",
-		SubPath:   states.ConfigMapDataKeyPromoteReplica,
-	}
-
-	initContainer := &newPrimary.StatefulSet.Spec.Template.Spec.InitContainers[0]
-	initContainer.VolumeMounts = append(initContainer.VolumeMounts, volumeMount)
-	initContainer.Command = []string{"sh", "-c", "/tmp/promote_replica_to_primary.sh"}
+// promoteReplicaToPrimary requests the selected Replica to promote online. If PostgreSql could not be requested to
+// promote, the blocking operation is removed so that the failover is attempted again. Once requested, the blocking
+// operation is kept, so that another Replica cannot be promoted, and its completion is checked by isFailOverCompleted.
+func (r *PrimaryToReplicaFailOver) promoteReplicaToPrimary(newPrimary statefulset.StatefulSetWrapper,
+	fencingOperation v1.KubegresFencingOperation) error {
 
-	err := r.activateOperationFailingOver(newPrimary)
+	err := r.activateOperationFailingOver(newPrimary, fencingOperation)
 	if err != nil {
 		r.kubegresContext.Log.ErrorEvent("FailOverOperationActivationErr", err,
 			"Error while activating a blocking operation for the FailOver of a Primary DB.",
@@ -262,23 +545,54 @@ func (r *PrimaryToReplicaFailOver) promoteReplicaToPrimary(newPrimary statefulse
 		return err
 	}
 
+	failoverSelection := r.createFailoverSelection(newPrimary)
+	r.kubegresContext.Status.SetLastFailoverSelection(failoverSelection)
+	r.logFailoverSelection(failoverSelection)
+	r.failoverHistory.RecordNewPrimary(newPrimary.Pod.Pod.Name)
+
 	r.kubegresContext.Log.InfoEvent("FailOver", "FailOver: Promoting Replica to Primary.",
 		"Replica to promote", newPrimary.StatefulSet.Name)
 
-	err2 := r.kubegresContext.Client.Update(r.kubegresContext.Ctx, &newPrimary.StatefulSet)
-	if err2 != nil {
-		r.kubegresContext.Log.ErrorEvent("FailOverErr", err2,
-			"FailOver: Unable to promote Replica to Primary.",
+	err = r.replicaPromotion.PromoteDb(newPrimary)
+	if err != nil {
+		r.kubegresContext.Log.ErrorEvent("FailOverErr", err,
+			"FailOver: Unable to promote the PostgreSql of a Replica to Primary.",
 			"Replica to promote", newPrimary.StatefulSet.Name)
 		r.blockingOperation.RemoveActiveOperation()
+		r.failoverHistory.RecordEnd(v1.FailoverOutcomeFailed)
+		return err
+	}
+
+	if err = r.replicaPromotion.LabelAsPrimary(newPrimary); err != nil {
+		r.kubegresContext.Log.ErrorEvent("FailOverErr", err,
+			"FailOver: Promoted the PostgreSql of a Replica but unable to label it as Primary. It must be fixed manually.",
+			"Replica to promote", newPrimary.StatefulSet.Name)
 		return err
 	}
 
 	return nil
 }
 
+// promoteReplicaToPrimaryOnRestart completes the promotion of a Replica whose PostgreSql did not complete its promotion
+// in time, by restarting its Pod with the promotion script as init container.
+func (r *PrimaryToReplicaFailOver) promoteReplicaToPrimaryOnRestart(newPrimary statefulset.StatefulSetWrapper) error {
+
+	r.kubegresContext.Log.WarningEvent("FailOverPromotionOnRestart",
+		"FailOver: The PostgreSql of the Replica did not complete its promotion in time. Restarting its Pod to complete the promotion.",
+		"Replica to promote", newPrimary.StatefulSet.Name)
+
+	if err := r.replicaPromotion.PromoteOnRestart(newPrimary); err != nil {
+		r.kubegresContext.Log.ErrorEvent("FailOverErr", err,
+			"FailOver: Unable to set the Replica to be promoted when its Pod restarts. It must be fixed manually.",
+			"Replica to promote", newPrimary.StatefulSet.Name)
+		return err
+	}
+	return nil
+}
+
 func (r *PrimaryToReplicaFailOver) waitBeforePromotingReplicaToPrimary(newPrimary statefulset.StatefulSetWrapper) error {
 
+	failingPrimaryPodName := r.getFailingPrimaryPodName()
 	r.deletePrimaryStatefulSet()
 
 	err := r.activateOperationWaitingBeforeFailingOver(newPrimary)
@@ -289,6 +603,12 @@ func (r *PrimaryToReplicaFailOver) waitBeforePromotingReplicaToPrimary(newPrimar
 		return err
 	}
 
+	r.failoverHistory.RecordStart(r.getFailoverReason(), failingPrimaryPodName, newPrimary.Pod.Pod.Name)
+
+	if _, err = r.fencePrimary(); err != nil {
+		return err
+	}
+
 	r.kubegresContext.Log.Info("FailOver: Waiting before promoting a Replica to a Primary...",
 		"Replica to promote", newPrimary.StatefulSet.Name)
 	return nil
@@ -300,14 +620,39 @@ func (r *PrimaryToReplicaFailOver) activateOperationWaitingBeforeFailingOver(new
 		newPrimary.InstanceIndex)
 }
 
-func (r *PrimaryToReplicaFailOver) activateOperationFailingOver(newPrimary statefulset.StatefulSetWrapper) error {
-	return r.blockingOperation.ActivateOperationOnStatefulSet(operation.OperationIdPrimaryDbCountSpecEnforcement,
+func (r *PrimaryToReplicaFailOver) activateOperationFailingOver(newPrimary statefulset.StatefulSetWrapper,
+	fencingOperation v1.KubegresFencingOperation) error {
+	return r.blockingOperation.ActivateOperationOnStatefulSetFailOver(operation.OperationIdPrimaryDbCountSpecEnforcement,
 		operation.OperationStepIdPrimaryDbFailingOver,
-		newPrimary.InstanceIndex)
+		newPrimary.InstanceIndex,
+		fencingOperation)
+}
+
+// fencePrimary continues the fencing of the failing Primary and records its state in the active blocking operation.
+// A Replica must not be promoted until the fencing is confirmed, otherwise two Primaries could accept writes.
+func (r *PrimaryToReplicaFailOver) fencePrimary() (v1.KubegresFencingOperation, error) {
+
+	fencingOperation, err := r.primaryFencing.Fence(r.blockingOperation.GetActiveOperation().FencingOperation)
+	r.blockingOperation.SetActiveOperationFencing(fencingOperation)
+	if err != nil {
+		return fencingOperation, err
+	}
+
+	if !fencingOperation.IsFenced {
+		r.kubegresContext.Log.Info("FailOver: Waiting for the Pod of the failing Primary to be fenced before promoting a Replica.",
+			"Pods to fence", fencingOperation.Pods)
+	}
+
+	return fencingOperation, nil
 }
 
 func (r *PrimaryToReplicaFailOver) deletePrimaryStatefulSet() {
 
+	// The PVC of the failing Primary is handled according to the policy 'failover.pvc' once the failover is completed
+	if failingPrimaryInstanceIndex := r.getFailingPrimaryInstanceIndex(); failingPrimaryInstanceIndex > 0 {
+		r.kubegresContext.Status.SetFailedPrimaryInstanceIndex(failingPrimaryInstanceIndex)
+	}
+
 	statefulSetToDelete := r.resourcesStates.StatefulSets.Primary.StatefulSet
 	r.kubegresContext.Log.Info("FailOver: Deleting the failing Primary StatefulSet.",
 		"Primary name", statefulSetToDelete.Name)
@@ -320,6 +665,41 @@ func (r *PrimaryToReplicaFailOver) deletePrimaryStatefulSet() {
 	}
 }
 
+// getFailingPrimaryInstanceIndex returns the instance index of the failing Primary. If its StatefulSet does not exist
+// anymore, the index is retrieved from the instances set in the status by the previous reconciliation.
+func (r *PrimaryToReplicaFailOver) getFailingPrimaryInstanceIndex() int32 {
+
+	primary := r.resourcesStates.StatefulSets.Primary
+	if primary.IsDeployed {
+		return primary.InstanceIndex
+	}
+
+	for _, instance := range r.kubegresContext.Status.GetInstances() {
+		if instance.Role == ctx.PrimaryRoleName {
+			return instance.InstanceIndex
+		}
+	}
+
+	return 0
+}
+
+// getFailingPrimaryPodName returns the Pod of the failing Primary. If it does not exist anymore, it is retrieved
+// from the status set by the previous reconciliation.
+func (r *PrimaryToReplicaFailOver) getFailingPrimaryPodName() string {
+	primary := r.resourcesStates.StatefulSets.Primary
+	if primary.IsDeployed && primary.Pod.IsDeployed {
+		return primary.Pod.Pod.Name
+	}
+	return r.kubegresContext.Status.GetCurrentPrimary()
+}
+
+func (r *PrimaryToReplicaFailOver) getFailoverReason() string {
+	if r.isManualFailoverRequested() {
+		return v1.FailoverReasonManualPromotion
+	}
+	return v1.FailoverReasonPrimaryUnhealthy
+}
+
 func (r *PrimaryToReplicaFailOver) logFailoverCannotHappenAsNoReplicaDeployed() {
 	message := ""
 	if r.isManualFailoverRequested() {
@@ -346,6 +726,30 @@ func (r *PrimaryToReplicaFailOver) logFailoverCannotHappenAsAutomaticFailoverIsD
 			"or remove that field from the YAML.")
 }
 
+func (r *PrimaryToReplicaFailOver) logFailoverCannotHappenAsAutomaticFailoverIsSuspended() {
+	r.kubegresContext.Log.WarningEvent("AutomaticFailoverIsSuspended",
+		"A failover is required for a Primary Pod as it is not healthy. "+
+			"However, a failover cannot happen because the automatic failovers are suspended as they happened too often. "+
+			"Please check the status field 'failoverSuspension'. To resume the automatic failovers, set the annotation '"+
+			ctx.FailoverSuspensionAckAnnotationKey+"' on the Kubegres resource. "+
+			"A Replica can also be promoted with the field 'failover.promotePod'.")
+}
+
+func (r *PrimaryToReplicaFailOver) logFailoverSelection(failoverSelection *v1.KubegresFailoverSelection) {
+
+	var candidates []string
+	for _, candidate := range failoverSelection.Candidates {
+		candidates = append(candidates, candidate.Pod+" (received: '"+candidate.ReceivedWalLsn+
+			"', replayed: '"+candidate.ReplayedWalLsn+"')")
+	}
+
+	r.kubegresContext.Log.InfoEvent("FailOverReplicaSelected",
+		"FailOver: Selected the Replica to promote to Primary.",
+		"Replica to promote", failoverSelection.PromotedPod,
+		"WAL LSN", failoverSelection.PromotedWalLsn,
+		"Candidates", strings.Join(candidates, ", "))
+}
+
 func (r *PrimaryToReplicaFailOver) logManualFailoverIsRequested() {
 	r.kubegresContext.Log.InfoEvent("ManualFailover",
 		"A manual failover to promote a Replica as a Primary was requested.")
@@ -359,6 +763,55 @@ func (r *PrimaryToReplicaFailOver) logFailoverCannotHappenAsNoHealthyReplica() s
 	return errorMsg
 }
 
+func (r *PrimaryToReplicaFailOver) logFailoverCannotHappenAsNoSynchronousReplica() string {
+	errorReason := "FailoverCannotHappenAsNotFoundSynchronousReplicaErr"
+	errorMsg := "We cannot Failover to a Replica because the field 'spec.replication.mode' is set to 'synchronous' " +
+		"and none of the ready Replicas was synchronous. Promoting one of them could lose committed transactions. " +
+		"Primary has to be fixed manually, or a Replica can be promoted with the field 'failover.promotePod'."
+	r.kubegresContext.Log.ErrorEvent(errorReason, errors.New(""), errorMsg)
+	r.setFailoverBlockedCondition("NoSynchronousReplica", errorMsg)
+	return errorMsg
+}
+
+func (r *PrimaryToReplicaFailOver) logFailoverCannotHappenAsReplicasLagTooMuch(maximumLagBytes int64) string {
+	errorReason := "FailoverCannotHappenAsReplicasLagTooMuchErr"
+	errorMsg := "We cannot Failover to a Replica because all the ready Replicas lag more than " +
+		strconv.FormatInt(maximumLagBytes, 10) + " bytes, as set in the field 'failover.maximumLagBytes', " +
+		"or their lag could not be measured. Promoting one of them could lose transactions. " +
+		"Primary has to be fixed manually, or a Replica can be promoted with the field 'failover.promotePod'."
+	r.kubegresContext.Log.ErrorEvent(errorReason, errors.New(""), errorMsg)
+	r.setFailoverBlockedCondition(failoverBlockedReasonReplicasLagTooMuch, errorMsg)
+	return errorMsg
+}
+
+func (r *PrimaryToReplicaFailOver) logFailoverCannotHappenAsNoPromotableReplica() string {
+	errorReason := "FailoverCannotHappenAsNotFoundPromotableReplicaErr"
+	errorMsg := "We cannot Failover to a Replica because the Node of every ready Replica either matches the field " +
+		"'failover.neverPromoteLabelSelector' or could not be retrieved. " +
+		"Primary has to be fixed manually, or a Replica can be promoted with the field 'failover.promotePod'."
+	r.kubegresContext.Log.ErrorEvent(errorReason, errors.New(""), errorMsg)
+	r.setFailoverBlockedCondition("NoPromotableReplica", errorMsg)
+	return errorMsg
+}
+
+// setFailoverBlockedCondition reports that a failover is required but cannot happen. The condition is reset
+// by the conditions status updater once the Primary is available again or a failover has started.
+func (r *PrimaryToReplicaFailOver) setFailoverBlockedCondition(reason, message string) {
+	r.kubegresContext.Status.SetCondition(metav1.Condition{
+		Type:    v1.ConditionTypeFailoverBlocked,
+		Status:  metav1.ConditionTrue,
+		Reason:  reason,
+		Message: message,
+	})
+}
+
+// isFailoverBlockedBy returns whether the failover is already reported as blocked for the given reason, so that
+// the same event is not emitted on every reconciliation.
+func (r *PrimaryToReplicaFailOver) isFailoverBlockedBy(reason string) bool {
+	condition := r.kubegresContext.Status.GetCondition(v1.ConditionTypeFailoverBlocked)
+	return condition != nil && condition.Status == metav1.ConditionTrue && condition.Reason == reason
+}
+
 func (r *PrimaryToReplicaFailOver) logManualFailoverCannotHappenAsConfigErr() string {
 	errorReason := "ManualFailoverCannotHappenAsConfigErr"
 	errorMsg := "The value of the field 'failover.promotePod' is set to '" + r.getPodToManuallyPromote() + "'. " +
@@ -0,0 +1,126 @@
+/*
+Copyright 2021 Reactive Tech Limited.
+"Reactive Tech Limited" is a company located in England, United Kingdom.
+https://www.reactive-tech.io
+
+Lead Developer: Alex Arica
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package failover
+
+import (
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"reactive-tech.io/kubegres/controllers/ctx"
+	"reactive-tech.io/kubegres/controllers/states/statefulset"
+)
+
+// PromotionPreference ranks the Replicas which can be promoted by an automatic failover with the fields
+// 'failover.preferredZones' and 'failover.neverPromoteLabelSelector'. Both are matched against the Node of
+// the Pod of a Replica.
+type PromotionPreference struct {
+	kubegresContext ctx.KubegresContext
+}
+
+func CreatePromotionPreference(kubegresContext ctx.KubegresContext) PromotionPreference {
+	return PromotionPreference{kubegresContext: kubegresContext}
+}
+
+func (r *PromotionPreference) IsConfigured() bool {
+	failover := r.kubegresContext.Kubegres.Spec.Failover
+	return len(failover.PreferredZones) > 0 || failover.NeverPromoteLabelSelector != nil
+}
+
+// GetMostPreferredReplicas returns the Replicas of the most preferred zone which can be promoted. A Replica whose
+// Node could not be retrieved is not returned, as it is unknown whether it can be promoted.
+func (r *PromotionPreference) GetMostPreferredReplicas(replicas []statefulset.StatefulSetWrapper) []statefulset.StatefulSetWrapper {
+
+	var mostPreferredReplicas []statefulset.StatefulSetWrapper
+	mostPreferredRank := -1
+
+	for _, replica := range replicas {
+
+		rank, canBePromoted := r.getRank(replica)
+		if !canBePromoted {
+			continue
+		}
+
+		if mostPreferredRank == -1 || rank < mostPreferredRank {
+			mostPreferredReplicas = nil
+			mostPreferredRank = rank
+		}
+
+		if rank == mostPreferredRank {
+			mostPreferredReplicas = append(mostPreferredReplicas, replica)
+		}
+	}
+
+	return mostPreferredReplicas
+}
+
+// getRank returns the index of the zone of a Replica in 'failover.preferredZones', or the number of preferred zones
+// if its zone is not listed, so that the lowest rank is the most preferred. It also returns whether the Replica
+// can be promoted.
+func (r *PromotionPreference) getRank(replica statefulset.StatefulSetWrapper) (rank int, canBePromoted bool) {
+
+	node, err := r.getNode(replica)
+	if err != nil {
+		r.kubegresContext.Log.ErrorEvent("FailoverPromotionPreferenceErr", err,
+			"Unable to retrieve the Node of a Replica to check whether it can be promoted. It is not promoted.",
+			"Replica", replica.StatefulSet.Name)
+		return 0, false
+	}
+
+	if r.isNeverPromoted(node) {
+		r.kubegresContext.Log.Info("FailOver: A Replica cannot be promoted as its Node matches 'failover.neverPromoteLabelSelector'.",
+			"Replica", replica.StatefulSet.Name, "Node", node.Name)
+		return 0, false
+	}
+
+	preferredZones := r.kubegresContext.Kubegres.Spec.Failover.PreferredZones
+	zone := node.Labels[core.LabelTopologyZone]
+	for index, preferredZone := range preferredZones {
+		if zone != "" && zone == preferredZone {
+			return index, true
+		}
+	}
+
+	return len(preferredZones), true
+}
+
+func (r *PromotionPreference) isNeverPromoted(node core.Node) bool {
+
+	labelSelector := r.kubegresContext.Kubegres.Spec.Failover.NeverPromoteLabelSelector
+	if labelSelector == nil {
+		return false
+	}
+
+	// An invalid selector is rejected by the spec checker before any failover can happen
+	selector, err := metav1.LabelSelectorAsSelector(labelSelector)
+	if err != nil {
+		return false
+	}
+
+	return selector.Matches(labels.Set(node.Labels))
+}
+
+func (r *PromotionPreference) getNode(replica statefulset.StatefulSetWrapper) (core.Node, error) {
+	node := core.Node{}
+	nodeKey := types.NamespacedName{Name: replica.Pod.Pod.Spec.NodeName}
+	err := r.kubegresContext.Client.Get(r.kubegresContext.Ctx, nodeKey, &node)
+	return node, err
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2021 Reactive Tech Limited.
+"Reactive Tech Limited" is a company located in England, United Kingdom.
+https://www.reactive-tech.io
+
+Lead Developer: Alex Arica
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package failover
+
+import (
+	"errors"
+	"strings"
+
+	core "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"reactive-tech.io/kubegres/controllers/ctx"
+	"reactive-tech.io/kubegres/controllers/postgres"
+	"reactive-tech.io/kubegres/controllers/states"
+	"reactive-tech.io/kubegres/controllers/states/statefulset"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ReplicaPromotion promotes a Replica as a Primary without restarting its Pod.
+//
+// PostgreSql is requested to promote online by calling 'pg_promote()' as superuser, without waiting for the promotion
+// to complete, so that the reconciliation is not blocked. Once requested, only the labels of the StatefulSet and of
+// the Pod are set to Primary, so that the Primary Service selects the Pod. The labels of the Pod template are left as
+// they are, as updating them would restart the Pod. They are set to Primary by StatefulSetsSpecsEnforcer the next
+// time the spec of the StatefulSet is updated, since its Pod is restarted anyway. The failover and the switchover
+// check on the next reconciliations that PostgreSql left the recovery before they complete.
+//
+// If PostgreSql did not complete its promotion within 'promotionWaitSeconds', the Pod might restart before the
+// promotion is completed. In that case only, the StatefulSet is also set to run the script
+// 'promote_replica_to_primary.sh' as init container, which restarts the Pod and promotes PostgreSql when it starts.
+type ReplicaPromotion struct {
+	kubegresContext ctx.KubegresContext
+	resourcesStates states.ResourcesStates
+	dbConnector     postgres.DbConnector
+}
+
+// The number of seconds to wait for PostgreSql to complete its promotion before promoting it on restart
+const promotionWaitSeconds = 60
+
+const promoteReplicaScriptPath = "/tmp/promote_replica_to_primary.sh"
+
+func CreateReplicaPromotion(kubegresContext ctx.KubegresContext, resourcesStates states.ResourcesStates) ReplicaPromotion {
+	return ReplicaPromotion{
+		kubegresContext: kubegresContext,
+		resourcesStates: resourcesStates,
+		dbConnector:     postgres.CreateDbConnector(kubegresContext),
+	}
+}
+
+// PromoteDb requests the PostgreSql instance of a Replica to promote, without waiting for the promotion to complete.
+// It does nothing if the instance is already promoted. It connects as superuser rather than as the replication role,
+// as the execution of 'pg_promote()' is only granted to the replication role by the script
+// 'primary_create_replication_role.sh' of the current Kubegres version. It is not granted on the clusters created
+// by an older version or with a custom version of that script, nor on the data copied from an external Primary with
+// 'spec.standby'.
+func (r *ReplicaPromotion) PromoteDb(replica statefulset.StatefulSetWrapper) error {
+
+	pod := replica.Pod.Pod
+	connection, err := r.dbConnector.ConnectAsSuperUser(pod)
+	if err != nil {
+		return err
+	}
+	defer connection.Close()
+
+	var isInRecovery bool
+	if err := connection.QueryRow("SELECT pg_is_in_recovery()").Scan(&isInRecovery); err != nil {
+		return err
+	}
+
+	if !isInRecovery {
+		r.kubegresContext.Log.Info("Skipping the promotion of a Replica as its PostgreSql is already promoted.", "Pod name", pod.Name)
+		return nil
+	}
+
+	var isRequested bool
+	if err := connection.QueryRow("SELECT pg_promote(false)").Scan(&isRequested); err != nil {
+		return err
+	}
+
+	if !isRequested {
+		return errors.New("PostgreSql could not be requested to promote")
+	}
+
+	r.kubegresContext.Log.Info("Requested the PostgreSql of a Replica to promote.", "Pod name", pod.Name)
+	return nil
+}
+
+// IsPromoted returns whether the PostgreSql instance with the given instance index is reachable and not in recovery,
+// as loaded at the start of the reconciliation.
+func (r *ReplicaPromotion) IsPromoted(instanceIndex int32) bool {
+	replicationState := r.resourcesStates.Replication.GetByInstanceIndex(instanceIndex)
+	return replicationState.IsReachable && !replicationState.IsInRecovery
+}
+
+// IsPromotionOnRestartRequired returns whether PostgreSql was requested to promote for longer than
+// 'promotionWaitSeconds' without completing its promotion, and its StatefulSet is not promoting it on restart yet.
+func (r *ReplicaPromotion) IsPromotionOnRestartRequired(replica statefulset.StatefulSetWrapper, nbreSecondsSincePromotionRequested int64) bool {
+	return nbreSecondsSincePromotionRequested >= promotionWaitSeconds &&
+		!r.IsPromoted(replica.InstanceIndex) &&
+		!r.isPromotingOnRestart(replica)
+}
+
+// LabelAsPrimary labels the StatefulSet and the Pod of a promoted Replica as Primary, so that the Primary Service
+// selects its Pod. The Pod template is not updated, so that the Pod is not restarted.
+func (r *ReplicaPromotion) LabelAsPrimary(replica statefulset.StatefulSetWrapper) error {
+
+	statefulSet := replica.StatefulSet
+	primaryStatefulSet := statefulSet.DeepCopy()
+	primaryStatefulSet.Labels["replicationRole"] = ctx.PrimaryRoleName
+
+	err := r.kubegresContext.Client.Patch(r.kubegresContext.Ctx, primaryStatefulSet, client.MergeFrom(&statefulSet))
+	if err != nil {
+		return err
+	}
+
+	return r.LabelPodAsPrimary(replica.Pod)
+}
+
+// LabelPodAsPrimary labels the Pod of a Primary as Primary. A Pod recreated from the template of a Replica promoted
+// online is labelled as Replica until the template is updated.
+func (r *ReplicaPromotion) LabelPodAsPrimary(podWrapper statefulset.PodWrapper) error {
+
+	if !podWrapper.IsDeployed || podWrapper.Pod.Labels["replicationRole"] == ctx.PrimaryRoleName {
+		return nil
+	}
+
+	pod := podWrapper.Pod
+	primaryPod := pod.DeepCopy()
+	primaryPod.Labels["replicationRole"] = ctx.PrimaryRoleName
+
+	err := r.kubegresContext.Client.Patch(r.kubegresContext.Ctx, primaryPod, client.MergeFrom(&pod))
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// PromoteOnRestart labels a Replica as Primary and sets its init container to run the script
+// 'promote_replica_to_primary.sh'. It is only used when PostgreSql did not complete its promotion in time, so that
+// the promotion is completed if the Pod restarts. Updating the template of the StatefulSet restarts the Pod.
+func (r *ReplicaPromotion) PromoteOnRestart(replica statefulset.StatefulSetWrapper) error {
+
+	statefulSet := replica.StatefulSet.DeepCopy()
+	statefulSet.Labels["replicationRole"] = ctx.PrimaryRoleName
+	statefulSet.Spec.Template.Labels["replicationRole"] = ctx.PrimaryRoleName
+
+	volumeMount := core.VolumeMount{
+		Name:      r.resourcesStates.Config.ConfigLocations.PromoteReplica,
+		MountPath: promoteReplicaScriptPath,
+		SubPath:   states.ConfigMapDataKeyPromoteReplica,
+	}
+
+	initContainer := &statefulSet.Spec.Template.Spec.InitContainers[0]
+	initContainer.VolumeMounts = append(initContainer.VolumeMounts, volumeMount)
+	initContainer.Command = []string{"sh", "-c", promoteReplicaScriptPath}
+
+	if err := r.kubegresContext.Client.Update(r.kubegresContext.Ctx, statefulSet); err != nil {
+		return err
+	}
+
+	return r.LabelPodAsPrimary(replica.Pod)
+}
+
+func (r *ReplicaPromotion) isPromotingOnRestart(replica statefulset.StatefulSetWrapper) bool {
+	initContainers := replica.StatefulSet.Spec.Template.Spec.InitContainers
+	return len(initContainers) > 0 && strings.Join(initContainers[0].Command, " ") == "sh -c "+promoteReplicaScriptPath
+}
@@ -60,7 +60,7 @@ func (r *AllStatefulSetsSpecEnforcer) CreateOperationConfigForStatefulSetSpecUpd
 	return operation.BlockingOperationConfig{
 		OperationId:                         operation.OperationIdStatefulSetSpecEnforcing,
 		StepId:                              operation.OperationStepIdStatefulSetSpecUpdating,
-		TimeOutInSeconds:                    300,
+		TimeOutInSeconds:                    r.kubegresContext.GetSpecUpdatingTimeoutSeconds(),
 		CompletionChecker:                   r.isStatefulSetSpecUpdated,
 		AfterCompletionMoveToTransitionStep: true,
 	}
@@ -71,7 +71,7 @@ func (r *AllStatefulSetsSpecEnforcer) CreateOperationConfigForStatefulSetSpecPod
 	return operation.BlockingOperationConfig{
 		OperationId:                         operation.OperationIdStatefulSetSpecEnforcing,
 		StepId:                              operation.OperationStepIdStatefulSetPodSpecUpdating,
-		TimeOutInSeconds:                    300,
+		TimeOutInSeconds:                    r.kubegresContext.GetSpecUpdatingTimeoutSeconds(),
 		CompletionChecker:                   r.isStatefulSetPodSpecUpdated,
 		AfterCompletionMoveToTransitionStep: true,
 	}
@@ -82,7 +82,7 @@ func (r *AllStatefulSetsSpecEnforcer) CreateOperationConfigForStatefulSetWaiting
 	return operation.BlockingOperationConfig{
 		OperationId:                         operation.OperationIdStatefulSetSpecEnforcing,
 		StepId:                              operation.OperationStepIdStatefulSetWaitingOnStuckPod,
-		TimeOutInSeconds:                    300,
+		TimeOutInSeconds:                    r.kubegresContext.GetSpecUpdatingTimeoutSeconds(),
 		CompletionChecker:                   r.isStatefulSetPodNotStuck,
 		AfterCompletionMoveToTransitionStep: true,
 	}
@@ -140,7 +140,9 @@ func (r *AllStatefulSetsSpecEnforcer) isStandbyEnabled() bool {
 }
 
 func (r *AllStatefulSetsSpecEnforcer) getAllReverseSortedByInstanceIndex() []statefulset.StatefulSetWrapper {
-	replicas := r.resourcesStates.StatefulSets.Replicas.All.GetAllReverseSortedByInstanceIndex()
+	var replicas []statefulset.StatefulSetWrapper
+	replicas = append(replicas, r.resourcesStates.StatefulSets.DelayedReplicas.All.GetAllReverseSortedByInstanceIndex()...)
+	replicas = append(replicas, r.resourcesStates.StatefulSets.Replicas.All.GetAllReverseSortedByInstanceIndex()...)
 	if r.isStandbyEnabled() {
 		return replicas
 	}
@@ -208,7 +210,11 @@ func (r *AllStatefulSetsSpecEnforcer) verifySpecEnforcementIsAppliedToPod(statef
 	specDifferences StatefulSetSpecDifferences) (isPodReadyAndSpecUpdated bool, err error) {
 
 	podWrapper := statefulSetWrapper.Pod
-	podSpecComparator := comparator.PodSpecComparator{Pod: podWrapper.Pod, PostgresSpec: r.kubegresContext.Kubegres.Spec}
+	podSpecComparator := comparator.PodSpecComparator{
+		Pod:              podWrapper.Pod,
+		PostgresSpec:     r.kubegresContext.Kubegres.Spec,
+		PasswordsVersion: r.kubegresContext.Status.GetPasswordsVersion(),
+	}
 	isPodSpecUpToDate := podSpecComparator.IsSpecUpToDate()
 
 	if podWrapper.IsStuck {
@@ -316,7 +322,11 @@ func (r *AllStatefulSetsSpecEnforcer) isStatefulSetPodSpecUpdated(operation post
 		return false
 	}
 
-	podSpecComparator := comparator.PodSpecComparator{Pod: podWrapper.Pod, PostgresSpec: r.kubegresContext.Kubegres.Spec}
+	podSpecComparator := comparator.PodSpecComparator{
+		Pod:              podWrapper.Pod,
+		PostgresSpec:     r.kubegresContext.Kubegres.Spec,
+		PasswordsVersion: r.kubegresContext.Status.GetPasswordsVersion(),
+	}
 	return podSpecComparator.IsSpecUpToDate()
 }
 
@@ -0,0 +1,75 @@
+/*
+Copyright 2021 Reactive Tech Limited.
+"Reactive Tech Limited" is a company located in England, United Kingdom.
+https://www.reactive-tech.io
+
+Lead Developer: Alex Arica
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statefulset_spec
+
+import (
+	apps "k8s.io/api/apps/v1"
+	"reactive-tech.io/kubegres/controllers/ctx"
+)
+
+// PasswordsVersionSpecEnforcer sets in the template of the Pods an annotation with the version of the passwords applied
+// to PostgreSql. When the passwords are rotated, the annotation changes and the Pods are restarted one by one,
+// so that their env-vars contain the new passwords.
+type PasswordsVersionSpecEnforcer struct {
+	kubegresContext ctx.KubegresContext
+}
+
+func CreatePasswordsVersionSpecEnforcer(kubegresContext ctx.KubegresContext) PasswordsVersionSpecEnforcer {
+	return PasswordsVersionSpecEnforcer{kubegresContext: kubegresContext}
+}
+
+func (r *PasswordsVersionSpecEnforcer) GetSpecName() string {
+	return "PasswordsVersion"
+}
+
+func (r *PasswordsVersionSpecEnforcer) CheckForSpecDifference(statefulSet *apps.StatefulSet) StatefulSetSpecDifference {
+
+	expected := r.kubegresContext.Status.GetPasswordsVersion()
+	if expected == "" {
+		return StatefulSetSpecDifference{}
+	}
+
+	current := statefulSet.Spec.Template.Annotations[ctx.PasswordsVersionAnnotationKey]
+
+	if current != expected {
+		return StatefulSetSpecDifference{
+			SpecName: r.GetSpecName(),
+			Current:  current,
+			Expected: expected,
+		}
+	}
+
+	return StatefulSetSpecDifference{}
+}
+
+func (r *PasswordsVersionSpecEnforcer) EnforceSpec(statefulSet *apps.StatefulSet) (wasSpecUpdated bool, err error) {
+
+	if statefulSet.Spec.Template.Annotations == nil {
+		statefulSet.Spec.Template.Annotations = map[string]string{}
+	}
+	statefulSet.Spec.Template.Annotations[ctx.PasswordsVersionAnnotationKey] = r.kubegresContext.Status.GetPasswordsVersion()
+
+	return true, nil
+}
+
+func (r *PasswordsVersionSpecEnforcer) OnSpecEnforcedSuccessfully(statefulSet *apps.StatefulSet) error {
+	return nil
+}
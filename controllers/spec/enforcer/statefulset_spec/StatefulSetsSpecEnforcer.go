@@ -85,6 +85,10 @@ func (r *StatefulSetsSpecsEnforcer) EnforceSpec(statefulSet *apps.StatefulSet) e
 
 	if len(updatedSpecDifferences) > 0 {
 		r.kubegresContext.Log.Info("Updating Spec of a StatefulSet", "StatefulSet name", statefulSet.Name)
+
+		// The Pod template of a Replica promoted online is still labelled as Replica, so that its Pod was not
+		// restarted. Its Pod is restarted with the updated spec, so the template is labelled as Primary too.
+		statefulSet.Spec.Template.Labels["replicationRole"] = statefulSet.Labels["replicationRole"]
 		err := r.kubegresContext.Client.Update(r.kubegresContext.Ctx, statefulSet)
 		if err != nil {
 			return err
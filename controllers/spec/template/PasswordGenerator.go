@@ -0,0 +1,49 @@
+/*
+Copyright 2021 Reactive Tech Limited.
+"Reactive Tech Limited" is a company located in England, United Kingdom.
+https://www.reactive-tech.io
+
+Lead Developer: Alex Arica
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package template
+
+import (
+	"crypto/rand"
+	"math/big"
+)
+
+const (
+	passwordLength     = 32
+	passwordCharacters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+)
+
+// GeneratePassword returns a random password made of alphanumeric characters so that it can be used as it is
+// in a PostgreSql connection string and in a SQL statement.
+func GeneratePassword() (string, error) {
+
+	password := make([]byte, passwordLength)
+	nbreCharacters := big.NewInt(int64(len(passwordCharacters)))
+
+	for i := range password {
+		index, err := rand.Int(rand.Reader, nbreCharacters)
+		if err != nil {
+			return "", err
+		}
+		password[i] = passwordCharacters[index.Int64()]
+	}
+
+	return string(password), nil
+}
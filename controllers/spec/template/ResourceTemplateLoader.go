@@ -74,6 +74,17 @@ func (r *ResourceTemplateLoader) LoadBackUpCronJob() (cronJob batch.CronJob, err
 	return *obj.(*batch.CronJob), nil
 }
 
+func (r *ResourceTemplateLoader) LoadPasswordsSecret() (secret core.Secret, err error) {
+	obj, err := r.decodeYaml(yaml.PasswordsSecretTemplate)
+
+	if err != nil {
+		r.log.Error(err, "Unable to load Kubegres Passwords Secret. Given error:")
+		return core.Secret{}, err
+	}
+
+	return *obj.(*core.Secret), nil
+}
+
 func (r *ResourceTemplateLoader) loadService(yamlContents string) (serviceTemplate core.Service, err error) {
 
 	obj, err := r.decodeYaml(yamlContents)
@@ -22,6 +22,7 @@ package template
 
 import (
 	"strconv"
+	"time"
 
 	apps "k8s.io/api/apps/v1"
 	batch "k8s.io/api/batch/v1"
@@ -131,11 +132,40 @@ func (r *ResourcesCreatorFromTemplate) CreateReplicaStatefulSet(statefulSetInsta
 	initContainer.Env[0].Value = primaryServiceName
 	initContainer.Env[1].ValueFrom = r.getEnvVar(ctx.EnvVarNameOfPostgresReplicationUserPsw).ValueFrom
 	initContainer.Env[2].Value = postgresSpec.Database.VolumeMount + "/" + ctx.DefaultDatabaseFolder
+	initContainer.Env[3].ValueFrom = r.getEnvVar(ctx.EnvVarNameOfPostgresSuperUserPsw).ValueFrom
 	initContainer.VolumeMounts[0].MountPath = postgresSpec.Database.VolumeMount
 
 	return statefulSetTemplate, nil
 }
 
+// CreateDelayedReplicaStatefulSet creates a Replica StatefulSet labeled with the replication role 'delayed-replica',
+// so that its Pod is not selected by the Replica Service.
+func (r *ResourcesCreatorFromTemplate) CreateDelayedReplicaStatefulSet(statefulSetInstanceIndex int32) (apps.StatefulSet, error) {
+
+	statefulSetTemplate, err := r.CreateReplicaStatefulSet(statefulSetInstanceIndex)
+	if err != nil {
+		return apps.StatefulSet{}, err
+	}
+
+	statefulSetTemplate.Labels["replicationRole"] = ctx.DelayedReplicaRoleName
+	statefulSetTemplate.Spec.Template.Labels["replicationRole"] = ctx.DelayedReplicaRoleName
+
+	return statefulSetTemplate, nil
+}
+
+// SetReplicaUpstreamHostName sets the host name of the Replica from which a new Replica is copied and then streams
+// the WAL with a cascading replication. If it is empty, the new Replica is copied from the Primary.
+func (r *ResourcesCreatorFromTemplate) SetReplicaUpstreamHostName(replicaStatefulSet *apps.StatefulSet, upstreamHostName string) {
+	replicaStatefulSet.Spec.Template.Spec.InitContainers[0].Env[4].Value = upstreamHostName
+}
+
+// SetReplicaToRewindFormerPrimary sets a Replica reusing the PVC of a former Primary to rewind its data with 'pg_rewind'
+// the first time its Pod starts. The rewind is identified by a new ID, so that it does not happen again once the Replica
+// is promoted online and its Pod restarts.
+func (r *ResourcesCreatorFromTemplate) SetReplicaToRewindFormerPrimary(replicaStatefulSet *apps.StatefulSet) {
+	replicaStatefulSet.Spec.Template.Spec.InitContainers[0].Env[5].Value = strconv.FormatInt(time.Now().UnixNano(), 10)
+}
+
 func (r *ResourcesCreatorFromTemplate) CreateBackUpCronJob(configMapNameForBackUp string) (batch.CronJob, error) {
 
 	backUpCronJob, err := r.templateFromFiles.LoadBackUpCronJob()
@@ -177,6 +207,46 @@ func (r *ResourcesCreatorFromTemplate) CreateBackUpCronJob(configMapNameForBackU
 	return backUpCronJob, nil
 }
 
+// CreatePasswordsSecret creates a Secret containing a new random password for the superuser and for the replication user.
+func (r *ResourcesCreatorFromTemplate) CreatePasswordsSecret() (core.Secret, error) {
+
+	superUserPassword, err := GeneratePassword()
+	if err != nil {
+		return core.Secret{}, err
+	}
+
+	replicationUserPassword, err := GeneratePassword()
+	if err != nil {
+		return core.Secret{}, err
+	}
+
+	return r.createPasswordsSecret(r.kubegresContext.GetPasswordsSecretName(), superUserPassword, replicationUserPassword)
+}
+
+// CreateAppliedPasswordsSecret creates the Secret storing the passwords which are set in PostgreSql.
+func (r *ResourcesCreatorFromTemplate) CreateAppliedPasswordsSecret(superUserPassword, replicationUserPassword string) (core.Secret, error) {
+	return r.createPasswordsSecret(r.kubegresContext.GetAppliedPasswordsSecretName(), superUserPassword, replicationUserPassword)
+}
+
+func (r *ResourcesCreatorFromTemplate) createPasswordsSecret(secretName, superUserPassword, replicationUserPassword string) (core.Secret, error) {
+
+	passwordsSecret, err := r.templateFromFiles.LoadPasswordsSecret()
+	if err != nil {
+		return core.Secret{}, err
+	}
+
+	passwordsSecret.Name = secretName
+	passwordsSecret.Namespace = r.kubegresContext.Kubegres.Namespace
+	passwordsSecret.OwnerReferences = r.getOwnerReference()
+	passwordsSecret.Labels["app"] = r.kubegresContext.Kubegres.Name
+	passwordsSecret.StringData = map[string]string{
+		ctx.PasswordsSecretKeySuperUser:       superUserPassword,
+		ctx.PasswordsSecretKeyReplicationUser: replicationUserPassword,
+	}
+
+	return passwordsSecret, nil
+}
+
 func (r *ResourcesCreatorFromTemplate) initService(service *core.Service) {
 
 	resourceName := r.kubegresContext.Kubegres.Name
@@ -211,6 +281,10 @@ func (r *ResourcesCreatorFromTemplate) initStatefulSet(
 	statefulSetTemplate.Spec.Template.Labels["index"] = instanceIndex
 	statefulSetTemplate.Spec.Template.Annotations = r.getCustomAnnotations()
 
+	if passwordsVersion := r.kubegresContext.Status.GetPasswordsVersion(); passwordsVersion != "" {
+		statefulSetTemplate.Spec.Template.Annotations[ctx.PasswordsVersionAnnotationKey] = passwordsVersion
+	}
+
 	statefulSetTemplateSpec := &statefulSetTemplate.Spec.Template.Spec
 
 	if postgresSpec.ImagePullSecrets != nil {
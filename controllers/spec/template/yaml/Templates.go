@@ -115,6 +115,9 @@ data:
     max_connections = 100
     shared_buffers = 128MB
 
+    # Required by 'pg_rewind' to restart a former Primary as a Replica after a switchover or a failover
+    wal_log_hints = on
+
     # Logging
     #log_destination = 'stderr,csvlog'
     #logging_collector = on
@@ -252,10 +255,16 @@ data:
     echo "$dt - Replication role created";
 
 
-  # This script replicates data from the Primary PostgreSql to the Replica database.
-  # It is executed once, the 1st time a Replica PostgreSql container is created.
+  # This script replicates data from the Primary PostgreSql, or from an upstream Replica with a cascading replication,
+  # to the Replica database. It is executed once, the 1st time a Replica PostgreSql container is created.
   # It is run in Replica containers.
   #
+  # The data of a former Primary is only rewound with 'pg_rewind' when the env-var 'FORMER_PRIMARY_REWIND_ID' is set,
+  # which the operator only sets on the StatefulSet rejoining a former Primary after a switchover or reusing its PVC
+  # after a failover. The rewind happens once per StatefulSet: the ID is then written in the file
+  # 'kubegres-rewind-id' next to the data folder, so that a Replica promoted online keeps its data when its Pod restarts.
+  # If the rewind fails, the data is kept and this script fails, so that it can be fixed manually.
+  #
   # If you modify this script, there is a risk of breaking the operator.
   #
   # This script will be located in the folder "/tmp"
@@ -267,12 +276,37 @@ data:
     dt=$(date '+%d/%m/%Y %H:%M:%S');
     echo "$dt - Attempting to copy Primary DB to Replica DB...";
 
+    rewindIdFile="$(dirname $PGDATA)/kubegres-rewind-id"
+    isRewindRequired=false
+    if [ -n "$FORMER_PRIMARY_REWIND_ID" ] && [ "$(cat $rewindIdFile 2>/dev/null)" != "$FORMER_PRIMARY_REWIND_ID" ]; then
+        isRewindRequired=true
+    fi
+
     if [ -z "$(ls -A $PGDATA)" ]; then
 
-        echo "$dt - Copying Primary DB to Replica DB folder: $PGDATA";
-        echo "$dt - Running: pg_basebackup -R -h $PRIMARY_HOST_NAME -D $PGDATA -P -U replication;";
+        # With a cascading replication, a Replica is copied from its upstream Replica and then streams from it.
+        # If the upstream Replica cannot be reached, the Replica is copied from the Primary DB.
+        if [ -n "$UPSTREAM_HOST_NAME" ]; then
+
+            echo "$dt - Copying upstream Replica DB to Replica DB folder: $PGDATA";
+            echo "$dt - Running: pg_basebackup -R -h $UPSTREAM_HOST_NAME -D $PGDATA -P -U replication;";
+
+            isCopied=true
+            pg_basebackup -R -h $UPSTREAM_HOST_NAME -D $PGDATA -P -U replication || isCopied=false
 
-        pg_basebackup -R -h $PRIMARY_HOST_NAME -D $PGDATA -P -U replication;
+            if [ "$isCopied" == false ]; then
+                echo "$dt - Copy from upstream Replica DB failed. Copying Primary DB instead.";
+                rm -rf ${PGDATA:?}/*;
+            fi
+        fi
+
+        if [ -z "$(ls -A $PGDATA)" ]; then
+
+            echo "$dt - Copying Primary DB to Replica DB folder: $PGDATA";
+            echo "$dt - Running: pg_basebackup -R -h $PRIMARY_HOST_NAME -D $PGDATA -P -U replication;";
+
+            pg_basebackup -R -h $PRIMARY_HOST_NAME -D $PGDATA -P -U replication;
+        fi
 
         if [ $UID == 0 ]
         then
@@ -281,14 +315,61 @@ data:
 
         echo "$dt - Copy completed";
 
+    elif [ ! -f "$PGDATA/standby.signal" ] && [ "$isRewindRequired" == true ]; then
+
+        # The folder contains the data of a former Primary DB, e.g. after a switchover.
+        # It is rewound to the timeline of the new Primary DB. If that fails, the data is kept and this script fails.
+        echo "$dt - Replica DB folder contains the data of a former Primary DB: $PGDATA";
+
+        superUserName=${POSTGRES_USER:-postgres}
+        sourceServer="host=$PRIMARY_HOST_NAME user=$superUserName dbname=postgres"
+        isRewound=true
+
+        echo "$dt - Running: pg_rewind --target-pgdata=$PGDATA --source-server=\"$sourceServer\";";
+
+        if [ $UID == 0 ]
+        then
+        PGPASSWORD=$POSTGRES_PASSWORD gosu postgres pg_rewind --target-pgdata=$PGDATA --source-server="$sourceServer" || isRewound=false
+        else
+        PGPASSWORD=$POSTGRES_PASSWORD pg_rewind --target-pgdata=$PGDATA --source-server="$sourceServer" || isRewound=false
+        fi
+
+        if [ "$isRewound" == true ]; then
+
+            # The setting 'primary_conninfo' is escaped for the connection string and then for the config file
+            connInfoPassword=$(printf '%s' "$PGPASSWORD" | sed -e 's/\\/\\\\/g' -e "s/'/\\\\'/g")
+            connInfo=$(printf '%s' "host=$PRIMARY_HOST_NAME user=replication password='$connInfoPassword'" | sed -e 's/\\/\\\\/g' -e "s/'/''/g")
+
+            sed -i -e '/^primary_conninfo/d' -e '/^default_transaction_read_only/d' $PGDATA/postgresql.auto.conf
+            echo "primary_conninfo = '$connInfo'" >> $PGDATA/postgresql.auto.conf
+            touch $PGDATA/standby.signal
+
+            echo "$dt - Rewind completed";
+
+        else
+            echo "$dt - Rewind failed. The Replica DB folder is kept as it is so that it can be fixed manually: $PGDATA";
+            exit 1
+        fi
+
+        if [ $UID == 0 ]
+        then
+        chown -R postgres:postgres $PGDATA;
+        fi
+
     else
         echo "$dt - Skipping copy from Primary DB because Replica DB already exists";
     fi
 
+    if [ "$isRewindRequired" == true ]; then
+        echo "$FORMER_PRIMARY_REWIND_ID" > $rewindIdFile
+    fi
 
-  # This script promotes a Replica to a Primary by creating a trigger-file signaling PostgreSql to start the promotion process.
-  # It is executed once, when a Replica is set to become a Primary.
-  # It is run in a selected Replica container by the operator.
+
+  # The operator promotes a Replica to a Primary online by calling 'pg_promote()', without restarting its Pod.
+  # This script is only set as the init container of a Replica whose PostgreSql did not complete its promotion in time,
+  # so that if its Pod restarts before PostgreSql was promoted, the promotion is completed. PostgreSql is started
+  # without accepting connections, promoted with 'pg_ctl promote' once it has replayed its WAL and then stopped cleanly.
+  # If any of these steps fails, the init container fails, so that the Pod does not start with an unpromoted PostgreSql.
   #
   # If you modify this script, there is a risk of breaking the operator.
   #
@@ -307,16 +388,35 @@ data:
       exit 0
     fi
 
-    promotionTriggerFilePath="$PGDATA/promote_replica_to_primary.log"
+    runAsPostgres=""
+    if [ "$(id -u)" = "0" ]; then
+      runAsPostgres="gosu postgres"
+    fi
 
-    if [ -f "$promotionTriggerFilePath" ]; then
-      echo "$dt - Skipping as the promotion trigger file '$promotionTriggerFilePath' already exists"
-      exit 0
+    echo "$dt - Starting PostgreSql without accepting connections in order to promote it";
+    $runAsPostgres pg_ctl -D "$PGDATA" -o "-c listen_addresses=''" -w -t 300 start
+
+    echo "$dt - Running: pg_ctl promote";
+    if ! $runAsPostgres pg_ctl -D "$PGDATA" -w -t 300 promote; then
+      echo "$dt - ERROR: PostgreSql could not be promoted. It must be fixed manually.";
+      $runAsPostgres pg_ctl -D "$PGDATA" -m fast -w stop || true
+      exit 1
     fi
 
-    echo "$dt - Promoting by creating the promotion trigger file: '$promotionTriggerFilePath'"
-    touch $promotionTriggerFilePath
+    echo "$dt - Stopping the promoted PostgreSql";
+    $runAsPostgres pg_ctl -D "$PGDATA" -m fast -w stop
 
+    echo "$dt - PostgreSql promoted to Primary";
+
+`
+	PasswordsSecretTemplate = `apiVersion: v1
+kind: Secret
+metadata:
+  name: postgres-name-passwords
+  namespace: default
+  labels:
+    app: postgres-name
+type: Opaque
 `
 	PrimaryServiceTemplate = `apiVersion: v1
 kind: Service
@@ -517,6 +617,9 @@ spec:
             - name: PRIMARY_HOST_NAME
             - name: PGPASSWORD
             - name: PGDATA
+            - name: POSTGRES_PASSWORD
+            - name: UPSTREAM_HOST_NAME
+            - name: FORMER_PRIMARY_REWIND_ID
 
           command:
             - sh
@@ -535,7 +638,7 @@ spec:
         - name: postgres-name-1
           image: postgres:latest
           imagePullPolicy: IfNotPresent
-          args: ["-c", "config_file=/etc/postgres.conf", "-c", "hba_file=/etc/pg_hba.conf", "-c", "promote_trigger_file=$(PGDATA)/promote_replica_to_primary.log"]
+          args: ["-c", "config_file=/etc/postgres.conf", "-c", "hba_file=/etc/pg_hba.conf"]
 
           ports:
             - containerPort: 5432
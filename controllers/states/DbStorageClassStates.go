@@ -60,8 +60,12 @@ func (r *DbStorageClassStates) GetStorageClass() (*storage.StorageClass, error)
 
 	namespace := ""
 	resourceName := r.getSpecStorageClassName()
-	resourceKey := client.ObjectKey{Namespace: namespace, Name: resourceName}
 	storageClass := &storage.StorageClass{}
+	if resourceName == "" {
+		return storageClass, nil
+	}
+
+	resourceKey := client.ObjectKey{Namespace: namespace, Name: resourceName}
 
 	err := r.kubegresContext.Client.Get(r.kubegresContext.Ctx, resourceKey, storageClass)
 
@@ -77,5 +81,9 @@ func (r *DbStorageClassStates) GetStorageClass() (*storage.StorageClass, error)
 }
 
 func (r *DbStorageClassStates) getSpecStorageClassName() string {
-	return *r.kubegresContext.Kubegres.Spec.Database.StorageClassName
+	storageClassName := r.kubegresContext.Kubegres.Spec.Database.StorageClassName
+	if storageClassName == nil {
+		return ""
+	}
+	return *storageClassName
 }
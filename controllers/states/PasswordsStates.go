@@ -0,0 +1,96 @@
+/*
+Copyright 2021 Reactive Tech Limited.
+"Reactive Tech Limited" is a company located in England, United Kingdom.
+https://www.reactive-tech.io
+
+Lead Developer: Alex Arica
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package states
+
+import (
+	core "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"reactive-tech.io/kubegres/controllers/ctx"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// PasswordsStates contains the states of the Secrets managed by Kubegres for the passwords of the PostgreSql users:
+// the Secret generated when 'spec.passwords.generate' is true and the Secret storing the passwords applied to PostgreSql.
+type PasswordsStates struct {
+	Generated SecretWrapper
+	Applied   SecretWrapper
+
+	kubegresContext ctx.KubegresContext
+}
+
+type SecretWrapper struct {
+	Name       string
+	IsDeployed bool
+	Secret     core.Secret
+}
+
+func loadPasswordsStates(kubegresContext ctx.KubegresContext) (PasswordsStates, error) {
+	passwordsStates := PasswordsStates{kubegresContext: kubegresContext}
+	err := passwordsStates.loadStates()
+	return passwordsStates, err
+}
+
+func (r *PasswordsStates) loadStates() (err error) {
+
+	r.Generated, err = r.loadSecretWrapper(r.kubegresContext.GetPasswordsSecretName())
+	if err != nil {
+		return err
+	}
+
+	r.Applied, err = r.loadSecretWrapper(r.kubegresContext.GetAppliedPasswordsSecretName())
+	return err
+}
+
+func (r *PasswordsStates) loadSecretWrapper(secretName string) (SecretWrapper, error) {
+
+	secretWrapper := SecretWrapper{Name: secretName}
+
+	secret, err := r.getDeployedSecret(secretName)
+	if err != nil {
+		return secretWrapper, err
+	}
+
+	if secret.Name != "" {
+		secretWrapper.IsDeployed = true
+		secretWrapper.Secret = *secret
+	}
+
+	return secretWrapper, nil
+}
+
+func (r *PasswordsStates) getDeployedSecret(secretName string) (*core.Secret, error) {
+
+	namespace := r.kubegresContext.Kubegres.Namespace
+	secretKey := client.ObjectKey{Namespace: namespace, Name: secretName}
+
+	secret := &core.Secret{}
+	err := r.kubegresContext.Client.Get(r.kubegresContext.Ctx, secretKey, secret)
+
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			err = nil
+		} else {
+			r.kubegresContext.Log.ErrorEvent("PasswordsSecretLoadingErr", err, "Unable to load a deployed Passwords Secret.", "Secret name", secretName)
+		}
+	}
+
+	return secret, err
+}
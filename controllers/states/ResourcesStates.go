@@ -22,26 +22,38 @@ package states
 
 import (
 	"reactive-tech.io/kubegres/controllers/ctx"
+	"reactive-tech.io/kubegres/controllers/states/replication"
 	"reactive-tech.io/kubegres/controllers/states/statefulset"
 )
 
 type ResourcesStates struct {
 	DbStorageClass DbStorageClassStates
 	StatefulSets   statefulset.StatefulSetsStates
+	Replication    replication.ReplicationStates
+	Cascade        replication.CascadeTopology
 	Services       ServicesStates
 	Config         ConfigStates
 	BackUp         BackUpStates
+	Passwords      PasswordsStates
 
 	kubegresContext ctx.KubegresContext
 }
 
 func LoadResourcesStates(kubegresContext ctx.KubegresContext) (ResourcesStates, error) {
 	resourcesStates := ResourcesStates{kubegresContext: kubegresContext}
-	err := resourcesStates.loadStates()
+	err := resourcesStates.loadStates(true)
 	return resourcesStates, err
 }
 
-func (r *ResourcesStates) loadStates() (err error) {
+// LoadResourcesStatesWithoutReplication loads the states of the resources without connecting to the PostgreSql
+// instances. It is used where a quick response is required, such as the admission webhook.
+func LoadResourcesStatesWithoutReplication(kubegresContext ctx.KubegresContext) (ResourcesStates, error) {
+	resourcesStates := ResourcesStates{kubegresContext: kubegresContext}
+	err := resourcesStates.loadStates(false)
+	return resourcesStates, err
+}
+
+func (r *ResourcesStates) loadStates(withReplication bool) (err error) {
 
 	err = r.loadDbStorageClassStates()
 	if err != nil {
@@ -58,6 +70,12 @@ func (r *ResourcesStates) loadStates() (err error) {
 		return err
 	}
 
+	r.loadCascadeTopology()
+
+	if withReplication {
+		r.loadReplicationStates()
+	}
+
 	err = r.loadServicesStates()
 	if err != nil {
 		return err
@@ -68,6 +86,11 @@ func (r *ResourcesStates) loadStates() (err error) {
 		return err
 	}
 
+	err = r.loadPasswordsStates()
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -86,6 +109,14 @@ func (r *ResourcesStates) loadStatefulSetsStates() (err error) {
 	return err
 }
 
+func (r *ResourcesStates) loadCascadeTopology() {
+	r.Cascade = replication.LoadCascadeTopology(r.kubegresContext, r.StatefulSets)
+}
+
+func (r *ResourcesStates) loadReplicationStates() {
+	r.Replication = replication.LoadReplicationStates(r.kubegresContext, r.StatefulSets)
+}
+
 func (r *ResourcesStates) loadServicesStates() (err error) {
 	r.Services, err = loadServicesStates(r.kubegresContext)
 	return err
@@ -95,3 +126,8 @@ func (r *ResourcesStates) loadBackUpStates() (err error) {
 	r.BackUp, err = loadBackUpStates(r.kubegresContext)
 	return err
 }
+
+func (r *ResourcesStates) loadPasswordsStates() (err error) {
+	r.Passwords, err = loadPasswordsStates(r.kubegresContext)
+	return err
+}
@@ -19,8 +19,10 @@ func (r *ResourcesStatesLogger) Log() {
 	r.logDbStorageClassStates()
 	r.logConfigStates()
 	r.logStatefulSetsStates()
+	r.logReplicationStates()
 	r.logServicesStates()
 	r.logBackUpStates()
+	r.logPasswordsStates()
 }
 
 func (r *ResourcesStatesLogger) logDbStorageClassStates() {
@@ -52,6 +54,10 @@ func (r *ResourcesStatesLogger) logStatefulSetsStates() {
 	for _, replicaStatefulSetWrapper := range statefulSets.Replicas.All.GetAllSortedByInstanceIndex() {
 		r.logStatefulSetWrapper("Replica states", replicaStatefulSetWrapper)
 	}
+
+	for _, delayedReplicaStatefulSetWrapper := range statefulSets.DelayedReplicas.All.GetAllSortedByInstanceIndex() {
+		r.logStatefulSetWrapper("Delayed Replica states", delayedReplicaStatefulSetWrapper)
+	}
 }
 
 func (r *ResourcesStatesLogger) logStatefulSetWrapper(logLabel string, statefulSetWrapper statefulset.StatefulSetWrapper) {
@@ -72,6 +78,19 @@ func (r *ResourcesStatesLogger) logStatefulSetWrapper(logLabel string, statefulS
 	}
 }
 
+func (r *ResourcesStatesLogger) logReplicationStates() {
+	for _, instance := range r.resourcesStates.Replication.GetAll() {
+		r.kubegresContext.Log.Info("Replication states: ",
+			"InstanceIndex", instance.InstanceIndex,
+			"IsReachable", instance.IsReachable,
+			"IsInRecovery", instance.IsInRecovery,
+			"WalLsn", instance.WalLsn.String(),
+			"ReplayLagBytes", instance.ReplayLagBytes,
+			"ReplayLagSeconds", instance.ReplayLagSeconds,
+			"UpstreamHost", instance.UpstreamHost)
+	}
+}
+
 func (r *ResourcesStatesLogger) logServicesStates() {
 	r.logServiceWrapper("Primary Service states", r.resourcesStates.Services.Primary)
 	r.logServiceWrapper("Replica Service states", r.resourcesStates.Services.Replica)
@@ -97,3 +116,16 @@ func (r *ResourcesStatesLogger) logBackUpStates() {
 		"ConfigMap", r.resourcesStates.BackUp.ConfigMap,
 		"CronJobLastScheduleTime", r.resourcesStates.BackUp.CronJobLastScheduleTime)
 }
+
+func (r *ResourcesStatesLogger) logPasswordsStates() {
+	if r.kubegresContext.Kubegres.Spec.Passwords.Generate {
+		r.logSecretWrapper("Generated Passwords Secret states", r.resourcesStates.Passwords.Generated)
+	}
+	r.logSecretWrapper("Applied Passwords Secret states", r.resourcesStates.Passwords.Applied)
+}
+
+func (r *ResourcesStatesLogger) logSecretWrapper(logLabel string, secretWrapper states.SecretWrapper) {
+	r.kubegresContext.Log.Info(logLabel+": ",
+		"IsDeployed", secretWrapper.IsDeployed,
+		"name", secretWrapper.Name)
+}
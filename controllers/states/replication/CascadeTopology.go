@@ -0,0 +1,105 @@
+/*
+Copyright 2021 Reactive Tech Limited.
+"Reactive Tech Limited" is a company located in England, United Kingdom.
+https://www.reactive-tech.io
+
+Lead Developer: Alex Arica
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replication
+
+import (
+	"reactive-tech.io/kubegres/controllers/ctx"
+	"reactive-tech.io/kubegres/controllers/states/statefulset"
+)
+
+// CascadeTopology contains the upstream instance of each Replica when 'spec.replication.cascade.enabled' is true.
+// The first 'numberOfDirectReplicas' ready Replicas by instance index stream from the Primary. The other ready Replicas
+// are spread across them by instance index. A Replica which is not ready cannot be an upstream Replica, so that its
+// downstream Replicas are rewired to another upstream Replica. Without cascading replication, all Replicas stream
+// from the Primary.
+type CascadeTopology struct {
+	directReplicas   []statefulset.StatefulSetWrapper
+	upstreamReplicas map[int32]statefulset.StatefulSetWrapper
+	kubegresContext  ctx.KubegresContext
+}
+
+func LoadCascadeTopology(kubegresContext ctx.KubegresContext, statefulSetsStates statefulset.StatefulSetsStates) CascadeTopology {
+	cascadeTopology := CascadeTopology{
+		upstreamReplicas: make(map[int32]statefulset.StatefulSetWrapper),
+		kubegresContext:  kubegresContext,
+	}
+	cascadeTopology.loadTopology(statefulSetsStates)
+	return cascadeTopology
+}
+
+// GetUpstreamHostName returns the host name from which a Replica streams the WAL.
+func (r *CascadeTopology) GetUpstreamHostName(instanceIndex int32) string {
+	if upstreamReplica, exists := r.upstreamReplicas[instanceIndex]; exists {
+		return r.kubegresContext.GetReplicaPodHostName(upstreamReplica.Pod.Pod.Name)
+	}
+	return r.kubegresContext.GetServiceResourceName(true)
+}
+
+// GetUpstreamReplica returns the Replica from which a Replica streams the WAL, or false if it streams from the Primary.
+func (r *CascadeTopology) GetUpstreamReplica(instanceIndex int32) (statefulset.StatefulSetWrapper, bool) {
+	upstreamReplica, exists := r.upstreamReplicas[instanceIndex]
+	return upstreamReplica, exists
+}
+
+// IsUpstreamReplica returns true if at least one Replica streams the WAL from the given Replica.
+func (r *CascadeTopology) IsUpstreamReplica(instanceIndex int32) bool {
+	for _, upstreamReplica := range r.upstreamReplicas {
+		if upstreamReplica.InstanceIndex == instanceIndex {
+			return true
+		}
+	}
+	return false
+}
+
+// GetUpstreamHostNameOfNewReplica returns the host name of the Replica from which a new Replica is copied and streams
+// the WAL, or an empty string if it is copied from the Primary.
+func (r *CascadeTopology) GetUpstreamHostNameOfNewReplica() string {
+
+	nbreDirectReplicas := len(r.directReplicas)
+	if nbreDirectReplicas == 0 || nbreDirectReplicas < int(r.kubegresContext.GetNumberOfDirectReplicas()) {
+		return ""
+	}
+
+	upstreamReplica := r.directReplicas[len(r.upstreamReplicas)%nbreDirectReplicas]
+	return r.kubegresContext.GetReplicaPodHostName(upstreamReplica.Pod.Pod.Name)
+}
+
+func (r *CascadeTopology) loadTopology(statefulSetsStates statefulset.StatefulSetsStates) {
+
+	if !r.kubegresContext.IsCascadingReplicationEnabled() {
+		return
+	}
+
+	nbreDirectReplicas := int(r.kubegresContext.GetNumberOfDirectReplicas())
+
+	for _, replica := range statefulSetsStates.Replicas.All.GetAllSortedByInstanceIndex() {
+		if !replica.Pod.IsReady {
+			continue
+		}
+
+		if len(r.directReplicas) < nbreDirectReplicas {
+			r.directReplicas = append(r.directReplicas, replica)
+			continue
+		}
+
+		r.upstreamReplicas[replica.InstanceIndex] = r.directReplicas[len(r.upstreamReplicas)%nbreDirectReplicas]
+	}
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2021 Reactive Tech Limited.
+"Reactive Tech Limited" is a company located in England, United Kingdom.
+https://www.reactive-tech.io
+
+Lead Developer: Alex Arica
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replication
+
+import (
+	"strings"
+
+	"reactive-tech.io/kubegres/controllers/ctx"
+	"reactive-tech.io/kubegres/controllers/postgres"
+	"reactive-tech.io/kubegres/controllers/states/statefulset"
+)
+
+// ReplicationStates contains the WAL positions of the PostgreSql instances which could be reached with a SQL connection.
+// If an instance cannot be reached, its states are still available with the flag 'IsReachable' set to false.
+type ReplicationStates struct {
+	instances       []InstanceReplicationState
+	kubegresContext ctx.KubegresContext
+	dbConnector     postgres.DbConnector
+}
+
+type InstanceReplicationState struct {
+	InstanceIndex int32
+	IsReachable   bool
+	IsInRecovery  bool
+
+	// The WAL position of the instance. For a Primary it is the current WAL LSN
+	// and for a Replica it is the last replayed WAL LSN.
+	WalLsn postgres.Lsn
+
+	// The last WAL LSN received and written to disk by a Replica
+	ReceivedWalLsn postgres.Lsn
+
+	// The number of bytes and seconds of WAL a Replica has to replay to catch-up with the Primary.
+	// They are set to -1 if unknown. The number of seconds is 0 for a streaming Replica which replayed all
+	// the WAL it received.
+	ReplayLagBytes   int64
+	ReplayLagSeconds int64
+
+	// Whether the WAL receiver of a Replica is streaming from its upstream instance
+	IsWalReceiverStreaming bool
+
+	// The host in the setting 'primary_conninfo' of a Replica, from which it streams the WAL
+	UpstreamHost string
+
+	// The setting 'primary_slot_name' of a Replica
+	SlotName string
+
+	// The names of the replication slots managed by Kubegres on the instance, sorted by name
+	ReplicationSlotNames []string
+}
+
+const unknownLag = -1
+
+func LoadReplicationStates(kubegresContext ctx.KubegresContext, statefulSetsStates statefulset.StatefulSetsStates) ReplicationStates {
+	replicationStates := ReplicationStates{
+		kubegresContext: kubegresContext,
+		dbConnector:     postgres.CreateDbConnector(kubegresContext),
+	}
+	replicationStates.loadStates(statefulSetsStates)
+	return replicationStates
+}
+
+func (r *ReplicationStates) GetByInstanceIndex(instanceIndex int32) InstanceReplicationState {
+	for _, instance := range r.instances {
+		if instance.InstanceIndex == instanceIndex {
+			return instance
+		}
+	}
+	return InstanceReplicationState{InstanceIndex: instanceIndex, ReplayLagBytes: unknownLag, ReplayLagSeconds: unknownLag}
+}
+
+func (r *ReplicationStates) GetAll() []InstanceReplicationState {
+	return r.instances
+}
+
+func (r *ReplicationStates) loadStates(statefulSetsStates statefulset.StatefulSetsStates) {
+
+	r.closePooledConnectionsOfDeletedPods(statefulSetsStates)
+
+	primary := statefulSetsStates.Primary
+	primaryState := InstanceReplicationState{ReplayLagBytes: unknownLag, ReplayLagSeconds: unknownLag}
+
+	if primary.IsDeployed {
+		primaryState = r.loadInstanceState(primary)
+		primaryState.ReplayLagBytes = 0
+		primaryState.ReplayLagSeconds = 0
+		r.instances = append(r.instances, primaryState)
+	}
+
+	for _, replica := range statefulSetsStates.GetReplicasAndDelayedReplicas() {
+
+		replicaState := r.loadInstanceState(replica)
+
+		if replicaState.IsReachable && primaryState.IsReachable {
+			replicaState.ReplayLagBytes = replicaState.WalLsn.BytesBehind(primaryState.WalLsn)
+			if replicaState.ReplayLagBytes == 0 {
+				replicaState.ReplayLagSeconds = 0
+			}
+		}
+
+		r.instances = append(r.instances, replicaState)
+	}
+}
+
+// closePooledConnectionsOfDeletedPods closes the connections kept open to the Pods which are not in the states anymore.
+func (r *ReplicationStates) closePooledConnectionsOfDeletedPods(statefulSetsStates statefulset.StatefulSetsStates) {
+	var podNames []string
+	for _, statefulSetWrapper := range statefulSetsStates.All.GetAllSortedByInstanceIndex() {
+		if statefulSetWrapper.Pod.IsDeployed {
+			podNames = append(podNames, statefulSetWrapper.Pod.Pod.Name)
+		}
+	}
+	r.dbConnector.ClosePooledConnectionsOfDeletedPods(podNames)
+}
+
+func (r *ReplicationStates) loadInstanceState(statefulSetWrapper statefulset.StatefulSetWrapper) InstanceReplicationState {
+
+	instanceState := InstanceReplicationState{
+		InstanceIndex:    statefulSetWrapper.InstanceIndex,
+		ReplayLagBytes:   unknownLag,
+		ReplayLagSeconds: unknownLag,
+	}
+
+	if !statefulSetWrapper.Pod.IsReady {
+		return instanceState
+	}
+
+	connection, err := r.dbConnector.ConnectAsSuperUser(statefulSetWrapper.Pod.Pod)
+	if err != nil {
+		r.kubegresContext.Log.Info("Unable to connect to PostgreSql in order to load its replication states.",
+			"Pod name", statefulSetWrapper.Pod.Pod.Name, "Error", err.Error())
+		return instanceState
+	}
+	defer connection.Close()
+
+	var walLsn, receivedWalLsn, primaryConnInfo, replicationSlotNames string
+	var replayLagSeconds float64
+	const query = `SELECT pg_is_in_recovery(),
+		COALESCE(CASE WHEN pg_is_in_recovery() THEN pg_last_wal_replay_lsn() ELSE pg_current_wal_lsn() END::text, ''),
+		COALESCE(pg_last_wal_receive_lsn()::text, ''),
+		COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), -1),
+		EXISTS (SELECT 1 FROM pg_stat_wal_receiver WHERE status = 'streaming'),
+		current_setting('primary_conninfo'),
+		current_setting('primary_slot_name'),
+		COALESCE((SELECT string_agg(slot_name::text, ',' ORDER BY slot_name) FROM pg_replication_slots
+			WHERE slot_type = 'physical' AND starts_with(slot_name::text, '` + ctx.ReplicationSlotNamePrefix + `')), '')`
+
+	err = connection.QueryRow(query).Scan(&instanceState.IsInRecovery, &walLsn, &receivedWalLsn, &replayLagSeconds,
+		&instanceState.IsWalReceiverStreaming, &primaryConnInfo, &instanceState.SlotName, &replicationSlotNames)
+	if err != nil {
+		r.kubegresContext.Log.Info("Unable to query the replication states of PostgreSql.",
+			"Pod name", statefulSetWrapper.Pod.Pod.Name, "Error", err.Error())
+		return instanceState
+	}
+
+	instanceState.IsReachable = true
+	instanceState.WalLsn, _ = postgres.ParseLsn(walLsn)
+	instanceState.ReceivedWalLsn, _ = postgres.ParseLsn(receivedWalLsn)
+	if instanceState.IsInRecovery {
+		instanceState.UpstreamHost, _ = postgres.GetConnInfoParam(primaryConnInfo, "host")
+	}
+	if replicationSlotNames != "" {
+		instanceState.ReplicationSlotNames = strings.Split(replicationSlotNames, ",")
+	}
+	if instanceState.IsInRecovery && replayLagSeconds >= 0 {
+		instanceState.ReplayLagSeconds = int64(replayLagSeconds)
+	}
+	if instanceState.IsInRecovery && instanceState.IsWalReceiverStreaming &&
+		instanceState.ReceivedWalLsn > 0 && instanceState.WalLsn >= instanceState.ReceivedWalLsn {
+
+		// The time of the last replayed transaction is not a lag when the Primary is idle: a streaming Replica
+		// which replayed all the WAL it received is caught-up, even if the Primary wrote WAL without transactions
+		instanceState.ReplayLagSeconds = 0
+	}
+
+	return instanceState
+}
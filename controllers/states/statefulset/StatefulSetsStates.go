@@ -30,10 +30,12 @@ import (
 )
 
 type StatefulSetsStates struct {
+	// The number of deployed StatefulSets counted in 'spec.replicas', which excludes the delayed Replicas
 	NbreDeployed             int32
 	SpecExpectedNbreToDeploy int32
 	Primary                  StatefulSetWrapper
 	Replicas                 Replicas
+	DelayedReplicas          Replicas
 	All                      StatefulSetWrappers
 	kubegresContext          ctx.KubegresContext
 }
@@ -75,6 +77,8 @@ func (r *StatefulSetsStates) loadStates() (err error) {
 		}
 	}
 
+	r.NbreDeployed -= r.DelayedReplicas.NbreDeployed
+
 	return nil
 }
 
@@ -101,8 +105,11 @@ func (r *StatefulSetsStates) createAndAppendStatefulSetStates(statefulSet apps.S
 			return err
 		}
 
+	} else if r.isDelayedReplica(statefulSet) {
+		r.DelayedReplicas.add(statefulSetWrapper)
+
 	} else {
-		r.addReplicaStatefulSetStates(statefulSetWrapper)
+		r.Replicas.add(statefulSetWrapper)
 	}
 
 	return nil
@@ -123,13 +130,20 @@ func (r *StatefulSetsStates) setPrimaryStatefulSetStates(statefulSet apps.Statef
 	return nil
 }
 
-func (r *StatefulSetsStates) addReplicaStatefulSetStates(statefulSetWrapper StatefulSetWrapper) {
+// GetReplicasAndDelayedReplicas returns the Replicas and then the delayed Replicas, each sorted by instance index.
+func (r *StatefulSetsStates) GetReplicasAndDelayedReplicas() []StatefulSetWrapper {
+	var replicas []StatefulSetWrapper
+	replicas = append(replicas, r.Replicas.All.GetAllSortedByInstanceIndex()...)
+	return append(replicas, r.DelayedReplicas.All.GetAllSortedByInstanceIndex()...)
+}
 
-	r.Replicas.NbreDeployed++
-	r.Replicas.All.Add(statefulSetWrapper)
+func (r *Replicas) add(statefulSetWrapper StatefulSetWrapper) {
+
+	r.NbreDeployed++
+	r.All.Add(statefulSetWrapper)
 
 	if statefulSetWrapper.IsReady {
-		r.Replicas.NbreReady++
+		r.NbreReady++
 	}
 }
 
@@ -142,8 +156,14 @@ func (r *StatefulSetsStates) getPodByInstanceIndex(instanceIndex int32, podsStat
 	return PodWrapper{}
 }
 
+// isPrimary checks the labels of the StatefulSet rather than of its Pod template, as the template of a Replica
+// promoted online is only labelled as Primary once its spec is updated.
 func (r *StatefulSetsStates) isPrimary(statefulSet apps.StatefulSet) bool {
-	return statefulSet.Spec.Template.Labels["replicationRole"] == ctx.PrimaryRoleName
+	return statefulSet.Labels["replicationRole"] == ctx.PrimaryRoleName
+}
+
+func (r *StatefulSetsStates) isDelayedReplica(statefulSet apps.StatefulSet) bool {
+	return statefulSet.Labels["replicationRole"] == ctx.DelayedReplicaRoleName
 }
 
 func (r *StatefulSetsStates) getDeployedStatefulSets() (*apps.StatefulSetList, error) {
@@ -0,0 +1,242 @@
+/*
+Copyright 2021 Reactive Tech Limited.
+"Reactive Tech Limited" is a company located in England, United Kingdom.
+https://www.reactive-tech.io
+
+Lead Developer: Alex Arica
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status_update
+
+import (
+	"strconv"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	postgresV1 "reactive-tech.io/kubegres/api/v1"
+	"reactive-tech.io/kubegres/controllers/ctx"
+	"reactive-tech.io/kubegres/controllers/operation"
+	"reactive-tech.io/kubegres/controllers/spec/checker"
+	"reactive-tech.io/kubegres/controllers/states"
+)
+
+// ConditionsStatusUpdater sets the standard conditions of the Kubegres status from the states of the deployed
+// resources, the result of the spec check and the active blocking operation.
+type ConditionsStatusUpdater struct {
+	kubegresContext   ctx.KubegresContext
+	resourcesStates   states.ResourcesStates
+	blockingOperation *operation.BlockingOperation
+}
+
+func CreateConditionsStatusUpdater(kubegresContext ctx.KubegresContext,
+	resourcesStates states.ResourcesStates,
+	blockingOperation *operation.BlockingOperation) ConditionsStatusUpdater {
+
+	return ConditionsStatusUpdater{
+		kubegresContext:   kubegresContext,
+		resourcesStates:   resourcesStates,
+		blockingOperation: blockingOperation,
+	}
+}
+
+func (r *ConditionsStatusUpdater) UpdateSpecCondition(specCheckResult checker.SpecCheckResult) {
+
+	if specCheckResult.HasSpecFatalError {
+		r.setCondition(postgresV1.ConditionTypeSpecInvalid, true, "SpecFatalError", specCheckResult.FatalErrorMessage)
+		return
+	}
+
+	r.setCondition(postgresV1.ConditionTypeSpecInvalid, false, "SpecValid", "The spec is valid.")
+}
+
+func (r *ConditionsStatusUpdater) UpdateConditions() {
+
+	isPrimaryAvailable := r.updatePrimaryAvailableCondition()
+	areReplicasReady := r.updateReplicasReadyCondition()
+	isFailingOver := r.updateFailingOverCondition()
+	hasOperationTimedOut := r.updateOperationTimedOutCondition()
+	isFailoverBlocked := r.updateFailoverBlockedCondition(isPrimaryAvailable, isFailingOver)
+	isFailoverSuspended := r.updateFailoverSuspendedCondition()
+	r.updatePausedCondition()
+	isSpecInvalid := r.isConditionTrue(postgresV1.ConditionTypeSpecInvalid)
+
+	switch {
+	case isSpecInvalid:
+		r.setCondition(postgresV1.ConditionTypeReady, false, "SpecInvalid", "The spec is invalid. Please check the condition 'SpecInvalid'.")
+	case hasOperationTimedOut:
+		r.setCondition(postgresV1.ConditionTypeReady, false, "OperationTimedOut", "An operation has timed out. Please check the condition 'OperationTimedOut'.")
+	case isFailoverBlocked:
+		r.setCondition(postgresV1.ConditionTypeReady, false, "FailoverBlocked", "A failover is required but no Replica can be promoted. Please check the condition 'FailoverBlocked'.")
+	case isFailoverSuspended && !isPrimaryAvailable:
+		r.setCondition(postgresV1.ConditionTypeReady, false, "FailoverSuspended", "The Primary is not available and the automatic failovers are suspended. Please check the condition 'FailoverSuspended'.")
+	case isFailingOver:
+		r.setCondition(postgresV1.ConditionTypeReady, false, "FailingOver", "A Replica is being promoted as the new Primary.")
+	case !isPrimaryAvailable:
+		r.setCondition(postgresV1.ConditionTypeReady, false, "PrimaryNotAvailable", "The Primary is not available.")
+	case !areReplicasReady:
+		r.setCondition(postgresV1.ConditionTypeReady, false, "ReplicasNotReady", "Not all expected Replicas are ready.")
+	default:
+		r.setCondition(postgresV1.ConditionTypeReady, true, "ClusterReady", "The Primary and all expected Replicas are ready.")
+	}
+
+	if r.kubegresContext.Status.GetObservedGeneration() != r.kubegresContext.Kubegres.Generation {
+		r.kubegresContext.Status.SetObservedGeneration(r.kubegresContext.Kubegres.Generation)
+	}
+}
+
+func (r *ConditionsStatusUpdater) updatePrimaryAvailableCondition() bool {
+
+	primary := r.resourcesStates.StatefulSets.Primary
+
+	if !primary.IsDeployed {
+		r.setCondition(postgresV1.ConditionTypePrimaryAvailable, false, "PrimaryNotDeployed", "There is no Primary deployed.")
+		return false
+
+	} else if !primary.IsReady {
+		r.setCondition(postgresV1.ConditionTypePrimaryAvailable, false, "PrimaryNotReady",
+			"The Primary '"+primary.StatefulSet.Name+"' is not ready.")
+		return false
+	}
+
+	r.setCondition(postgresV1.ConditionTypePrimaryAvailable, true, "PrimaryReady",
+		"The Primary '"+primary.StatefulSet.Name+"' is ready.")
+	return true
+}
+
+func (r *ConditionsStatusUpdater) updateReplicasReadyCondition() bool {
+
+	nbreExpectedReplicas := r.resourcesStates.StatefulSets.SpecExpectedNbreToDeploy - 1
+	if nbreExpectedReplicas < 0 {
+		nbreExpectedReplicas = 0
+	}
+	nbreReadyReplicas := r.resourcesStates.StatefulSets.Replicas.NbreReady
+
+	message := strconv.Itoa(int(nbreReadyReplicas)) + " of " + strconv.Itoa(int(nbreExpectedReplicas)) + " expected Replicas are ready."
+
+	if nbreReadyReplicas < nbreExpectedReplicas {
+		r.setCondition(postgresV1.ConditionTypeReplicasReady, false, "ReplicasNotReady", message)
+		return false
+	}
+
+	r.setCondition(postgresV1.ConditionTypeReplicasReady, true, "ReplicasReady", message)
+	return true
+}
+
+func (r *ConditionsStatusUpdater) updateFailingOverCondition() bool {
+
+	activeOperation := r.blockingOperation.GetActiveOperation()
+
+	if activeOperation.OperationId == operation.OperationIdPrimaryDbCountSpecEnforcement &&
+		(activeOperation.StepId == operation.OperationStepIdPrimaryDbWaitingBeforeFailingOver ||
+			activeOperation.StepId == operation.OperationStepIdPrimaryDbFailingOver) {
+
+		r.setCondition(postgresV1.ConditionTypeFailingOver, true, "FailoverInProgress", activeOperation.StepId)
+		return true
+	}
+
+	if activeOperation.OperationId == operation.OperationIdPrimaryDbSwitchover {
+		r.setCondition(postgresV1.ConditionTypeFailingOver, true, "SwitchoverInProgress", activeOperation.StepId)
+		return true
+	}
+
+	r.setCondition(postgresV1.ConditionTypeFailingOver, false, "NoFailoverInProgress", "There is no failover in progress.")
+	return false
+}
+
+func (r *ConditionsStatusUpdater) updateOperationTimedOutCondition() bool {
+
+	activeOperation := r.blockingOperation.GetActiveOperation()
+
+	if activeOperation.HasTimedOut {
+		r.setCondition(postgresV1.ConditionTypeOperationTimedOut, true, "OperationTimedOut",
+			"The operation '"+activeOperation.OperationId+"' has timed out at the step '"+activeOperation.StepId+"'. "+
+				"A manual intervention is required. Please check Kubegres' logs and events.")
+		return true
+	}
+
+	r.setCondition(postgresV1.ConditionTypeOperationTimedOut, false, "NoOperationTimedOut", "There is no timed-out operation.")
+	return false
+}
+
+// updateFailoverBlockedCondition keeps the condition set by the failover when it cannot promote any Replica,
+// until the Primary is available again or a failover has started.
+func (r *ConditionsStatusUpdater) updateFailoverBlockedCondition(isPrimaryAvailable, isFailingOver bool) bool {
+
+	if !isPrimaryAvailable && !isFailingOver && r.isConditionTrue(postgresV1.ConditionTypeFailoverBlocked) {
+		return true
+	}
+
+	r.setCondition(postgresV1.ConditionTypeFailoverBlocked, false, "NoFailoverBlocked", "There is no blocked failover.")
+	return false
+}
+
+func (r *ConditionsStatusUpdater) updateFailoverSuspendedCondition() bool {
+
+	suspension := r.kubegresContext.Status.GetFailoverSuspension()
+	if suspension.IsSuspended {
+		r.setCondition(postgresV1.ConditionTypeFailoverSuspended, true, suspension.Reason,
+			"The automatic failovers are suspended as they happened too often. To resume them, please set the annotation '"+
+				ctx.FailoverSuspensionAckAnnotationKey+"' on the Kubegres resource.")
+		return true
+	}
+
+	r.setCondition(postgresV1.ConditionTypeFailoverSuspended, false, "FailoverNotSuspended", "The automatic failovers are not suspended.")
+	return false
+}
+
+// updatePausedCondition also emits an event when the reconciliation is paused or resumed.
+func (r *ConditionsStatusUpdater) updatePausedCondition() {
+
+	wasPaused := r.isConditionTrue(postgresV1.ConditionTypePaused)
+
+	if r.kubegresContext.IsPaused() {
+		r.setCondition(postgresV1.ConditionTypePaused, true, "ReconciliationPaused",
+			"The reconciliation is paused with the annotation '"+ctx.PausedAnnotationKey+"'. "+
+				"The spec is not enforced and a failover cannot happen. Only the status is refreshed.")
+
+		if !wasPaused {
+			r.kubegresContext.Log.WarningEvent("ReconciliationPaused",
+				"The reconciliation is paused with the annotation '"+ctx.PausedAnnotationKey+"'. "+
+					"The spec is not enforced and a failover cannot happen until that annotation is removed.")
+		}
+		return
+	}
+
+	r.setCondition(postgresV1.ConditionTypePaused, false, "ReconciliationNotPaused", "The reconciliation is not paused.")
+
+	if wasPaused {
+		r.kubegresContext.Log.InfoEvent("ReconciliationResumed",
+			"The reconciliation is resumed as the annotation '"+ctx.PausedAnnotationKey+"' was removed.")
+	}
+}
+
+func (r *ConditionsStatusUpdater) isConditionTrue(conditionType string) bool {
+	condition := r.kubegresContext.Status.GetCondition(conditionType)
+	return condition != nil && condition.Status == metav1.ConditionTrue
+}
+
+func (r *ConditionsStatusUpdater) setCondition(conditionType string, isTrue bool, reason, message string) {
+
+	conditionStatus := metav1.ConditionFalse
+	if isTrue {
+		conditionStatus = metav1.ConditionTrue
+	}
+
+	r.kubegresContext.Status.SetCondition(metav1.Condition{
+		Type:    conditionType,
+		Status:  conditionStatus,
+		Reason:  reason,
+		Message: message,
+	})
+}
@@ -0,0 +1,186 @@
+/*
+Copyright 2021 Reactive Tech Limited.
+"Reactive Tech Limited" is a company located in England, United Kingdom.
+https://www.reactive-tech.io
+
+Lead Developer: Alex Arica
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status_update
+
+import (
+	"reflect"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	postgresV1 "reactive-tech.io/kubegres/api/v1"
+	"reactive-tech.io/kubegres/controllers/ctx"
+	"reactive-tech.io/kubegres/controllers/states"
+	"reactive-tech.io/kubegres/controllers/states/statefulset"
+)
+
+// InstancesStatusUpdater sets in the Kubegres status the topology of the deployed PostgreSql instances.
+//
+// The WAL LSN and replication lag change continuously. In order to not update the status at each reconciliation,
+// they are only refreshed when the topology changes or when 'InstancesRefreshPeriod' has elapsed since the last refresh.
+// The last WAL LSN of the Primary is refreshed at the same time, even if the other fields did not change, as a failover
+// relies on it to know how much WAL the Replicas are missing. Refreshing it at each reconciliation would update the status
+// at each reconciliation on a Primary taking writes, which would trigger another reconciliation.
+type InstancesStatusUpdater struct {
+	kubegresContext ctx.KubegresContext
+	resourcesStates states.ResourcesStates
+}
+
+const InstancesRefreshPeriod = 30 * time.Second
+
+func CreateInstancesStatusUpdater(kubegresContext ctx.KubegresContext, resourcesStates states.ResourcesStates) InstancesStatusUpdater {
+	return InstancesStatusUpdater{kubegresContext: kubegresContext, resourcesStates: resourcesStates}
+}
+
+func (r *InstancesStatusUpdater) UpdateInstances() {
+
+	r.updateCurrentPrimary()
+	r.updateScaleStatus()
+
+	expectedInstances := r.createInstances()
+	currentInstances := r.kubegresContext.Status.GetInstances()
+
+	hasTopologyChanged := !reflect.DeepEqual(r.withoutReplicationStates(currentInstances), r.withoutReplicationStates(expectedInstances))
+	if !hasTopologyChanged && !r.isRefreshPeriodElapsed() {
+		return
+	}
+
+	r.updateLastPrimaryWalLsn(expectedInstances)
+
+	if reflect.DeepEqual(currentInstances, expectedInstances) {
+		return
+	}
+
+	now := metav1.Now()
+	r.kubegresContext.Status.SetInstances(expectedInstances)
+	r.kubegresContext.Status.SetInstancesUpdateTime(&now)
+}
+
+// updateLastPrimaryWalLsn keeps the last WAL LSN of the Primary once it cannot be queried anymore, so that the lag
+// of the Replicas can still be measured during a failover.
+func (r *InstancesStatusUpdater) updateLastPrimaryWalLsn(instances []postgresV1.KubegresInstance) {
+
+	for _, instance := range instances {
+		if instance.Role == ctx.PrimaryRoleName && instance.WalLsn != "" &&
+			instance.WalLsn != r.kubegresContext.Status.GetLastPrimaryWalLsn() {
+
+			r.kubegresContext.Status.SetLastPrimaryWalLsn(instance.WalLsn)
+		}
+	}
+}
+
+func (r *InstancesStatusUpdater) updateCurrentPrimary() {
+
+	currentPrimary := ""
+	primary := r.resourcesStates.StatefulSets.Primary
+	if primary.IsDeployed && primary.Pod.IsDeployed {
+		currentPrimary = primary.Pod.Pod.Name
+	}
+
+	if r.kubegresContext.Status.GetCurrentPrimary() != currentPrimary {
+		r.kubegresContext.Status.SetCurrentPrimary(currentPrimary)
+	}
+}
+
+// updateScaleStatus sets the fields read by the scale subresource, so that "kubectl scale" and autoscalers
+// can know how many instances are deployed and which Pods belong to them.
+func (r *InstancesStatusUpdater) updateScaleStatus() {
+
+	nbreDeployed := r.resourcesStates.StatefulSets.NbreDeployed
+	if r.kubegresContext.Status.GetReplicas() != nbreDeployed {
+		r.kubegresContext.Status.SetReplicas(nbreDeployed)
+	}
+
+	selector := "app=" + r.kubegresContext.Kubegres.Name
+	if r.kubegresContext.Status.GetSelector() != selector {
+		r.kubegresContext.Status.SetSelector(selector)
+	}
+}
+
+func (r *InstancesStatusUpdater) createInstances() []postgresV1.KubegresInstance {
+
+	var instances []postgresV1.KubegresInstance
+
+	for _, statefulSetWrapper := range r.resourcesStates.StatefulSets.All.GetAllSortedByInstanceIndex() {
+		instances = append(instances, r.createInstance(statefulSetWrapper))
+	}
+
+	return instances
+}
+
+func (r *InstancesStatusUpdater) createInstance(statefulSetWrapper statefulset.StatefulSetWrapper) postgresV1.KubegresInstance {
+
+	role := ctx.ReplicaRoleName
+	if statefulSetWrapper.StatefulSet.Name == r.resourcesStates.StatefulSets.Primary.StatefulSet.Name {
+		role = ctx.PrimaryRoleName
+	} else if _, err := r.resourcesStates.StatefulSets.DelayedReplicas.All.GetByInstanceIndex(statefulSetWrapper.InstanceIndex); err == nil {
+		role = ctx.DelayedReplicaRoleName
+	}
+
+	instance := postgresV1.KubegresInstance{
+		InstanceIndex: statefulSetWrapper.InstanceIndex,
+		StatefulSet:   statefulSetWrapper.StatefulSet.Name,
+		Role:          role,
+		IsReady:       statefulSetWrapper.Pod.IsReady,
+		IsStuck:       statefulSetWrapper.Pod.IsStuck,
+	}
+
+	if statefulSetWrapper.Pod.IsDeployed {
+		instance.Pod = statefulSetWrapper.Pod.Pod.Name
+		instance.Node = statefulSetWrapper.Pod.Pod.Spec.NodeName
+	}
+
+	replicationState := r.resourcesStates.Replication.GetByInstanceIndex(statefulSetWrapper.InstanceIndex)
+	if !replicationState.IsReachable {
+		return instance
+	}
+
+	instance.WalLsn = replicationState.WalLsn.String()
+
+	if role != ctx.PrimaryRoleName && replicationState.ReplayLagBytes >= 0 {
+		replayLagBytes := replicationState.ReplayLagBytes
+		instance.ReplayLagBytes = &replayLagBytes
+	}
+
+	if role != ctx.PrimaryRoleName && replicationState.ReplayLagSeconds >= 0 {
+		replayLagSeconds := replicationState.ReplayLagSeconds
+		instance.ReplayLagSeconds = &replayLagSeconds
+	}
+
+	return instance
+}
+
+func (r *InstancesStatusUpdater) withoutReplicationStates(instances []postgresV1.KubegresInstance) []postgresV1.KubegresInstance {
+
+	var topology []postgresV1.KubegresInstance
+	for _, instance := range instances {
+		instance.WalLsn = ""
+		instance.ReplayLagBytes = nil
+		instance.ReplayLagSeconds = nil
+		topology = append(topology, instance)
+	}
+
+	return topology
+}
+
+func (r *InstancesStatusUpdater) isRefreshPeriodElapsed() bool {
+	lastUpdateTime := r.kubegresContext.Status.GetInstancesUpdateTime()
+	return lastUpdateTime == nil || time.Since(lastUpdateTime.Time) >= InstancesRefreshPeriod
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2021 Reactive Tech Limited.
+"Reactive Tech Limited" is a company located in England, United Kingdom.
+https://www.reactive-tech.io
+
+Lead Developer: Alex Arica
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"k8s.io/client-go/tools/record"
+	postgresV1 "reactive-tech.io/kubegres/api/v1"
+	ctx2 "reactive-tech.io/kubegres/controllers/ctx"
+	"reactive-tech.io/kubegres/controllers/ctx/log"
+	"reactive-tech.io/kubegres/controllers/ctx/status"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func createKubegresContext(ctx context.Context,
+	kubegres *postgresV1.Kubegres,
+	client client.Client,
+	logger logr.Logger,
+	recorder record.EventRecorder) ctx2.KubegresContext {
+
+	logWrapper := log.LogWrapper{Kubegres: kubegres, Logger: logger.WithValues("Kubegres name", kubegres.Name), Recorder: recorder}
+
+	return ctx2.KubegresContext{
+		Kubegres: kubegres,
+		Status: &status.KubegresStatusWrapper{
+			Kubegres: kubegres,
+			Ctx:      ctx,
+			Log:      logWrapper,
+			Client:   client,
+		},
+		Ctx:    ctx,
+		Log:    logWrapper,
+		Client: client,
+	}
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2021 Reactive Tech Limited.
+"Reactive Tech Limited" is a company located in England, United Kingdom.
+https://www.reactive-tech.io
+
+Lead Developer: Alex Arica
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	postgresV1 "reactive-tech.io/kubegres/api/v1"
+	"reactive-tech.io/kubegres/controllers/spec/defaultspec"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+//+kubebuilder:webhook:path=/mutate-kubegres-reactive-tech-io-v1-kubegres,mutating=true,failurePolicy=fail,sideEffects=None,groups=kubegres.reactive-tech.io,resources=kubegres,verbs=create;update,versions=v1,name=mkubegres.kb.io,admissionReviewVersions=v1
+
+// KubegresDefaulter sets at admission time a default value for each undefined field of the Kubegres spec,
+// so that the Kubegres controller never has to update the spec of a Kubegres resource.
+type KubegresDefaulter struct {
+	Client   client.Client
+	Logger   logr.Logger
+	Recorder record.EventRecorder
+}
+
+var _ webhook.CustomDefaulter = &KubegresDefaulter{}
+
+func (r *KubegresDefaulter) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&postgresV1.Kubegres{}).
+		WithDefaulter(r).
+		Complete()
+}
+
+func (r *KubegresDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+
+	kubegres := obj.(*postgresV1.Kubegres)
+	kubegresContext := createKubegresContext(ctx, kubegres, r.Client, r.Logger, r.Recorder)
+	defaultStorageClass := defaultspec.CreateDefaultStorageClass(kubegresContext)
+
+	if err := defaultspec.SetDefaultForUndefinedSpecValues(kubegresContext, defaultStorageClass); err != nil {
+		return apierrors.NewInternalError(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2021 Reactive Tech Limited.
+"Reactive Tech Limited" is a company located in England, United Kingdom.
+https://www.reactive-tech.io
+
+Lead Developer: Alex Arica
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-logr/logr"
+	admissionv1 "k8s.io/api/admission/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
+	postgresV1 "reactive-tech.io/kubegres/api/v1"
+	ctx2 "reactive-tech.io/kubegres/controllers/ctx"
+	"reactive-tech.io/kubegres/controllers/spec/checker"
+	"reactive-tech.io/kubegres/controllers/spec/defaultspec"
+	"reactive-tech.io/kubegres/controllers/states"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+//+kubebuilder:webhook:path=/validate-kubegres-reactive-tech-io-v1-kubegres,mutating=false,failurePolicy=fail,sideEffects=None,groups=kubegres.reactive-tech.io,resources=kubegres,verbs=create;update,versions=v1,name=vkubegres.kb.io,admissionReviewVersions=v1
+
+// KubegresValidator rejects at admission time the Kubegres resources which have an invalid spec.
+// It runs the same checks as the ones run by SpecChecker during a reconciliation, except that the fields which
+// cannot be changed are compared with the previous spec and that the resources referenced by the spec which are
+// not deployed are returned as warnings.
+type KubegresValidator struct {
+	Client   client.Client
+	Logger   logr.Logger
+	Recorder record.EventRecorder
+	decoder  *admission.Decoder
+}
+
+var _ admission.Handler = &KubegresValidator{}
+var _ admission.DecoderInjector = &KubegresValidator{}
+
+func (r *KubegresValidator) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	mgr.GetWebhookServer().Register("/validate-kubegres-reactive-tech-io-v1-kubegres", &webhook.Admission{Handler: r})
+	return nil
+}
+
+func (r *KubegresValidator) InjectDecoder(decoder *admission.Decoder) error {
+	r.decoder = decoder
+	return nil
+}
+
+func (r *KubegresValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+
+	kubegres := &postgresV1.Kubegres{}
+	if err := r.decoder.DecodeRaw(req.Object, kubegres); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	var oldKubegres *postgresV1.Kubegres
+	if req.Operation == admissionv1.Update {
+		oldKubegres = &postgresV1.Kubegres{}
+		if err := r.decoder.DecodeRaw(req.OldObject, oldKubegres); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+	}
+
+	return r.validate(ctx, kubegres, oldKubegres)
+}
+
+func (r *KubegresValidator) validate(ctx context.Context, kubegres, oldKubegres *postgresV1.Kubegres) admission.Response {
+
+	kubegres = kubegres.DeepCopy()
+	if kubegres.Spec.Replicas == nil {
+		replicas := int32(0)
+		kubegres.Spec.Replicas = &replicas
+	}
+
+	kubegresContext := createKubegresContext(ctx, kubegres, r.Client, r.Logger, r.Recorder)
+	defaultspec.AddGeneratedPasswordsEnvVars(kubegresContext)
+
+	resourcesStates, err := states.LoadResourcesStatesWithoutReplication(kubegresContext)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
+	specChecker := checker.CreateSpecChecker(kubegresContext, resourcesStates)
+	specErrs := specChecker.ValidateSpecValues()
+
+	var oldSpec *postgresV1.KubegresSpec
+	if oldKubegres != nil {
+		oldSpec = &oldKubegres.Spec
+		specErrs = append(specErrs, specChecker.ValidateSpecUpdate(oldSpec)...)
+	}
+
+	if oldKubegres == nil || oldKubegres.Spec.Failover.PromotePod != kubegres.Spec.Failover.PromotePod {
+		specErrs = append(specErrs, specChecker.ValidatePromotePod()...)
+	}
+
+	if oldKubegres == nil || oldKubegres.Spec.Failover.SwitchoverPod != kubegres.Spec.Failover.SwitchoverPod {
+		specErrs = append(specErrs, specChecker.ValidateSwitchoverPod()...)
+	}
+
+	if len(specErrs) > 0 {
+		r.Logger.Info("Rejected Kubegres spec at admission.", "Kubegres name", kubegres.Name, "Errors", specErrs.ToAggregate().Error())
+		invalidErr := apierrors.NewInvalid(postgresV1.GroupVersion.WithKind(ctx2.KindKubegres).GroupKind(), kubegres.Name, specErrs)
+		return admission.Response{AdmissionResponse: admissionv1.AdmissionResponse{Allowed: false, Result: &invalidErr.ErrStatus}}
+	}
+
+	// A resource referenced by the spec may be deployed after the Kubegres resource. So it does not reject the spec.
+	// The Kubegres controller waits until that resource is deployed.
+	var warnings []string
+	for _, specWarning := range specChecker.ValidateDeployedResources(oldSpec) {
+		warnings = append(warnings, specWarning.Error())
+	}
+
+	if len(warnings) > 0 {
+		r.Logger.Info("Admitted Kubegres spec with warnings.", "Kubegres name", kubegres.Name, "Warnings", warnings)
+	}
+
+	return admission.Allowed("").WithWarnings(warnings...)
+}
@@ -37,7 +37,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	kubegresv1 "reactive-tech.io/kubegres/api/v1"
+	kubegresv2 "reactive-tech.io/kubegres/api/v2"
 	"reactive-tech.io/kubegres/controllers"
+	"reactive-tech.io/kubegres/controllers/webhook"
 	//+kubebuilder:scaffold:imports
 )
 
@@ -52,6 +54,7 @@ func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
 	utilruntime.Must(kubegresv1.AddToScheme(scheme))
+	utilruntime.Must(kubegresv2.AddToScheme(scheme))
 	//+kubebuilder:scaffold:scheme
 }
 
@@ -105,6 +108,28 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", ctx2.KindKubegres)
 		os.Exit(1)
 	}
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err = (&kubegresv2.Kubegres{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create conversion webhook", "webhook", ctx2.KindKubegres)
+			os.Exit(1)
+		}
+		if err = (&webhook.KubegresDefaulter{
+			Client:   mgr.GetClient(),
+			Logger:   ctrl.Log.WithName("webhooks").WithName(ctx2.KindKubegres),
+			Recorder: mgr.GetEventRecorderFor("Kubegres-webhook"),
+		}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", ctx2.KindKubegres)
+			os.Exit(1)
+		}
+		if err = (&webhook.KubegresValidator{
+			Client:   mgr.GetClient(),
+			Logger:   ctrl.Log.WithName("webhooks").WithName(ctx2.KindKubegres),
+			Recorder: mgr.GetEventRecorderFor("Kubegres-webhook"),
+		}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", ctx2.KindKubegres)
+			os.Exit(1)
+		}
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
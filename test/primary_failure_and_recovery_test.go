@@ -29,6 +29,8 @@ import (
 	. "github.com/onsi/gomega"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	postgresv1 "reactive-tech.io/kubegres/api/v1"
+	"reactive-tech.io/kubegres/controllers/operation"
+	"reactive-tech.io/kubegres/controllers/postgres"
 	"reactive-tech.io/kubegres/test/resourceConfigs"
 	"reactive-tech.io/kubegres/test/util"
 )
@@ -140,10 +142,16 @@ var _ = Describe("Primary instances is not available, checking recovery works",
 
 			// First failover
 
+			primaryPodName := test.getPrimaryPodName()
+
 			test.whenPrimaryIsDeleted()
 
+			test.thenFailingPrimaryShouldBeFencedBeforePromotion(primaryPodName)
+
 			test.thenPodsStatesShouldBe(1, 2)
 
+			test.thenLastFailoverSelectionShouldBeTheMostAdvancedReplica(2)
+
 			test.ThenPrimaryDbContainsExpectedNbreUsers(expectedNbreUsers)
 			test.ThenReplicaDbContainsExpectedNbreUsers(expectedNbreUsers)
 
@@ -159,6 +167,8 @@ var _ = Describe("Primary instances is not available, checking recovery works",
 
 			test.thenPodsStatesShouldBe(1, 2)
 
+			test.thenLastFailoverSelectionShouldBeTheMostAdvancedReplica(2)
+
 			test.ThenPrimaryDbContainsExpectedNbreUsers(expectedNbreUsers)
 			test.ThenReplicaDbContainsExpectedNbreUsers(expectedNbreUsers)
 
@@ -172,6 +182,81 @@ var _ = Describe("Primary instances is not available, checking recovery works",
 		})
 	})
 
+	Context("GIVEN Kubegres with 1 primary and 2 replicas AND the node of the primary becomes unreachable without its Pod being deleted", func() {
+
+		It("THEN the primary Pod should be force-deleted only once its node is NotReady for the grace period AND a replica should become primary AND existing data available", func() {
+
+			log.Print("START OF: Test 'GIVEN Kubegres with 1 primary and 2 replicas AND the node of the primary becomes unreachable without its Pod being deleted'")
+
+			test.givenNewKubegresSpecIsSetTo(3)
+
+			test.whenKubegresIsCreated()
+
+			test.thenPodsStatesShouldBe(1, 2)
+
+			expectedNbreUsers := 0
+
+			test.GivenUserAddedInPrimaryDb()
+			expectedNbreUsers++
+
+			primaryPodName := test.getPrimaryPodName()
+			primaryNodeName := test.getPrimaryNodeName()
+
+			test.whenNodeIsPaused(primaryNodeName)
+
+			test.thenFailingPrimaryShouldBeForceDeletedBeforePromotion(primaryPodName)
+
+			test.thenPodsStatesShouldBe(1, 2)
+
+			test.ThenPrimaryDbContainsExpectedNbreUsers(expectedNbreUsers)
+			test.ThenReplicaDbContainsExpectedNbreUsers(expectedNbreUsers)
+
+			test.GivenUserAddedInPrimaryDb()
+			expectedNbreUsers++
+
+			test.ThenPrimaryDbContainsExpectedNbreUsers(expectedNbreUsers)
+			test.ThenReplicaDbContainsExpectedNbreUsers(expectedNbreUsers)
+
+			log.Print("END OF: Test 'GIVEN Kubegres with 1 primary and 2 replicas AND the node of the primary becomes unreachable without its Pod being deleted'")
+		})
+	})
+
+	Context("GIVEN Kubegres with 1 primary and 2 replicas created without granting 'pg_promote' to the replication role AND primary is deleted", func() {
+
+		It("THEN the failover should take place with a replica becoming primary AND existing data available", func() {
+
+			log.Print("START OF: Test 'GIVEN Kubegres with 1 primary and 2 replicas created without granting 'pg_promote' to the replication role AND primary is deleted'")
+
+			test.givenNewKubegresSpecIsSetTo(3)
+
+			test.whenKubegresIsCreated()
+
+			test.thenPodsStatesShouldBe(1, 2)
+
+			test.givenReplicationRoleIsNotGrantedToPromote()
+
+			expectedNbreUsers := 0
+
+			test.GivenUserAddedInPrimaryDb()
+			expectedNbreUsers++
+
+			test.whenPrimaryIsDeleted()
+
+			test.thenPodsStatesShouldBe(1, 2)
+
+			test.ThenPrimaryDbContainsExpectedNbreUsers(expectedNbreUsers)
+			test.ThenReplicaDbContainsExpectedNbreUsers(expectedNbreUsers)
+
+			test.GivenUserAddedInPrimaryDb()
+			expectedNbreUsers++
+
+			test.ThenPrimaryDbContainsExpectedNbreUsers(expectedNbreUsers)
+			test.ThenReplicaDbContainsExpectedNbreUsers(expectedNbreUsers)
+
+			log.Print("END OF: Test 'GIVEN Kubegres with 1 primary and 2 replicas created without granting 'pg_promote' to the replication role AND primary is deleted'")
+		})
+	})
+
 	Context("GIVEN Kubegres with 1 primary and 2 replicas using custom-configs map AND primary is deleted", func() {
 
 		It("THEN the failover should take place with a replica becoming primary AND a new replica created AND existing data available, twice", func() {
@@ -250,6 +335,14 @@ func (r *PrimaryFailureAndRecoveryTest) givenNewKubegresSpecIsSetToWithCustomCon
 	r.kubegresResource.Spec.CustomConfig = resourceConfigs.CustomConfigMapWithPromoteReplicaScriptResourceName
 }
 
+// givenReplicationRoleIsNotGrantedToPromote revokes 'pg_promote' from the replication role, as for a cluster
+// created by a former version of Kubegres or with a custom script creating the replication role.
+func (r *PrimaryFailureAndRecoveryTest) givenReplicationRoleIsNotGrantedToPromote() {
+	Eventually(func() bool {
+		return r.connectionPrimaryDb.ExecSql("REVOKE EXECUTE ON FUNCTION pg_promote FROM replication")
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
+
 func (r *PrimaryFailureAndRecoveryTest) whenKubegresIsCreated() {
 	r.resourceCreator.CreateKubegres(r.kubegresResource)
 }
@@ -277,6 +370,43 @@ func (r *PrimaryFailureAndRecoveryTest) whenPrimaryIsDeleted() {
 	Expect(nbreDeleted).Should(Equal(1))
 }
 
+func (r *PrimaryFailureAndRecoveryTest) getPrimaryPodName() string {
+	kubegresResources, err := r.resourceRetriever.GetKubegresResources()
+	Expect(err).Should(Succeed())
+
+	for _, kubegresResource := range kubegresResources.Resources {
+		if kubegresResource.IsPrimary {
+			return kubegresResource.Pod.Name
+		}
+	}
+
+	Fail("The Primary Pod is not deployed")
+	return ""
+}
+
+func (r *PrimaryFailureAndRecoveryTest) getPrimaryNodeName() string {
+	kubegresResources, err := r.resourceRetriever.GetKubegresResources()
+	Expect(err).Should(Succeed())
+
+	for _, kubegresResource := range kubegresResources.Resources {
+		if kubegresResource.IsPrimary {
+			return kubegresResource.Pod.Spec.NodeName
+		}
+	}
+
+	Fail("The Primary Pod is not deployed")
+	return ""
+}
+
+// whenNodeIsPaused freezes the kubelet and the Pods of the given node until the end of the test.
+// The Pods of that node are not deleted and their containers are not stopped.
+func (r *PrimaryFailureAndRecoveryTest) whenNodeIsPaused(nodeName string) {
+	kindCluster.PauseNode(nodeName)
+	DeferCleanup(func() {
+		kindCluster.UnpauseNode(nodeName)
+	})
+}
+
 func (r *PrimaryFailureAndRecoveryTest) whenPrimaryAndItsPVCAreDeleted() {
 	kubegresResources, err := r.resourceRetriever.GetKubegresResources()
 	if err != nil {
@@ -351,6 +481,119 @@ func (r *PrimaryFailureAndRecoveryTest) thenPodsStatesShouldBe(nbrePrimary, nbre
 	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
 }
 
+func (r *PrimaryFailureAndRecoveryTest) thenFailingPrimaryShouldBeFencedBeforePromotion(primaryPodName string) {
+	Eventually(func() bool {
+
+		kubegres, err := r.resourceRetriever.GetKubegres()
+		if err != nil {
+			log.Println("ERROR while retrieving Kubegres resource: ", err)
+			return false
+		}
+
+		blockingOperation := kubegres.Status.BlockingOperation
+		if blockingOperation.StepId != operation.OperationStepIdPrimaryDbFailingOver {
+			log.Println("Waiting for the failover to promote a Replica")
+			return false
+		}
+
+		// The Pod of the deleted Primary may already be terminated when the fencing starts
+		fencingOperation := blockingOperation.FencingOperation
+		if !fencingOperation.IsFenced {
+			log.Println("The Pod '" + primaryPodName + "' was not fenced before promoting a Replica")
+			return false
+		}
+
+		for _, fencedPodName := range fencingOperation.Pods {
+			Expect(fencedPodName).Should(Equal(primaryPodName))
+		}
+
+		return true
+
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
+
+func (r *PrimaryFailureAndRecoveryTest) thenFailingPrimaryShouldBeForceDeletedBeforePromotion(primaryPodName string) {
+	Eventually(func() bool {
+
+		kubegres, err := r.resourceRetriever.GetKubegres()
+		if err != nil {
+			log.Println("ERROR while retrieving Kubegres resource: ", err)
+			return false
+		}
+
+		blockingOperation := kubegres.Status.BlockingOperation
+		fencingOperation := blockingOperation.FencingOperation
+
+		if blockingOperation.StepId == operation.OperationStepIdPrimaryDbWaitingBeforeFailingOver &&
+			len(fencingOperation.Pods) > 0 {
+			// As the kubelet of the node cannot stop the Pod, it stays terminating until it is force-deleted
+			Expect(fencingOperation.IsFenced).Should(BeFalse())
+		}
+
+		if blockingOperation.StepId != operation.OperationStepIdPrimaryDbFailingOver {
+			log.Println("Waiting for the failover to promote a Replica")
+			return false
+		}
+
+		Expect(fencingOperation.IsFenced).Should(BeTrue())
+		Expect(fencingOperation.Pods).Should(ConsistOf(primaryPodName))
+		Expect(fencingOperation.ForceDeletedPods).Should(ConsistOf(primaryPodName))
+
+		return true
+
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
+
+func (r *PrimaryFailureAndRecoveryTest) thenLastFailoverSelectionShouldBeTheMostAdvancedReplica(expectedNbreCandidates int) {
+	Eventually(func() bool {
+
+		kubegresResources, err := r.resourceRetriever.GetKubegresResources()
+		if err != nil || !kubegresResources.AreAllReady {
+			return false
+		}
+
+		kubegres, err := r.resourceRetriever.GetKubegres()
+		if err != nil {
+			log.Println("ERROR while retrieving Kubegres resource: ", err)
+			return false
+		}
+
+		failoverSelection := kubegres.Status.LastFailoverSelection
+		if failoverSelection == nil {
+			log.Println("Waiting for the status to contain the last failover selection")
+			return false
+		}
+
+		if failoverSelection.PromotedPod != kubegres.Status.CurrentPrimary {
+			log.Println("The promoted Pod '" + failoverSelection.PromotedPod + "' is not the current Primary '" + kubegres.Status.CurrentPrimary + "'")
+			return false
+		}
+
+		if len(failoverSelection.Candidates) != expectedNbreCandidates {
+			log.Println("The last failover selection does not contain the expected number of candidates: " + strconv.Itoa(expectedNbreCandidates))
+			return false
+		}
+
+		promotedWalLsn, err := postgres.ParseLsn(failoverSelection.PromotedWalLsn)
+		if err != nil {
+			log.Println("ERROR while parsing the WAL LSN of the promoted Pod: ", err)
+			return false
+		}
+
+		for _, candidate := range failoverSelection.Candidates {
+			receivedWalLsn, _ := postgres.ParseLsn(candidate.ReceivedWalLsn)
+			replayedWalLsn, _ := postgres.ParseLsn(candidate.ReplayedWalLsn)
+			if receivedWalLsn > promotedWalLsn || replayedWalLsn > promotedWalLsn {
+				log.Println("The candidate '" + candidate.Pod + "' is more advanced than the promoted Pod '" + failoverSelection.PromotedPod + "'")
+				return false
+			}
+		}
+
+		return true
+
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
+
 func (r *PrimaryFailureAndRecoveryTest) GivenUserAddedInPrimaryDb() {
 	Eventually(func() bool {
 		return r.connectionPrimaryDb.InsertUser()
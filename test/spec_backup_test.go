@@ -105,15 +105,15 @@ var _ = Describe("Setting Kubegres specs 'backup.*'", Label("group:3"), func() {
 
 	Context("GIVEN new Kubegres is created with spec 'backup.schedule' BUT WITHOUT spec 'backup.volumeMount''", func() {
 
-		It("THEN an error event should be logged", func() {
+		It("THEN the creation of Kubegres should be rejected", func() {
 
 			log.Print("START OF: Test 'GIVEN new Kubegres is created with spec 'backup.schedule' BUT WITHOUT spec 'backup.volumeMount''")
 
 			test.givenNewKubegresSpecIsSetTo(ctx.BaseConfigMapName, scheduleBackupEveryMin, resourceConfigs.BackUpPvcResourceName, "", 3)
 
-			test.whenKubegresIsCreated()
+			test.whenKubegresIsCreatedWithInvalidSpec()
 
-			test.thenErrorEventShouldBeLogged("spec.Backup.VolumeMount")
+			test.thenKubegresShouldBeRejected("spec.Backup.VolumeMount")
 
 			log.Print("END OF: Test 'GIVEN new Kubegres is created with spec 'backup.schedule' BUT WITHOUT spec 'backup.volumeMount''")
 		})
@@ -121,15 +121,15 @@ var _ = Describe("Setting Kubegres specs 'backup.*'", Label("group:3"), func() {
 
 	Context("GIVEN new Kubegres is created with spec 'backup.schedule' AND 'backup.volumeMount' BUT WITHOUT spec 'backup.pvcName''", func() {
 
-		It("THEN an error event should be logged", func() {
+		It("THEN the creation of Kubegres should be rejected", func() {
 
 			log.Print("START OF: Test 'GIVEN new Kubegres is created with spec 'backup.schedule' AND 'backup.volumeMount' BUT WITHOUT spec 'backup.pvcName''")
 
 			test.givenNewKubegresSpecIsSetTo(ctx.BaseConfigMapName, scheduleBackupEveryMin, "", "/tmp/my-kubegres", 3)
 
-			test.whenKubegresIsCreated()
+			test.whenKubegresIsCreatedWithInvalidSpec()
 
-			test.thenErrorEventShouldBeLogged("spec.Backup.PvcName")
+			test.thenKubegresShouldBeRejected("spec.Backup.PvcName")
 
 			log.Print("END OF: Test 'GIVEN new Kubegres is created with spec 'backup.schedule' AND 'backup.volumeMount' BUT WITHOUT spec 'backup.pvcName''")
 		})
@@ -271,6 +271,7 @@ type SpecBackUpTest struct {
 	resourceCreator                 util.TestResourceCreator
 	resourceRetriever               util.TestResourceRetriever
 	resourceModifier                util.TestResourceModifier
+	admissionErr                    error
 }
 
 func (r *SpecBackUpTest) givenNewKubegresSpecIsSetTo(customConfig, backupSchedule, backupPvcName, backupVolumeMount string, specNbreReplicas int32) {
@@ -315,24 +316,17 @@ func (r *SpecBackUpTest) whenKubegresIsCreated() {
 	r.resourceCreator.CreateKubegres(r.kubegresResource)
 }
 
+func (r *SpecBackUpTest) whenKubegresIsCreatedWithInvalidSpec() {
+	r.admissionErr = r.resourceCreator.CreateKubegresWithInvalidSpec(r.kubegresResource)
+}
+
 func (r *SpecBackUpTest) whenKubernetesIsUpdated() {
 	r.resourceCreator.UpdateResource(r.kubegresResource, "Kubegres")
 }
 
-func (r *SpecBackUpTest) thenErrorEventShouldBeLogged(specName string) {
-	expectedErrorEvent := util.EventRecord{
-		Eventtype: v12.EventTypeWarning,
-		Reason:    "SpecCheckErr",
-		Message:   "In the Resources Spec the value of '" + specName + "' is undefined. Please set a value otherwise this operator cannot work correctly.",
-	}
-	Eventually(func() bool {
-		_, err := r.resourceRetriever.GetKubegres()
-		if err != nil {
-			return false
-		}
-		return eventRecorderTest.CheckEventExist(expectedErrorEvent)
-
-	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+func (r *SpecBackUpTest) thenKubegresShouldBeRejected(specName string) {
+	Expect(r.admissionErr).Should(MatchError(ContainSubstring("In the Resources Spec the value of '" + specName + "' is undefined. " +
+		"Please set a value otherwise this operator cannot work correctly.")))
 }
 
 func (r *SpecBackUpTest) thenErrorEventSayingPvcIsNotDeployed() {
@@ -26,7 +26,6 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
-	v12 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	postgresv1 "reactive-tech.io/kubegres/api/v1"
 	"reactive-tech.io/kubegres/test/resourceConfigs"
@@ -57,15 +56,15 @@ var _ = Describe("Setting Kubegres specs 'database.size'", Label("group:3"), fun
 
 	Context("GIVEN new Kubegres is created without spec 'database.size'", func() {
 
-		It("THEN an error event should be logged", func() {
+		It("THEN the creation of Kubegres should be rejected", func() {
 
 			log.Print("START OF: Test 'GIVEN new Kubegres is created without spec 'database.size''")
 
 			test.givenNewKubegresSpecIsSetTo("", 3)
 
-			test.whenKubegresIsCreated()
+			test.whenKubegresIsCreatedWithInvalidSpec()
 
-			test.thenErrorEventShouldBeLogged()
+			test.thenKubegresShouldBeRejected()
 
 			log.Print("END OF: Test 'GIVEN new Kubegres is created without spec 'database.size'")
 		})
@@ -93,15 +92,15 @@ var _ = Describe("Setting Kubegres specs 'database.size'", Label("group:3"), fun
 			log.Print("END OF: Test 'GIVEN new Kubegres is created with spec 'database.size' set to '300Mi' and spec 'replica' set to 3'")
 		})
 
-		It("GIVEN existing Kubegres is updated with spec 'database.size' set from '300Mi' to '400Mi' THEN an error event should be logged", func() {
+		It("GIVEN existing Kubegres is updated with spec 'database.size' set from '300Mi' to '400Mi' THEN the update should be rejected", func() {
 
 			log.Print("START OF: Test 'GIVEN existing Kubegres is updated with spec 'database.size' set from '300Mi' to '400Mi'")
 
 			test.givenExistingKubegresSpecIsSetTo("400Mi")
 
-			test.whenKubernetesIsUpdated()
+			test.whenKubernetesIsUpdatedWithInvalidSpec()
 
-			test.thenErrorEventShouldBeLoggedSayingCannotChangeStorageSize("300Mi", "400Mi")
+			test.thenUpdateShouldBeRejectedSayingCannotChangeStorageSize("300Mi", "400Mi")
 
 			test.thenPodsStatesShouldBe("300Mi", 1, 2)
 
@@ -122,6 +121,7 @@ type SpecDatabaseSizeTest struct {
 	dbQueryTestCases                testcases.DbQueryTestCases
 	resourceCreator                 util.TestResourceCreator
 	resourceRetriever               util.TestResourceRetriever
+	admissionErr                    error
 }
 
 func (r *SpecDatabaseSizeTest) givenNewKubegresSpecIsSetTo(databaseSize string, specNbreReplicas int32) {
@@ -147,24 +147,21 @@ func (r *SpecDatabaseSizeTest) whenKubegresIsCreated() {
 	r.resourceCreator.CreateKubegres(r.kubegresResource)
 }
 
+func (r *SpecDatabaseSizeTest) whenKubegresIsCreatedWithInvalidSpec() {
+	r.admissionErr = r.resourceCreator.CreateKubegresWithInvalidSpec(r.kubegresResource)
+}
+
+func (r *SpecDatabaseSizeTest) whenKubernetesIsUpdatedWithInvalidSpec() {
+	r.admissionErr = r.resourceCreator.UpdateResourceWithInvalidSpec(r.kubegresResource, "Kubegres")
+}
+
 func (r *SpecDatabaseSizeTest) whenKubernetesIsUpdated() {
 	r.resourceCreator.UpdateResource(r.kubegresResource, "Kubegres")
 }
 
-func (r *SpecDatabaseSizeTest) thenErrorEventShouldBeLogged() {
-	expectedErrorEvent := util.EventRecord{
-		Eventtype: v12.EventTypeWarning,
-		Reason:    "SpecCheckErr",
-		Message:   "In the Resources Spec the value of 'spec.database.size' is undefined. Please set a value otherwise this operator cannot work correctly.",
-	}
-	Eventually(func() bool {
-		_, err := r.resourceRetriever.GetKubegres()
-		if err != nil {
-			return false
-		}
-		return eventRecorderTest.CheckEventExist(expectedErrorEvent)
-
-	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+func (r *SpecDatabaseSizeTest) thenKubegresShouldBeRejected() {
+	Expect(r.admissionErr).Should(MatchError(ContainSubstring("In the Resources Spec the value of 'spec.database.size' is undefined. " +
+		"Please set a value otherwise this operator cannot work correctly.")))
 }
 
 func (r *SpecDatabaseSizeTest) thenPodsStatesShouldBe(databaseSize string, nbrePrimary, nbreReplicas int) bool {
@@ -212,21 +209,9 @@ func (r *SpecDatabaseSizeTest) thenDeployedKubegresSpecShouldBeSetTo(databaseSiz
 	Expect(r.kubegresResource.Spec.Database.Size).Should(Equal(databaseSize))
 }
 
-func (r *SpecDatabaseSizeTest) thenErrorEventShouldBeLoggedSayingCannotChangeStorageSize(currentValue, newValue string) {
-	expectedErrorEvent := util.EventRecord{
-		Eventtype: v12.EventTypeWarning,
-		Reason:    "SpecCheckErr",
-		Message: "In the Resources Spec the value of 'spec.database.size' cannot be changed from '" + currentValue + "' to '" + newValue + "' after Pods were created. " +
-			"The StorageClass does not allow volume expansion. The option AllowVolumeExpansion is set to false. " +
-			"We roll-backed Kubegres spec to the currently working value '" + currentValue + "'. " +
-			"If you know what you are doing, you can manually update that spec in every StatefulSet of your PostgreSql cluster and then Kubegres will automatically update itself.",
-	}
-	Eventually(func() bool {
-		_, err := r.resourceRetriever.GetKubegres()
-		if err != nil {
-			return false
-		}
-		return eventRecorderTest.CheckEventExist(expectedErrorEvent)
-
-	}, time.Second*10, time.Second*5).Should(BeTrue())
+func (r *SpecDatabaseSizeTest) thenUpdateShouldBeRejectedSayingCannotChangeStorageSize(currentValue, newValue string) {
+	Expect(r.admissionErr).Should(MatchError(ContainSubstring("In the Resources Spec the value of 'spec.database.size' cannot be changed from '" + currentValue + "' to '" + newValue + "' after Pods were created. " +
+		"The StorageClass does not allow volume expansion. The option AllowVolumeExpansion is set to false. " +
+		"Please rollback that value to '" + currentValue + "'. " +
+		"If you know what you are doing, you can manually update that spec in every StatefulSet of your PostgreSql cluster and then Kubegres will automatically update itself.")))
 }
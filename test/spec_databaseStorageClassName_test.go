@@ -104,15 +104,15 @@ var _ = Describe("Setting Kubegres specs 'database.storageClassName'", Label("gr
 			log.Print("END OF: Test 'GIVEN new Kubegres is created with spec 'database.storageClassName' set to 'standard' and spec 'replica' set to 3'")
 		})
 
-		It("GIVEN existing Kubegres is updated with spec 'database.storageClassName' set from 'standard' to 'anything' THEN an error event should be logged", func() {
+		It("GIVEN existing Kubegres is updated with spec 'database.storageClassName' set from 'standard' to 'anything' THEN the update should be rejected", func() {
 
 			log.Print("START OF: Test 'GIVEN existing Kubegres is updated with spec 'database.storageClassName' set from 'standard' to 'anything'")
 
 			test.givenExistingKubegresSpecIsSetTo("anything")
 
-			test.whenKubernetesIsUpdated()
+			test.whenKubernetesIsUpdatedWithInvalidSpec()
 
-			test.thenErrorEventShouldBeLoggedSayingCannotChangeStorageClassName("standard", "anything")
+			test.thenUpdateShouldBeRejectedSayingCannotChangeStorageClassName("standard", "anything")
 
 			test.thenPodsStatesShouldBe("standard", 1, 2)
 
@@ -133,6 +133,7 @@ type SpecDatabaseStorageClassTest struct {
 	dbQueryTestCases                testcases.DbQueryTestCases
 	resourceCreator                 util.TestResourceCreator
 	resourceRetriever               util.TestResourceRetriever
+	admissionErr                    error
 }
 
 func (r *SpecDatabaseStorageClassTest) givenNewKubegresSpecIsSetTo(databaseStorageClassName *string, specNbreReplicas int32) {
@@ -158,6 +159,10 @@ func (r *SpecDatabaseStorageClassTest) whenKubegresIsCreated() {
 	r.resourceCreator.CreateKubegres(r.kubegresResource)
 }
 
+func (r *SpecDatabaseStorageClassTest) whenKubernetesIsUpdatedWithInvalidSpec() {
+	r.admissionErr = r.resourceCreator.UpdateResourceWithInvalidSpec(r.kubegresResource, "Kubegres")
+}
+
 func (r *SpecDatabaseStorageClassTest) whenKubernetesIsUpdated() {
 	r.resourceCreator.UpdateResource(r.kubegresResource, "Kubegres")
 }
@@ -240,21 +245,9 @@ func (r *SpecDatabaseStorageClassTest) thenEventShouldBeLoggedSayingStorageClass
 	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
 }
 
-func (r *SpecDatabaseStorageClassTest) thenErrorEventShouldBeLoggedSayingCannotChangeStorageClassName(currentValue, newValue string) {
-	expectedErrorEvent := util.EventRecord{
-		Eventtype: v12.EventTypeWarning,
-		Reason:    "SpecCheckErr",
-		Message: "In the Resources Spec the value of 'spec.database.storageClassName' cannot be changed from '" + currentValue + "' to '" + newValue + "' after Pods were created. " +
-			"Otherwise, the cluster of PostgreSql servers risk of being inconsistent. " +
-			"We roll-backed Kubegres spec to the currently working value '" + currentValue + "'. " +
-			"If you know what you are doing, you can manually update that spec in every StatefulSet of your PostgreSql cluster and then Kubegres will automatically update itself.",
-	}
-	Eventually(func() bool {
-		_, err := r.resourceRetriever.GetKubegres()
-		if err != nil {
-			return false
-		}
-		return eventRecorderTest.CheckEventExist(expectedErrorEvent)
-
-	}, time.Second*10, time.Second*5).Should(BeTrue())
+func (r *SpecDatabaseStorageClassTest) thenUpdateShouldBeRejectedSayingCannotChangeStorageClassName(currentValue, newValue string) {
+	Expect(r.admissionErr).Should(MatchError(ContainSubstring("In the Resources Spec the value of 'spec.database.storageClassName' cannot be changed from '" + currentValue + "' to '" + newValue + "' after Pods were created. " +
+		"Otherwise, the cluster of PostgreSql servers risk of being inconsistent. " +
+		"Please rollback that value to '" + currentValue + "'. " +
+		"If you know what you are doing, you can manually update that spec in every StatefulSet of your PostgreSql cluster and then Kubegres will automatically update itself.")))
 }
@@ -101,15 +101,15 @@ var _ = Describe("Setting Kubegres specs 'database.volumeMount'", Label("group:3
 			log.Print("END OF: Test 'GIVEN new Kubegres is created with spec 'database.volumeMount' set to '/tmp/folder1' and spec 'replica' set to 3'")
 		})
 
-		It("GIVEN existing Kubegres is updated with spec 'database.volumeMount' set from '/tmp/folder1' to '/tmp/folder2' THEN an error event should be logged", func() {
+		It("GIVEN existing Kubegres is updated with spec 'database.volumeMount' set from '/tmp/folder1' to '/tmp/folder2' THEN the update should be rejected", func() {
 
 			log.Print("START OF: Test 'GIVEN existing Kubegres is updated with spec 'database.volumeMount' set from '/tmp/folder1' to '/tmp/folder2'")
 
 			test.givenExistingKubegresSpecIsSetTo("/tmp/folder2")
 
-			test.whenKubernetesIsUpdated()
+			test.whenKubernetesIsUpdatedWithInvalidSpec()
 
-			test.thenErrorEventShouldBeLoggedSayingCannotChangeDatabaseVolumeMount("/tmp/folder1", "/tmp/folder2")
+			test.thenUpdateShouldBeRejectedSayingCannotChangeDatabaseVolumeMount("/tmp/folder1", "/tmp/folder2")
 
 			test.thenPodsStatesShouldBe("/tmp/folder1", 1, 2)
 
@@ -130,6 +130,7 @@ type SpecDatabaseVolumeMountTest struct {
 	dbQueryTestCases                testcases.DbQueryTestCases
 	resourceCreator                 util.TestResourceCreator
 	resourceRetriever               util.TestResourceRetriever
+	admissionErr                    error
 }
 
 func (r *SpecDatabaseVolumeMountTest) givenNewKubegresSpecIsSetTo(databaseVolumeMount string, specNbreReplicas int32) {
@@ -155,6 +156,10 @@ func (r *SpecDatabaseVolumeMountTest) whenKubegresIsCreated() {
 	r.resourceCreator.CreateKubegres(r.kubegresResource)
 }
 
+func (r *SpecDatabaseVolumeMountTest) whenKubernetesIsUpdatedWithInvalidSpec() {
+	r.admissionErr = r.resourceCreator.UpdateResourceWithInvalidSpec(r.kubegresResource, "Kubegres")
+}
+
 func (r *SpecDatabaseVolumeMountTest) whenKubernetesIsUpdated() {
 	r.resourceCreator.UpdateResource(r.kubegresResource, "Kubegres")
 }
@@ -221,21 +226,9 @@ func (r *SpecDatabaseVolumeMountTest) thenDeployedKubegresSpecShouldBeSetTo(data
 	Expect(r.kubegresResource.Spec.Database.VolumeMount).Should(Equal(databaseVolumeMount))
 }
 
-func (r *SpecDatabaseVolumeMountTest) thenErrorEventShouldBeLoggedSayingCannotChangeDatabaseVolumeMount(currentValue, newValue string) {
-	expectedErrorEvent := util.EventRecord{
-		Eventtype: v12.EventTypeWarning,
-		Reason:    "SpecCheckErr",
-		Message: "In the Resources Spec the value of 'spec.database.volumeMount' cannot be changed from '" + currentValue + "' to '" + newValue + "' after Pods were created. " +
-			"Otherwise, the cluster of PostgreSql servers risk of being inconsistent. " +
-			"We roll-backed Kubegres spec to the currently working value '" + currentValue + "'. " +
-			"If you know what you are doing, you can manually update that spec in every StatefulSet of your PostgreSql cluster and then Kubegres will automatically update itself.",
-	}
-	Eventually(func() bool {
-		_, err := r.resourceRetriever.GetKubegres()
-		if err != nil {
-			return false
-		}
-		return eventRecorderTest.CheckEventExist(expectedErrorEvent)
-
-	}, time.Second*10, time.Second*5).Should(BeTrue())
+func (r *SpecDatabaseVolumeMountTest) thenUpdateShouldBeRejectedSayingCannotChangeDatabaseVolumeMount(currentValue, newValue string) {
+	Expect(r.admissionErr).Should(MatchError(ContainSubstring("In the Resources Spec the value of 'spec.database.volumeMount' cannot be changed from '" + currentValue + "' to '" + newValue + "' after Pods were created. " +
+		"Otherwise, the cluster of PostgreSql servers risk of being inconsistent. " +
+		"Please rollback that value to '" + currentValue + "'. " +
+		"If you know what you are doing, you can manually update that spec in every StatefulSet of your PostgreSql cluster and then Kubegres will automatically update itself.")))
 }
@@ -53,15 +53,15 @@ var _ = Describe("Setting Kubegres spec 'env.*'", Label("group:1"), Label("group
 
 	Context("GIVEN new Kubegres is created without environment variable of postgres super-user password", func() {
 
-		It("THEN an error event should be logged saying the super-user password is missing", func() {
+		It("THEN the creation of Kubegres should be rejected", func() {
 
 			log.Print("START OF: Test 'GIVEN new Kubegres is created without environment variable of postgres super-user password'")
 
 			test.givenNewKubegresWithoutEnvVarOfPostgresSuperUserPassword()
 
-			test.whenKubegresIsCreated()
+			test.whenKubegresIsCreatedWithInvalidSpec()
 
-			test.thenErrorEventShouldBeLogged("spec.env.POSTGRES_PASSWORD")
+			test.thenKubegresShouldBeRejected("spec.env.POSTGRES_PASSWORD")
 
 			log.Print("END OF: Test 'GIVEN new Kubegres is created without environment variable of postgres super-user password'")
 		})
@@ -69,15 +69,15 @@ var _ = Describe("Setting Kubegres spec 'env.*'", Label("group:1"), Label("group
 
 	Context("GIVEN new Kubegres is created without environment variable of postgres replication-user password", func() {
 
-		It("THEN an error event should be logged saying the replication-user password is missing", func() {
+		It("THEN the creation of Kubegres should be rejected", func() {
 
 			log.Print("START OF: Test 'GIVEN new Kubegres is created without environment variable of postgres replication-user password'")
 
 			test.givenNewKubegresWithoutEnvVarOfPostgresReplicationUserPassword()
 
-			test.whenKubegresIsCreated()
+			test.whenKubegresIsCreatedWithInvalidSpec()
 
-			test.thenErrorEventShouldBeLogged("spec.env.POSTGRES_REPLICATION_PASSWORD")
+			test.thenKubegresShouldBeRejected("spec.env.POSTGRES_REPLICATION_PASSWORD")
 
 			log.Print("END OF: Test 'GIVEN new Kubegres is created without environment variable of postgres replication-user password'")
 		})
@@ -134,6 +134,7 @@ type SpecEnVariablesTest struct {
 	resourceModifier      util.TestResourceModifier
 	customEnvVariableName string
 	customEnvVariableKey  string
+	admissionErr          error
 }
 
 func (r *SpecEnVariablesTest) givenNewKubegresWithoutEnvVarOfPostgresSuperUserPassword() {
@@ -171,6 +172,10 @@ func (r *SpecEnVariablesTest) whenKubegresIsCreated() {
 	r.resourceCreator.CreateKubegres(r.kubegresResource)
 }
 
+func (r *SpecEnVariablesTest) whenKubegresIsCreatedWithInvalidSpec() {
+	r.admissionErr = r.resourceCreator.CreateKubegresWithInvalidSpec(r.kubegresResource)
+}
+
 func (r *SpecEnVariablesTest) thenPodsShouldContainAllEnvVariables(nbrePrimary, nbreReplicas int) bool {
 	return Eventually(func() bool {
 
@@ -231,18 +236,7 @@ func (r *SpecEnVariablesTest) doesEnvVarExist(envVarName string, envVars []v12.E
 	return false
 }
 
-func (r *SpecEnVariablesTest) thenErrorEventShouldBeLogged(specName string) {
-	expectedErrorEvent := util.EventRecord{
-		Eventtype: v12.EventTypeWarning,
-		Reason:    "SpecCheckErr",
-		Message:   "In the Resources Spec the value of '" + specName + "' is undefined. Please set a value otherwise this operator cannot work correctly.",
-	}
-	Eventually(func() bool {
-		_, err := r.resourceRetriever.GetKubegres()
-		if err != nil {
-			return false
-		}
-		return eventRecorderTest.CheckEventExist(expectedErrorEvent)
-
-	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+func (r *SpecEnVariablesTest) thenKubegresShouldBeRejected(specName string) {
+	Expect(r.admissionErr).Should(MatchError(ContainSubstring("In the Resources Spec the value of '" + specName + "' is undefined. " +
+		"Please set a value otherwise this operator cannot work correctly.")))
 }
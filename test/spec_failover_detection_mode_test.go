@@ -0,0 +1,208 @@
+/*
+Copyright 2021 Reactive Tech Limited.
+"Reactive Tech Limited" is a company located in England, United Kingdom.
+https://www.reactive-tech.io
+
+Lead Developer: Alex Arica
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"log"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	postgresv1 "reactive-tech.io/kubegres/api/v1"
+	"reactive-tech.io/kubegres/test/resourceConfigs"
+	"reactive-tech.io/kubegres/test/util"
+)
+
+// The readiness probe of the PostgreSql instances fails once this file exists, while PostgreSql keeps running
+const unreadyFileForTest = "/tmp/kubegres-test-unready"
+
+var _ = Describe("Setting Kubegres spec 'failover.detectionMode' to 'replicasQuorum'", Label("group:3"), func() {
+
+	var test = SpecFailoverDetectionModeTest{}
+
+	BeforeEach(func() {
+		//Skip("Temporarily skipping test")
+
+		namespace := resourceConfigs.DefaultNamespace
+		test.resourceRetriever = util.CreateTestResourceRetriever(k8sClientTest, namespace)
+		test.resourceCreator = util.CreateTestResourceCreator(k8sClientTest, test.resourceRetriever, namespace)
+		test.connectionPrimaryDb = util.InitDbConnectionDbUtil(test.resourceCreator, resourceConfigs.KubegresResourceName, resourceConfigs.ServiceToSqlQueryPrimaryDbNodePort, true)
+	})
+
+	AfterEach(func() {
+		test.resourceCreator.DeleteAllTestResources()
+	})
+
+	Context("GIVEN new Kubegres is created with spec 'failover.detectionMode' set to 'replicasQuorum' AND with 3 instances AND the Pod of the Primary is not ready while the Replicas are still streaming from it", func() {
+
+		It("THEN a failover should not happen AND once the Primary StatefulSet is deleted, a Replica should be promoted", func() {
+
+			log.Print("START OF: Test 'GIVEN new Kubegres is created with spec 'failover.detectionMode' set to 'replicasQuorum' AND with 3 instances AND the Pod of the Primary is not ready while the Replicas are still streaming from it'")
+
+			test.givenNewKubegresSpecIsSetTo(3)
+
+			test.whenKubegresIsCreated()
+
+			test.thenPodsStatesShouldBe(1, 2)
+
+			primaryPodName := test.getPrimaryPodName()
+
+			test.givenPrimaryPodIsNotReady()
+
+			test.thenFailoverShouldNotHappen(primaryPodName)
+
+			test.whenPrimaryStatefulSetIsDeleted()
+
+			test.thenPodsStatesShouldBe(1, 2)
+
+			log.Print("END OF: Test 'GIVEN new Kubegres is created with spec 'failover.detectionMode' set to 'replicasQuorum' AND with 3 instances AND the Pod of the Primary is not ready while the Replicas are still streaming from it'")
+		})
+	})
+})
+
+type SpecFailoverDetectionModeTest struct {
+	kubegresResource    *postgresv1.Kubegres
+	connectionPrimaryDb util.DbConnectionDbUtil
+	resourceCreator     util.TestResourceCreator
+	resourceRetriever   util.TestResourceRetriever
+}
+
+func (r *SpecFailoverDetectionModeTest) givenNewKubegresSpecIsSetTo(specNbreReplicas int32) {
+	r.kubegresResource = resourceConfigs.LoadKubegresYaml()
+	r.kubegresResource.Spec.Replicas = &specNbreReplicas
+	r.kubegresResource.Spec.Failover.DetectionMode = postgresv1.FailoverDetectionModeReplicasQuorum
+
+	command := []string{"sh", "-c", "test ! -f " + unreadyFileForTest + " && exec pg_isready -U postgres -h $POD_IP"}
+	r.kubegresResource.Spec.Probe.ReadinessProbe = &core.Probe{
+		ProbeHandler:     core.ProbeHandler{Exec: &core.ExecAction{Command: command}},
+		TimeoutSeconds:   int32(5),
+		PeriodSeconds:    int32(5),
+		SuccessThreshold: int32(1),
+		FailureThreshold: int32(2),
+	}
+}
+
+// givenPrimaryPodIsNotReady makes the readiness probe of the Primary fail, while PostgreSql keeps running
+// and streaming its WAL to the Replicas.
+func (r *SpecFailoverDetectionModeTest) givenPrimaryPodIsNotReady() {
+	Eventually(func() bool {
+		return r.connectionPrimaryDb.ExecSql("COPY (SELECT 1) TO '" + unreadyFileForTest + "'")
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+
+	Eventually(func() bool {
+		kubegresResources, err := r.resourceRetriever.GetKubegresResources()
+		if err != nil {
+			return false
+		}
+
+		for _, kubegresResource := range kubegresResources.Resources {
+			if kubegresResource.IsPrimary && !kubegresResource.IsReady {
+				log.Println("The Primary Pod '" + kubegresResource.Pod.Name + "' is not ready")
+				return true
+			}
+		}
+		return false
+
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
+
+func (r *SpecFailoverDetectionModeTest) whenKubegresIsCreated() {
+	r.resourceCreator.CreateKubegres(r.kubegresResource)
+}
+
+func (r *SpecFailoverDetectionModeTest) whenPrimaryStatefulSetIsDeleted() {
+	kubegresResources, err := r.resourceRetriever.GetKubegresResources()
+	if err != nil {
+		Expect(err).Should(Succeed())
+		return
+	}
+
+	nbreDeleted := 0
+	for _, kubegresResource := range kubegresResources.Resources {
+		if kubegresResource.IsPrimary {
+			log.Println("Attempting to delete StatefulSet: '" + kubegresResource.StatefulSet.Name + "'")
+			if r.resourceCreator.DeleteResource(kubegresResource.StatefulSet.Resource, kubegresResource.StatefulSet.Name) {
+				nbreDeleted++
+				time.Sleep(5 * time.Second)
+			}
+		}
+	}
+
+	Expect(nbreDeleted).Should(Equal(1))
+}
+
+func (r *SpecFailoverDetectionModeTest) getPrimaryPodName() string {
+	kubegresResources, err := r.resourceRetriever.GetKubegresResources()
+	Expect(err).Should(Succeed())
+
+	for _, kubegresResource := range kubegresResources.Resources {
+		if kubegresResource.IsPrimary {
+			return kubegresResource.Pod.Name
+		}
+	}
+
+	Fail("The Primary is not deployed")
+	return ""
+}
+
+func (r *SpecFailoverDetectionModeTest) thenFailoverShouldNotHappen(expectedPrimaryPodName string) {
+	Consistently(func() bool {
+
+		kubegres, err := r.resourceRetriever.GetKubegres()
+		if err != nil {
+			log.Println("ERROR while retrieving Kubegres resource: ", err)
+			return false
+		}
+
+		if len(kubegres.Status.FailoverHistory) > 0 {
+			log.Println("A failover happened although the Replicas were still streaming from the Primary")
+			return false
+		}
+
+		return r.getPrimaryPodName() == expectedPrimaryPodName
+
+	}, 90*time.Second, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
+
+func (r *SpecFailoverDetectionModeTest) thenPodsStatesShouldBe(nbrePrimary, nbreReplicas int) bool {
+	return Eventually(func() bool {
+
+		kubegresResources, err := r.resourceRetriever.GetKubegresResources()
+		if err != nil && !apierrors.IsNotFound(err) {
+			log.Println("ERROR while retrieving Kubegres kubegresResources")
+			return false
+		}
+
+		if kubegresResources.AreAllReady &&
+			kubegresResources.NbreDeployedPrimary == nbrePrimary &&
+			kubegresResources.NbreDeployedReplicas == nbreReplicas {
+
+			time.Sleep(resourceConfigs.TestRetryInterval)
+			log.Println("Deployed and Ready StatefulSets check successful")
+			return true
+		}
+
+		return false
+
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
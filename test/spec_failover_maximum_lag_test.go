@@ -0,0 +1,308 @@
+/*
+Copyright 2021 Reactive Tech Limited.
+"Reactive Tech Limited" is a company located in England, United Kingdom.
+https://www.reactive-tech.io
+
+Lead Developer: Alex Arica
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"log"
+	"strconv"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	postgresv1 "reactive-tech.io/kubegres/api/v1"
+	"reactive-tech.io/kubegres/controllers/postgres"
+	"reactive-tech.io/kubegres/controllers/status_update"
+	"reactive-tech.io/kubegres/test/resourceConfigs"
+	"reactive-tech.io/kubegres/test/util"
+)
+
+var _ = Describe("Setting Kubegres spec 'failover.maximumLagBytes'", Label("group:3"), func() {
+
+	var test = SpecFailoverMaximumLagTest{}
+
+	BeforeEach(func() {
+		//Skip("Temporarily skipping test")
+
+		namespace := resourceConfigs.DefaultNamespace
+		test.resourceRetriever = util.CreateTestResourceRetriever(k8sClientTest, namespace)
+		test.resourceCreator = util.CreateTestResourceCreator(k8sClientTest, test.resourceRetriever, namespace)
+		test.connectionPrimaryDb = util.InitDbConnectionDbUtil(test.resourceCreator, resourceConfigs.KubegresResourceName, resourceConfigs.ServiceToSqlQueryPrimaryDbNodePort, true)
+		test.connectionReplicaDb = util.InitDbConnectionDbUtil(test.resourceCreator, resourceConfigs.KubegresResourceName, resourceConfigs.ServiceToSqlQueryReplicaDbNodePort, false)
+	})
+
+	AfterEach(func() {
+		test.resourceCreator.DeleteAllTestResources()
+	})
+
+	Context("GIVEN new Kubegres is created with spec 'failover.maximumLagBytes' set to 16MB AND with 3 instances AND the Primary fails", func() {
+
+		It("THEN a Replica should be promoted AND no data should be lost", func() {
+
+			log.Print("START OF: Test 'GIVEN new Kubegres is created with spec 'failover.maximumLagBytes' set to 16MB AND with 3 instances AND the Primary fails'")
+
+			test.givenNewKubegresSpecIsSetTo(3, 16*1024*1024)
+
+			test.whenKubegresIsCreated()
+
+			test.thenPodsStatesShouldBe(1, 2)
+
+			test.givenUserAddedInPrimaryDb()
+
+			test.whenPrimaryStatefulSetIsDeleted()
+
+			test.thenPodsStatesShouldBe(1, 2)
+
+			test.thenConditionStatusShouldBe(postgresv1.ConditionTypeFailoverBlocked, metav1.ConditionFalse)
+
+			test.thenPrimaryDbContainsExpectedNbreUsers(1)
+
+			log.Print("END OF: Test 'GIVEN new Kubegres is created with spec 'failover.maximumLagBytes' set to 16MB AND with 3 instances AND the Primary fails'")
+		})
+	})
+
+	Context("GIVEN new Kubegres is created with spec 'failover.maximumLagBytes' set to 1KB AND with 2 instances AND the Replica lags AND the Primary fails", func() {
+
+		It("THEN the Replica should not be promoted AND the condition 'FailoverBlocked' should be true", func() {
+
+			log.Print("START OF: Test 'GIVEN new Kubegres is created with spec 'failover.maximumLagBytes' set to 1KB AND with 2 instances AND the Replica lags AND the Primary fails'")
+
+			test.givenNewKubegresSpecIsSetTo(2, 1024)
+
+			test.whenKubegresIsCreated()
+
+			test.thenPodsStatesShouldBe(1, 1)
+
+			test.givenUserAddedInPrimaryDb()
+
+			test.givenReplicaStopsReceivingWal()
+
+			test.givenWalIsWrittenInPrimaryDb()
+
+			test.thenLastPrimaryWalLsnShouldBeRecordedWithinRefreshPeriod()
+
+			test.thenReplicaReplayLagBytesShouldBeGreaterThan(1024)
+
+			test.whenPrimaryStatefulSetIsDeleted()
+
+			test.thenConditionStatusShouldBe(postgresv1.ConditionTypeFailoverBlocked, metav1.ConditionTrue)
+
+			test.thenConditionStatusShouldBe(postgresv1.ConditionTypeReady, metav1.ConditionFalse)
+
+			test.thenPodsStatesShouldBe(0, 1)
+
+			test.thenFailoverShouldNotStart()
+
+			log.Print("END OF: Test 'GIVEN new Kubegres is created with spec 'failover.maximumLagBytes' set to 1KB AND with 2 instances AND the Replica lags AND the Primary fails'")
+		})
+	})
+})
+
+type SpecFailoverMaximumLagTest struct {
+	kubegresResource    *postgresv1.Kubegres
+	connectionPrimaryDb util.DbConnectionDbUtil
+	connectionReplicaDb util.DbConnectionDbUtil
+	resourceCreator     util.TestResourceCreator
+	resourceRetriever   util.TestResourceRetriever
+}
+
+func (r *SpecFailoverMaximumLagTest) givenNewKubegresSpecIsSetTo(specNbreReplicas int32, maximumLagBytes int64) {
+	r.kubegresResource = resourceConfigs.LoadKubegresYaml()
+	r.kubegresResource.Spec.Replicas = &specNbreReplicas
+	r.kubegresResource.Spec.Failover.MaximumLagBytes = &maximumLagBytes
+}
+
+func (r *SpecFailoverMaximumLagTest) givenUserAddedInPrimaryDb() {
+	Eventually(func() bool {
+		return r.connectionPrimaryDb.InsertUser()
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
+
+// givenReplicaStopsReceivingWal resets the connection of the Replica to the Primary, so that it stays ready
+// but does not receive any WAL anymore.
+func (r *SpecFailoverMaximumLagTest) givenReplicaStopsReceivingWal() {
+	Eventually(func() bool {
+		return r.connectionReplicaDb.ExecSql("ALTER SYSTEM SET primary_conninfo = ''") &&
+			r.connectionReplicaDb.ExecSql("SELECT pg_reload_conf()")
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
+
+// givenWalIsWrittenInPrimaryDb switches the WAL file of the Primary once a user is added, so that its WAL LSN
+// moves forward by up to the size of a WAL file.
+func (r *SpecFailoverMaximumLagTest) givenWalIsWrittenInPrimaryDb() {
+	r.givenUserAddedInPrimaryDb()
+	Eventually(func() bool {
+		return r.connectionPrimaryDb.ExecSql("SELECT pg_switch_wal()")
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
+
+func (r *SpecFailoverMaximumLagTest) whenKubegresIsCreated() {
+	r.resourceCreator.CreateKubegres(r.kubegresResource)
+}
+
+func (r *SpecFailoverMaximumLagTest) whenPrimaryStatefulSetIsDeleted() {
+	kubegresResources, err := r.resourceRetriever.GetKubegresResources()
+	if err != nil {
+		Expect(err).Should(Succeed())
+		return
+	}
+
+	nbreDeleted := 0
+	for _, kubegresResource := range kubegresResources.Resources {
+		if kubegresResource.IsPrimary {
+			log.Println("Attempting to delete StatefulSet: '" + kubegresResource.StatefulSet.Name + "'")
+			if r.resourceCreator.DeleteResource(kubegresResource.StatefulSet.Resource, kubegresResource.StatefulSet.Name) {
+				nbreDeleted++
+				time.Sleep(5 * time.Second)
+			}
+		}
+	}
+
+	Expect(nbreDeleted).Should(Equal(1))
+}
+
+func (r *SpecFailoverMaximumLagTest) thenPodsStatesShouldBe(nbrePrimary, nbreReplicas int) bool {
+	return Eventually(func() bool {
+
+		kubegresResources, err := r.resourceRetriever.GetKubegresResources()
+		if err != nil && !apierrors.IsNotFound(err) {
+			log.Println("ERROR while retrieving Kubegres kubegresResources")
+			return false
+		}
+
+		if kubegresResources.AreAllReady &&
+			kubegresResources.NbreDeployedPrimary == nbrePrimary &&
+			kubegresResources.NbreDeployedReplicas == nbreReplicas {
+
+			time.Sleep(resourceConfigs.TestRetryInterval)
+			log.Println("Deployed and Ready StatefulSets check successful")
+			return true
+		}
+
+		return false
+
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
+
+// thenLastPrimaryWalLsnShouldBeRecordedWithinRefreshPeriod checks that the WAL LSN of the Primary is recorded
+// in the status once 'InstancesRefreshPeriod' elapsed, as the reconciliation is requeued with that period.
+func (r *SpecFailoverMaximumLagTest) thenLastPrimaryWalLsnShouldBeRecordedWithinRefreshPeriod() {
+
+	primaryWalLsn, err := postgres.ParseLsn(r.connectionPrimaryDb.GetCurrentWalLsn())
+	Expect(err).Should(Succeed())
+
+	Eventually(func() bool {
+
+		kubegres, err := r.resourceRetriever.GetKubegres()
+		if err != nil {
+			log.Println("ERROR while retrieving Kubegres resource: ", err)
+			return false
+		}
+
+		lastPrimaryWalLsn, err := postgres.ParseLsn(kubegres.Status.LastPrimaryWalLsn)
+		if err != nil || lastPrimaryWalLsn < primaryWalLsn {
+			log.Println("Waiting for the WAL LSN '" + primaryWalLsn.String() + "' of the Primary to be recorded in the status.")
+			return false
+		}
+		return true
+
+	}, 2*status_update.InstancesRefreshPeriod+10*time.Second, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
+
+func (r *SpecFailoverMaximumLagTest) thenReplicaReplayLagBytesShouldBeGreaterThan(lagBytes int64) {
+	Eventually(func() bool {
+
+		kubegres, err := r.resourceRetriever.GetKubegres()
+		if err != nil {
+			log.Println("ERROR while retrieving Kubegres resource: ", err)
+			return false
+		}
+
+		for _, instance := range kubegres.Status.Instances {
+			if instance.Role != resourceConfigs.PrimaryReplicationRole && instance.ReplayLagBytes != nil && *instance.ReplayLagBytes > lagBytes {
+				return true
+			}
+		}
+
+		log.Println("Waiting for the Replica to lag more than " + strconv.FormatInt(lagBytes, 10) + " bytes.")
+		return false
+
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
+
+func (r *SpecFailoverMaximumLagTest) thenConditionStatusShouldBe(conditionType string, expectedStatus metav1.ConditionStatus) {
+	Eventually(func() bool {
+
+		kubegres, err := r.resourceRetriever.GetKubegres()
+		if err != nil {
+			log.Println("ERROR while retrieving Kubegres resource: ", err)
+			return false
+		}
+
+		condition := meta.FindStatusCondition(kubegres.Status.Conditions, conditionType)
+		if condition == nil || condition.Status != expectedStatus {
+			log.Println("Waiting for the condition '" + conditionType + "' to have the status '" + string(expectedStatus) + "'")
+			return false
+		}
+
+		return true
+
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
+
+// thenFailoverShouldNotStart checks that a failover blocked by the lag of the Replicas is neither recorded
+// nor started as a blocking operation.
+func (r *SpecFailoverMaximumLagTest) thenFailoverShouldNotStart() {
+	Consistently(func() bool {
+
+		kubegres, err := r.resourceRetriever.GetKubegres()
+		if err != nil {
+			log.Println("ERROR while retrieving Kubegres resource: ", err)
+			return false
+		}
+
+		if len(kubegres.Status.FailoverHistory) > 0 || kubegres.Status.BlockingOperation.OperationId != "" {
+			log.Println("A failover started although the Replica lags more than 'failover.maximumLagBytes'")
+			return false
+		}
+
+		return true
+
+	}, 30*time.Second, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
+
+func (r *SpecFailoverMaximumLagTest) thenPrimaryDbContainsExpectedNbreUsers(expectedNbreUsers int) {
+	Eventually(func() bool {
+
+		users := r.connectionPrimaryDb.GetUsers()
+		r.connectionPrimaryDb.Close()
+
+		if len(users) != expectedNbreUsers {
+			log.Println("Primary DB does not contain the expected number of users. Expected: " + strconv.Itoa(expectedNbreUsers) + " Given: " + strconv.Itoa(len(users)))
+			return false
+		}
+
+		return true
+
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
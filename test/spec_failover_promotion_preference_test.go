@@ -0,0 +1,263 @@
+/*
+Copyright 2021 Reactive Tech Limited.
+"Reactive Tech Limited" is a company located in England, United Kingdom.
+https://www.reactive-tech.io
+
+Lead Developer: Alex Arica
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"context"
+	"log"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	postgresv1 "reactive-tech.io/kubegres/api/v1"
+	"reactive-tech.io/kubegres/test/resourceConfigs"
+	"reactive-tech.io/kubegres/test/util"
+)
+
+const preferredZoneForTest = "kubegres-test-preferred-zone"
+
+var _ = Describe("Setting Kubegres spec 'failover.preferredZones' and 'failover.neverPromoteLabelSelector'", Label("group:3"), func() {
+
+	var test = SpecFailoverPromotionPreferenceTest{}
+
+	BeforeEach(func() {
+		//Skip("Temporarily skipping test")
+
+		namespace := resourceConfigs.DefaultNamespace
+		test.resourceRetriever = util.CreateTestResourceRetriever(k8sClientTest, namespace)
+		test.resourceCreator = util.CreateTestResourceCreator(k8sClientTest, test.resourceRetriever, namespace)
+		test.labelledNodeName = ""
+	})
+
+	AfterEach(func() {
+		test.removeZoneLabelFromNode()
+		test.resourceCreator.DeleteAllTestResources()
+	})
+
+	Context("GIVEN new Kubegres is created with spec 'failover.preferredZones' AND with 3 instances AND the Node of the last Replica is in the preferred zone", func() {
+
+		It("THEN the Replica in the preferred zone should be promoted when the Primary fails", func() {
+
+			log.Print("START OF: Test 'GIVEN new Kubegres is created with spec 'failover.preferredZones' AND with 3 instances AND the Node of the last Replica is in the preferred zone'")
+
+			test.givenNewKubegresSpecIsSetTo(3)
+
+			test.givenKubegresSpecHasPreferredZone(preferredZoneForTest)
+
+			test.whenKubegresIsCreated()
+
+			test.thenPodsStatesShouldBe(1, 2)
+
+			preferredReplica := test.givenNodeOfLastReplicaIsInZone(preferredZoneForTest)
+
+			test.whenPrimaryStatefulSetIsDeleted()
+
+			test.thenPodsStatesShouldBe(1, 2)
+
+			test.thenPrimaryPodShouldBe(preferredReplica.Pod.Name)
+
+			log.Print("END OF: Test 'GIVEN new Kubegres is created with spec 'failover.preferredZones' AND with 3 instances AND the Node of the last Replica is in the preferred zone'")
+		})
+	})
+
+	Context("GIVEN new Kubegres is created with spec 'failover.neverPromoteLabelSelector' matching all the Nodes AND with 2 instances", func() {
+
+		It("THEN the Replica should not be promoted when the Primary fails AND the condition 'FailoverBlocked' should be true", func() {
+
+			log.Print("START OF: Test 'GIVEN new Kubegres is created with spec 'failover.neverPromoteLabelSelector' matching all the Nodes AND with 2 instances'")
+
+			test.givenNewKubegresSpecIsSetTo(2)
+
+			test.givenKubegresSpecHasNeverPromoteLabelSelector(map[string]string{"kubernetes.io/os": "linux"})
+
+			test.whenKubegresIsCreated()
+
+			test.thenPodsStatesShouldBe(1, 1)
+
+			test.whenPrimaryStatefulSetIsDeleted()
+
+			test.thenConditionStatusShouldBe(postgresv1.ConditionTypeFailoverBlocked, metav1.ConditionTrue)
+
+			test.thenPodsStatesShouldBe(0, 1)
+
+			log.Print("END OF: Test 'GIVEN new Kubegres is created with spec 'failover.neverPromoteLabelSelector' matching all the Nodes AND with 2 instances'")
+		})
+	})
+})
+
+type SpecFailoverPromotionPreferenceTest struct {
+	kubegresResource  *postgresv1.Kubegres
+	labelledNodeName  string
+	resourceCreator   util.TestResourceCreator
+	resourceRetriever util.TestResourceRetriever
+}
+
+func (r *SpecFailoverPromotionPreferenceTest) givenNewKubegresSpecIsSetTo(specNbreReplicas int32) {
+	r.kubegresResource = resourceConfigs.LoadKubegresYaml()
+	r.kubegresResource.Spec.Replicas = &specNbreReplicas
+}
+
+func (r *SpecFailoverPromotionPreferenceTest) givenKubegresSpecHasPreferredZone(zone string) {
+	r.kubegresResource.Spec.Failover.PreferredZones = []string{zone}
+}
+
+func (r *SpecFailoverPromotionPreferenceTest) givenKubegresSpecHasNeverPromoteLabelSelector(matchLabels map[string]string) {
+	r.kubegresResource.Spec.Failover.NeverPromoteLabelSelector = &metav1.LabelSelector{MatchLabels: matchLabels}
+}
+
+// givenNodeOfLastReplicaIsInZone sets the zone label on the Node of the Replica with the highest StatefulSet name,
+// which is the least preferred Replica by default. The test is skipped if both Replicas run on the same Node.
+func (r *SpecFailoverPromotionPreferenceTest) givenNodeOfLastReplicaIsInZone(zone string) util.TestKubegresResource {
+
+	kubegresResources, err := r.resourceRetriever.GetKubegresResources()
+	Expect(err).Should(Succeed())
+
+	var replicas []util.TestKubegresResource
+	for _, kubegresResource := range kubegresResources.Resources {
+		if !kubegresResource.IsPrimary {
+			replicas = append(replicas, kubegresResource)
+		}
+	}
+	Expect(len(replicas)).Should(Equal(2))
+
+	lastReplica, otherReplica := replicas[0], replicas[1]
+	if otherReplica.StatefulSet.Name > lastReplica.StatefulSet.Name {
+		lastReplica, otherReplica = otherReplica, lastReplica
+	}
+
+	if lastReplica.Pod.Spec.NodeName == otherReplica.Pod.Spec.NodeName {
+		Skip("Both Replicas run on the same Node '" + lastReplica.Pod.Spec.NodeName + "'")
+	}
+
+	node := &core.Node{}
+	Expect(k8sClientTest.Get(context.Background(), types.NamespacedName{Name: lastReplica.Pod.Spec.NodeName}, node)).Should(Succeed())
+
+	node.Labels[core.LabelTopologyZone] = zone
+	Expect(k8sClientTest.Update(context.Background(), node)).Should(Succeed())
+	r.labelledNodeName = node.Name
+
+	log.Println("Set the zone '" + zone + "' on the Node '" + node.Name + "' of the Replica '" + lastReplica.Pod.Name + "'")
+	return lastReplica
+}
+
+func (r *SpecFailoverPromotionPreferenceTest) removeZoneLabelFromNode() {
+	if r.labelledNodeName == "" {
+		return
+	}
+
+	node := &core.Node{}
+	if err := k8sClientTest.Get(context.Background(), types.NamespacedName{Name: r.labelledNodeName}, node); err != nil {
+		log.Println("Unable to retrieve the Node '"+r.labelledNodeName+"' to remove its zone label: ", err)
+		return
+	}
+
+	delete(node.Labels, core.LabelTopologyZone)
+	if err := k8sClientTest.Update(context.Background(), node); err != nil {
+		log.Println("Unable to remove the zone label from the Node '"+r.labelledNodeName+"': ", err)
+	}
+}
+
+func (r *SpecFailoverPromotionPreferenceTest) whenKubegresIsCreated() {
+	r.resourceCreator.CreateKubegres(r.kubegresResource)
+}
+
+func (r *SpecFailoverPromotionPreferenceTest) whenPrimaryStatefulSetIsDeleted() {
+	kubegresResources, err := r.resourceRetriever.GetKubegresResources()
+	if err != nil {
+		Expect(err).Should(Succeed())
+		return
+	}
+
+	nbreDeleted := 0
+	for _, kubegresResource := range kubegresResources.Resources {
+		if kubegresResource.IsPrimary {
+			log.Println("Attempting to delete StatefulSet: '" + kubegresResource.StatefulSet.Name + "'")
+			if r.resourceCreator.DeleteResource(kubegresResource.StatefulSet.Resource, kubegresResource.StatefulSet.Name) {
+				nbreDeleted++
+				time.Sleep(5 * time.Second)
+			}
+		}
+	}
+
+	Expect(nbreDeleted).Should(Equal(1))
+}
+
+func (r *SpecFailoverPromotionPreferenceTest) thenPodsStatesShouldBe(nbrePrimary, nbreReplicas int) bool {
+	return Eventually(func() bool {
+
+		kubegresResources, err := r.resourceRetriever.GetKubegresResources()
+		if err != nil && !apierrors.IsNotFound(err) {
+			log.Println("ERROR while retrieving Kubegres kubegresResources")
+			return false
+		}
+
+		if kubegresResources.AreAllReady &&
+			kubegresResources.NbreDeployedPrimary == nbrePrimary &&
+			kubegresResources.NbreDeployedReplicas == nbreReplicas {
+
+			time.Sleep(resourceConfigs.TestRetryInterval)
+			log.Println("Deployed and Ready StatefulSets check successful")
+			return true
+		}
+
+		return false
+
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
+
+func (r *SpecFailoverPromotionPreferenceTest) thenPrimaryPodShouldBe(expectedPrimaryPodName string) {
+	kubegresResources, err := r.resourceRetriever.GetKubegresResources()
+	Expect(err).Should(Succeed())
+
+	for _, kubegresResource := range kubegresResources.Resources {
+		if kubegresResource.IsPrimary {
+			Expect(kubegresResource.Pod.Name).Should(Equal(expectedPrimaryPodName))
+			return
+		}
+	}
+
+	Fail("The Primary is not deployed")
+}
+
+func (r *SpecFailoverPromotionPreferenceTest) thenConditionStatusShouldBe(conditionType string, expectedStatus metav1.ConditionStatus) {
+	Eventually(func() bool {
+
+		kubegres, err := r.resourceRetriever.GetKubegres()
+		if err != nil {
+			log.Println("ERROR while retrieving Kubegres resource: ", err)
+			return false
+		}
+
+		condition := meta.FindStatusCondition(kubegres.Status.Conditions, conditionType)
+		if condition == nil || condition.Status != expectedStatus {
+			log.Println("Waiting for the condition '" + conditionType + "' to have the status '" + string(expectedStatus) + "'")
+			return false
+		}
+
+		return true
+
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
@@ -0,0 +1,270 @@
+/*
+Copyright 2021 Reactive Tech Limited.
+"Reactive Tech Limited" is a company located in England, United Kingdom.
+https://www.reactive-tech.io
+
+Lead Developer: Alex Arica
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"log"
+	"strconv"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	postgresv1 "reactive-tech.io/kubegres/api/v1"
+	"reactive-tech.io/kubegres/test/resourceConfigs"
+	"reactive-tech.io/kubegres/test/util"
+)
+
+var _ = Describe("Setting Kubegres spec 'failover.pvc'", Label("group:3"), func() {
+
+	var test = SpecFailoverPvcTest{}
+
+	BeforeEach(func() {
+		//Skip("Temporarily skipping test")
+
+		namespace := resourceConfigs.DefaultNamespace
+		test.resourceRetriever = util.CreateTestResourceRetriever(k8sClientTest, namespace)
+		test.resourceCreator = util.CreateTestResourceCreator(k8sClientTest, test.resourceRetriever, namespace)
+		test.connectionPrimaryDb = util.InitDbConnectionDbUtil(test.resourceCreator, resourceConfigs.KubegresResourceName, resourceConfigs.ServiceToSqlQueryPrimaryDbNodePort, true)
+		test.connectionReplicaDb = util.InitDbConnectionDbUtil(test.resourceCreator, resourceConfigs.KubegresResourceName, resourceConfigs.ServiceToSqlQueryReplicaDbNodePort, false)
+	})
+
+	AfterEach(func() {
+		test.resourceCreator.DeleteAllTestResources()
+	})
+
+	Context("GIVEN new Kubegres is created with spec 'failover.pvc' set to 'delete' AND with 2 instances AND the Primary fails", func() {
+
+		It("THEN a failover should happen AND the PVC of the failed Primary should be deleted", func() {
+
+			log.Print("START OF: Test 'GIVEN new Kubegres is created with spec 'failover.pvc' set to 'delete' AND with 2 instances AND the Primary fails'")
+
+			test.givenNewKubegresSpecIsSetTo(2, postgresv1.FailoverPvcPolicyDelete)
+
+			test.whenKubegresIsCreated()
+
+			test.thenPodsStatesShouldBe(1, 1)
+
+			failedPrimaryPvcName := test.getPrimaryPvcName()
+
+			test.whenPrimaryPodIsDeleted()
+
+			test.thenPodsStatesShouldBe(1, 1)
+
+			test.thenPvcShouldNotExist(failedPrimaryPvcName)
+
+			log.Print("END OF: Test 'GIVEN new Kubegres is created with spec 'failover.pvc' set to 'delete' AND with 2 instances AND the Primary fails'")
+		})
+	})
+
+	Context("GIVEN new Kubegres is created with spec 'failover.pvc' set to 'reuse' AND with 2 instances AND the Primary fails", func() {
+
+		It("THEN a failover should happen AND the failed Primary should rejoin as a Replica with its PVC AND no data should be lost", func() {
+
+			log.Print("START OF: Test 'GIVEN new Kubegres is created with spec 'failover.pvc' set to 'reuse' AND with 2 instances AND the Primary fails'")
+
+			test.givenNewKubegresSpecIsSetTo(2, postgresv1.FailoverPvcPolicyReuse)
+
+			test.whenKubegresIsCreated()
+
+			test.thenPodsStatesShouldBe(1, 1)
+
+			expectedNbreUsers := 0
+
+			test.givenUserAddedInPrimaryDb()
+			expectedNbreUsers++
+
+			primaryPodName, replicaPodName := test.getDeployedPodNames()
+
+			test.whenPrimaryPodIsDeleted()
+
+			test.thenPodsStatesShouldBe(1, 1)
+
+			test.thenDeployedPodNamesShouldBe(replicaPodName, primaryPodName)
+
+			test.thenPrimaryDbContainsExpectedNbreUsers(expectedNbreUsers)
+			test.thenReplicaDbContainsExpectedNbreUsers(expectedNbreUsers)
+
+			log.Print("END OF: Test 'GIVEN new Kubegres is created with spec 'failover.pvc' set to 'reuse' AND with 2 instances AND the Primary fails'")
+		})
+	})
+})
+
+type SpecFailoverPvcTest struct {
+	kubegresResource    *postgresv1.Kubegres
+	connectionPrimaryDb util.DbConnectionDbUtil
+	connectionReplicaDb util.DbConnectionDbUtil
+	resourceCreator     util.TestResourceCreator
+	resourceRetriever   util.TestResourceRetriever
+}
+
+func (r *SpecFailoverPvcTest) givenNewKubegresSpecIsSetTo(specNbreReplicas int32, pvcPolicy string) {
+	r.kubegresResource = resourceConfigs.LoadKubegresYaml()
+	r.kubegresResource.Spec.Replicas = &specNbreReplicas
+	r.kubegresResource.Spec.Failover.Pvc = pvcPolicy
+}
+
+func (r *SpecFailoverPvcTest) givenUserAddedInPrimaryDb() {
+	Eventually(func() bool {
+		return r.connectionPrimaryDb.InsertUser()
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
+
+func (r *SpecFailoverPvcTest) whenKubegresIsCreated() {
+	r.resourceCreator.CreateKubegres(r.kubegresResource)
+}
+
+// whenPrimaryPodIsDeleted deletes the Pod of the Primary and its StatefulSet so that a failover happens,
+// while keeping its PVC.
+func (r *SpecFailoverPvcTest) whenPrimaryPodIsDeleted() {
+	kubegresResources, err := r.resourceRetriever.GetKubegresResources()
+	if err != nil {
+		Expect(err).Should(Succeed())
+		return
+	}
+
+	nbreDeleted := 0
+	for _, kubegresResource := range kubegresResources.Resources {
+		if kubegresResource.IsPrimary {
+			log.Println("Attempting to delete StatefulSet: '" + kubegresResource.StatefulSet.Name + "'")
+			if r.resourceCreator.DeleteResource(kubegresResource.StatefulSet.Resource, kubegresResource.StatefulSet.Name) {
+				nbreDeleted++
+				time.Sleep(5 * time.Second)
+			}
+		}
+	}
+
+	Expect(nbreDeleted).Should(Equal(1))
+}
+
+func (r *SpecFailoverPvcTest) getPrimaryPvcName() string {
+	kubegresResources, err := r.resourceRetriever.GetKubegresResources()
+	Expect(err).Should(Succeed())
+
+	for _, kubegresResource := range kubegresResources.Resources {
+		if kubegresResource.IsPrimary {
+			return "postgres-db-" + kubegresResource.StatefulSet.Name + "-0"
+		}
+	}
+
+	Fail("The Primary is not deployed")
+	return ""
+}
+
+func (r *SpecFailoverPvcTest) getDeployedPodNames() (primaryPodName, replicaPodName string) {
+
+	kubegresResources, err := r.resourceRetriever.GetKubegresResources()
+	if err != nil {
+		Expect(err).Should(Succeed())
+		return
+	}
+
+	for _, kubegresResource := range kubegresResources.Resources {
+		if kubegresResource.IsPrimary {
+			primaryPodName = kubegresResource.Pod.Name
+		} else {
+			replicaPodName = kubegresResource.Pod.Name
+		}
+	}
+
+	return primaryPodName, replicaPodName
+}
+
+func (r *SpecFailoverPvcTest) thenPodsStatesShouldBe(nbrePrimary, nbreReplicas int) bool {
+	return Eventually(func() bool {
+
+		kubegresResources, err := r.resourceRetriever.GetKubegresResources()
+		if err != nil && !apierrors.IsNotFound(err) {
+			log.Println("ERROR while retrieving Kubegres kubegresResources")
+			return false
+		}
+
+		if kubegresResources.AreAllReady &&
+			kubegresResources.NbreDeployedPrimary == nbrePrimary &&
+			kubegresResources.NbreDeployedReplicas == nbreReplicas {
+
+			time.Sleep(resourceConfigs.TestRetryInterval)
+			log.Println("Deployed and Ready StatefulSets check successful")
+			return true
+		}
+
+		return false
+
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
+
+func (r *SpecFailoverPvcTest) thenPvcShouldNotExist(pvcName string) {
+	Eventually(func() bool {
+
+		pvcs, err := r.resourceRetriever.GetKubegresPvc()
+		if err != nil {
+			return false
+		}
+
+		for _, pvc := range pvcs.Items {
+			if pvc.Name == pvcName {
+				log.Println("Waiting for the PVC '" + pvcName + "' to be deleted")
+				return false
+			}
+		}
+
+		return true
+
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
+
+func (r *SpecFailoverPvcTest) thenDeployedPodNamesShouldBe(expectedPrimaryPodName, expectedReplicaPodName string) {
+	primaryPodName, replicaPodName := r.getDeployedPodNames()
+	Expect(primaryPodName).Should(Equal(expectedPrimaryPodName))
+	Expect(replicaPodName).Should(Equal(expectedReplicaPodName))
+}
+
+func (r *SpecFailoverPvcTest) thenPrimaryDbContainsExpectedNbreUsers(expectedNbreUsers int) {
+	Eventually(func() bool {
+
+		users := r.connectionPrimaryDb.GetUsers()
+		r.connectionPrimaryDb.Close()
+
+		if len(users) != expectedNbreUsers {
+			log.Println("Primary DB does not contain the expected number of users. Expected: " + strconv.Itoa(expectedNbreUsers) + " Given: " + strconv.Itoa(len(users)))
+			return false
+		}
+
+		return true
+
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
+
+func (r *SpecFailoverPvcTest) thenReplicaDbContainsExpectedNbreUsers(expectedNbreUsers int) {
+	Eventually(func() bool {
+
+		users := r.connectionReplicaDb.GetUsers()
+		r.connectionReplicaDb.Close()
+
+		if len(users) != expectedNbreUsers {
+			log.Println("Replica DB does not contain the expected number of users: " + strconv.Itoa(expectedNbreUsers))
+			return false
+		}
+
+		return true
+
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
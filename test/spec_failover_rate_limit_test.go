@@ -0,0 +1,232 @@
+/*
+Copyright 2021 Reactive Tech Limited.
+"Reactive Tech Limited" is a company located in England, United Kingdom.
+https://www.reactive-tech.io
+
+Lead Developer: Alex Arica
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"log"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	postgresv1 "reactive-tech.io/kubegres/api/v1"
+	"reactive-tech.io/kubegres/controllers/ctx"
+	"reactive-tech.io/kubegres/test/resourceConfigs"
+	"reactive-tech.io/kubegres/test/util"
+)
+
+var _ = Describe("Setting Kubegres spec 'failover.minimumIntervalSeconds'", Label("group:3"), func() {
+
+	var test = SpecFailoverRateLimitTest{}
+
+	BeforeEach(func() {
+		//Skip("Temporarily skipping test")
+
+		namespace := resourceConfigs.DefaultNamespace
+		test.resourceRetriever = util.CreateTestResourceRetriever(k8sClientTest, namespace)
+		test.resourceCreator = util.CreateTestResourceCreator(k8sClientTest, test.resourceRetriever, namespace)
+	})
+
+	AfterEach(func() {
+		test.resourceCreator.DeleteAllTestResources()
+	})
+
+	Context("GIVEN new Kubegres is created with spec 'failover.minimumIntervalSeconds' set to 3600 AND with 3 instances AND the Primary fails twice", func() {
+
+		It("THEN the second failover should be suspended until it is acknowledged with an annotation", func() {
+
+			log.Print("START OF: Test 'GIVEN new Kubegres is created with spec 'failover.minimumIntervalSeconds' set to 3600 AND with 3 instances AND the Primary fails twice'")
+
+			test.givenNewKubegresSpecIsSetTo(3, 3600)
+
+			test.whenKubegresIsCreated()
+
+			test.thenPodsStatesShouldBe(1, 2)
+
+			test.whenPrimaryStatefulSetIsDeleted()
+
+			test.thenPodsStatesShouldBe(1, 2)
+
+			test.thenConditionStatusShouldBe(postgresv1.ConditionTypeFailoverSuspended, metav1.ConditionFalse)
+
+			test.thenStatusAutomaticFailoversShouldBe(1)
+
+			test.whenPrimaryStatefulSetIsDeleted()
+
+			test.thenConditionStatusShouldBe(postgresv1.ConditionTypeFailoverSuspended, metav1.ConditionTrue)
+
+			test.thenStatusFailoverSuspensionReasonShouldBe(postgresv1.FailoverSuspensionReasonMinimumIntervalNotElapsed)
+
+			test.thenPodsStatesShouldBe(0, 2)
+
+			test.whenFailoverSuspensionIsAcknowledged()
+
+			test.thenPodsStatesShouldBe(1, 2)
+
+			test.thenConditionStatusShouldBe(postgresv1.ConditionTypeFailoverSuspended, metav1.ConditionFalse)
+
+			test.thenAcknowledgementAnnotationShouldBeRemoved()
+
+			test.thenStatusAutomaticFailoversShouldBe(1)
+
+			log.Print("END OF: Test 'GIVEN new Kubegres is created with spec 'failover.minimumIntervalSeconds' set to 3600 AND with 3 instances AND the Primary fails twice'")
+		})
+	})
+})
+
+type SpecFailoverRateLimitTest struct {
+	kubegresResource  *postgresv1.Kubegres
+	resourceCreator   util.TestResourceCreator
+	resourceRetriever util.TestResourceRetriever
+}
+
+func (r *SpecFailoverRateLimitTest) givenNewKubegresSpecIsSetTo(specNbreReplicas int32, minimumIntervalSeconds int64) {
+	r.kubegresResource = resourceConfigs.LoadKubegresYaml()
+	r.kubegresResource.Spec.Replicas = &specNbreReplicas
+	r.kubegresResource.Spec.Failover.MinimumIntervalSeconds = &minimumIntervalSeconds
+}
+
+func (r *SpecFailoverRateLimitTest) whenKubegresIsCreated() {
+	r.resourceCreator.CreateKubegres(r.kubegresResource)
+}
+
+func (r *SpecFailoverRateLimitTest) whenFailoverSuspensionIsAcknowledged() {
+	var err error
+	r.kubegresResource, err = r.resourceRetriever.GetKubegres()
+	Expect(err).Should(Succeed())
+
+	if r.kubegresResource.Annotations == nil {
+		r.kubegresResource.Annotations = map[string]string{}
+	}
+	r.kubegresResource.Annotations[ctx.FailoverSuspensionAckAnnotationKey] = "true"
+	r.resourceCreator.UpdateResource(r.kubegresResource, "Kubegres")
+}
+
+func (r *SpecFailoverRateLimitTest) whenPrimaryStatefulSetIsDeleted() {
+	kubegresResources, err := r.resourceRetriever.GetKubegresResources()
+	if err != nil {
+		Expect(err).Should(Succeed())
+		return
+	}
+
+	nbreDeleted := 0
+	for _, kubegresResource := range kubegresResources.Resources {
+		if kubegresResource.IsPrimary {
+			log.Println("Attempting to delete StatefulSet: '" + kubegresResource.StatefulSet.Name + "'")
+			if r.resourceCreator.DeleteResource(kubegresResource.StatefulSet.Resource, kubegresResource.StatefulSet.Name) {
+				nbreDeleted++
+				time.Sleep(5 * time.Second)
+			}
+		}
+	}
+
+	Expect(nbreDeleted).Should(Equal(1))
+}
+
+func (r *SpecFailoverRateLimitTest) thenPodsStatesShouldBe(nbrePrimary, nbreReplicas int) bool {
+	return Eventually(func() bool {
+
+		kubegresResources, err := r.resourceRetriever.GetKubegresResources()
+		if err != nil && !apierrors.IsNotFound(err) {
+			log.Println("ERROR while retrieving Kubegres kubegresResources")
+			return false
+		}
+
+		if kubegresResources.AreAllReady &&
+			kubegresResources.NbreDeployedPrimary == nbrePrimary &&
+			kubegresResources.NbreDeployedReplicas == nbreReplicas {
+
+			time.Sleep(resourceConfigs.TestRetryInterval)
+			log.Println("Deployed and Ready StatefulSets check successful")
+			return true
+		}
+
+		return false
+
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
+
+func (r *SpecFailoverRateLimitTest) thenConditionStatusShouldBe(conditionType string, expectedStatus metav1.ConditionStatus) {
+	Eventually(func() bool {
+
+		kubegres, err := r.resourceRetriever.GetKubegres()
+		if err != nil {
+			log.Println("ERROR while retrieving Kubegres resource: ", err)
+			return false
+		}
+
+		condition := meta.FindStatusCondition(kubegres.Status.Conditions, conditionType)
+		if condition == nil || condition.Status != expectedStatus {
+			log.Println("Waiting for the condition '" + conditionType + "' to have the status '" + string(expectedStatus) + "'")
+			return false
+		}
+
+		return true
+
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
+
+func (r *SpecFailoverRateLimitTest) thenStatusFailoverSuspensionReasonShouldBe(expectedReason string) {
+	kubegres, err := r.resourceRetriever.GetKubegres()
+	Expect(err).Should(Succeed())
+
+	Expect(kubegres.Status.FailoverSuspension.IsSuspended).Should(BeTrue())
+	Expect(kubegres.Status.FailoverSuspension.Reason).Should(Equal(expectedReason))
+}
+
+// thenStatusAutomaticFailoversShouldBe checks the number of ended automatic failovers counted by the rate limit.
+// The failovers which started before the last acknowledgement are not counted.
+func (r *SpecFailoverRateLimitTest) thenStatusAutomaticFailoversShouldBe(expectedNbreFailovers int) {
+	Eventually(func() bool {
+
+		kubegres, err := r.resourceRetriever.GetKubegres()
+		if err != nil {
+			log.Println("ERROR while retrieving Kubegres resource: ", err)
+			return false
+		}
+
+		automaticFailovers := kubegres.Status.FailoverSuspension.AutomaticFailovers
+		if len(automaticFailovers) != expectedNbreFailovers || automaticFailovers[len(automaticFailovers)-1].EndTime == nil {
+			log.Println("Waiting for the number of ended automatic failovers in status to be: ", expectedNbreFailovers)
+			return false
+		}
+
+		return true
+
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
+
+func (r *SpecFailoverRateLimitTest) thenAcknowledgementAnnotationShouldBeRemoved() {
+	Eventually(func() bool {
+
+		kubegres, err := r.resourceRetriever.GetKubegres()
+		if err != nil {
+			log.Println("ERROR while retrieving Kubegres resource: ", err)
+			return false
+		}
+
+		_, isAnnotationSet := kubegres.Annotations[ctx.FailoverSuspensionAckAnnotationKey]
+		return !isAnnotationSet
+
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
@@ -0,0 +1,227 @@
+/*
+Copyright 2021 Reactive Tech Limited.
+"Reactive Tech Limited" is a company located in England, United Kingdom.
+https://www.reactive-tech.io
+
+Lead Developer: Alex Arica
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"log"
+	"strconv"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	postgresv1 "reactive-tech.io/kubegres/api/v1"
+	"reactive-tech.io/kubegres/controllers/operation"
+	"reactive-tech.io/kubegres/test/resourceConfigs"
+	"reactive-tech.io/kubegres/test/util"
+)
+
+var _ = Describe("Setting Kubegres spec 'failover' timings", Label("group:3"), func() {
+
+	var test = SpecFailoverTimingsTest{}
+
+	BeforeEach(func() {
+		//Skip("Temporarily skipping test")
+
+		namespace := resourceConfigs.DefaultNamespace
+		test.resourceRetriever = util.CreateTestResourceRetriever(k8sClientTest, namespace)
+		test.resourceCreator = util.CreateTestResourceCreator(k8sClientTest, test.resourceRetriever, namespace)
+		test.connectionPrimaryDb = util.InitDbConnectionDbUtil(test.resourceCreator, resourceConfigs.KubegresResourceName, resourceConfigs.ServiceToSqlQueryPrimaryDbNodePort, true)
+		test.connectionReplicaDb = util.InitDbConnectionDbUtil(test.resourceCreator, resourceConfigs.KubegresResourceName, resourceConfigs.ServiceToSqlQueryReplicaDbNodePort, false)
+	})
+
+	AfterEach(func() {
+		test.resourceCreator.DeleteAllTestResources()
+	})
+
+	Context("GIVEN new Kubegres is created with spec 'failover.stabilizationSeconds' greater than 'failover.promotionTimeoutSeconds'", func() {
+
+		It("THEN the creation of Kubegres should be rejected", func() {
+
+			log.Print("START OF: Test 'GIVEN new Kubegres is created with spec 'failover.stabilizationSeconds' greater than 'failover.promotionTimeoutSeconds''")
+
+			test.givenNewKubegresSpecIsSetTo(3, 5, 120, 60)
+
+			test.whenKubegresIsCreatedWithInvalidSpec()
+
+			test.thenKubegresShouldBeRejected()
+
+			log.Print("END OF: Test 'GIVEN new Kubegres is created with spec 'failover.stabilizationSeconds' greater than 'failover.promotionTimeoutSeconds''")
+		})
+	})
+
+	Context("GIVEN Kubegres with 1 primary and 2 replicas and short failover timings AND primary is deleted", func() {
+
+		It("THEN the failover should use the timings in spec AND existing data should be available", func() {
+
+			log.Print("START OF: Test 'GIVEN Kubegres with 1 primary and 2 replicas and short failover timings AND primary is deleted'")
+
+			test.givenNewKubegresSpecIsSetTo(3, 2, 10, 120)
+
+			test.whenKubegresIsCreated()
+
+			test.thenPodsStatesShouldBe(1, 2)
+
+			expectedNbreUsers := 0
+
+			test.givenUserAddedInPrimaryDb()
+			expectedNbreUsers++
+
+			test.whenPrimaryIsDeleted()
+
+			test.thenFailingOverShouldTimeOutAfter(120)
+
+			test.thenPodsStatesShouldBe(1, 2)
+
+			test.thenReplicaDbContainsExpectedNbreUsers(expectedNbreUsers)
+
+			log.Print("END OF: Test 'GIVEN Kubegres with 1 primary and 2 replicas and short failover timings AND primary is deleted'")
+		})
+	})
+
+})
+
+type SpecFailoverTimingsTest struct {
+	kubegresResource    *postgresv1.Kubegres
+	connectionPrimaryDb util.DbConnectionDbUtil
+	connectionReplicaDb util.DbConnectionDbUtil
+	resourceCreator     util.TestResourceCreator
+	resourceRetriever   util.TestResourceRetriever
+	admissionErr        error
+}
+
+func (r *SpecFailoverTimingsTest) givenNewKubegresSpecIsSetTo(specNbreReplicas int32, detectionDelaySeconds,
+	stabilizationSeconds, promotionTimeoutSeconds int64) {
+
+	r.kubegresResource = resourceConfigs.LoadKubegresYaml()
+	r.kubegresResource.Spec.Replicas = &specNbreReplicas
+	r.kubegresResource.Spec.Failover.DetectionDelaySeconds = &detectionDelaySeconds
+	r.kubegresResource.Spec.Failover.StabilizationSeconds = &stabilizationSeconds
+	r.kubegresResource.Spec.Failover.PromotionTimeoutSeconds = &promotionTimeoutSeconds
+}
+
+func (r *SpecFailoverTimingsTest) givenUserAddedInPrimaryDb() {
+	Eventually(func() bool {
+		return r.connectionPrimaryDb.InsertUser()
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
+
+func (r *SpecFailoverTimingsTest) whenKubegresIsCreated() {
+	r.resourceCreator.CreateKubegres(r.kubegresResource)
+}
+
+func (r *SpecFailoverTimingsTest) whenKubegresIsCreatedWithInvalidSpec() {
+	r.admissionErr = r.resourceCreator.CreateKubegresWithInvalidSpec(r.kubegresResource)
+}
+
+func (r *SpecFailoverTimingsTest) whenPrimaryIsDeleted() {
+	kubegresResources, err := r.resourceRetriever.GetKubegresResources()
+	if err != nil {
+		Expect(err).Should(Succeed())
+		return
+	}
+
+	nbreDeleted := 0
+	for _, kubegresResource := range kubegresResources.Resources {
+		if kubegresResource.IsPrimary {
+			log.Println("Attempting to delete StatefulSet: '" + kubegresResource.StatefulSet.Name + "'")
+			if !r.resourceCreator.DeleteResource(kubegresResource.StatefulSet.Resource, kubegresResource.StatefulSet.Name) {
+				log.Println("StatefulSet CANNOT BE deleted: '" + kubegresResource.StatefulSet.Name + "'")
+			} else {
+				nbreDeleted++
+			}
+		}
+	}
+
+	Expect(nbreDeleted).Should(Equal(1))
+}
+
+func (r *SpecFailoverTimingsTest) thenKubegresShouldBeRejected() {
+	Expect(r.admissionErr).Should(MatchError(ContainSubstring("In the Resources Spec the value of 'spec.failover.stabilizationSeconds' must be lower than the value of " +
+		"'spec.failover.promotionTimeoutSeconds'. Otherwise, a failover would always time-out.")))
+}
+
+func (r *SpecFailoverTimingsTest) thenFailingOverShouldTimeOutAfter(promotionTimeoutSeconds int64) {
+	Eventually(func() bool {
+
+		kubegres, err := r.resourceRetriever.GetKubegres()
+		if err != nil {
+			log.Println("ERROR while retrieving Kubegres resource: ", err)
+			return false
+		}
+
+		blockingOperation := kubegres.Status.BlockingOperation
+		if blockingOperation.StepId != operation.OperationStepIdPrimaryDbFailingOver {
+			log.Println("Waiting for the failover to promote a Replica")
+			return false
+		}
+
+		nbreSecondsBeforeTimeOut := blockingOperation.TimeOutEpocInSeconds - time.Now().Unix()
+		if nbreSecondsBeforeTimeOut > promotionTimeoutSeconds {
+			log.Println("The failover times-out in " + strconv.FormatInt(nbreSecondsBeforeTimeOut, 10) + " seconds " +
+				"instead of " + strconv.FormatInt(promotionTimeoutSeconds, 10) + " seconds")
+			return false
+		}
+
+		return true
+
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
+
+func (r *SpecFailoverTimingsTest) thenPodsStatesShouldBe(nbrePrimary, nbreReplicas int) bool {
+	return Eventually(func() bool {
+
+		kubegresResources, err := r.resourceRetriever.GetKubegresResources()
+		if err != nil && !apierrors.IsNotFound(err) {
+			log.Println("ERROR while retrieving Kubegres kubegresResources")
+			return false
+		}
+
+		if kubegresResources.AreAllReady &&
+			kubegresResources.NbreDeployedPrimary == nbrePrimary &&
+			kubegresResources.NbreDeployedReplicas == nbreReplicas {
+
+			time.Sleep(resourceConfigs.TestRetryInterval)
+			log.Println("Deployed and Ready StatefulSets check successful")
+			return true
+		}
+
+		return false
+
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
+
+func (r *SpecFailoverTimingsTest) thenReplicaDbContainsExpectedNbreUsers(expectedNbreUsers int) {
+	Eventually(func() bool {
+
+		users := r.connectionReplicaDb.GetUsers()
+		r.connectionReplicaDb.Close()
+
+		if len(users) != expectedNbreUsers ||
+			r.connectionReplicaDb.NbreInsertedUsers != expectedNbreUsers {
+			log.Println("Replica DB does not contain the expected number of users: " + strconv.Itoa(expectedNbreUsers))
+			return false
+		}
+
+		return true
+
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
@@ -26,7 +26,6 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
-	v12 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	postgresv1 "reactive-tech.io/kubegres/api/v1"
 	"reactive-tech.io/kubegres/test/resourceConfigs"
@@ -57,15 +56,15 @@ var _ = Describe("Setting Kubegres spec 'image'", Label("group:4"), func() {
 
 	Context("GIVEN new Kubegres is created without spec 'image'", func() {
 
-		It("THEN An error event should be logged", func() {
+		It("THEN the creation of Kubegres should be rejected", func() {
 
 			log.Print("START OF: Test 'GIVEN new Kubegres is created without spec 'image''")
 
 			test.givenNewKubegresSpecIsSetTo("", 3)
 
-			test.whenKubegresIsCreated()
+			test.whenKubegresIsCreatedWithInvalidSpec()
 
-			test.thenErrorEventShouldBeLogged()
+			test.thenKubegresShouldBeRejected()
 
 			log.Print("END OF: Test 'GIVEN new Kubegres is created without spec 'image''")
 		})
@@ -121,6 +120,7 @@ type SpecImageTest struct {
 	dbQueryTestCases                testcases.DbQueryTestCases
 	resourceCreator                 util.TestResourceCreator
 	resourceRetriever               util.TestResourceRetriever
+	admissionErr                    error
 }
 
 func (r *SpecImageTest) givenNewKubegresSpecIsSetTo(image string, specNbreReplicas int32) {
@@ -146,24 +146,17 @@ func (r *SpecImageTest) whenKubegresIsCreated() {
 	r.resourceCreator.CreateKubegres(r.kubegresResource)
 }
 
+func (r *SpecImageTest) whenKubegresIsCreatedWithInvalidSpec() {
+	r.admissionErr = r.resourceCreator.CreateKubegresWithInvalidSpec(r.kubegresResource)
+}
+
 func (r *SpecImageTest) whenKubernetesIsUpdated() {
 	r.resourceCreator.UpdateResource(r.kubegresResource, "Kubegres")
 }
 
-func (r *SpecImageTest) thenErrorEventShouldBeLogged() {
-	expectedErrorEvent := util.EventRecord{
-		Eventtype: v12.EventTypeWarning,
-		Reason:    "SpecCheckErr",
-		Message:   "In the Resources Spec the value of 'spec.image' is undefined. Please set a value otherwise this operator cannot work correctly.",
-	}
-	Eventually(func() bool {
-		_, err := r.resourceRetriever.GetKubegres()
-		if err != nil {
-			return false
-		}
-		return eventRecorderTest.CheckEventExist(expectedErrorEvent)
-
-	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+func (r *SpecImageTest) thenKubegresShouldBeRejected() {
+	Expect(r.admissionErr).Should(MatchError(ContainSubstring("In the Resources Spec the value of 'spec.image' is undefined. " +
+		"Please set a value otherwise this operator cannot work correctly.")))
 }
 
 func (r *SpecImageTest) thenPodsStatesShouldBe(image string, nbrePrimary, nbreReplicas int) bool {
@@ -0,0 +1,175 @@
+/*
+Copyright 2021 Reactive Tech Limited.
+"Reactive Tech Limited" is a company located in England, United Kingdom.
+https://www.reactive-tech.io
+
+Lead Developer: Alex Arica
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"log"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v12 "k8s.io/api/core/v1"
+	postgresv1 "reactive-tech.io/kubegres/api/v1"
+	"reactive-tech.io/kubegres/controllers/ctx"
+	"reactive-tech.io/kubegres/test/resourceConfigs"
+	"reactive-tech.io/kubegres/test/util"
+	"reactive-tech.io/kubegres/test/util/testcases"
+)
+
+var _ = Describe("Rotating the passwords of an existing Kubegres", Label("group:2"), func() {
+
+	var test = PasswordsRotationTest{}
+
+	BeforeEach(func() {
+		//Skip("Temporarily skipping test")
+
+		namespace := resourceConfigs.DefaultNamespace
+		test.resourceRetriever = util.CreateTestResourceRetriever(k8sClientTest, namespace)
+		test.resourceCreator = util.CreateTestResourceCreator(k8sClientTest, test.resourceRetriever, namespace)
+		test.dbQueryTestCases = testcases.InitDbQueryTestCases(test.resourceCreator, resourceConfigs.KubegresResourceName)
+	})
+
+	AfterEach(func() {
+		test.resourceCreator.DeleteAllTestResources()
+	})
+
+	Context("GIVEN Kubegres with generated passwords AND spec 'replica' set to 3 AND the replication password is changed in the generated Secret", func() {
+
+		It("THEN the new replication password should be applied AND the Pods restarted AND the data still replicated", func() {
+
+			log.Print("START OF: Test 'GIVEN Kubegres with generated passwords AND the replication password is changed'")
+
+			test.givenNewKubegresWithGeneratedReplicationPassword(3)
+
+			test.whenKubegresIsCreated()
+
+			test.thenPodsStatesShouldBe(1, 2, "")
+
+			test.dbQueryTestCases.ThenWeCanSqlQueryPrimaryDb()
+			test.dbQueryTestCases.ThenWeCanSqlQueryReplicaDb()
+
+			test.whenReplicationPasswordIsChangedInGeneratedSecret("newReplicationUserPsw")
+
+			test.thenAppliedReplicationPasswordShouldBe("newReplicationUserPsw")
+
+			passwordsVersion := test.thenStatusPasswordsVersionShouldBeSet()
+
+			test.thenPodsStatesShouldBe(1, 2, passwordsVersion)
+
+			test.dbQueryTestCases.ThenWeCanSqlQueryPrimaryDb()
+			test.dbQueryTestCases.ThenWeCanSqlQueryReplicaDb()
+
+			log.Print("END OF: Test 'GIVEN Kubegres with generated passwords AND the replication password is changed'")
+		})
+	})
+})
+
+type PasswordsRotationTest struct {
+	kubegresResource  *postgresv1.Kubegres
+	dbQueryTestCases  testcases.DbQueryTestCases
+	resourceCreator   util.TestResourceCreator
+	resourceRetriever util.TestResourceRetriever
+	resourceModifier  util.TestResourceModifier
+}
+
+func (r *PasswordsRotationTest) givenNewKubegresWithGeneratedReplicationPassword(specNbreReplicas int32) {
+	r.kubegresResource = resourceConfigs.LoadKubegresYaml()
+	r.kubegresResource.Spec.Replicas = &specNbreReplicas
+	r.kubegresResource.Spec.Passwords.Generate = true
+	r.kubegresResource.Spec.Env = []v12.EnvVar{}
+	r.resourceModifier.AppendEnvVarFromSecretKey(ctx.EnvVarNameOfPostgresSuperUserPsw, "superUserPassword", r.kubegresResource)
+}
+
+func (r *PasswordsRotationTest) whenKubegresIsCreated() {
+	r.resourceCreator.CreateKubegres(r.kubegresResource)
+}
+
+func (r *PasswordsRotationTest) whenReplicationPasswordIsChangedInGeneratedSecret(newPassword string) {
+
+	secretName := resourceConfigs.KubegresResourceName + ctx.PasswordsSecretNameSuffix
+	secret, err := r.resourceRetriever.GetSecret(secretName)
+	Expect(err).Should(Succeed())
+
+	secret.Data[ctx.PasswordsSecretKeyReplicationUser] = []byte(newPassword)
+	r.resourceCreator.UpdateResource(secret, secretName)
+}
+
+func (r *PasswordsRotationTest) thenAppliedReplicationPasswordShouldBe(expectedPassword string) {
+	Eventually(func() bool {
+
+		secretName := resourceConfigs.KubegresResourceName + ctx.AppliedPasswordsSecretNameSuffix
+		secret, err := r.resourceRetriever.GetSecret(secretName)
+		if err != nil {
+			log.Println("Waiting for the Secret '" + secretName + "' to be deployed.")
+			return false
+		}
+
+		return string(secret.Data[ctx.PasswordsSecretKeyReplicationUser]) == expectedPassword
+
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
+
+func (r *PasswordsRotationTest) thenStatusPasswordsVersionShouldBeSet() string {
+
+	passwordsVersion := ""
+
+	Eventually(func() bool {
+
+		kubegres, err := r.resourceRetriever.GetKubegres()
+		if err != nil {
+			log.Println("ERROR while retrieving Kubegres resource: ", err)
+			return false
+		}
+
+		passwordsVersion = kubegres.Status.PasswordsVersion
+		return passwordsVersion != ""
+
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+
+	return passwordsVersion
+}
+
+func (r *PasswordsRotationTest) thenPodsStatesShouldBe(nbrePrimary, nbreReplicas int, expectedPasswordsVersion string) {
+	Eventually(func() bool {
+
+		kubegresResources, err := r.resourceRetriever.GetKubegresResources()
+		if err != nil {
+			log.Println("ERROR while retrieving Kubegres kubegresResources")
+			return false
+		}
+
+		if !kubegresResources.AreAllReady ||
+			kubegresResources.NbreDeployedPrimary != nbrePrimary ||
+			kubegresResources.NbreDeployedReplicas != nbreReplicas {
+			log.Println("Waiting for all Pods to be ready")
+			return false
+		}
+
+		for _, resource := range kubegresResources.Resources {
+			if resource.Pod.Metadata.Annotations[ctx.PasswordsVersionAnnotationKey] != expectedPasswordsVersion {
+				log.Println("Pod '" + resource.Pod.Name + "' has not been restarted with the passwords version '" + expectedPasswordsVersion + "'. Waiting...")
+				return false
+			}
+		}
+
+		return true
+
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2021 Reactive Tech Limited.
+"Reactive Tech Limited" is a company located in England, United Kingdom.
+https://www.reactive-tech.io
+
+Lead Developer: Alex Arica
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"log"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v12 "k8s.io/api/core/v1"
+	postgresv1 "reactive-tech.io/kubegres/api/v1"
+	"reactive-tech.io/kubegres/controllers/ctx"
+	"reactive-tech.io/kubegres/test/resourceConfigs"
+	"reactive-tech.io/kubegres/test/util"
+	"reactive-tech.io/kubegres/test/util/testcases"
+)
+
+var _ = Describe("Setting Kubegres spec 'passwords.generate'", Label("group:1"), func() {
+
+	var test = SpecPasswordsTest{}
+
+	BeforeEach(func() {
+		//Skip("Temporarily skipping test")
+
+		namespace := resourceConfigs.DefaultNamespace
+		test.resourceRetriever = util.CreateTestResourceRetriever(k8sClientTest, namespace)
+		test.resourceCreator = util.CreateTestResourceCreator(k8sClientTest, test.resourceRetriever, namespace)
+		test.dbQueryTestCases = testcases.InitDbQueryTestCases(test.resourceCreator, resourceConfigs.KubegresResourceName)
+	})
+
+	AfterEach(func() {
+		test.resourceCreator.DeleteAllTestResources()
+	})
+
+	Context("GIVEN new Kubegres is created with spec 'passwords.generate' set to true AND without password env-vars AND spec 'replica' set to 3", func() {
+
+		It("THEN a Secret with generated passwords should be created AND 1 primary and 2 replica should use it", func() {
+
+			log.Print("START OF: Test 'GIVEN new Kubegres is created with spec 'passwords.generate' set to true AND without password env-vars'")
+
+			test.givenNewKubegresWithGeneratedPasswords(3)
+
+			test.whenKubegresIsCreated()
+
+			test.thenPasswordsSecretShouldBeGenerated()
+
+			test.thenPodsShouldReferenceSecretForEnvVar(1, 2, ctx.EnvVarNameOfPostgresSuperUserPsw, generatedPasswordsSecretName())
+			test.thenPodsShouldReferenceSecretForEnvVar(1, 2, ctx.EnvVarNameOfPostgresReplicationUserPsw, generatedPasswordsSecretName())
+
+			test.thenDeployedKubegresSpecShouldNotHaveEnvVars()
+
+			log.Print("END OF: Test 'GIVEN new Kubegres is created with spec 'passwords.generate' set to true AND without password env-vars'")
+		})
+	})
+
+	Context("GIVEN new Kubegres is created with spec 'passwords.generate' set to true AND env-var of super-user password AND spec 'replica' set to 3", func() {
+
+		It("THEN the super-user password should come from the user's Secret AND the replication password from the generated Secret", func() {
+
+			log.Print("START OF: Test 'GIVEN new Kubegres is created with spec 'passwords.generate' set to true AND env-var of super-user password'")
+
+			test.givenNewKubegresWithGeneratedPasswordsAndSuperUserPassword(3)
+
+			test.whenKubegresIsCreated()
+
+			test.thenPasswordsSecretShouldBeGenerated()
+
+			test.thenPodsShouldReferenceSecretForEnvVar(1, 2, ctx.EnvVarNameOfPostgresSuperUserPsw, resourceConfigs.SecretResourceName)
+			test.thenPodsShouldReferenceSecretForEnvVar(1, 2, ctx.EnvVarNameOfPostgresReplicationUserPsw, generatedPasswordsSecretName())
+
+			test.dbQueryTestCases.ThenWeCanSqlQueryPrimaryDb()
+			test.dbQueryTestCases.ThenWeCanSqlQueryReplicaDb()
+
+			log.Print("END OF: Test 'GIVEN new Kubegres is created with spec 'passwords.generate' set to true AND env-var of super-user password'")
+		})
+	})
+})
+
+type SpecPasswordsTest struct {
+	kubegresResource  *postgresv1.Kubegres
+	dbQueryTestCases  testcases.DbQueryTestCases
+	resourceCreator   util.TestResourceCreator
+	resourceRetriever util.TestResourceRetriever
+	resourceModifier  util.TestResourceModifier
+}
+
+func generatedPasswordsSecretName() string {
+	return resourceConfigs.KubegresResourceName + ctx.PasswordsSecretNameSuffix
+}
+
+func (r *SpecPasswordsTest) givenNewKubegresWithGeneratedPasswords(specNbreReplicas int32) {
+	r.kubegresResource = resourceConfigs.LoadKubegresYaml()
+	r.kubegresResource.Spec.Replicas = &specNbreReplicas
+	r.kubegresResource.Spec.Passwords.Generate = true
+	r.kubegresResource.Spec.Env = []v12.EnvVar{}
+}
+
+func (r *SpecPasswordsTest) givenNewKubegresWithGeneratedPasswordsAndSuperUserPassword(specNbreReplicas int32) {
+	r.givenNewKubegresWithGeneratedPasswords(specNbreReplicas)
+	r.resourceModifier.AppendEnvVarFromSecretKey(ctx.EnvVarNameOfPostgresSuperUserPsw, "superUserPassword", r.kubegresResource)
+}
+
+func (r *SpecPasswordsTest) whenKubegresIsCreated() {
+	r.resourceCreator.CreateKubegres(r.kubegresResource)
+}
+
+func (r *SpecPasswordsTest) thenPasswordsSecretShouldBeGenerated() {
+	Eventually(func() bool {
+
+		secret, err := r.resourceRetriever.GetSecret(generatedPasswordsSecretName())
+		if err != nil {
+			log.Println("Waiting for the Secret '" + generatedPasswordsSecretName() + "' to be generated.")
+			return false
+		}
+
+		if len(secret.OwnerReferences) != 1 || secret.OwnerReferences[0].Name != resourceConfigs.KubegresResourceName {
+			log.Println("The Secret '" + generatedPasswordsSecretName() + "' is not owned by the Kubegres resource.")
+			return false
+		}
+
+		return len(secret.Data[ctx.PasswordsSecretKeySuperUser]) > 0 &&
+			len(secret.Data[ctx.PasswordsSecretKeyReplicationUser]) > 0
+
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
+
+func (r *SpecPasswordsTest) thenPodsShouldReferenceSecretForEnvVar(nbrePrimary, nbreReplicas int, envVarName, secretName string) {
+	Eventually(func() bool {
+
+		kubegresResources, err := r.resourceRetriever.GetKubegresResources()
+		if err != nil {
+			log.Println("ERROR while retrieving Kubegres kubegresResources")
+			return false
+		}
+
+		if !kubegresResources.AreAllReady ||
+			kubegresResources.NbreDeployedPrimary != nbrePrimary ||
+			kubegresResources.NbreDeployedReplicas != nbreReplicas {
+			log.Println("Waiting for all Pods to be ready")
+			return false
+		}
+
+		for _, resource := range kubegresResources.Resources {
+			if r.getSecretNameOfEnvVar(envVarName, resource.Pod.Spec.Containers[0].Env) != secretName {
+				log.Println("Pod '" + resource.Pod.Name + "' doesn't have the env variable '" + envVarName + "' referencing the Secret '" + secretName + "'. Waiting...")
+				return false
+			}
+		}
+
+		return true
+
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
+
+func (r *SpecPasswordsTest) thenDeployedKubegresSpecShouldNotHaveEnvVars() {
+	Eventually(func() bool {
+
+		kubegres, err := r.resourceRetriever.GetKubegres()
+		if err != nil {
+			log.Println("ERROR while retrieving Kubegres resource: ", err)
+			return false
+		}
+
+		return len(kubegres.Spec.Env) == 0
+
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
+
+func (r *SpecPasswordsTest) getSecretNameOfEnvVar(envVarName string, envVars []v12.EnvVar) string {
+	for _, envVar := range envVars {
+		if envVar.Name == envVarName && envVar.ValueFrom != nil && envVar.ValueFrom.SecretKeyRef != nil {
+			return envVar.ValueFrom.SecretKeyRef.Name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2021 Reactive Tech Limited.
+"Reactive Tech Limited" is a company located in England, United Kingdom.
+https://www.reactive-tech.io
+
+Lead Developer: Alex Arica
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"log"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	postgresv1 "reactive-tech.io/kubegres/api/v1"
+	"reactive-tech.io/kubegres/controllers/ctx"
+	"reactive-tech.io/kubegres/test/resourceConfigs"
+	"reactive-tech.io/kubegres/test/util"
+)
+
+var _ = Describe("Setting the annotation 'kubegres.reactive-tech.io/paused' on Kubegres", Label("group:3"), func() {
+
+	var test = SpecPausedTest{}
+
+	BeforeEach(func() {
+		//Skip("Temporarily skipping test")
+
+		namespace := resourceConfigs.DefaultNamespace
+		test.resourceRetriever = util.CreateTestResourceRetriever(k8sClientTest, namespace)
+		test.resourceCreator = util.CreateTestResourceCreator(k8sClientTest, test.resourceRetriever, namespace)
+	})
+
+	AfterEach(func() {
+		test.resourceCreator.DeleteAllTestResources()
+	})
+
+	Context("GIVEN Kubegres with 3 instances is paused AND a Replica StatefulSet is deleted", func() {
+
+		It("THEN the Replica should not be re-created until the annotation is removed", func() {
+
+			log.Print("START OF: Test 'GIVEN Kubegres with 3 instances is paused AND a Replica StatefulSet is deleted'")
+
+			test.givenNewKubegresSpecIsSetTo(3)
+
+			test.whenKubegresIsCreated()
+
+			test.thenPodsStatesShouldBe(1, 2)
+
+			test.whenPausedAnnotationIsSetTo("true")
+
+			test.thenConditionStatusShouldBe(postgresv1.ConditionTypePaused, metav1.ConditionTrue)
+
+			test.whenReplicaStatefulSetIsDeleted()
+
+			test.thenPodsStatesShouldRemain(1, 1)
+
+			test.whenPausedAnnotationIsRemoved()
+
+			test.thenConditionStatusShouldBe(postgresv1.ConditionTypePaused, metav1.ConditionFalse)
+
+			test.thenPodsStatesShouldBe(1, 2)
+
+			log.Print("END OF: Test 'GIVEN Kubegres with 3 instances is paused AND a Replica StatefulSet is deleted'")
+		})
+	})
+})
+
+type SpecPausedTest struct {
+	kubegresResource  *postgresv1.Kubegres
+	resourceCreator   util.TestResourceCreator
+	resourceRetriever util.TestResourceRetriever
+}
+
+func (r *SpecPausedTest) givenNewKubegresSpecIsSetTo(specNbreReplicas int32) {
+	r.kubegresResource = resourceConfigs.LoadKubegresYaml()
+	r.kubegresResource.Spec.Replicas = &specNbreReplicas
+}
+
+func (r *SpecPausedTest) whenKubegresIsCreated() {
+	r.resourceCreator.CreateKubegres(r.kubegresResource)
+}
+
+func (r *SpecPausedTest) whenPausedAnnotationIsSetTo(value string) {
+	var err error
+	r.kubegresResource, err = r.resourceRetriever.GetKubegres()
+	Expect(err).Should(Succeed())
+
+	if r.kubegresResource.Annotations == nil {
+		r.kubegresResource.Annotations = map[string]string{}
+	}
+	r.kubegresResource.Annotations[ctx.PausedAnnotationKey] = value
+	r.resourceCreator.UpdateResource(r.kubegresResource, "Kubegres")
+}
+
+func (r *SpecPausedTest) whenPausedAnnotationIsRemoved() {
+	var err error
+	r.kubegresResource, err = r.resourceRetriever.GetKubegres()
+	Expect(err).Should(Succeed())
+
+	delete(r.kubegresResource.Annotations, ctx.PausedAnnotationKey)
+	r.resourceCreator.UpdateResource(r.kubegresResource, "Kubegres")
+}
+
+func (r *SpecPausedTest) whenReplicaStatefulSetIsDeleted() {
+	kubegresResources, err := r.resourceRetriever.GetKubegresResources()
+	if err != nil {
+		Expect(err).Should(Succeed())
+		return
+	}
+
+	for _, kubegresResource := range kubegresResources.Resources {
+		if !kubegresResource.IsPrimary {
+			log.Println("Attempting to delete StatefulSet: '" + kubegresResource.StatefulSet.Name + "'")
+			Expect(r.resourceCreator.DeleteResource(kubegresResource.StatefulSet.Resource, kubegresResource.StatefulSet.Name)).Should(BeTrue())
+			return
+		}
+	}
+
+	Fail("There is no Replica deployed")
+}
+
+func (r *SpecPausedTest) thenPodsStatesShouldBe(nbrePrimary, nbreReplicas int) bool {
+	return Eventually(func() bool {
+		return r.arePodsStates(nbrePrimary, nbreReplicas)
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
+
+func (r *SpecPausedTest) thenPodsStatesShouldRemain(nbrePrimary, nbreReplicas int) {
+	r.thenPodsStatesShouldBe(nbrePrimary, nbreReplicas)
+
+	Consistently(func() bool {
+		return r.arePodsStates(nbrePrimary, nbreReplicas)
+	}, 60*time.Second, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
+
+func (r *SpecPausedTest) arePodsStates(nbrePrimary, nbreReplicas int) bool {
+
+	kubegresResources, err := r.resourceRetriever.GetKubegresResources()
+	if err != nil && !apierrors.IsNotFound(err) {
+		log.Println("ERROR while retrieving Kubegres kubegresResources")
+		return false
+	}
+
+	if kubegresResources.AreAllReady &&
+		kubegresResources.NbreDeployedPrimary == nbrePrimary &&
+		kubegresResources.NbreDeployedReplicas == nbreReplicas {
+
+		log.Println("Deployed and Ready StatefulSets check successful")
+		return true
+	}
+
+	return false
+}
+
+func (r *SpecPausedTest) thenConditionStatusShouldBe(conditionType string, expectedStatus metav1.ConditionStatus) {
+	Eventually(func() bool {
+
+		kubegres, err := r.resourceRetriever.GetKubegres()
+		if err != nil {
+			log.Println("ERROR while retrieving Kubegres resource: ", err)
+			return false
+		}
+
+		condition := meta.FindStatusCondition(kubegres.Status.Conditions, conditionType)
+		if condition == nil || condition.Status != expectedStatus {
+			log.Println("Waiting for the condition '" + conditionType + "' to have the status '" + string(expectedStatus) + "'")
+			return false
+		}
+
+		return true
+
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
@@ -27,7 +27,6 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
-	v12 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	postgresv1 "reactive-tech.io/kubegres/api/v1"
 	"reactive-tech.io/kubegres/test/resourceConfigs"
@@ -73,6 +72,7 @@ var _ = Describe("Primary instances is not available, when the promotion of a Po
 			expectedNbreUsers++
 
 			replicaPodNameToPromote := test.getReplicaPodName()
+			replicaPodUidToPromote := test.getReplicaPodUid()
 
 			test.givenExistingKubegresSpecIsSetTo(replicaPodNameToPromote)
 
@@ -86,6 +86,8 @@ var _ = Describe("Primary instances is not available, when the promotion of a Po
 
 			test.thenPrimaryPodNameMatches(replicaPodNameToPromote)
 
+			test.thenPrimaryPodWasNotRestarted(replicaPodUidToPromote)
+
 			test.thenPrimaryDbContainsExpectedNbreUsers(expectedNbreUsers)
 			test.thenReplicaDbContainsExpectedNbreUsers(expectedNbreUsers)
 
@@ -93,6 +95,53 @@ var _ = Describe("Primary instances is not available, when the promotion of a Po
 		})
 	})
 
+	Context("GIVEN Kubegres with 1 primary and 1 replica AND once deployed we update YAML with 'failover.promotePod' is set to a Pod name AND the Pod of the new primary restarts", func() {
+
+		It("THEN the new primary should restart as primary with its data", func() {
+
+			log.Print("START OF: Test 'GIVEN Kubegres with 1 primary and 1 replica AND once deployed we update YAML with 'failover.promotePod' is set to a Pod name AND the Pod of the new primary restarts'")
+
+			test.givenNewKubegresSpecIsSetTo(2)
+
+			test.whenKubegresIsCreated()
+
+			test.thenPodsStatesShouldBe(1, 1)
+
+			expectedNbreUsers := 0
+
+			test.givenUserAddedInPrimaryDb()
+			expectedNbreUsers++
+
+			replicaPodNameToPromote := test.getReplicaPodName()
+
+			test.givenExistingKubegresSpecIsSetTo(replicaPodNameToPromote)
+
+			test.whenKubernetesIsUpdated()
+
+			time.Sleep(time.Second * 10)
+
+			test.thenPodsStatesShouldBe(1, 1)
+
+			test.thenPrimaryPodNameMatches(replicaPodNameToPromote)
+
+			test.givenUserAddedInPrimaryDb()
+			expectedNbreUsers++
+
+			test.whenPrimaryPodIsDeleted()
+
+			time.Sleep(time.Second * 10)
+
+			test.thenPodsStatesShouldBe(1, 1)
+
+			test.thenPrimaryPodNameMatches(replicaPodNameToPromote)
+
+			test.thenPrimaryDbContainsExpectedNbreUsers(expectedNbreUsers)
+			test.thenReplicaDbContainsExpectedNbreUsers(expectedNbreUsers)
+
+			log.Print("END OF: Test 'GIVEN Kubegres with 1 primary and 1 replica AND once deployed we update YAML with 'failover.promotePod' is set to a Pod name AND the Pod of the new primary restarts'")
+		})
+	})
+
 	Context("GIVEN Kubegres with 1 primary and 1 replica AND once deployed we update YAML with 'failover.isDisabled' is true AND with 'failover.promotePod' is set to a Pod name", func() {
 
 		It("THEN the replica Pod set in spec 'failover.promotePod' should become the new primary AND a new replica should be created", func() {
@@ -136,7 +185,7 @@ var _ = Describe("Primary instances is not available, when the promotion of a Po
 
 	Context("GIVEN Kubegres with 1 primary and 1 replica AND once deployed we update YAML with 'failover.promotePod' is set to a Pod name which does NOT exist", func() {
 
-		It("THEN nothing should happen AND an error message should be logged as event saying Pod does NOT exist", func() {
+		It("THEN the update should be rejected saying Pod does NOT exist AND nothing should happen", func() {
 
 			log.Print("START OF: Test 'GIVEN Kubegres with 1 primary and 1 replica AND once deployed we update YAML with 'failover.promotePod' is set to a Pod name which does NOT exist'")
 
@@ -160,7 +209,9 @@ var _ = Describe("Primary instances is not available, when the promotion of a Po
 
 			test.givenExistingKubegresSpecIsSetTo(replicaPodNameToPromote)
 
-			test.whenKubernetesIsUpdated()
+			test.whenKubernetesIsUpdatedWithInvalidSpec()
+
+			test.thenUpdateShouldBeRejected(replicaPodNameToPromote)
 
 			time.Sleep(time.Second * 10)
 
@@ -168,8 +219,6 @@ var _ = Describe("Primary instances is not available, when the promotion of a Po
 
 			test.thenPromotePodFieldInSpecShouldBeCleared()
 
-			test.thenErrorEventShouldBeLogged(replicaPodNameToPromote)
-
 			test.thenDeployedPodNamesMatch(primaryPodName, replicaPodName)
 
 			test.thenPrimaryDbContainsExpectedNbreUsers(expectedNbreUsers)
@@ -181,7 +230,7 @@ var _ = Describe("Primary instances is not available, when the promotion of a Po
 
 	Context("GIVEN Kubegres with 1 primary and 1 replica AND once deployed we update YAML with 'failover.promotePod' is set the Primary Pod name", func() {
 
-		It("THEN nothing should happen", func() {
+		It("THEN the update should be rejected AND nothing should happen", func() {
 
 			log.Print("START OF: Test 'GIVEN Kubegres with 1 primary and 1 replica AND once deployed we update YAML with 'failover.promotePod' is set the Primary Pod name'")
 
@@ -205,7 +254,9 @@ var _ = Describe("Primary instances is not available, when the promotion of a Po
 
 			test.givenExistingKubegresSpecIsSetTo(replicaPodNameToPromote)
 
-			test.whenKubernetesIsUpdated()
+			test.whenKubernetesIsUpdatedWithInvalidSpec()
+
+			test.thenUpdateShouldBeRejected(replicaPodNameToPromote)
 
 			time.Sleep(time.Second * 10)
 
@@ -231,6 +282,7 @@ type SpecFailoverIsDisabledAndPromotePodAreSetTest struct {
 	resourceRetriever     util.TestResourceRetriever
 	customEnvVariableName string
 	customEnvVariableKey  string
+	admissionErr          error
 }
 
 func (r *SpecFailoverIsDisabledAndPromotePodAreSetTest) givenNewKubegresSpecIsSetTo(specNbreReplicas int32) {
@@ -294,10 +346,34 @@ func (r *SpecFailoverIsDisabledAndPromotePodAreSetTest) whenKubegresIsCreated()
 	r.resourceCreator.CreateKubegres(r.kubegresResource)
 }
 
+func (r *SpecFailoverIsDisabledAndPromotePodAreSetTest) whenKubernetesIsUpdatedWithInvalidSpec() {
+	r.admissionErr = r.resourceCreator.UpdateResourceWithInvalidSpec(r.kubegresResource, "Kubegres")
+}
+
 func (r *SpecFailoverIsDisabledAndPromotePodAreSetTest) whenKubernetesIsUpdated() {
 	r.resourceCreator.UpdateResource(r.kubegresResource, "Kubegres")
 }
 
+// whenPrimaryPodIsDeleted deletes the Pod of the Primary without deleting its StatefulSet, so that the Pod restarts
+// with the init container of its StatefulSet.
+func (r *SpecFailoverIsDisabledAndPromotePodAreSetTest) whenPrimaryPodIsDeleted() {
+	kubegresResources, err := r.resourceRetriever.GetKubegresResources()
+	if err != nil {
+		Expect(err).Should(Succeed())
+		return
+	}
+
+	for _, kubegresResource := range kubegresResources.Resources {
+		if kubegresResource.IsPrimary {
+			log.Println("Attempting to delete Pod: '" + kubegresResource.Pod.Name + "'")
+			Expect(r.resourceCreator.DeleteResource(kubegresResource.Pod.Resource, kubegresResource.Pod.Name)).Should(BeTrue())
+			return
+		}
+	}
+
+	Fail("The Primary is not deployed")
+}
+
 func (r *SpecFailoverIsDisabledAndPromotePodAreSetTest) thenPrimaryPodNameMatches(expectedPromotedPod string) {
 	kubegresResources, err := r.resourceRetriever.GetKubegresResources()
 	if err != nil {
@@ -343,23 +419,10 @@ func (r *SpecFailoverIsDisabledAndPromotePodAreSetTest) thenPodsStatesShouldBe(n
 	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
 }
 
-func (r *SpecFailoverIsDisabledAndPromotePodAreSetTest) thenErrorEventShouldBeLogged(podName string) {
-
-	expectedErrorEvent := util.EventRecord{
-		Eventtype: v12.EventTypeWarning,
-		Reason:    "ManualFailoverCannotHappenAsConfigErr",
-		Message: "The value of the field 'failover.promotePod' is set to '" + podName + "'. " +
-			"That value is either the name of a Primary Pod OR a Replica Pod which is not ready OR a Pod which does not exist. " +
-			"Please set the name of a Replica Pod that you would like to promote as a Primary Pod.",
-	}
-	Eventually(func() bool {
-		_, err := r.resourceRetriever.GetKubegres()
-		if err != nil {
-			return false
-		}
-		return eventRecorderTest.CheckEventExist(expectedErrorEvent)
-
-	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+func (r *SpecFailoverIsDisabledAndPromotePodAreSetTest) thenUpdateShouldBeRejected(podName string) {
+	Expect(r.admissionErr).Should(MatchError(ContainSubstring("The value of the field 'failover.promotePod' is set to '" + podName + "'. " +
+		"That value is either the name of a Primary Pod OR a Pod which does not exist. " +
+		"Please set the name of a Replica Pod that you would like to promote as a Primary Pod.")))
 }
 
 func (r *SpecFailoverIsDisabledAndPromotePodAreSetTest) getReplicaPodName() string {
@@ -372,6 +435,35 @@ func (r *SpecFailoverIsDisabledAndPromotePodAreSetTest) getReplicaPodName() stri
 	return ""
 }
 
+func (r *SpecFailoverIsDisabledAndPromotePodAreSetTest) getReplicaPodUid() string {
+	kubegresResources, _ := r.resourceRetriever.GetKubegresResources()
+	for _, kubegresResource := range kubegresResources.Resources {
+		if !kubegresResource.IsPrimary {
+			return string(kubegresResource.Pod.Metadata.UID)
+		}
+	}
+	return ""
+}
+
+// thenPrimaryPodWasNotRestarted checks that the Replica was promoted online, without re-creating its Pod.
+func (r *SpecFailoverIsDisabledAndPromotePodAreSetTest) thenPrimaryPodWasNotRestarted(expectedPodUid string) {
+	kubegresResources, err := r.resourceRetriever.GetKubegresResources()
+	if err != nil {
+		Expect(err).Should(Succeed())
+		return
+	}
+
+	for _, kubegresResource := range kubegresResources.Resources {
+		if kubegresResource.IsPrimary {
+			Expect(string(kubegresResource.Pod.Metadata.UID)).Should(Equal(expectedPodUid))
+			Expect(kubegresResource.Pod.Resource.Status.ContainerStatuses[0].RestartCount).Should(BeZero())
+			return
+		}
+	}
+
+	Fail("The Primary is not deployed")
+}
+
 func (r *SpecFailoverIsDisabledAndPromotePodAreSetTest) thenPrimaryDbContainsExpectedNbreUsers(expectedNbreUsers int) {
 	Eventually(func() bool {
 
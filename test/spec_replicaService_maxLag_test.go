@@ -0,0 +1,312 @@
+/*
+Copyright 2021 Reactive Tech Limited.
+"Reactive Tech Limited" is a company located in England, United Kingdom.
+https://www.reactive-tech.io
+
+Lead Developer: Alex Arica
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"log"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	postgresv1 "reactive-tech.io/kubegres/api/v1"
+	"reactive-tech.io/kubegres/test/resourceConfigs"
+	"reactive-tech.io/kubegres/test/util"
+)
+
+var _ = Describe("Setting Kubegres spec 'replicaService.maxLag'", Label("group:3"), func() {
+
+	var test = SpecReplicaServiceMaxLagTest{}
+
+	BeforeEach(func() {
+		//Skip("Temporarily skipping test")
+
+		namespace := resourceConfigs.DefaultNamespace
+		test.resourceRetriever = util.CreateTestResourceRetriever(k8sClientTest, namespace)
+		test.resourceCreator = util.CreateTestResourceCreator(k8sClientTest, test.resourceRetriever, namespace)
+		test.connectionPrimaryDb = util.InitDbConnectionDbUtil(test.resourceCreator, resourceConfigs.KubegresResourceName, resourceConfigs.ServiceToSqlQueryPrimaryDbNodePort, true)
+		test.connectionReplicaDb = util.InitDbConnectionDbUtil(test.resourceCreator, resourceConfigs.KubegresResourceName, resourceConfigs.ServiceToSqlQueryReplicaDbNodePort, false)
+	})
+
+	AfterEach(func() {
+		test.resourceCreator.DeleteAllTestResources()
+	})
+
+	Context("GIVEN new Kubegres is created with spec 'replicaService.maxLag' set to '2s' AND the Replica stops replaying the WAL", func() {
+
+		It("THEN the Replica should be removed from the Replica Service AND it should be added back once 'replicaService.maxLag' is removed", func() {
+
+			log.Print("START OF: Test 'GIVEN new Kubegres is created with spec 'replicaService.maxLag' set to '2s' AND the Replica stops replaying the WAL'")
+
+			test.givenNewKubegresSpecIsSetTo(2, "2s")
+
+			test.whenKubegresIsCreated()
+
+			test.thenPodsStatesShouldBe(1, 1)
+
+			replicaPodName := test.thenReplicaPodName()
+
+			test.thenReplicaServiceShouldSelectPod(replicaPodName)
+
+			test.whenReplicaStopsReplayingWal()
+
+			test.thenReplicaServiceShouldNotSelectPodWhileWritingOnPrimary(replicaPodName)
+
+			test.givenExistingKubegresSpecIsSetWithoutMaxLag()
+
+			test.whenKubegresIsUpdated()
+
+			test.thenReplicaServiceShouldSelectPod(replicaPodName)
+
+			test.whenReplicaResumesReplayingWal()
+
+			log.Print("END OF: Test 'GIVEN new Kubegres is created with spec 'replicaService.maxLag' set to '2s' AND the Replica stops replaying the WAL'")
+		})
+	})
+
+	Context("GIVEN new Kubegres is created with spec 'replicaService.maxLag' set to '2s' AND the Primary stays idle", func() {
+
+		It("THEN the Replica should stay selected by the Replica Service", func() {
+
+			log.Print("START OF: Test 'GIVEN new Kubegres is created with spec 'replicaService.maxLag' set to '2s' AND the Primary stays idle'")
+
+			test.givenNewKubegresSpecIsSetTo(2, "2s")
+
+			test.whenKubegresIsCreated()
+
+			test.thenPodsStatesShouldBe(1, 1)
+
+			replicaPodName := test.thenReplicaPodName()
+
+			test.whenUsersAreInsertedOnPrimary(1)
+
+			test.thenReplicaServiceShouldSelectPod(replicaPodName)
+
+			test.thenReplicaServiceShouldKeepSelectingPodWhileThePrimaryIsIdle(replicaPodName)
+
+			log.Print("END OF: Test 'GIVEN new Kubegres is created with spec 'replicaService.maxLag' set to '2s' AND the Primary stays idle'")
+		})
+	})
+
+	Context("GIVEN new Kubegres is created with spec 'replicaService.maxLag' set to '1m' AND the Replicas replay the WAL", func() {
+
+		It("THEN all Replicas should be selected by the Replica Service", func() {
+
+			log.Print("START OF: Test 'GIVEN new Kubegres is created with spec 'replicaService.maxLag' set to '1m' AND the Replicas replay the WAL'")
+
+			test.givenNewKubegresSpecIsSetTo(3, "1m")
+
+			test.whenKubegresIsCreated()
+
+			test.thenPodsStatesShouldBe(1, 2)
+
+			test.whenUsersAreInsertedOnPrimary(3)
+
+			test.thenReplicaServiceShouldSelectNbrePods(2)
+
+			log.Print("END OF: Test 'GIVEN new Kubegres is created with spec 'replicaService.maxLag' set to '1m' AND the Replicas replay the WAL'")
+		})
+	})
+})
+
+type SpecReplicaServiceMaxLagTest struct {
+	kubegresResource    *postgresv1.Kubegres
+	connectionPrimaryDb util.DbConnectionDbUtil
+	connectionReplicaDb util.DbConnectionDbUtil
+	resourceCreator     util.TestResourceCreator
+	resourceRetriever   util.TestResourceRetriever
+}
+
+func (r *SpecReplicaServiceMaxLagTest) givenNewKubegresSpecIsSetTo(specNbreReplicas int32, maxLag string) {
+	r.kubegresResource = resourceConfigs.LoadKubegresYaml()
+	r.kubegresResource.Spec.Replicas = &specNbreReplicas
+	r.kubegresResource.Spec.ReplicaService.MaxLag = maxLag
+}
+
+func (r *SpecReplicaServiceMaxLagTest) givenExistingKubegresSpecIsSetWithoutMaxLag() {
+	var err error
+	r.kubegresResource, err = r.resourceRetriever.GetKubegres()
+
+	if err != nil {
+		log.Println("Error while getting Kubegres resource : ", err)
+		Expect(err).Should(Succeed())
+		return
+	}
+
+	r.kubegresResource.Spec.ReplicaService.MaxLag = ""
+}
+
+func (r *SpecReplicaServiceMaxLagTest) whenKubegresIsCreated() {
+	r.resourceCreator.CreateKubegres(r.kubegresResource)
+}
+
+func (r *SpecReplicaServiceMaxLagTest) whenKubegresIsUpdated() {
+	r.resourceCreator.UpdateResource(r.kubegresResource, "Kubegres")
+}
+
+func (r *SpecReplicaServiceMaxLagTest) whenReplicaStopsReplayingWal() {
+	Eventually(func() bool {
+		return r.connectionReplicaDb.ExecSql("SELECT pg_wal_replay_pause()")
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
+
+func (r *SpecReplicaServiceMaxLagTest) whenReplicaResumesReplayingWal() {
+	Eventually(func() bool {
+		return r.connectionReplicaDb.ExecSql("SELECT pg_wal_replay_resume()")
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
+
+func (r *SpecReplicaServiceMaxLagTest) whenUsersAreInsertedOnPrimary(nbreUsers int) {
+	for i := 0; i < nbreUsers; i++ {
+		Eventually(func() bool {
+			return r.connectionPrimaryDb.InsertUser()
+		}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+	}
+}
+
+func (r *SpecReplicaServiceMaxLagTest) thenPodsStatesShouldBe(nbrePrimary, nbreReplicas int) bool {
+	return Eventually(func() bool {
+
+		kubegresResources, err := r.resourceRetriever.GetKubegresResources()
+		if err != nil && !apierrors.IsNotFound(err) {
+			log.Println("ERROR while retrieving Kubegres kubegresResources")
+			return false
+		}
+
+		if kubegresResources.AreAllReady &&
+			kubegresResources.NbreDeployedPrimary == nbrePrimary &&
+			kubegresResources.NbreDeployedReplicas == nbreReplicas {
+
+			time.Sleep(resourceConfigs.TestRetryInterval)
+			log.Println("Deployed and Ready StatefulSets check successful")
+			return true
+		}
+
+		return false
+
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
+
+func (r *SpecReplicaServiceMaxLagTest) thenReplicaPodName() string {
+	kubegresResources, err := r.resourceRetriever.GetKubegresResources()
+	Expect(err).Should(Succeed())
+
+	for _, kubegresResource := range kubegresResources.Resources {
+		if !kubegresResource.IsPrimary {
+			return kubegresResource.Pod.Name
+		}
+	}
+
+	Fail("No Replica is deployed")
+	return ""
+}
+
+// thenReplicaServiceShouldNotSelectPodWhileWritingOnPrimary inserts users on the Primary, so that the replay lag
+// of a Replica which stopped replaying the WAL increases, until the Replica Service does not select its Pod.
+func (r *SpecReplicaServiceMaxLagTest) thenReplicaServiceShouldNotSelectPodWhileWritingOnPrimary(podName string) {
+	Eventually(func() bool {
+
+		r.connectionPrimaryDb.InsertUser()
+
+		isSelected, err := r.isPodSelectedByReplicaService(podName)
+		if err != nil {
+			log.Println("ERROR while retrieving the endpoints of the Replica Service: ", err)
+			return false
+		}
+
+		if isSelected {
+			log.Println("Waiting for the Replica Service to stop selecting the Pod '" + podName + "'.")
+		}
+		return !isSelected
+
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
+
+// thenReplicaServiceShouldKeepSelectingPodWhileThePrimaryIsIdle runs checkpoints on the Primary, which write WAL
+// without any transaction, so that the time since the last transaction replayed by the Replica keeps increasing
+// while the Replica is caught-up.
+func (r *SpecReplicaServiceMaxLagTest) thenReplicaServiceShouldKeepSelectingPodWhileThePrimaryIsIdle(podName string) {
+	Consistently(func() bool {
+
+		r.connectionPrimaryDb.ExecSql("CHECKPOINT")
+
+		isSelected, err := r.isPodSelectedByReplicaService(podName)
+		if err != nil {
+			log.Println("ERROR while retrieving the endpoints of the Replica Service: ", err)
+			return true
+		}
+
+		if !isSelected {
+			log.Println("The Replica Service stopped selecting the Pod '" + podName + "' while the Primary is idle.")
+		}
+		return isSelected
+
+	}, 60*time.Second, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
+
+func (r *SpecReplicaServiceMaxLagTest) thenReplicaServiceShouldSelectPod(podName string) {
+	Eventually(func() bool {
+
+		isSelected, err := r.isPodSelectedByReplicaService(podName)
+		if err != nil {
+			log.Println("ERROR while retrieving the endpoints of the Replica Service: ", err)
+			return false
+		}
+
+		return isSelected
+
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
+
+func (r *SpecReplicaServiceMaxLagTest) thenReplicaServiceShouldSelectNbrePods(nbrePods int) {
+	Eventually(func() bool {
+
+		endpoints, err := r.resourceRetriever.GetEndpoints(resourceConfigs.KubegresResourceName + "-replica")
+		if err != nil {
+			log.Println("ERROR while retrieving the endpoints of the Replica Service: ", err)
+			return false
+		}
+
+		nbreAddresses := 0
+		for _, subset := range endpoints.Subsets {
+			nbreAddresses += len(subset.Addresses)
+		}
+		return nbreAddresses == nbrePods
+
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
+
+func (r *SpecReplicaServiceMaxLagTest) isPodSelectedByReplicaService(podName string) (bool, error) {
+
+	endpoints, err := r.resourceRetriever.GetEndpoints(resourceConfigs.KubegresResourceName + "-replica")
+	if err != nil {
+		return false, err
+	}
+
+	for _, subset := range endpoints.Subsets {
+		for _, address := range subset.Addresses {
+			if address.TargetRef != nil && address.TargetRef.Name == podName {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
@@ -26,7 +26,6 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
-	v12 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	postgresv1 "reactive-tech.io/kubegres/api/v1"
 	"reactive-tech.io/kubegres/test/resourceConfigs"
@@ -57,15 +56,15 @@ var _ = Describe("Setting Kubegres spec 'replica'", Label("group:5"), func() {
 
 	Context("GIVEN new Kubegres is created with spec 'replica' set to nil", func() {
 
-		It("THEN a validation error event should be logged", func() {
+		It("THEN the creation of Kubegres should be rejected", func() {
 
 			log.Print("START OF: Test 'GIVEN new Kubegres is created with spec 'replica' set to nil'")
 
 			test.givenNewKubegresSpecIsSetToNil()
 
-			test.whenKubegresIsCreated()
+			test.whenKubegresIsCreatedWithInvalidSpec()
 
-			test.thenErrorEventShouldBeLogged()
+			test.thenKubegresShouldBeRejected()
 
 			log.Print("END OF: Test 'GIVEN new Kubegres is created with spec 'replica' set to nil'")
 		})
@@ -73,15 +72,15 @@ var _ = Describe("Setting Kubegres spec 'replica'", Label("group:5"), func() {
 
 	Context("GIVEN new Kubegres is created with spec 'replica' set to 0", func() {
 
-		It("THEN a validation error event should be logged", func() {
+		It("THEN the creation of Kubegres should be rejected", func() {
 
 			log.Print("START OF: Test 'GIVEN new Kubegres is created with spec 'replica' set to 0'")
 
 			test.givenNewKubegresSpecIsSetTo(0)
 
-			test.whenKubegresIsCreated()
+			test.whenKubegresIsCreatedWithInvalidSpec()
 
-			test.thenErrorEventShouldBeLogged()
+			test.thenKubegresShouldBeRejected()
 
 			log.Print("END OF: Test 'GIVEN new Kubegres is created with spec 'replica' set to 0'")
 		})
@@ -218,6 +217,7 @@ type SpecReplicaTest struct {
 	dbQueryTestCases                testcases.DbQueryTestCases
 	resourceCreator                 util.TestResourceCreator
 	resourceRetriever               util.TestResourceRetriever
+	admissionErr                    error
 }
 
 func (r *SpecReplicaTest) givenNewKubegresSpecIsSetToNil() {
@@ -247,24 +247,17 @@ func (r *SpecReplicaTest) whenKubegresIsCreated() {
 	r.resourceCreator.CreateKubegres(r.kubegresResource)
 }
 
+func (r *SpecReplicaTest) whenKubegresIsCreatedWithInvalidSpec() {
+	r.admissionErr = r.resourceCreator.CreateKubegresWithInvalidSpec(r.kubegresResource)
+}
+
 func (r *SpecReplicaTest) whenKubernetesIsUpdated() {
 	r.resourceCreator.UpdateResource(r.kubegresResource, "Kubegres")
 }
 
-func (r *SpecReplicaTest) thenErrorEventShouldBeLogged() {
-	expectedErrorEvent := util.EventRecord{
-		Eventtype: v12.EventTypeWarning,
-		Reason:    "SpecCheckErr",
-		Message:   "In the Resources Spec the value of 'spec.replicas' is undefined. Please set a value otherwise this operator cannot work correctly.",
-	}
-	Eventually(func() bool {
-		_, err := r.resourceRetriever.GetKubegres()
-		if err != nil {
-			return false
-		}
-		return eventRecorderTest.CheckEventExist(expectedErrorEvent)
-
-	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+func (r *SpecReplicaTest) thenKubegresShouldBeRejected() {
+	Expect(r.admissionErr).Should(MatchError(ContainSubstring("In the Resources Spec the value of 'spec.replicas' is undefined. " +
+		"Please set a value otherwise this operator cannot work correctly.")))
 }
 
 func (r *SpecReplicaTest) thenPodsStatesShouldBe(nbrePrimary, nbreReplicas int) bool {
@@ -0,0 +1,261 @@
+/*
+Copyright 2021 Reactive Tech Limited.
+"Reactive Tech Limited" is a company located in England, United Kingdom.
+https://www.reactive-tech.io
+
+Lead Developer: Alex Arica
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"log"
+	"strconv"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	postgresv1 "reactive-tech.io/kubegres/api/v1"
+	"reactive-tech.io/kubegres/test/resourceConfigs"
+	"reactive-tech.io/kubegres/test/util"
+)
+
+var _ = Describe("Setting Kubegres spec 'replication.cascade'", Label("group:3"), func() {
+
+	var test = SpecReplicationCascadeTest{}
+
+	BeforeEach(func() {
+		//Skip("Temporarily skipping test")
+
+		namespace := resourceConfigs.DefaultNamespace
+		test.resourceRetriever = util.CreateTestResourceRetriever(k8sClientTest, namespace)
+		test.resourceCreator = util.CreateTestResourceCreator(k8sClientTest, test.resourceRetriever, namespace)
+		test.connectionPrimaryDb = util.InitDbConnectionDbUtil(test.resourceCreator, resourceConfigs.KubegresResourceName, resourceConfigs.ServiceToSqlQueryPrimaryDbNodePort, true)
+		test.connectionReplicaDb = util.InitDbConnectionDbUtil(test.resourceCreator, resourceConfigs.KubegresResourceName, resourceConfigs.ServiceToSqlQueryReplicaDbNodePort, false)
+	})
+
+	AfterEach(func() {
+		test.resourceCreator.DeleteAllTestResources()
+	})
+
+	Context("GIVEN new Kubegres is created with spec 'replication.cascade.enabled' set to true AND 'replication.cascade.numberOfDirectReplicas' set to 1 AND with 3 instances", func() {
+
+		It("THEN only 1 Replica should stream from the Primary AND when that Replica fails, the other Replica should stream from the Primary AND the data should be replicated", func() {
+
+			log.Print("START OF: Test 'GIVEN new Kubegres is created with spec 'replication.cascade.enabled' set to true AND 'replication.cascade.numberOfDirectReplicas' set to 1 AND with 3 instances'")
+
+			test.givenNewKubegresSpecIsSetTo(3, 1)
+
+			test.whenKubegresIsCreated()
+
+			test.thenPodsStatesShouldBe(1, 2)
+
+			test.thenLastReplicaShouldBeCopiedFromUpstreamReplica()
+
+			test.thenNbreWalSendersOnPrimaryShouldBe(1)
+
+			test.givenUserAddedInPrimaryDb()
+
+			test.thenReplicaDbContainsExpectedNbreUsers(1)
+
+			test.whenDirectReplicaIsDeleted()
+
+			test.thenPodsStatesShouldBe(1, 2)
+
+			test.thenNbreWalSendersOnPrimaryShouldBe(1)
+
+			test.givenUserAddedInPrimaryDb()
+
+			test.thenReplicaDbContainsExpectedNbreUsers(2)
+
+			log.Print("END OF: Test 'GIVEN new Kubegres is created with spec 'replication.cascade.enabled' set to true AND 'replication.cascade.numberOfDirectReplicas' set to 1 AND with 3 instances'")
+		})
+	})
+
+	Context("GIVEN Kubegres is running with spec 'replication.cascade.enabled' set to true AND it is updated to false", func() {
+
+		It("THEN all Replicas should stream from the Primary", func() {
+
+			log.Print("START OF: Test 'GIVEN Kubegres is running with spec 'replication.cascade.enabled' set to true AND it is updated to false'")
+
+			test.givenNewKubegresSpecIsSetTo(3, 1)
+
+			test.whenKubegresIsCreated()
+
+			test.thenPodsStatesShouldBe(1, 2)
+
+			test.thenNbreWalSendersOnPrimaryShouldBe(1)
+
+			test.givenExistingKubegresSpecIsSetTo(false)
+
+			test.whenKubegresIsUpdated()
+
+			test.thenNbreWalSendersOnPrimaryShouldBe(2)
+
+			log.Print("END OF: Test 'GIVEN Kubegres is running with spec 'replication.cascade.enabled' set to true AND it is updated to false'")
+		})
+	})
+})
+
+type SpecReplicationCascadeTest struct {
+	kubegresResource    *postgresv1.Kubegres
+	connectionPrimaryDb util.DbConnectionDbUtil
+	connectionReplicaDb util.DbConnectionDbUtil
+	resourceCreator     util.TestResourceCreator
+	resourceRetriever   util.TestResourceRetriever
+}
+
+func (r *SpecReplicationCascadeTest) givenNewKubegresSpecIsSetTo(specNbreReplicas, numberOfDirectReplicas int32) {
+	r.kubegresResource = resourceConfigs.LoadKubegresYaml()
+	r.kubegresResource.Spec.Replicas = &specNbreReplicas
+	r.kubegresResource.Spec.Replication.Cascade.Enabled = true
+	r.kubegresResource.Spec.Replication.Cascade.NumberOfDirectReplicas = &numberOfDirectReplicas
+}
+
+func (r *SpecReplicationCascadeTest) givenExistingKubegresSpecIsSetTo(isCascadeEnabled bool) {
+	var err error
+	r.kubegresResource, err = r.resourceRetriever.GetKubegres()
+
+	if err != nil {
+		log.Println("Error while getting Kubegres resource : ", err)
+		Expect(err).Should(Succeed())
+		return
+	}
+
+	r.kubegresResource.Spec.Replication.Cascade.Enabled = isCascadeEnabled
+}
+
+func (r *SpecReplicationCascadeTest) givenUserAddedInPrimaryDb() {
+	Eventually(func() bool {
+		return r.connectionPrimaryDb.InsertUser()
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
+
+func (r *SpecReplicationCascadeTest) whenKubegresIsCreated() {
+	r.resourceCreator.CreateKubegres(r.kubegresResource)
+}
+
+func (r *SpecReplicationCascadeTest) whenKubegresIsUpdated() {
+	r.resourceCreator.UpdateResource(r.kubegresResource, "Kubegres")
+}
+
+// whenDirectReplicaIsDeleted deletes the Replica with the lowest instance index, which streams from the Primary.
+func (r *SpecReplicationCascadeTest) whenDirectReplicaIsDeleted() {
+	directReplica, isFound := r.getReplicaByInstanceIndexOrder(true)
+	Expect(isFound).Should(BeTrue())
+
+	log.Println("Attempting to delete StatefulSet: '" + directReplica.StatefulSet.Name + "'")
+	Expect(r.resourceCreator.DeleteResource(directReplica.StatefulSet.Resource, directReplica.StatefulSet.Name)).Should(BeTrue())
+	time.Sleep(5 * time.Second)
+}
+
+func (r *SpecReplicationCascadeTest) thenPodsStatesShouldBe(nbrePrimary, nbreReplicas int) bool {
+	return Eventually(func() bool {
+
+		kubegresResources, err := r.resourceRetriever.GetKubegresResources()
+		if err != nil && !apierrors.IsNotFound(err) {
+			log.Println("ERROR while retrieving Kubegres kubegresResources")
+			return false
+		}
+
+		if kubegresResources.AreAllReady &&
+			kubegresResources.NbreDeployedPrimary == nbrePrimary &&
+			kubegresResources.NbreDeployedReplicas == nbreReplicas {
+
+			time.Sleep(resourceConfigs.TestRetryInterval)
+			log.Println("Deployed and Ready StatefulSets check successful")
+			return true
+		}
+
+		return false
+
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
+
+// thenLastReplicaShouldBeCopiedFromUpstreamReplica checks that the Replica with the highest instance index was
+// created with the host name of the Pod of the direct Replica as upstream.
+func (r *SpecReplicationCascadeTest) thenLastReplicaShouldBeCopiedFromUpstreamReplica() {
+	directReplica, isFound := r.getReplicaByInstanceIndexOrder(true)
+	Expect(isFound).Should(BeTrue())
+
+	lastReplica, isFound := r.getReplicaByInstanceIndexOrder(false)
+	Expect(isFound).Should(BeTrue())
+
+	upstreamHostName := ""
+	for _, envVar := range lastReplica.StatefulSet.Spec.Template.Spec.InitContainers[0].Env {
+		if envVar.Name == "UPSTREAM_HOST_NAME" {
+			upstreamHostName = envVar.Value
+		}
+	}
+
+	Expect(upstreamHostName).Should(Equal(directReplica.Pod.Name + "." + resourceConfigs.KubegresResourceName + "-replica"))
+}
+
+func (r *SpecReplicationCascadeTest) thenNbreWalSendersOnPrimaryShouldBe(expectedNbreWalSenders int) {
+	Eventually(func() bool {
+
+		nbreWalSenders := r.connectionPrimaryDb.GetNbreWalSenders()
+		r.connectionPrimaryDb.Close()
+
+		if nbreWalSenders != expectedNbreWalSenders {
+			log.Println("Primary DB does not have the expected number of WAL senders. Expected: " + strconv.Itoa(expectedNbreWalSenders) + " Given: " + strconv.Itoa(nbreWalSenders))
+			return false
+		}
+
+		return true
+
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
+
+func (r *SpecReplicationCascadeTest) thenReplicaDbContainsExpectedNbreUsers(expectedNbreUsers int) {
+	Eventually(func() bool {
+
+		users := r.connectionReplicaDb.GetUsers()
+		r.connectionReplicaDb.Close()
+
+		if len(users) != expectedNbreUsers {
+			log.Println("Replica DB does not contain the expected number of users. Expected: " + strconv.Itoa(expectedNbreUsers) + " Given: " + strconv.Itoa(len(users)))
+			return false
+		}
+
+		return true
+
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
+
+// getReplicaByInstanceIndexOrder returns the Replica with either the lowest or the highest instance index.
+func (r *SpecReplicationCascadeTest) getReplicaByInstanceIndexOrder(isLowest bool) (util.TestKubegresResource, bool) {
+	kubegresResources, err := r.resourceRetriever.GetKubegresResources()
+	Expect(err).Should(Succeed())
+
+	var selectedReplica util.TestKubegresResource
+	selectedInstanceIndex, isFound := 0, false
+
+	for _, kubegresResource := range kubegresResources.Resources {
+		if kubegresResource.IsPrimary {
+			continue
+		}
+
+		instanceIndex, err := strconv.Atoi(kubegresResource.StatefulSet.Metadata.Labels["index"])
+		Expect(err).Should(Succeed())
+
+		if !isFound || (isLowest && instanceIndex < selectedInstanceIndex) || (!isLowest && instanceIndex > selectedInstanceIndex) {
+			selectedReplica, selectedInstanceIndex, isFound = kubegresResource, instanceIndex, true
+		}
+	}
+
+	return selectedReplica, isFound
+}
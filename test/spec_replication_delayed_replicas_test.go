@@ -0,0 +1,239 @@
+/*
+Copyright 2021 Reactive Tech Limited.
+"Reactive Tech Limited" is a company located in England, United Kingdom.
+https://www.reactive-tech.io
+
+Lead Developer: Alex Arica
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"log"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	postgresv1 "reactive-tech.io/kubegres/api/v1"
+	"reactive-tech.io/kubegres/controllers/ctx"
+	"reactive-tech.io/kubegres/test/resourceConfigs"
+	"reactive-tech.io/kubegres/test/util"
+)
+
+var _ = Describe("Setting Kubegres spec 'replication.delayedReplicas'", Label("group:3"), func() {
+
+	var test = SpecReplicationDelayedReplicasTest{}
+
+	BeforeEach(func() {
+		//Skip("Temporarily skipping test")
+
+		namespace := resourceConfigs.DefaultNamespace
+		test.resourceRetriever = util.CreateTestResourceRetriever(k8sClientTest, namespace)
+		test.resourceCreator = util.CreateTestResourceCreator(k8sClientTest, test.resourceRetriever, namespace)
+	})
+
+	AfterEach(func() {
+		test.resourceCreator.DeleteAllTestResources()
+	})
+
+	Context("GIVEN new Kubegres is created with spec 'replicas' set to 2 AND 'replication.delayedReplicas' with 1 delayed Replica", func() {
+
+		It("THEN the delayed Replica should be deployed AND excluded from the Replica Service AND it should not be promoted when the Primary fails", func() {
+
+			log.Print("START OF: Test 'GIVEN new Kubegres is created with spec 'replicas' set to 2 AND 'replication.delayedReplicas' with 1 delayed Replica'")
+
+			test.givenNewKubegresSpecIsSetTo(2, "1h")
+
+			test.whenKubegresIsCreated()
+
+			test.thenPodsStatesShouldBe(1, 2)
+
+			delayedReplicaPodName := test.thenStatusInstanceWithRoleShouldExist(ctx.DelayedReplicaRoleName)
+
+			test.thenReplicaServiceShouldNotSelectPod(delayedReplicaPodName)
+
+			test.whenPrimaryIsDeleted()
+
+			test.thenPodsStatesShouldBe(1, 2)
+
+			test.thenPrimaryPodShouldNotBe(delayedReplicaPodName)
+
+			test.thenStatusInstanceWithRoleShouldExist(ctx.DelayedReplicaRoleName)
+
+			log.Print("END OF: Test 'GIVEN new Kubegres is created with spec 'replicas' set to 2 AND 'replication.delayedReplicas' with 1 delayed Replica'")
+		})
+	})
+
+	Context("GIVEN Kubegres is running with 1 delayed Replica AND 'replication.delayedReplicas' is updated to be empty", func() {
+
+		It("THEN the delayed Replica should be undeployed", func() {
+
+			log.Print("START OF: Test 'GIVEN Kubegres is running with 1 delayed Replica AND 'replication.delayedReplicas' is updated to be empty'")
+
+			test.givenNewKubegresSpecIsSetTo(2, "1h")
+
+			test.whenKubegresIsCreated()
+
+			test.thenPodsStatesShouldBe(1, 2)
+
+			test.givenExistingKubegresSpecIsSetWithoutDelayedReplicas()
+
+			test.whenKubegresIsUpdated()
+
+			test.thenPodsStatesShouldBe(1, 1)
+
+			log.Print("END OF: Test 'GIVEN Kubegres is running with 1 delayed Replica AND 'replication.delayedReplicas' is updated to be empty'")
+		})
+	})
+})
+
+type SpecReplicationDelayedReplicasTest struct {
+	kubegresResource  *postgresv1.Kubegres
+	resourceCreator   util.TestResourceCreator
+	resourceRetriever util.TestResourceRetriever
+}
+
+func (r *SpecReplicationDelayedReplicasTest) givenNewKubegresSpecIsSetTo(specNbreReplicas int32, delay string) {
+	r.kubegresResource = resourceConfigs.LoadKubegresYaml()
+	r.kubegresResource.Spec.Replicas = &specNbreReplicas
+	r.kubegresResource.Spec.Replication.DelayedReplicas = []postgresv1.KubegresDelayedReplica{{Delay: delay}}
+}
+
+func (r *SpecReplicationDelayedReplicasTest) givenExistingKubegresSpecIsSetWithoutDelayedReplicas() {
+	var err error
+	r.kubegresResource, err = r.resourceRetriever.GetKubegres()
+
+	if err != nil {
+		log.Println("Error while getting Kubegres resource : ", err)
+		Expect(err).Should(Succeed())
+		return
+	}
+
+	r.kubegresResource.Spec.Replication.DelayedReplicas = nil
+}
+
+func (r *SpecReplicationDelayedReplicasTest) whenKubegresIsCreated() {
+	r.resourceCreator.CreateKubegres(r.kubegresResource)
+}
+
+func (r *SpecReplicationDelayedReplicasTest) whenKubegresIsUpdated() {
+	r.resourceCreator.UpdateResource(r.kubegresResource, "Kubegres")
+}
+
+func (r *SpecReplicationDelayedReplicasTest) whenPrimaryIsDeleted() {
+	kubegresResources, err := r.resourceRetriever.GetKubegresResources()
+	Expect(err).Should(Succeed())
+
+	for _, kubegresResource := range kubegresResources.Resources {
+		if kubegresResource.IsPrimary {
+			log.Println("Attempting to delete StatefulSet: '" + kubegresResource.StatefulSet.Name + "'")
+			Expect(r.resourceCreator.DeleteResource(kubegresResource.StatefulSet.Resource, kubegresResource.StatefulSet.Name)).Should(BeTrue())
+			time.Sleep(5 * time.Second)
+			return
+		}
+	}
+
+	Fail("The Primary is not deployed")
+}
+
+func (r *SpecReplicationDelayedReplicasTest) thenPodsStatesShouldBe(nbrePrimary, nbreReplicas int) bool {
+	return Eventually(func() bool {
+
+		kubegresResources, err := r.resourceRetriever.GetKubegresResources()
+		if err != nil && !apierrors.IsNotFound(err) {
+			log.Println("ERROR while retrieving Kubegres kubegresResources")
+			return false
+		}
+
+		if kubegresResources.AreAllReady &&
+			kubegresResources.NbreDeployedPrimary == nbrePrimary &&
+			kubegresResources.NbreDeployedReplicas == nbreReplicas {
+
+			time.Sleep(resourceConfigs.TestRetryInterval)
+			log.Println("Deployed and Ready StatefulSets check successful")
+			return true
+		}
+
+		return false
+
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
+
+// thenStatusInstanceWithRoleShouldExist returns the name of the Pod of the instance with the given role in the status.
+func (r *SpecReplicationDelayedReplicasTest) thenStatusInstanceWithRoleShouldExist(role string) string {
+	podName := ""
+
+	Eventually(func() bool {
+
+		kubegres, err := r.resourceRetriever.GetKubegres()
+		if err != nil {
+			log.Println("ERROR while retrieving Kubegres resource: ", err)
+			return false
+		}
+
+		for _, instance := range kubegres.Status.Instances {
+			if instance.Role == role && instance.IsReady {
+				podName = instance.Pod
+				return true
+			}
+		}
+
+		log.Println("Waiting for the status to contain a ready instance with the role '" + role + "'.")
+		return false
+
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+
+	return podName
+}
+
+func (r *SpecReplicationDelayedReplicasTest) thenReplicaServiceShouldNotSelectPod(podName string) {
+	Eventually(func() bool {
+
+		endpoints, err := r.resourceRetriever.GetEndpoints(resourceConfigs.KubegresResourceName + "-replica")
+		if err != nil {
+			log.Println("ERROR while retrieving the endpoints of the Replica Service: ", err)
+			return false
+		}
+
+		nbreAddresses := 0
+		for _, subset := range endpoints.Subsets {
+			for _, address := range subset.Addresses {
+				if address.TargetRef != nil && address.TargetRef.Name == podName {
+					log.Println("The Replica Service selects the Pod '" + podName + "'.")
+					return false
+				}
+				nbreAddresses++
+			}
+		}
+
+		return nbreAddresses == 1
+
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
+
+func (r *SpecReplicationDelayedReplicasTest) thenPrimaryPodShouldNotBe(podName string) {
+	kubegresResources, err := r.resourceRetriever.GetKubegresResources()
+	Expect(err).Should(Succeed())
+
+	for _, kubegresResource := range kubegresResources.Resources {
+		if kubegresResource.IsPrimary {
+			Expect(kubegresResource.Pod.Name).ShouldNot(Equal(podName))
+			return
+		}
+	}
+
+	Fail("The Primary is not deployed")
+}
@@ -0,0 +1,255 @@
+/*
+Copyright 2021 Reactive Tech Limited.
+"Reactive Tech Limited" is a company located in England, United Kingdom.
+https://www.reactive-tech.io
+
+Lead Developer: Alex Arica
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"log"
+	"strconv"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	postgresv1 "reactive-tech.io/kubegres/api/v1"
+	"reactive-tech.io/kubegres/test/resourceConfigs"
+	"reactive-tech.io/kubegres/test/util"
+)
+
+var _ = Describe("Setting Kubegres spec 'replication.mode' to 'synchronous'", Label("group:3"), func() {
+
+	var test = SpecReplicationModeTest{}
+
+	BeforeEach(func() {
+		//Skip("Temporarily skipping test")
+
+		namespace := resourceConfigs.DefaultNamespace
+		test.resourceRetriever = util.CreateTestResourceRetriever(k8sClientTest, namespace)
+		test.resourceCreator = util.CreateTestResourceCreator(k8sClientTest, test.resourceRetriever, namespace)
+		test.connectionPrimaryDb = util.InitDbConnectionDbUtil(test.resourceCreator, resourceConfigs.KubegresResourceName, resourceConfigs.ServiceToSqlQueryPrimaryDbNodePort, true)
+	})
+
+	AfterEach(func() {
+		test.resourceCreator.DeleteAllTestResources()
+	})
+
+	Context("GIVEN new Kubegres is created with spec 'replication.mode' set to 'synchronous' AND 'replication.numberOfSyncStandbys' set to 1 AND with 3 instances", func() {
+
+		It("THEN 1 Replica should be synchronous AND when the Primary fails, the synchronous Replica should be promoted AND no data should be lost", func() {
+
+			log.Print("START OF: Test 'GIVEN new Kubegres is created with spec 'replication.mode' set to 'synchronous' AND 'replication.numberOfSyncStandbys' set to 1 AND with 3 instances'")
+
+			test.givenNewKubegresSpecIsSetTo(3, 1)
+
+			test.whenKubegresIsCreated()
+
+			test.thenPodsStatesShouldBe(1, 2)
+
+			synchronousStandby := test.thenStatusSynchronousStandbysShouldContain(1)
+
+			expectedNbreUsers := 0
+
+			test.givenUserAddedInPrimaryDb()
+			expectedNbreUsers++
+
+			test.whenPrimaryPodIsDeleted()
+
+			test.thenPodsStatesShouldBe(1, 2)
+
+			test.thenPrimaryPodShouldBe(synchronousStandby + "-0")
+
+			test.thenPrimaryDbContainsExpectedNbreUsers(expectedNbreUsers)
+
+			log.Print("END OF: Test 'GIVEN new Kubegres is created with spec 'replication.mode' set to 'synchronous' AND 'replication.numberOfSyncStandbys' set to 1 AND with 3 instances'")
+		})
+	})
+
+	Context("GIVEN Kubegres is running with spec 'replication.mode' set to 'synchronous' AND it is updated to 'asynchronous'", func() {
+
+		It("THEN the status should not contain any synchronous Replica", func() {
+
+			log.Print("START OF: Test 'GIVEN Kubegres is running with spec 'replication.mode' set to 'synchronous' AND it is updated to 'asynchronous''")
+
+			test.givenNewKubegresSpecIsSetTo(3, 2)
+
+			test.whenKubegresIsCreated()
+
+			test.thenPodsStatesShouldBe(1, 2)
+
+			test.thenStatusSynchronousStandbysShouldContain(2)
+
+			test.givenExistingKubegresSpecIsSetTo(postgresv1.ReplicationModeAsynchronous)
+
+			test.whenKubegresIsUpdated()
+
+			test.thenStatusSynchronousStandbysShouldContain(0)
+
+			test.givenUserAddedInPrimaryDb()
+
+			test.thenPrimaryDbContainsExpectedNbreUsers(1)
+
+			log.Print("END OF: Test 'GIVEN Kubegres is running with spec 'replication.mode' set to 'synchronous' AND it is updated to 'asynchronous''")
+		})
+	})
+})
+
+type SpecReplicationModeTest struct {
+	kubegresResource    *postgresv1.Kubegres
+	connectionPrimaryDb util.DbConnectionDbUtil
+	resourceCreator     util.TestResourceCreator
+	resourceRetriever   util.TestResourceRetriever
+}
+
+func (r *SpecReplicationModeTest) givenNewKubegresSpecIsSetTo(specNbreReplicas, numberOfSyncStandbys int32) {
+	r.kubegresResource = resourceConfigs.LoadKubegresYaml()
+	r.kubegresResource.Spec.Replicas = &specNbreReplicas
+	r.kubegresResource.Spec.Replication.Mode = postgresv1.ReplicationModeSynchronous
+	r.kubegresResource.Spec.Replication.NumberOfSyncStandbys = &numberOfSyncStandbys
+}
+
+func (r *SpecReplicationModeTest) givenExistingKubegresSpecIsSetTo(replicationMode string) {
+	var err error
+	r.kubegresResource, err = r.resourceRetriever.GetKubegres()
+
+	if err != nil {
+		log.Println("Error while getting Kubegres resource : ", err)
+		Expect(err).Should(Succeed())
+		return
+	}
+
+	r.kubegresResource.Spec.Replication.Mode = replicationMode
+}
+
+func (r *SpecReplicationModeTest) givenUserAddedInPrimaryDb() {
+	Eventually(func() bool {
+		return r.connectionPrimaryDb.InsertUser()
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
+
+func (r *SpecReplicationModeTest) whenKubegresIsCreated() {
+	r.resourceCreator.CreateKubegres(r.kubegresResource)
+}
+
+func (r *SpecReplicationModeTest) whenKubegresIsUpdated() {
+	r.resourceCreator.UpdateResource(r.kubegresResource, "Kubegres")
+}
+
+func (r *SpecReplicationModeTest) whenPrimaryPodIsDeleted() {
+	kubegresResources, err := r.resourceRetriever.GetKubegresResources()
+	if err != nil {
+		Expect(err).Should(Succeed())
+		return
+	}
+
+	nbreDeleted := 0
+	for _, kubegresResource := range kubegresResources.Resources {
+		if kubegresResource.IsPrimary {
+			log.Println("Attempting to delete StatefulSet: '" + kubegresResource.StatefulSet.Name + "'")
+			if r.resourceCreator.DeleteResource(kubegresResource.StatefulSet.Resource, kubegresResource.StatefulSet.Name) {
+				nbreDeleted++
+				time.Sleep(5 * time.Second)
+			}
+		}
+	}
+
+	Expect(nbreDeleted).Should(Equal(1))
+}
+
+func (r *SpecReplicationModeTest) thenPodsStatesShouldBe(nbrePrimary, nbreReplicas int) bool {
+	return Eventually(func() bool {
+
+		kubegresResources, err := r.resourceRetriever.GetKubegresResources()
+		if err != nil && !apierrors.IsNotFound(err) {
+			log.Println("ERROR while retrieving Kubegres kubegresResources")
+			return false
+		}
+
+		if kubegresResources.AreAllReady &&
+			kubegresResources.NbreDeployedPrimary == nbrePrimary &&
+			kubegresResources.NbreDeployedReplicas == nbreReplicas {
+
+			time.Sleep(resourceConfigs.TestRetryInterval)
+			log.Println("Deployed and Ready StatefulSets check successful")
+			return true
+		}
+
+		return false
+
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
+
+// thenStatusSynchronousStandbysShouldContain returns the first synchronous Replica in the status.
+func (r *SpecReplicationModeTest) thenStatusSynchronousStandbysShouldContain(nbreSynchronousStandbys int) string {
+	synchronousStandby := ""
+
+	Eventually(func() bool {
+
+		kubegres, err := r.resourceRetriever.GetKubegres()
+		if err != nil {
+			log.Println("ERROR while retrieving Kubegres resource: ", err)
+			return false
+		}
+
+		synchronousStandbys := kubegres.Status.SynchronousStandbys
+		if len(synchronousStandbys) != nbreSynchronousStandbys {
+			log.Println("Waiting for the status to contain " + strconv.Itoa(nbreSynchronousStandbys) + " synchronous Replicas. " +
+				"Given: " + strconv.Itoa(len(synchronousStandbys)))
+			return false
+		}
+
+		if nbreSynchronousStandbys > 0 {
+			synchronousStandby = synchronousStandbys[0]
+		}
+		return true
+
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+
+	return synchronousStandby
+}
+
+func (r *SpecReplicationModeTest) thenPrimaryPodShouldBe(expectedPrimaryPodName string) {
+	kubegresResources, err := r.resourceRetriever.GetKubegresResources()
+	Expect(err).Should(Succeed())
+
+	for _, kubegresResource := range kubegresResources.Resources {
+		if kubegresResource.IsPrimary {
+			Expect(kubegresResource.Pod.Name).Should(Equal(expectedPrimaryPodName))
+			return
+		}
+	}
+
+	Fail("The Primary is not deployed")
+}
+
+func (r *SpecReplicationModeTest) thenPrimaryDbContainsExpectedNbreUsers(expectedNbreUsers int) {
+	Eventually(func() bool {
+
+		users := r.connectionPrimaryDb.GetUsers()
+		r.connectionPrimaryDb.Close()
+
+		if len(users) != expectedNbreUsers {
+			log.Println("Primary DB does not contain the expected number of users. Expected: " + strconv.Itoa(expectedNbreUsers) + " Given: " + strconv.Itoa(len(users)))
+			return false
+		}
+
+		return true
+
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
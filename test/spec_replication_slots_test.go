@@ -0,0 +1,195 @@
+/*
+Copyright 2021 Reactive Tech Limited.
+"Reactive Tech Limited" is a company located in England, United Kingdom.
+https://www.reactive-tech.io
+
+Lead Developer: Alex Arica
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"log"
+	"reflect"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	postgresv1 "reactive-tech.io/kubegres/api/v1"
+	"reactive-tech.io/kubegres/test/resourceConfigs"
+	"reactive-tech.io/kubegres/test/util"
+)
+
+var _ = Describe("Setting Kubegres spec 'replication.slots'", Label("group:3"), func() {
+
+	var test = SpecReplicationSlotsTest{}
+
+	BeforeEach(func() {
+		//Skip("Temporarily skipping test")
+
+		namespace := resourceConfigs.DefaultNamespace
+		test.resourceRetriever = util.CreateTestResourceRetriever(k8sClientTest, namespace)
+		test.resourceCreator = util.CreateTestResourceCreator(k8sClientTest, test.resourceRetriever, namespace)
+		test.connectionPrimaryDb = util.InitDbConnectionDbUtil(test.resourceCreator, resourceConfigs.KubegresResourceName, resourceConfigs.ServiceToSqlQueryPrimaryDbNodePort, true)
+	})
+
+	AfterEach(func() {
+		test.resourceCreator.DeleteAllTestResources()
+	})
+
+	Context("GIVEN new Kubegres is created with spec 'replication.slots.enabled' set to true AND with 3 instances", func() {
+
+		It("THEN each Replica should stream with its slot on the Primary AND the slot of an undeployed Replica should be dropped", func() {
+
+			log.Print("START OF: Test 'GIVEN new Kubegres is created with spec 'replication.slots.enabled' set to true AND with 3 instances'")
+
+			test.givenNewKubegresSpecIsSetTo(3, "200Mi")
+
+			test.whenKubegresIsCreated()
+
+			test.thenPodsStatesShouldBe(1, 2)
+
+			test.thenPrimaryReplicationSlotsShouldBe(map[string]bool{"kubegres_instance_2": true, "kubegres_instance_3": true})
+
+			test.thenPrimaryMaxSlotWalKeepSizeShouldBe("200MB")
+
+			test.givenExistingKubegresSpecIsSetTo(2, true)
+
+			test.whenKubegresIsUpdated()
+
+			test.thenPodsStatesShouldBe(1, 1)
+
+			test.thenPrimaryReplicationSlotsShouldBe(map[string]bool{"kubegres_instance_2": true})
+
+			log.Print("END OF: Test 'GIVEN new Kubegres is created with spec 'replication.slots.enabled' set to true AND with 3 instances'")
+		})
+	})
+
+	Context("GIVEN Kubegres is running with spec 'replication.slots.enabled' set to true AND it is updated to false", func() {
+
+		It("THEN the replication slots should be dropped", func() {
+
+			log.Print("START OF: Test 'GIVEN Kubegres is running with spec 'replication.slots.enabled' set to true AND it is updated to false'")
+
+			test.givenNewKubegresSpecIsSetTo(3, "")
+
+			test.whenKubegresIsCreated()
+
+			test.thenPodsStatesShouldBe(1, 2)
+
+			test.thenPrimaryReplicationSlotsShouldBe(map[string]bool{"kubegres_instance_2": true, "kubegres_instance_3": true})
+
+			test.givenExistingKubegresSpecIsSetTo(3, false)
+
+			test.whenKubegresIsUpdated()
+
+			test.thenPrimaryReplicationSlotsShouldBe(map[string]bool{})
+
+			log.Print("END OF: Test 'GIVEN Kubegres is running with spec 'replication.slots.enabled' set to true AND it is updated to false'")
+		})
+	})
+})
+
+type SpecReplicationSlotsTest struct {
+	kubegresResource    *postgresv1.Kubegres
+	connectionPrimaryDb util.DbConnectionDbUtil
+	resourceCreator     util.TestResourceCreator
+	resourceRetriever   util.TestResourceRetriever
+}
+
+func (r *SpecReplicationSlotsTest) givenNewKubegresSpecIsSetTo(specNbreReplicas int32, maxSlotWalKeepSize string) {
+	r.kubegresResource = resourceConfigs.LoadKubegresYaml()
+	r.kubegresResource.Spec.Replicas = &specNbreReplicas
+	r.kubegresResource.Spec.Replication.Slots.Enabled = true
+	r.kubegresResource.Spec.Replication.Slots.MaxSlotWalKeepSize = maxSlotWalKeepSize
+}
+
+func (r *SpecReplicationSlotsTest) givenExistingKubegresSpecIsSetTo(specNbreReplicas int32, areSlotsEnabled bool) {
+	var err error
+	r.kubegresResource, err = r.resourceRetriever.GetKubegres()
+
+	if err != nil {
+		log.Println("Error while getting Kubegres resource : ", err)
+		Expect(err).Should(Succeed())
+		return
+	}
+
+	r.kubegresResource.Spec.Replicas = &specNbreReplicas
+	r.kubegresResource.Spec.Replication.Slots.Enabled = areSlotsEnabled
+}
+
+func (r *SpecReplicationSlotsTest) whenKubegresIsCreated() {
+	r.resourceCreator.CreateKubegres(r.kubegresResource)
+}
+
+func (r *SpecReplicationSlotsTest) whenKubegresIsUpdated() {
+	r.resourceCreator.UpdateResource(r.kubegresResource, "Kubegres")
+}
+
+func (r *SpecReplicationSlotsTest) thenPodsStatesShouldBe(nbrePrimary, nbreReplicas int) bool {
+	return Eventually(func() bool {
+
+		kubegresResources, err := r.resourceRetriever.GetKubegresResources()
+		if err != nil && !apierrors.IsNotFound(err) {
+			log.Println("ERROR while retrieving Kubegres kubegresResources")
+			return false
+		}
+
+		if kubegresResources.AreAllReady &&
+			kubegresResources.NbreDeployedPrimary == nbrePrimary &&
+			kubegresResources.NbreDeployedReplicas == nbreReplicas {
+
+			time.Sleep(resourceConfigs.TestRetryInterval)
+			log.Println("Deployed and Ready StatefulSets check successful")
+			return true
+		}
+
+		return false
+
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
+
+func (r *SpecReplicationSlotsTest) thenPrimaryReplicationSlotsShouldBe(expectedReplicationSlots map[string]bool) {
+	Eventually(func() bool {
+
+		replicationSlots := r.connectionPrimaryDb.GetReplicationSlots()
+		r.connectionPrimaryDb.Close()
+
+		if replicationSlots == nil || !reflect.DeepEqual(replicationSlots, expectedReplicationSlots) {
+			log.Println("Primary DB does not have the expected replication slots. Expected: ", expectedReplicationSlots, " Given: ", replicationSlots)
+			return false
+		}
+
+		return true
+
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
+
+func (r *SpecReplicationSlotsTest) thenPrimaryMaxSlotWalKeepSizeShouldBe(expectedSize string) {
+	Eventually(func() bool {
+
+		size := r.connectionPrimaryDb.ShowSetting("max_slot_wal_keep_size")
+		r.connectionPrimaryDb.Close()
+
+		if size != expectedSize {
+			log.Println("Primary DB does not have the expected 'max_slot_wal_keep_size'. Expected: " + expectedSize + " Given: " + size)
+			return false
+		}
+
+		return true
+
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
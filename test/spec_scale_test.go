@@ -0,0 +1,163 @@
+/*
+Copyright 2021 Reactive Tech Limited.
+"Reactive Tech Limited" is a company located in England, United Kingdom.
+https://www.reactive-tech.io
+
+Lead Developer: Alex Arica
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"context"
+	"log"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+	postgresv1 "reactive-tech.io/kubegres/api/v1"
+	"reactive-tech.io/kubegres/test/resourceConfigs"
+	"reactive-tech.io/kubegres/test/util"
+)
+
+var _ = Describe("Scaling Kubegres with the scale subresource", Label("group:1"), func() {
+
+	var test = SpecScaleTest{}
+
+	BeforeEach(func() {
+		//Skip("Temporarily skipping test")
+
+		namespace := resourceConfigs.DefaultNamespace
+		test.resourceRetriever = util.CreateTestResourceRetriever(k8sClientTest, namespace)
+		test.resourceCreator = util.CreateTestResourceCreator(k8sClientTest, test.resourceRetriever, namespace)
+	})
+
+	AfterEach(func() {
+		if !test.keepCreatedResourcesForNextTest {
+			test.resourceCreator.DeleteAllTestResources()
+		} else {
+			test.keepCreatedResourcesForNextTest = false
+		}
+	})
+
+	Context("GIVEN new Kubegres is created with spec 'replica' set to 2 and later it is scaled to 3", func() {
+
+		It("GIVEN new Kubegres is created with spec 'replica' set to 2 THEN the status should contain 2 replicas and the Pods selector", func() {
+
+			log.Print("START OF: Test 'GIVEN new Kubegres is created with spec 'replica' set to 2'")
+
+			test.givenNewKubegresSpecIsSetTo(2)
+
+			test.whenKubegresIsCreated()
+
+			test.thenPodsStatesShouldBe(1, 1)
+
+			test.thenStatusScaleShouldBe(2)
+
+			test.keepCreatedResourcesForNextTest = true
+
+			log.Print("END OF: Test 'GIVEN new Kubegres is created with spec 'replica' set to 2'")
+		})
+
+		It("GIVEN existing Kubegres is scaled from 2 to 3 with the scale subresource THEN 1 more replica should be created", func() {
+
+			log.Print("START OF: Test 'GIVEN existing Kubegres is scaled from 2 to 3'")
+
+			test.whenKubegresIsScaledTo(3)
+
+			test.thenPodsStatesShouldBe(1, 2)
+
+			test.thenStatusScaleShouldBe(3)
+
+			log.Print("END OF: Test 'GIVEN existing Kubegres is scaled from 2 to 3'")
+		})
+	})
+
+})
+
+type SpecScaleTest struct {
+	keepCreatedResourcesForNextTest bool
+	kubegresResource                *postgresv1.Kubegres
+	resourceCreator                 util.TestResourceCreator
+	resourceRetriever               util.TestResourceRetriever
+}
+
+func (r *SpecScaleTest) givenNewKubegresSpecIsSetTo(specNbreReplicas int32) {
+	r.kubegresResource = resourceConfigs.LoadKubegresYaml()
+	r.kubegresResource.Spec.Replicas = &specNbreReplicas
+}
+
+func (r *SpecScaleTest) whenKubegresIsCreated() {
+	r.resourceCreator.CreateKubegres(r.kubegresResource)
+}
+
+func (r *SpecScaleTest) whenKubegresIsScaledTo(nbreReplicas int32) {
+
+	dynamicClient, err := dynamic.NewForConfig(cfgTest)
+	Expect(err).Should(Succeed())
+
+	kubegresResource := dynamicClient.Resource(postgresv1.GroupVersion.WithResource("kubegres")).Namespace(resourceConfigs.DefaultNamespace)
+
+	scale, err := kubegresResource.Get(context.Background(), resourceConfigs.KubegresResourceName, metav1.GetOptions{}, "scale")
+	Expect(err).Should(Succeed())
+
+	err = unstructured.SetNestedField(scale.Object, int64(nbreReplicas), "spec", "replicas")
+	Expect(err).Should(Succeed())
+
+	_, err = kubegresResource.Update(context.Background(), scale, metav1.UpdateOptions{}, "scale")
+	Expect(err).Should(Succeed())
+}
+
+func (r *SpecScaleTest) thenPodsStatesShouldBe(nbrePrimary, nbreReplicas int) bool {
+	return Eventually(func() bool {
+
+		pods, err := r.resourceRetriever.GetKubegresResources()
+		if err != nil && !apierrors.IsNotFound(err) {
+			log.Println("ERROR while retrieving Kubegres pods")
+			return false
+		}
+
+		if pods.AreAllReady &&
+			pods.NbreDeployedPrimary == nbrePrimary &&
+			pods.NbreDeployedReplicas == nbreReplicas {
+
+			time.Sleep(resourceConfigs.TestRetryInterval)
+			log.Println("Deployed and Ready StatefulSets check successful")
+			return true
+		}
+
+		return false
+
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
+
+func (r *SpecScaleTest) thenStatusScaleShouldBe(nbreReplicas int32) {
+	Eventually(func() bool {
+
+		kubegres, err := r.resourceRetriever.GetKubegres()
+		if err != nil {
+			log.Println("ERROR while retrieving Kubegres resource: ", err)
+			return false
+		}
+
+		return kubegres.Status.Replicas == nbreReplicas &&
+			kubegres.Status.Selector == "app="+resourceConfigs.KubegresResourceName
+
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
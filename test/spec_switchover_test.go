@@ -0,0 +1,410 @@
+/*
+Copyright 2021 Reactive Tech Limited.
+"Reactive Tech Limited" is a company located in England, United Kingdom.
+https://www.reactive-tech.io
+
+Lead Developer: Alex Arica
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v12 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	postgresv1 "reactive-tech.io/kubegres/api/v1"
+	"reactive-tech.io/kubegres/test/resourceConfigs"
+	"reactive-tech.io/kubegres/test/util"
+)
+
+var _ = Describe("Switchover is requested by setting 'failover.switchoverPod' THEN the Replica should be promoted and the former Primary should rejoin as a Replica", Label("group:4"), func() {
+
+	var test = SpecSwitchoverTest{}
+
+	BeforeEach(func() {
+		//Skip("Temporarily skipping test")
+
+		namespace := resourceConfigs.DefaultNamespace
+		test.resourceRetriever = util.CreateTestResourceRetriever(k8sClientTest, namespace)
+		test.resourceCreator = util.CreateTestResourceCreator(k8sClientTest, test.resourceRetriever, namespace)
+		test.connectionPrimaryDb = util.InitDbConnectionDbUtil(test.resourceCreator, resourceConfigs.KubegresResourceName, resourceConfigs.ServiceToSqlQueryPrimaryDbNodePort, true)
+		test.connectionReplicaDb = util.InitDbConnectionDbUtil(test.resourceCreator, resourceConfigs.KubegresResourceName, resourceConfigs.ServiceToSqlQueryReplicaDbNodePort, false)
+	})
+
+	AfterEach(func() {
+		test.resourceCreator.DeleteAllTestResources()
+	})
+
+	Context("GIVEN Kubegres with 1 primary and 1 replica AND once deployed we update YAML with 'failover.switchoverPod' set to the Replica Pod name", func() {
+
+		It("THEN the Replica Pod should become the new primary AND the former Primary Pod should become a replica AND no data should be lost", func() {
+
+			log.Print("START OF: Test 'GIVEN Kubegres with 1 primary and 1 replica AND once deployed we update YAML with 'failover.switchoverPod' set to the Replica Pod name'")
+
+			test.givenNewKubegresSpecIsSetTo(2)
+
+			test.whenKubegresIsCreated()
+
+			test.thenPodsStatesShouldBe(1, 1)
+
+			expectedNbreUsers := 0
+
+			test.givenUserAddedInPrimaryDb()
+			expectedNbreUsers++
+
+			test.givenUserAddedInPrimaryDb()
+			expectedNbreUsers++
+
+			primaryPodName, replicaPodName := test.getDeployedPodNames()
+
+			test.givenExistingKubegresSpecIsSetTo(replicaPodName)
+
+			test.whenKubernetesIsUpdated()
+
+			test.thenSwitchoverShouldBeCompleted(replicaPodName, primaryPodName)
+
+			test.thenPodsStatesShouldBe(1, 1)
+
+			test.thenSwitchoverPodFieldInSpecShouldBeCleared()
+
+			test.thenDeployedPodNamesMatch(replicaPodName, primaryPodName)
+
+			test.thenPrimaryDbContainsExpectedNbreUsers(expectedNbreUsers)
+			test.thenReplicaDbContainsExpectedNbreUsers(expectedNbreUsers)
+
+			test.givenUserAddedInPrimaryDb()
+			expectedNbreUsers++
+
+			test.thenPrimaryDbContainsExpectedNbreUsers(expectedNbreUsers)
+			test.thenReplicaDbContainsExpectedNbreUsers(expectedNbreUsers)
+
+			log.Print("END OF: Test 'GIVEN Kubegres with 1 primary and 1 replica AND once deployed we update YAML with 'failover.switchoverPod' set to the Replica Pod name'")
+		})
+	})
+
+	Context("GIVEN Kubegres with 1 primary and 1 replica using a custom 'postgres.conf' without 'wal_log_hints' AND once deployed we update YAML with 'failover.switchoverPod' set to the Replica Pod name", func() {
+
+		It("THEN a warning should be logged saying the former Primary cannot be rewound AND the Replica Pod should become the new primary AND the former Primary should be replaced by a new Replica AND its PVC should be kept AND no data should be lost", func() {
+
+			log.Print("START OF: Test 'GIVEN Kubegres with 1 primary and 1 replica using a custom 'postgres.conf' without 'wal_log_hints' AND once deployed we update YAML with 'failover.switchoverPod' set to the Replica Pod name'")
+
+			test.givenNewKubegresSpecIsSetToWithPostgresConfWithoutWalLogHints(2)
+
+			test.whenKubegresIsCreated()
+
+			test.thenPodsStatesShouldBe(1, 1)
+
+			expectedNbreUsers := 0
+
+			test.givenUserAddedInPrimaryDb()
+			expectedNbreUsers++
+
+			primaryPodName, replicaPodName := test.getDeployedPodNames()
+
+			test.givenExistingKubegresSpecIsSetTo(replicaPodName)
+
+			test.whenKubernetesIsUpdated()
+
+			test.thenWalLogHintsDisabledWarningShouldBeLogged(primaryPodName)
+
+			test.thenFormerPrimaryShouldBeReplacedByNewReplica(replicaPodName, primaryPodName)
+
+			test.thenPodsStatesShouldBe(1, 1)
+
+			test.thenPvcOfFormerPrimaryShouldBeKept(primaryPodName)
+
+			test.thenPrimaryDbContainsExpectedNbreUsers(expectedNbreUsers)
+			test.thenReplicaDbContainsExpectedNbreUsers(expectedNbreUsers)
+
+			log.Print("END OF: Test 'GIVEN Kubegres with 1 primary and 1 replica using a custom 'postgres.conf' without 'wal_log_hints' AND once deployed we update YAML with 'failover.switchoverPod' set to the Replica Pod name'")
+		})
+	})
+
+	Context("GIVEN Kubegres with 1 primary and 1 replica AND once deployed we update YAML with 'failover.switchoverPod' set to a Pod name which does NOT exist", func() {
+
+		It("THEN the update should be rejected saying Pod does NOT exist AND nothing should happen", func() {
+
+			log.Print("START OF: Test 'GIVEN Kubegres with 1 primary and 1 replica AND once deployed we update YAML with 'failover.switchoverPod' set to a Pod name which does NOT exist'")
+
+			test.givenNewKubegresSpecIsSetTo(2)
+
+			test.whenKubegresIsCreated()
+
+			test.thenPodsStatesShouldBe(1, 1)
+
+			expectedNbreUsers := 0
+
+			test.givenUserAddedInPrimaryDb()
+			expectedNbreUsers++
+
+			primaryPodName, replicaPodName := test.getDeployedPodNames()
+
+			switchoverPodName := "Pod_does_not_exist"
+
+			test.givenExistingKubegresSpecIsSetTo(switchoverPodName)
+
+			test.whenKubernetesIsUpdatedWithInvalidSpec()
+
+			test.thenUpdateShouldBeRejected(switchoverPodName)
+
+			time.Sleep(time.Second * 10)
+
+			test.thenPodsStatesShouldBe(1, 1)
+
+			test.thenDeployedPodNamesMatch(primaryPodName, replicaPodName)
+
+			test.thenPrimaryDbContainsExpectedNbreUsers(expectedNbreUsers)
+			test.thenReplicaDbContainsExpectedNbreUsers(expectedNbreUsers)
+
+			log.Print("END OF: Test 'GIVEN Kubegres with 1 primary and 1 replica AND once deployed we update YAML with 'failover.switchoverPod' set to a Pod name which does NOT exist'")
+		})
+	})
+})
+
+type SpecSwitchoverTest struct {
+	kubegresResource    *postgresv1.Kubegres
+	connectionPrimaryDb util.DbConnectionDbUtil
+	connectionReplicaDb util.DbConnectionDbUtil
+	resourceCreator     util.TestResourceCreator
+	resourceRetriever   util.TestResourceRetriever
+	admissionErr        error
+}
+
+func (r *SpecSwitchoverTest) givenNewKubegresSpecIsSetTo(specNbreReplicas int32) {
+	r.kubegresResource = resourceConfigs.LoadKubegresYaml()
+	r.kubegresResource.Spec.Replicas = &specNbreReplicas
+}
+
+func (r *SpecSwitchoverTest) givenNewKubegresSpecIsSetToWithPostgresConfWithoutWalLogHints(specNbreReplicas int32) {
+	r.resourceCreator.CreateConfigMapWithPostgresConf()
+	r.kubegresResource = resourceConfigs.LoadKubegresYaml()
+	r.kubegresResource.Spec.Replicas = &specNbreReplicas
+	r.kubegresResource.Spec.CustomConfig = resourceConfigs.CustomConfigMapWithPostgresConfResourceName
+
+	// The former Primary cannot be rewound, so its deployment as a Replica times out and it is replaced
+	replicaDeployingSeconds := int64(60)
+	r.kubegresResource.Spec.Timeouts.ReplicaDeployingSeconds = &replicaDeployingSeconds
+}
+
+func (r *SpecSwitchoverTest) givenExistingKubegresSpecIsSetTo(switchoverPodName string) {
+	var err error
+	r.kubegresResource, err = r.resourceRetriever.GetKubegres()
+
+	if err != nil {
+		log.Println("Error while getting Kubegres resource : ", err)
+		Expect(err).Should(Succeed())
+		return
+	}
+
+	r.kubegresResource.Spec.Failover.SwitchoverPod = switchoverPodName
+}
+
+func (r *SpecSwitchoverTest) givenUserAddedInPrimaryDb() {
+	Eventually(func() bool {
+		return r.connectionPrimaryDb.InsertUser()
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
+
+func (r *SpecSwitchoverTest) getDeployedPodNames() (primaryPodName, replicaPodName string) {
+
+	kubegresResources, err := r.resourceRetriever.GetKubegresResources()
+	if err != nil {
+		Expect(err).Should(Succeed())
+		return
+	}
+
+	for _, kubegresResource := range kubegresResources.Resources {
+		if kubegresResource.IsPrimary {
+			primaryPodName = kubegresResource.Pod.Name
+		} else {
+			replicaPodName = kubegresResource.Pod.Name
+		}
+	}
+
+	return primaryPodName, replicaPodName
+}
+
+func (r *SpecSwitchoverTest) whenKubegresIsCreated() {
+	r.resourceCreator.CreateKubegres(r.kubegresResource)
+}
+
+func (r *SpecSwitchoverTest) whenKubernetesIsUpdatedWithInvalidSpec() {
+	r.admissionErr = r.resourceCreator.UpdateResourceWithInvalidSpec(r.kubegresResource, "Kubegres")
+}
+
+func (r *SpecSwitchoverTest) whenKubernetesIsUpdated() {
+	r.resourceCreator.UpdateResource(r.kubegresResource, "Kubegres")
+}
+
+func (r *SpecSwitchoverTest) thenSwitchoverShouldBeCompleted(newPrimaryPodName, formerPrimaryPodName string) {
+
+	formerPrimaryStatefulSetName := strings.TrimSuffix(formerPrimaryPodName, "-0")
+	expectedEvent := util.EventRecord{
+		Eventtype: v12.EventTypeNormal,
+		Reason:    "SwitchoverCompleted",
+		Message:   "Switchover: The former Primary is ready as a Replica of the new Primary. 'Replica name': " + formerPrimaryStatefulSetName,
+	}
+
+	Eventually(func() bool {
+
+		kubegres, err := r.resourceRetriever.GetKubegres()
+		if err != nil {
+			return false
+		}
+
+		if kubegres.Status.CurrentPrimary != newPrimaryPodName {
+			log.Println("Waiting for the Pod '" + newPrimaryPodName + "' to become the Primary")
+			return false
+		}
+
+		return kubegres.Status.BlockingOperation.OperationId == "" && eventRecorderTest.CheckEventExist(expectedEvent)
+
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
+
+func (r *SpecSwitchoverTest) thenWalLogHintsDisabledWarningShouldBeLogged(primaryPodName string) {
+
+	expectedEvent := util.EventRecord{
+		Eventtype: v12.EventTypeWarning,
+		Reason:    "SwitchoverWalLogHintsDisabled",
+		Message: "Switchover: The setting 'wal_log_hints' and the data checksums are disabled on the Primary, " +
+			"so 'pg_rewind' cannot restart the former Primary as a Replica. Its data will be kept in its PVC and " +
+			"it will be replaced by a new Replica copied from the new Primary once the deployment of the former " +
+			"Primary as a Replica times out. " +
+			"Please set 'wal_log_hints = on' in the custom 'postgres.conf'. 'Primary': " + primaryPodName,
+	}
+
+	Eventually(func() bool {
+		return eventRecorderTest.CheckEventExist(expectedEvent)
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
+
+func (r *SpecSwitchoverTest) thenSwitchoverPodFieldInSpecShouldBeCleared() {
+	Eventually(func() bool {
+
+		kubegres, err := r.resourceRetriever.GetKubegres()
+		if err != nil {
+			return false
+		}
+
+		return kubegres.Spec.Failover.SwitchoverPod == ""
+
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
+
+func (r *SpecSwitchoverTest) thenPodsStatesShouldBe(nbrePrimary, nbreReplicas int) bool {
+
+	return Eventually(func() bool {
+
+		kubegresResources, err := r.resourceRetriever.GetKubegresResources()
+		if err != nil && !apierrors.IsNotFound(err) {
+			log.Println("ERROR while retrieving Kubegres kubegresResources")
+			return false
+		}
+
+		if kubegresResources.AreAllReady &&
+			kubegresResources.NbreDeployedPrimary == nbrePrimary &&
+			kubegresResources.NbreDeployedReplicas == nbreReplicas {
+
+			time.Sleep(resourceConfigs.TestRetryInterval)
+			log.Println("Deployed and Ready StatefulSets check successful")
+			return true
+		}
+
+		return false
+
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
+
+func (r *SpecSwitchoverTest) thenDeployedPodNamesMatch(expectedPrimaryPodName, expectedReplicaPodName string) {
+	primaryPodName, replicaPodName := r.getDeployedPodNames()
+	Expect(primaryPodName).Should(Equal(expectedPrimaryPodName))
+	Expect(replicaPodName).Should(Equal(expectedReplicaPodName))
+}
+
+func (r *SpecSwitchoverTest) thenFormerPrimaryShouldBeReplacedByNewReplica(newPrimaryPodName, formerPrimaryPodName string) {
+	Eventually(func() bool {
+
+		primaryPodName, replicaPodName := r.getDeployedPodNames()
+		if primaryPodName != newPrimaryPodName || replicaPodName == "" || replicaPodName == formerPrimaryPodName {
+			log.Println("Waiting for the former Primary '" + formerPrimaryPodName + "' to be replaced by a new Replica")
+			return false
+		}
+
+		return true
+
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
+
+// thenPvcOfFormerPrimaryShouldBeKept checks that the data of a former Primary which could not be rewound is not deleted.
+func (r *SpecSwitchoverTest) thenPvcOfFormerPrimaryShouldBeKept(formerPrimaryPodName string) {
+	pvcList, err := r.resourceRetriever.GetKubegresPvc()
+	Expect(err).Should(Succeed())
+
+	expectedPvcName := "postgres-db-" + formerPrimaryPodName
+	for _, pvc := range pvcList.Items {
+		if pvc.Name == expectedPvcName {
+			return
+		}
+	}
+
+	Fail("The PVC '" + expectedPvcName + "' of the former Primary does not exist")
+}
+
+func (r *SpecSwitchoverTest) thenUpdateShouldBeRejected(podName string) {
+	Expect(r.admissionErr).Should(MatchError(ContainSubstring("The value of the field 'failover.switchoverPod' is set to '" + podName + "'. " +
+		"That value is either the name of a Primary Pod OR a Pod which does not exist. " +
+		"Please set the name of a Replica Pod that you would like to promote as a Primary Pod.")))
+}
+
+func (r *SpecSwitchoverTest) thenPrimaryDbContainsExpectedNbreUsers(expectedNbreUsers int) {
+	Eventually(func() bool {
+
+		users := r.connectionPrimaryDb.GetUsers()
+		r.connectionPrimaryDb.Close()
+
+		if len(users) != expectedNbreUsers ||
+			r.connectionPrimaryDb.NbreInsertedUsers != expectedNbreUsers {
+			log.Println("Primary DB does not contain the expected number of users. Expected: " + strconv.Itoa(expectedNbreUsers) + " Given: " + strconv.Itoa(len(users)))
+			return false
+		}
+
+		return true
+
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
+
+func (r *SpecSwitchoverTest) thenReplicaDbContainsExpectedNbreUsers(expectedNbreUsers int) {
+	Eventually(func() bool {
+
+		users := r.connectionReplicaDb.GetUsers()
+		r.connectionReplicaDb.Close()
+
+		if len(users) != expectedNbreUsers ||
+			r.connectionReplicaDb.NbreInsertedUsers != expectedNbreUsers {
+			log.Println("Replica DB does not contain the expected number of users: " + strconv.Itoa(expectedNbreUsers))
+			return false
+		}
+
+		return true
+
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
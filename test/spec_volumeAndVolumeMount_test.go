@@ -59,7 +59,7 @@ var _ = Describe("Setting Kubegres specs 'volume.volume' and 'volume.volumeMount
 
 	Context("GIVEN new Kubegres is created with a 'volume.volume' and 'volume.volumeMount' which have a reserved name", func() {
 
-		It("THEN 2 error events should be logged as it is not possible to use a reserved name", func() {
+		It("THEN the creation of Kubegres should be rejected as it is not possible to use a reserved name", func() {
 
 			log.Print("START OF: Test 'GIVEN new Kubegres is created with a 'volume.volume' and 'volume.volumeMount' " +
 				"which have a reserved name'")
@@ -74,10 +74,10 @@ var _ = Describe("Setting Kubegres specs 'volume.volume' and 'volume.volumeMount
 
 			test.givenNewKubegresSpecIsSetTo(customVolumes, customVolumeMounts, 3)
 
-			test.whenKubegresIsCreated()
+			test.whenKubegresIsCreatedWithInvalidSpec()
 
-			test.thenErrorEventShouldBeLoggedAboutVolumeName()
-			test.thenErrorEventShouldBeLoggedAboutVolumeMountName()
+			test.thenKubegresShouldBeRejectedAboutVolumeName()
+			test.thenKubegresShouldBeRejectedAboutVolumeMountName()
 
 			log.Print("END OF: Test 'GIVEN new Kubegres is created with a 'volume.volume' and 'volume.volumeMount' " +
 				"which have a reserved name'")
@@ -87,7 +87,7 @@ var _ = Describe("Setting Kubegres specs 'volume.volume' and 'volume.volumeMount
 	Context("GIVEN new Kubegres is created with a 'volume.volumeMount' which has a mountPath set to the path of "+
 		"Postgres database folder", func() {
 
-		It("THEN an error event should be logged as it is not possible to use Postgres database as a mountPath", func() {
+		It("THEN the creation of Kubegres should be rejected as it is not possible to use Postgres database as a mountPath", func() {
 
 			log.Print("START OF: Test 'GIVEN new Kubegres is created with a 'volume.volumeMount' which has a mountPath " +
 				"set to the path of Postgres database folder'")
@@ -101,9 +101,9 @@ var _ = Describe("Setting Kubegres specs 'volume.volume' and 'volume.volumeMount
 
 			test.givenNewKubegresSpecIsSetTo(customVolumes, customVolumeMounts, 3)
 
-			test.whenKubegresIsCreated()
+			test.whenKubegresIsCreatedWithInvalidSpec()
 
-			test.thenErrorEventShouldBeLoggedAboutVolumeMountPath()
+			test.thenKubegresShouldBeRejectedAboutVolumeMountPath()
 
 			log.Print("END OF: Test 'GIVEN new Kubegres is created with a 'volume.volumeMount' which has a mountPath " +
 				"set to the path of Postgres database folder'")
@@ -246,6 +246,7 @@ type SpecVolumeAndVolumeMountTest struct {
 	dbQueryTestCases                testcases.DbQueryTestCases
 	resourceCreator                 util.TestResourceCreator
 	resourceRetriever               util.TestResourceRetriever
+	admissionErr                    error
 }
 
 func (r *SpecVolumeAndVolumeMountTest) givenVolumeWithMemory(volumeName, memoryQuantity string) v12.Volume {
@@ -376,62 +377,30 @@ func (r *SpecVolumeAndVolumeMountTest) whenKubegresIsCreated() {
 	r.resourceCreator.CreateKubegres(r.kubegresResource)
 }
 
+func (r *SpecVolumeAndVolumeMountTest) whenKubegresIsCreatedWithInvalidSpec() {
+	r.admissionErr = r.resourceCreator.CreateKubegresWithInvalidSpec(r.kubegresResource)
+}
+
 func (r *SpecVolumeAndVolumeMountTest) whenKubernetesIsUpdated() {
 	r.resourceCreator.UpdateResource(r.kubegresResource, "Kubegres")
 }
 
-func (r *SpecVolumeAndVolumeMountTest) thenErrorEventShouldBeLoggedAboutVolumeName() {
-	expectedErrorEvent := util.EventRecord{
-		Eventtype: v12.EventTypeWarning,
-		Reason:    "SpecCheckErr",
-		Message: "In the Resources Spec the value of 'spec.Volume.Volumes' has an entry with a volume name " +
-			"which is a reserved name: " + ctx.DatabaseVolumeName + " . That name cannot be used and it is reserved for " +
-			"Kubegres internal usages. Please change that name in the YAML.",
-	}
-	Eventually(func() bool {
-		_, err := r.resourceRetriever.GetKubegres()
-		if err != nil {
-			return false
-		}
-		return eventRecorderTest.CheckEventExist(expectedErrorEvent)
-
-	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+func (r *SpecVolumeAndVolumeMountTest) thenKubegresShouldBeRejectedAboutVolumeName() {
+	Expect(r.admissionErr).Should(MatchError(ContainSubstring("In the Resources Spec the value of 'spec.Volume.Volumes' has an entry with a volume name " +
+		"which is a reserved name: " + ctx.DatabaseVolumeName + " . That name cannot be used and it is reserved for " +
+		"Kubegres internal usages. Please change that name in the YAML.")))
 }
 
-func (r *SpecVolumeAndVolumeMountTest) thenErrorEventShouldBeLoggedAboutVolumeMountName() {
-	expectedErrorEvent := util.EventRecord{
-		Eventtype: v12.EventTypeWarning,
-		Reason:    "SpecCheckErr",
-		Message: "In the Resources Spec the value of 'spec.Volume.VolumeMounts' has an entry with a volume name " +
-			"which is a reserved name: " + ctx.DatabaseVolumeName + " . That name cannot be used and it is reserved for " +
-			"Kubegres internal usages. Please change that name in the YAML.",
-	}
-	Eventually(func() bool {
-		_, err := r.resourceRetriever.GetKubegres()
-		if err != nil {
-			return false
-		}
-		return eventRecorderTest.CheckEventExist(expectedErrorEvent)
-
-	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+func (r *SpecVolumeAndVolumeMountTest) thenKubegresShouldBeRejectedAboutVolumeMountName() {
+	Expect(r.admissionErr).Should(MatchError(ContainSubstring("In the Resources Spec the value of 'spec.Volume.VolumeMounts' has an entry with a volume name " +
+		"which is a reserved name: " + ctx.DatabaseVolumeName + " . That name cannot be used and it is reserved for " +
+		"Kubegres internal usages. Please change that name in the YAML.")))
 }
 
-func (r *SpecVolumeAndVolumeMountTest) thenErrorEventShouldBeLoggedAboutVolumeMountPath() {
-	expectedErrorEvent := util.EventRecord{
-		Eventtype: v12.EventTypeWarning,
-		Reason:    "SpecCheckErr",
-		Message: "In the Resources Spec the value of 'spec.Volume.VolumeMounts' has an entry with a 'mountPath' value " +
-			"which is reserved for the Postgres database: " + r.kubegresResource.Spec.Database.VolumeMount + " . " +
-			"That value cannot be used and it is reserved for Kubegres internal usages. Please change that value in the YAML.",
-	}
-	Eventually(func() bool {
-		_, err := r.resourceRetriever.GetKubegres()
-		if err != nil {
-			return false
-		}
-		return eventRecorderTest.CheckEventExist(expectedErrorEvent)
-
-	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+func (r *SpecVolumeAndVolumeMountTest) thenKubegresShouldBeRejectedAboutVolumeMountPath() {
+	Expect(r.admissionErr).Should(MatchError(ContainSubstring("In the Resources Spec the value of 'spec.Volume.VolumeMounts' has an entry with a 'mountPath' value " +
+		"which is reserved for the Postgres database: " + r.kubegresResource.Spec.Database.VolumeMount + " . " +
+		"That value cannot be used and it is reserved for Kubegres internal usages. Please change that value in the YAML.")))
 }
 
 func (r *SpecVolumeAndVolumeMountTest) thenStatefulSetsStatesShouldBe(
@@ -59,7 +59,7 @@ var _ = Describe("Setting Kubegres spec 'volume.volumeClaimTemplates'", Label("g
 
 	Context("GIVEN new Kubegres is created with a 'volume.volumeClaimTemplate' and 'volume.volumeMount' which have a reserved name", func() {
 
-		It("THEN 2 error events should be logged as it is not possible to use a reserved name", func() {
+		It("THEN the creation of Kubegres should be rejected as it is not possible to use a reserved name", func() {
 
 			log.Print("START OF: Test 'GIVEN new Kubegres is created with a 'volume.volumeClaimTemplate' and 'volume.volumeMount' which have a reserved name'")
 
@@ -73,10 +73,10 @@ var _ = Describe("Setting Kubegres spec 'volume.volumeClaimTemplates'", Label("g
 
 			test.givenNewKubegresSpecIsSetTo(customVolumeClaims, customVolumeMounts, 3)
 
-			test.whenKubegresIsCreated()
+			test.whenKubegresIsCreatedWithInvalidSpec()
 
-			test.thenErrorEventShouldBeLoggedAboutVolumeClaimTemplateName()
-			test.thenErrorEventShouldBeLoggedAboutVolumeMountName()
+			test.thenKubegresShouldBeRejectedAboutVolumeClaimTemplateName()
+			test.thenKubegresShouldBeRejectedAboutVolumeMountName()
 
 			log.Print("END OF: Test 'GIVEN new Kubegres is created with a 'volume.volumeClaimTemplate' and 'volume.volumeMount' which have a reserved name'")
 		})
@@ -148,7 +148,7 @@ var _ = Describe("Setting Kubegres spec 'volume.volumeClaimTemplates'", Label("g
 		})
 
 		It("GIVEN existing Kubegres is updated with the update of one custom 'volume.volumeClaimTemplates' from 10Mi to 15Mi "+
-			"THEN an error event should be logged as it is not possible to update 'volume.volumeClaimTemplates'", func() {
+			"THEN the update should be rejected as it is not possible to update 'volume.volumeClaimTemplates'", func() {
 
 			log.Print("START OF: Test 'GIVEN existing Kubegres is updated with the update of one custom 'volume.volumeClaimTemplates' from 10Mi to 15Mi'")
 
@@ -159,11 +159,9 @@ var _ = Describe("Setting Kubegres spec 'volume.volumeClaimTemplates'", Label("g
 
 			test.givenVolumesAreUpdatedOrAddedToTheExistingKubegresSpec(customVolumesUpdate, customVolumeMountsToUpdate)
 
-			eventRecorderTest.RemoveAllEvents()
-
-			test.whenKubernetesIsUpdated()
+			test.whenKubernetesIsUpdatedWithInvalidSpec()
 
-			test.thenErrorEventShouldBeLoggedAboutVolumeClaimTemplateSpecChanged()
+			test.thenUpdateShouldBeRejectedAboutVolumeClaimTemplateSpecChanged()
 
 			test.keepCreatedResourcesForNextTest = true
 
@@ -171,7 +169,7 @@ var _ = Describe("Setting Kubegres spec 'volume.volumeClaimTemplates'", Label("g
 		})
 
 		It("GIVEN existing Kubegres is updated with the removal of one custom 'volume.volumeClaimTemplates' "+
-			"THEN an error event should be logged as it is not possible to update 'volume.volumeClaimTemplates'", func() {
+			"THEN the update should be rejected as it is not possible to update 'volume.volumeClaimTemplates'", func() {
 
 			log.Print("START OF: Test 'GIVEN existing Kubegres is updated with the removal of one custom 'volume.volumeClaimTemplates'")
 
@@ -182,11 +180,9 @@ var _ = Describe("Setting Kubegres spec 'volume.volumeClaimTemplates'", Label("g
 
 			test.givenVolumesAreRemovedFromTheExistingKubegresSpec(customVolumesToRemove, customVolumeMountsToRemove)
 
-			eventRecorderTest.RemoveAllEvents()
+			test.whenKubernetesIsUpdatedWithInvalidSpec()
 
-			test.whenKubernetesIsUpdated()
-
-			test.thenErrorEventShouldBeLoggedAboutVolumeClaimTemplateSpecChanged()
+			test.thenUpdateShouldBeRejectedAboutVolumeClaimTemplateSpecChanged()
 
 			log.Print("END OF: Test 'GIVEN existing Kubegres is updated with the removal of one custom 'volume.volumeClaimTemplates'")
 		})
@@ -201,6 +197,7 @@ type SpecVolumeClaimTemplatesTest struct {
 	dbQueryTestCases                testcases.DbQueryTestCases
 	resourceCreator                 util.TestResourceCreator
 	resourceRetriever               util.TestResourceRetriever
+	admissionErr                    error
 }
 
 func (r *SpecVolumeClaimTemplatesTest) givenVolumeClaimTemplate(volumeName, volumeSize string) postgresv1.VolumeClaimTemplate {
@@ -327,63 +324,35 @@ func (r *SpecVolumeClaimTemplatesTest) whenKubegresIsCreated() {
 	r.resourceCreator.CreateKubegres(r.kubegresResource)
 }
 
+func (r *SpecVolumeClaimTemplatesTest) whenKubegresIsCreatedWithInvalidSpec() {
+	r.admissionErr = r.resourceCreator.CreateKubegresWithInvalidSpec(r.kubegresResource)
+}
+
+func (r *SpecVolumeClaimTemplatesTest) whenKubernetesIsUpdatedWithInvalidSpec() {
+	r.admissionErr = r.resourceCreator.UpdateResourceWithInvalidSpec(r.kubegresResource, "Kubegres")
+}
+
 func (r *SpecVolumeClaimTemplatesTest) whenKubernetesIsUpdated() {
 	r.resourceCreator.UpdateResource(r.kubegresResource, "Kubegres")
 }
 
 //
 
-func (r *SpecVolumeClaimTemplatesTest) thenErrorEventShouldBeLoggedAboutVolumeClaimTemplateName() {
-	expectedErrorEvent := util.EventRecord{
-		Eventtype: v12.EventTypeWarning,
-		Reason:    "SpecCheckErr",
-		Message: "In the Resources Spec the value of 'spec.Volume.VolumeClaimTemplates' has an entry with a volume name " +
-			"which is a reserved name: " + ctx.DatabaseVolumeName + " . That name cannot be used and it is reserved for " +
-			"Kubegres internal usages. Please change that name in the YAML.",
-	}
-	Eventually(func() bool {
-		_, err := r.resourceRetriever.GetKubegres()
-		if err != nil {
-			return false
-		}
-		return eventRecorderTest.CheckEventExist(expectedErrorEvent)
-
-	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+func (r *SpecVolumeClaimTemplatesTest) thenKubegresShouldBeRejectedAboutVolumeClaimTemplateName() {
+	Expect(r.admissionErr).Should(MatchError(ContainSubstring("In the Resources Spec the value of 'spec.Volume.VolumeClaimTemplates' has an entry with a volume name " +
+		"which is a reserved name: " + ctx.DatabaseVolumeName + " . That name cannot be used and it is reserved for " +
+		"Kubegres internal usages. Please change that name in the YAML.")))
 }
 
-func (r *SpecVolumeClaimTemplatesTest) thenErrorEventShouldBeLoggedAboutVolumeMountName() {
-	expectedErrorEvent := util.EventRecord{
-		Eventtype: v12.EventTypeWarning,
-		Reason:    "SpecCheckErr",
-		Message: "In the Resources Spec the value of 'spec.Volume.VolumeMounts' has an entry with a volume name " +
-			"which is a reserved name: " + ctx.DatabaseVolumeName + " . That name cannot be used and it is reserved for " +
-			"Kubegres internal usages. Please change that name in the YAML.",
-	}
-	Eventually(func() bool {
-		_, err := r.resourceRetriever.GetKubegres()
-		if err != nil {
-			return false
-		}
-		return eventRecorderTest.CheckEventExist(expectedErrorEvent)
-
-	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+func (r *SpecVolumeClaimTemplatesTest) thenKubegresShouldBeRejectedAboutVolumeMountName() {
+	Expect(r.admissionErr).Should(MatchError(ContainSubstring("In the Resources Spec the value of 'spec.Volume.VolumeMounts' has an entry with a volume name " +
+		"which is a reserved name: " + ctx.DatabaseVolumeName + " . That name cannot be used and it is reserved for " +
+		"Kubegres internal usages. Please change that name in the YAML.")))
 }
 
-func (r *SpecVolumeClaimTemplatesTest) thenErrorEventShouldBeLoggedAboutVolumeClaimTemplateSpecChanged() {
-	expectedErrorEvent := util.EventRecord{
-		Eventtype: v12.EventTypeWarning,
-		Reason:    "SpecCheckErr",
-		Message: "In the Resources Spec, the array 'spec.Volume.VolumeClaimTemplates' has changed. Kubernetes does not " +
-			"allow to update that field in StatefulSet specification. Please rollback your changes in the YAML.",
-	}
-	Eventually(func() bool {
-		_, err := r.resourceRetriever.GetKubegres()
-		if err != nil {
-			return false
-		}
-		return eventRecorderTest.CheckEventExist(expectedErrorEvent)
-
-	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+func (r *SpecVolumeClaimTemplatesTest) thenUpdateShouldBeRejectedAboutVolumeClaimTemplateSpecChanged() {
+	Expect(r.admissionErr).Should(MatchError(ContainSubstring("In the Resources Spec, the array 'spec.Volume.VolumeClaimTemplates' has changed. Kubernetes does not " +
+		"allow to update that field in StatefulSet specification. Please rollback your changes in the YAML.")))
 }
 
 func (r *SpecVolumeClaimTemplatesTest) thenStatefulSetsStatesShouldBe(
@@ -0,0 +1,151 @@
+/*
+Copyright 2021 Reactive Tech Limited.
+"Reactive Tech Limited" is a company located in England, United Kingdom.
+https://www.reactive-tech.io
+
+Lead Developer: Alex Arica
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"log"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	postgresv1 "reactive-tech.io/kubegres/api/v1"
+	"reactive-tech.io/kubegres/test/resourceConfigs"
+	"reactive-tech.io/kubegres/test/util"
+)
+
+var _ = Describe("Checking Kubegres status conditions", Label("group:1"), func() {
+
+	var test = StatusConditionsTest{}
+
+	BeforeEach(func() {
+		//Skip("Temporarily skipping test")
+
+		namespace := resourceConfigs.DefaultNamespace
+		test.resourceRetriever = util.CreateTestResourceRetriever(k8sClientTest, namespace)
+		test.resourceCreator = util.CreateTestResourceCreator(k8sClientTest, test.resourceRetriever, namespace)
+	})
+
+	AfterEach(func() {
+		test.resourceCreator.DeleteAllTestResources()
+	})
+
+	Context("GIVEN new Kubegres is created with spec 'replica' set to 3", func() {
+
+		It("THEN the conditions 'Ready', 'PrimaryAvailable' and 'ReplicasReady' should be true AND 'observedGeneration' should be up-to-date", func() {
+
+			log.Print("START OF: Test 'GIVEN new Kubegres is created with spec 'replica' set to 3'")
+
+			test.givenNewKubegresSpecIsSetTo(3)
+
+			test.whenKubegresIsCreated()
+
+			test.thenConditionStatusShouldBe(postgresv1.ConditionTypeReady, metav1.ConditionTrue)
+			test.thenConditionStatusShouldBe(postgresv1.ConditionTypePrimaryAvailable, metav1.ConditionTrue)
+			test.thenConditionStatusShouldBe(postgresv1.ConditionTypeReplicasReady, metav1.ConditionTrue)
+			test.thenConditionStatusShouldBe(postgresv1.ConditionTypeSpecInvalid, metav1.ConditionFalse)
+			test.thenConditionStatusShouldBe(postgresv1.ConditionTypeFailingOver, metav1.ConditionFalse)
+			test.thenConditionStatusShouldBe(postgresv1.ConditionTypeOperationTimedOut, metav1.ConditionFalse)
+
+			test.thenObservedGenerationShouldBeUpToDate()
+
+			log.Print("END OF: Test 'GIVEN new Kubegres is created with spec 'replica' set to 3'")
+		})
+	})
+
+	Context("GIVEN new Kubegres is created without the environment variable of postgres super-user password", func() {
+
+		It("THEN the conditions 'SpecInvalid' should be true AND 'Ready' should be false", func() {
+
+			log.Print("START OF: Test 'GIVEN new Kubegres is created without the environment variable of postgres super-user password'")
+
+			test.givenNewKubegresSpecIsSetTo(3)
+			test.givenKubegresSpecHasNoSuperUserPassword()
+
+			test.whenKubegresIsCreated()
+
+			test.thenConditionStatusShouldBe(postgresv1.ConditionTypeSpecInvalid, metav1.ConditionTrue)
+			test.thenConditionStatusShouldBe(postgresv1.ConditionTypeReady, metav1.ConditionFalse)
+
+			log.Print("END OF: Test 'GIVEN new Kubegres is created without the environment variable of postgres super-user password'")
+		})
+	})
+
+})
+
+type StatusConditionsTest struct {
+	kubegresResource  *postgresv1.Kubegres
+	resourceCreator   util.TestResourceCreator
+	resourceRetriever util.TestResourceRetriever
+}
+
+func (r *StatusConditionsTest) givenNewKubegresSpecIsSetTo(specNbreReplicas int32) {
+	r.kubegresResource = resourceConfigs.LoadKubegresYaml()
+	r.kubegresResource.Spec.Replicas = &specNbreReplicas
+}
+
+func (r *StatusConditionsTest) givenKubegresSpecHasNoSuperUserPassword() {
+	var envVars = r.kubegresResource.Spec.Env[:0]
+	for _, envVar := range r.kubegresResource.Spec.Env {
+		if envVar.Name != "POSTGRES_PASSWORD" {
+			envVars = append(envVars, envVar)
+		}
+	}
+	r.kubegresResource.Spec.Env = envVars
+}
+
+func (r *StatusConditionsTest) whenKubegresIsCreated() {
+	r.resourceCreator.CreateKubegres(r.kubegresResource)
+}
+
+func (r *StatusConditionsTest) thenConditionStatusShouldBe(conditionType string, expectedStatus metav1.ConditionStatus) {
+	Eventually(func() bool {
+
+		kubegres, err := r.resourceRetriever.GetKubegres()
+		if err != nil {
+			log.Println("ERROR while retrieving Kubegres resource: ", err)
+			return false
+		}
+
+		condition := meta.FindStatusCondition(kubegres.Status.Conditions, conditionType)
+		if condition == nil || condition.Status != expectedStatus {
+			log.Println("Waiting for the condition '" + conditionType + "' to have the status '" + string(expectedStatus) + "'")
+			return false
+		}
+
+		return true
+
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
+
+func (r *StatusConditionsTest) thenObservedGenerationShouldBeUpToDate() {
+	Eventually(func() bool {
+
+		kubegres, err := r.resourceRetriever.GetKubegres()
+		if err != nil {
+			log.Println("ERROR while retrieving Kubegres resource: ", err)
+			return false
+		}
+
+		return kubegres.Status.ObservedGeneration == kubegres.Generation
+
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
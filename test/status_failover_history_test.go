@@ -0,0 +1,182 @@
+/*
+Copyright 2021 Reactive Tech Limited.
+"Reactive Tech Limited" is a company located in England, United Kingdom.
+https://www.reactive-tech.io
+
+Lead Developer: Alex Arica
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"log"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	postgresv1 "reactive-tech.io/kubegres/api/v1"
+	"reactive-tech.io/kubegres/test/resourceConfigs"
+	"reactive-tech.io/kubegres/test/util"
+)
+
+var _ = Describe("Checking Kubegres status 'failoverHistory'", Label("group:3"), func() {
+
+	var test = StatusFailoverHistoryTest{}
+
+	BeforeEach(func() {
+		//Skip("Temporarily skipping test")
+
+		namespace := resourceConfigs.DefaultNamespace
+		test.resourceRetriever = util.CreateTestResourceRetriever(k8sClientTest, namespace)
+		test.resourceCreator = util.CreateTestResourceCreator(k8sClientTest, test.resourceRetriever, namespace)
+	})
+
+	AfterEach(func() {
+		test.resourceCreator.DeleteAllTestResources()
+	})
+
+	Context("GIVEN new Kubegres is created with 2 instances AND the Primary fails", func() {
+
+		It("THEN the status 'failoverHistory' should contain a succeeded failover from the former Primary to the Replica", func() {
+
+			log.Print("START OF: Test 'GIVEN new Kubegres is created with 2 instances AND the Primary fails'")
+
+			test.givenNewKubegresSpecIsSetTo(2)
+
+			test.whenKubegresIsCreated()
+
+			test.thenPodsStatesShouldBe(1, 1)
+
+			primaryPodName, replicaPodName := test.getDeployedPodNames()
+
+			test.whenPrimaryStatefulSetIsDeleted()
+
+			test.thenPodsStatesShouldBe(1, 1)
+
+			test.thenLastFailoverRecordShouldBe(primaryPodName, replicaPodName, postgresv1.FailoverReasonPrimaryUnhealthy)
+
+			log.Print("END OF: Test 'GIVEN new Kubegres is created with 2 instances AND the Primary fails'")
+		})
+	})
+})
+
+type StatusFailoverHistoryTest struct {
+	kubegresResource  *postgresv1.Kubegres
+	resourceCreator   util.TestResourceCreator
+	resourceRetriever util.TestResourceRetriever
+}
+
+func (r *StatusFailoverHistoryTest) givenNewKubegresSpecIsSetTo(specNbreReplicas int32) {
+	r.kubegresResource = resourceConfigs.LoadKubegresYaml()
+	r.kubegresResource.Spec.Replicas = &specNbreReplicas
+}
+
+func (r *StatusFailoverHistoryTest) whenKubegresIsCreated() {
+	r.resourceCreator.CreateKubegres(r.kubegresResource)
+}
+
+func (r *StatusFailoverHistoryTest) whenPrimaryStatefulSetIsDeleted() {
+	kubegresResources, err := r.resourceRetriever.GetKubegresResources()
+	if err != nil {
+		Expect(err).Should(Succeed())
+		return
+	}
+
+	nbreDeleted := 0
+	for _, kubegresResource := range kubegresResources.Resources {
+		if kubegresResource.IsPrimary {
+			log.Println("Attempting to delete StatefulSet: '" + kubegresResource.StatefulSet.Name + "'")
+			if r.resourceCreator.DeleteResource(kubegresResource.StatefulSet.Resource, kubegresResource.StatefulSet.Name) {
+				nbreDeleted++
+				time.Sleep(5 * time.Second)
+			}
+		}
+	}
+
+	Expect(nbreDeleted).Should(Equal(1))
+}
+
+func (r *StatusFailoverHistoryTest) getDeployedPodNames() (primaryPodName, replicaPodName string) {
+
+	kubegresResources, err := r.resourceRetriever.GetKubegresResources()
+	if err != nil {
+		Expect(err).Should(Succeed())
+		return
+	}
+
+	for _, kubegresResource := range kubegresResources.Resources {
+		if kubegresResource.IsPrimary {
+			primaryPodName = kubegresResource.Pod.Name
+		} else {
+			replicaPodName = kubegresResource.Pod.Name
+		}
+	}
+
+	return primaryPodName, replicaPodName
+}
+
+func (r *StatusFailoverHistoryTest) thenPodsStatesShouldBe(nbrePrimary, nbreReplicas int) bool {
+	return Eventually(func() bool {
+
+		kubegresResources, err := r.resourceRetriever.GetKubegresResources()
+		if err != nil && !apierrors.IsNotFound(err) {
+			log.Println("ERROR while retrieving Kubegres kubegresResources")
+			return false
+		}
+
+		if kubegresResources.AreAllReady &&
+			kubegresResources.NbreDeployedPrimary == nbrePrimary &&
+			kubegresResources.NbreDeployedReplicas == nbreReplicas {
+
+			time.Sleep(resourceConfigs.TestRetryInterval)
+			log.Println("Deployed and Ready StatefulSets check successful")
+			return true
+		}
+
+		return false
+
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
+
+func (r *StatusFailoverHistoryTest) thenLastFailoverRecordShouldBe(formerPrimary, newPrimary, reason string) {
+	Eventually(func() bool {
+
+		kubegres, err := r.resourceRetriever.GetKubegres()
+		if err != nil {
+			log.Println("ERROR while retrieving Kubegres resource: ", err)
+			return false
+		}
+
+		history := kubegres.Status.FailoverHistory
+		if len(history) == 0 {
+			log.Println("Waiting for the status 'failoverHistory' to contain a record")
+			return false
+		}
+
+		record := history[len(history)-1]
+		if record.Outcome != postgresv1.FailoverOutcomeSucceeded {
+			log.Println("Waiting for the last failover record to succeed. Outcome: '" + record.Outcome + "'")
+			return false
+		}
+
+		return record.FormerPrimary == formerPrimary &&
+			record.NewPrimary == newPrimary &&
+			record.Reason == reason &&
+			record.EndTime != nil &&
+			!record.HasTimedOut
+
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
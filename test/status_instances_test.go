@@ -0,0 +1,124 @@
+/*
+Copyright 2021 Reactive Tech Limited.
+"Reactive Tech Limited" is a company located in England, United Kingdom.
+https://www.reactive-tech.io
+
+Lead Developer: Alex Arica
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"log"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	postgresv1 "reactive-tech.io/kubegres/api/v1"
+	"reactive-tech.io/kubegres/test/resourceConfigs"
+	"reactive-tech.io/kubegres/test/util"
+)
+
+var _ = Describe("Checking Kubegres status instances", Label("group:1"), func() {
+
+	var test = StatusInstancesTest{}
+
+	BeforeEach(func() {
+		//Skip("Temporarily skipping test")
+
+		namespace := resourceConfigs.DefaultNamespace
+		test.resourceRetriever = util.CreateTestResourceRetriever(k8sClientTest, namespace)
+		test.resourceCreator = util.CreateTestResourceCreator(k8sClientTest, test.resourceRetriever, namespace)
+	})
+
+	AfterEach(func() {
+		test.resourceCreator.DeleteAllTestResources()
+	})
+
+	Context("GIVEN new Kubegres is created with spec 'replica' set to 3", func() {
+
+		It("THEN the status should contain 3 ready instances AND 'currentPrimary' should be the Pod of the Primary", func() {
+
+			log.Print("START OF: Test 'GIVEN new Kubegres is created with spec 'replica' set to 3'")
+
+			test.givenNewKubegresSpecIsSetTo(3)
+
+			test.whenKubegresIsCreated()
+
+			test.thenStatusInstancesShouldMatchDeployedPods(1, 2)
+
+			log.Print("END OF: Test 'GIVEN new Kubegres is created with spec 'replica' set to 3'")
+		})
+	})
+
+})
+
+type StatusInstancesTest struct {
+	kubegresResource  *postgresv1.Kubegres
+	resourceCreator   util.TestResourceCreator
+	resourceRetriever util.TestResourceRetriever
+}
+
+func (r *StatusInstancesTest) givenNewKubegresSpecIsSetTo(specNbreReplicas int32) {
+	r.kubegresResource = resourceConfigs.LoadKubegresYaml()
+	r.kubegresResource.Spec.Replicas = &specNbreReplicas
+}
+
+func (r *StatusInstancesTest) whenKubegresIsCreated() {
+	r.resourceCreator.CreateKubegres(r.kubegresResource)
+}
+
+func (r *StatusInstancesTest) thenStatusInstancesShouldMatchDeployedPods(nbrePrimary, nbreReplicas int) {
+	Eventually(func() bool {
+
+		kubegresResources, err := r.resourceRetriever.GetKubegresResources()
+		if err != nil || !kubegresResources.AreAllReady {
+			return false
+		}
+
+		kubegres, err := r.resourceRetriever.GetKubegres()
+		if err != nil {
+			log.Println("ERROR while retrieving Kubegres resource: ", err)
+			return false
+		}
+
+		if len(kubegres.Status.Instances) != nbrePrimary+nbreReplicas {
+			log.Println("Waiting for the status to contain the expected number of instances")
+			return false
+		}
+
+		nbreReadyPrimary, nbreReadyReplicas := 0, 0
+		for _, instance := range kubegres.Status.Instances {
+			if !instance.IsReady {
+				return false
+			}
+
+			if instance.Role == resourceConfigs.PrimaryReplicationRole {
+				nbreReadyPrimary++
+			} else {
+				nbreReadyReplicas++
+			}
+		}
+
+		for _, kubegresResource := range kubegresResources.Resources {
+			if kubegresResource.IsPrimary && kubegres.Status.CurrentPrimary != kubegresResource.Pod.Name {
+				log.Println("The status 'currentPrimary' is '" + kubegres.Status.CurrentPrimary + "' instead of '" + kubegresResource.Pod.Name + "'")
+				return false
+			}
+		}
+
+		return nbreReadyPrimary == nbrePrimary && nbreReadyReplicas == nbreReplicas
+
+	}, resourceConfigs.TestTimeout, resourceConfigs.TestRetryInterval).Should(BeTrue())
+}
@@ -22,14 +22,20 @@ package test
 
 import (
 	"context"
+	"crypto/tls"
 	"log"
+	"net"
 	"path/filepath"
+	"strconv"
 	"testing"
 	"time"
 
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/tools/record"
 	"reactive-tech.io/kubegres/controllers"
+	"reactive-tech.io/kubegres/controllers/webhook"
 	"reactive-tech.io/kubegres/test/util"
 	"reactive-tech.io/kubegres/test/util/kindcluster"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -37,6 +43,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/envtest"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -51,7 +58,7 @@ import (
 
 var kindCluster kindcluster.KindTestClusterUtil
 
-// var cfgTest *rest.Config
+var cfgTest *rest.Config
 var k8sClientTest client.Client
 var testEnv *envtest.Environment
 var eventRecorderTest util.MockEventRecorderTestUtil
@@ -76,11 +83,17 @@ var _ = BeforeSuite(func() {
 		CRDDirectoryPaths:     []string{filepath.Join("..", "config", "crd", "bases")},
 		ErrorIfCRDPathMissing: true,
 		UseExistingCluster:    &useExistingCluster,
+		WebhookInstallOptions: envtest.WebhookInstallOptions{
+			Paths:                        []string{filepath.Join("..", "config", "webhook")},
+			LocalServingHost:             "0.0.0.0",
+			LocalServingHostExternalName: kindCluster.GetHostAddress(),
+		},
 	}
 
 	cfg, err := testEnv.Start()
 	Expect(err).ToNot(HaveOccurred())
 	Expect(cfg).ToNot(BeNil())
+	cfgTest = cfg
 
 	err = postgresv1.AddToScheme(scheme.Scheme)
 	Expect(err).NotTo(HaveOccurred())
@@ -91,14 +104,37 @@ var _ = BeforeSuite(func() {
 	Expect(err).ToNot(HaveOccurred())
 	Expect(k8sClientTest).ToNot(BeNil())
 
+	// The conversion webhook does not run in the test cluster. So the Kubegres resources are stored
+	// with the version used by the tests and the controller, which does not require any conversion.
+	err = setKubegresCrdStorageVersion(postgresv1.GroupVersion.Version)
+	Expect(err).ToNot(HaveOccurred())
+
+	webhookInstallOptions := &testEnv.WebhookInstallOptions
 	k8sManager, err := ctrl.NewManager(cfg, ctrl.Options{
-		Scheme: scheme.Scheme,
+		Scheme:  scheme.Scheme,
+		Host:    webhookInstallOptions.LocalServingHost,
+		Port:    webhookInstallOptions.LocalServingPort,
+		CertDir: webhookInstallOptions.LocalServingCertDir,
 	})
 	Expect(err).ToNot(HaveOccurred())
 
 	mockLogger := util.CreateMockLogger()
 	eventRecorderTest = util.MockEventRecorderTestUtil{}
 
+	err = (&webhook.KubegresDefaulter{
+		Client:   k8sManager.GetClient(),
+		Logger:   mockLogger,
+		Recorder: record.EventRecorder(&eventRecorderTest),
+	}).SetupWebhookWithManager(k8sManager)
+	Expect(err).ToNot(HaveOccurred())
+
+	err = (&webhook.KubegresValidator{
+		Client:   k8sManager.GetClient(),
+		Logger:   mockLogger,
+		Recorder: record.EventRecorder(&eventRecorderTest),
+	}).SetupWebhookWithManager(k8sManager)
+	Expect(err).ToNot(HaveOccurred())
+
 	err = (&controllers.KubegresReconciler{
 		Client:   k8sManager.GetClient(),
 		Logger:   mockLogger,
@@ -126,6 +162,16 @@ var _ = BeforeSuite(func() {
 
 	log.Println("Kubernetes has started")
 
+	// Wait for the webhook server to accept connections before the tests create Kubegres resources
+	webhookAddress := net.JoinHostPort(webhookInstallOptions.LocalServingHost, strconv.Itoa(webhookInstallOptions.LocalServingPort))
+	Eventually(func() error {
+		conn, err := tls.DialWithDialer(&net.Dialer{Timeout: time.Second}, "tcp", webhookAddress, &tls.Config{InsecureSkipVerify: true})
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}, time.Second*30, time.Second*1).Should(Succeed())
+
 	log.Print("END OF: BeforeSuite")
 })
 
@@ -144,3 +190,30 @@ var _ = AfterSuite(func() {
 
 	log.Print("END OF: Suite AfterSuite")
 })
+
+func setKubegresCrdStorageVersion(storageVersion string) error {
+
+	crd := &unstructured.Unstructured{}
+	crd.SetGroupVersionKind(schema.GroupVersionKind{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition"})
+
+	err := k8sClientTest.Get(context.Background(), client.ObjectKey{Name: "kubegres." + postgresv1.GroupVersion.Group}, crd)
+	if err != nil {
+		return err
+	}
+
+	versions, _, err := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	if err != nil {
+		return err
+	}
+
+	for _, version := range versions {
+		versionFields := version.(map[string]interface{})
+		versionFields["storage"] = versionFields["name"] == storageVersion
+	}
+
+	if err = unstructured.SetNestedSlice(crd.Object, versions, "spec", "versions"); err != nil {
+		return err
+	}
+
+	return k8sClientTest.Update(context.Background(), crd)
+}
@@ -193,6 +193,105 @@ func (r *DbConnectionDbUtil) DeleteUser(userIdToDelete string) bool {
 	return true
 }
 
+func (r *DbConnectionDbUtil) ExecSql(sqlQuery string) bool {
+	if !r.connect() {
+		return false
+	}
+
+	_, err := r.db.Exec(sqlQuery)
+	if err != nil {
+		r.logError("Error of query: "+sqlQuery+" ", err)
+		return false
+	}
+
+	r.logInfo("Success of: " + sqlQuery)
+	return true
+}
+
+// GetNbreWalSenders returns the number of Replicas streaming the WAL from the connected PostgreSql instance,
+// or -1 if it cannot be queried.
+func (r *DbConnectionDbUtil) GetNbreWalSenders() int {
+	if !r.connect() {
+		return -1
+	}
+
+	sqlQuery := "SELECT count(*) FROM pg_stat_replication"
+	nbreWalSenders := -1
+	if err := r.db.QueryRow(sqlQuery).Scan(&nbreWalSenders); err != nil {
+		r.logError("Error of query: "+sqlQuery+" ", err)
+		return -1
+	}
+
+	r.logInfo("Success of: " + sqlQuery)
+	return nbreWalSenders
+}
+
+// ShowSetting returns the value of a setting of the connected PostgreSql instance, or an empty string if it
+// cannot be queried.
+func (r *DbConnectionDbUtil) ShowSetting(settingName string) string {
+	if !r.connect() {
+		return ""
+	}
+
+	sqlQuery := "SELECT current_setting($1)"
+	var value string
+	if err := r.db.QueryRow(sqlQuery, settingName).Scan(&value); err != nil {
+		r.logError("Error of query: "+sqlQuery+" ", err)
+		return ""
+	}
+
+	r.logInfo("Success of: " + sqlQuery)
+	return value
+}
+
+// GetCurrentWalLsn returns the current WAL LSN of the connected PostgreSql Primary, or an empty string if it
+// cannot be queried.
+func (r *DbConnectionDbUtil) GetCurrentWalLsn() string {
+	if !r.connect() {
+		return ""
+	}
+
+	sqlQuery := "SELECT pg_current_wal_lsn()::text"
+	var walLsn string
+	if err := r.db.QueryRow(sqlQuery).Scan(&walLsn); err != nil {
+		r.logError("Error of query: "+sqlQuery+" ", err)
+		return ""
+	}
+
+	r.logInfo("Success of: " + sqlQuery)
+	return walLsn
+}
+
+// GetReplicationSlots returns whether each replication slot of the connected PostgreSql instance is active,
+// by slot name, or nil if they cannot be queried.
+func (r *DbConnectionDbUtil) GetReplicationSlots() map[string]bool {
+	if !r.connect() {
+		return nil
+	}
+
+	sqlQuery := "SELECT slot_name, active FROM pg_replication_slots"
+	rows, err := r.db.Query(sqlQuery)
+	if err != nil {
+		r.logError("Error of query: "+sqlQuery+" ", err)
+		return nil
+	}
+	defer rows.Close()
+
+	replicationSlots := make(map[string]bool)
+	for rows.Next() {
+		var slotName string
+		var isActive bool
+		if err := rows.Scan(&slotName, &isActive); err != nil {
+			r.logError("Error while retrieving a replication slot's row: ", err)
+			return nil
+		}
+		replicationSlots[slotName] = isActive
+	}
+
+	r.logInfo("Success of: " + sqlQuery)
+	return replicationSlots
+}
+
 func (r *DbConnectionDbUtil) GetUsers() []AccountUser {
 
 	var accountUsers []AccountUser
@@ -82,6 +82,26 @@ func (r *TestResourceCreator) UpdateResource(resourceToUpdate client.Object, res
 	}
 }
 
+// CreateKubegresWithInvalidSpec expects the creation of the given Kubegres resource to be rejected by the API server
+// and returns the error explaining why it was rejected.
+func (r *TestResourceCreator) CreateKubegresWithInvalidSpec(resourceToCreate *postgresv1.Kubegres) error {
+	ctx := context.Background()
+	err := r.client.Create(ctx, resourceToCreate)
+	log.Println("Creation of Kubegres resource rejected: ", err)
+	gomega.Expect(apierrors.IsInvalid(err)).Should(gomega.BeTrue())
+	return err
+}
+
+// UpdateResourceWithInvalidSpec expects the update of the given resource to be rejected by the API server
+// and returns the error explaining why it was rejected.
+func (r *TestResourceCreator) UpdateResourceWithInvalidSpec(resourceToUpdate client.Object, resourceName string) error {
+	ctx := context.Background()
+	err := r.client.Update(ctx, resourceToUpdate)
+	log.Println("Update of resource '"+resourceName+"' rejected: ", err)
+	gomega.Expect(apierrors.IsInvalid(err)).Should(gomega.BeTrue())
+	return err
+}
+
 func (r *TestResourceCreator) CreateExternalPostgres() {
 	existingService := v1.Service{}
 	serviceToCreate := resourceConfigs2.LoadYamlServiceExternalDB()
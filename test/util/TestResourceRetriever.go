@@ -110,12 +110,24 @@ func (r *TestResourceRetriever) GetService(serviceResourceName string) (*core.Se
 	return resourceToRetrieve, err
 }
 
+func (r *TestResourceRetriever) GetEndpoints(serviceResourceName string) (*core.Endpoints, error) {
+	resourceToRetrieve := &core.Endpoints{}
+	err := r.getResource(serviceResourceName, resourceToRetrieve)
+	return resourceToRetrieve, err
+}
+
 func (r *TestResourceRetriever) GetStatefulSet(statefulSetName string) (*v1.StatefulSet, error) {
 	resourceToRetrieve := &v1.StatefulSet{}
 	err := r.getResource(statefulSetName, resourceToRetrieve)
 	return resourceToRetrieve, err
 }
 
+func (r *TestResourceRetriever) GetSecret(secretResourceName string) (*core.Secret, error) {
+	resourceToRetrieve := &core.Secret{}
+	err := r.getResource(secretResourceName, resourceToRetrieve)
+	return resourceToRetrieve, err
+}
+
 func (r *TestResourceRetriever) GetBackUpPvc() (*core.PersistentVolumeClaim, error) {
 	resourceToRetrieve := &core.PersistentVolumeClaim{}
 	err := r.getResource(resourceConfigs.BackUpPvcResourceName, resourceToRetrieve)
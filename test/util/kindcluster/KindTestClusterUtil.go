@@ -23,6 +23,7 @@ package kindcluster
 import (
 	"bytes"
 	"log"
+	"net"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -105,6 +106,76 @@ func (r *KindTestClusterUtil) DeleteCluster() bool {
 	return true
 }
 
+// GetHostAddress returns the IP address of the host in the Docker network of the Kind cluster,
+// so that the API server of the cluster can call the webhooks served by the tests.
+func (r *KindTestClusterUtil) GetHostAddress() string {
+
+	dockerExecPath := r.getDockerExecPath()
+
+	var out bytes.Buffer
+	cmdInspectNetwork := &exec.Cmd{
+		Path:   dockerExecPath,
+		Args:   []string{dockerExecPath, "network", "inspect", "kind", "--format", "{{range .IPAM.Config}}{{.Gateway}} {{end}}"},
+		Stdout: &out,
+		Stderr: os.Stdout,
+	}
+
+	err := cmdInspectNetwork.Run()
+	if err != nil {
+		log.Fatal("Unable to execute the command 'docker network inspect kind'", err)
+	}
+
+	for _, gateway := range strings.Fields(out.String()) {
+		if ip := net.ParseIP(gateway); ip != nil && ip.To4() != nil {
+			return gateway
+		}
+	}
+
+	log.Fatal("Unable to find the IPv4 gateway of the Docker network 'kind'")
+	return ""
+}
+
+// PauseNode freezes all the processes of the given node of the Kind cluster, including its kubelet and its Pods,
+// so that the node becomes unreachable without its Pods being deleted.
+func (r *KindTestClusterUtil) PauseNode(nodeName string) {
+	r.runDockerCommand("pause", nodeName)
+}
+
+// UnpauseNode resumes the processes of a node of the Kind cluster paused with PauseNode.
+func (r *KindTestClusterUtil) UnpauseNode(nodeName string) {
+	r.runDockerCommand("unpause", nodeName)
+}
+
+func (r *KindTestClusterUtil) runDockerCommand(command, nodeName string) {
+
+	log.Println("Running 'docker " + command + " " + nodeName + "'")
+
+	dockerExecPath := r.getDockerExecPath()
+
+	var out bytes.Buffer
+	cmdDocker := &exec.Cmd{
+		Path:   dockerExecPath,
+		Args:   []string{dockerExecPath, command, nodeName},
+		Stdout: &out,
+		Stderr: os.Stdout,
+	}
+
+	err := cmdDocker.Run()
+	if err != nil {
+		log.Fatal("Unable to execute the command 'docker "+command+" "+nodeName+"'", err)
+	}
+}
+
+func (r *KindTestClusterUtil) getDockerExecPath() string {
+	dockerExecPath, err := exec.LookPath("docker")
+	if err != nil {
+		log.Fatal("We cannot find the executable 'docker'. " +
+			"Make sure 'docker' is installed and the executable 'docker' " +
+			"is in the classpath before running the tests.")
+	}
+	return dockerExecPath
+}
+
 func (r *KindTestClusterUtil) isClusterRunning() bool {
 
 	var out bytes.Buffer